@@ -0,0 +1,277 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Methods son los métodos HTTP en el orden en que se recorren las operaciones
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// Document es el subconjunto de OpenAPI 3.1 que usa Contractis
+type Document struct {
+	OpenAPI    string             `json:"openapi"`
+	Info       Info               `json:"info"`
+	Paths      Ordered[*PathItem] `json:"paths"`
+	Components Components         `json:"components"`
+	Security   []map[string][]any `json:"security,omitempty"`
+}
+
+// Info describe la API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components agrupa los objetos reutilizables del documento
+type Components struct {
+	Schemas    Ordered[*Schema]      `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+	Responses  map[string]*Response  `json:"responses"`
+}
+
+// PathItem agrupa las operaciones de una ruta
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Post       *Operation   `json:"post"`
+	Put        *Operation   `json:"put"`
+	Patch      *Operation   `json:"patch"`
+	Delete     *Operation   `json:"delete"`
+}
+
+// Operation es una operación HTTP de la API
+type Operation struct {
+	OperationID string             `json:"operationId"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description"`
+	Parameters  []*Parameter       `json:"parameters"`
+	RequestBody *RequestBody       `json:"requestBody"`
+	Responses   Ordered[*Response] `json:"responses"`
+	// Security reemplaza la seguridad global del documento; nil si no se declara
+	Security []map[string][]any `json:"security"`
+}
+
+// Public indica si la operación declara "security": [] y no exige credenciales
+func (o *Operation) Public() bool {
+	return o.Security != nil && len(o.Security) == 0
+}
+
+// Parameter es un parámetro de ruta, query o cabecera
+type Parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody es el cuerpo de una operación
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response es una respuesta posible de una operación
+type Response struct {
+	Ref         string                `json:"$ref"`
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// MediaType asocia un tipo de contenido con su esquema
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema es el subconjunto de JSON Schema que usa la especificación
+type Schema struct {
+	Ref                  string           `json:"$ref"`
+	Type                 TypeList         `json:"type"`
+	Format               string           `json:"format"`
+	Description          string           `json:"description"`
+	Enum                 []any            `json:"enum"`
+	Items                *Schema          `json:"items"`
+	Properties           Ordered[*Schema] `json:"properties"`
+	Required             []string         `json:"required"`
+	AdditionalProperties *Schema          `json:"additionalProperties"`
+}
+
+// TypeList admite "type" como cadena o como lista (p. ej. ["array", "null"])
+type TypeList []string
+
+// UnmarshalJSON decodifica ambas formas de "type"
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Primary retorna el tipo distinto de "null"
+func (t TypeList) Primary() string {
+	for _, name := range t {
+		if name != "null" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Nullable indica si el esquema admite null
+func (t TypeList) Nullable() bool {
+	for _, name := range t {
+		if name == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAny indica si el esquema es {} y admite cualquier valor
+func (s *Schema) IsAny() bool {
+	return s.Ref == "" && len(s.Type) == 0 && s.Properties.Keys == nil && s.Items == nil && s.AdditionalProperties == nil
+}
+
+// IsRequired indica si la propiedad es obligatoria en el esquema
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Ordered es un objeto JSON que conserva el orden de sus claves
+type Ordered[T any] struct {
+	Keys   []string
+	Values map[string]T
+}
+
+// UnmarshalJSON decodifica el objeto recordando el orden de aparición
+func (o *Ordered[T]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("expected object")
+	}
+	o.Keys = nil
+	o.Values = make(map[string]T)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var value T
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.Keys = append(o.Keys, key)
+		o.Values[key] = value
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// Load decodifica la especificación embebida
+func Load() (*Document, error) {
+	return Parse(OpenAPISpec)
+}
+
+// Parse decodifica un documento OpenAPI
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	return &doc, nil
+}
+
+// Operation retorna la operación de la ruta para el método indicado
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "PATCH":
+		return p.Patch
+	case "DELETE":
+		return p.Delete
+	}
+	return nil
+}
+
+// ResolveSchema sigue las referencias #/components/schemas/...
+func (d *Document) ResolveSchema(schema *Schema) (*Schema, error) {
+	for schema != nil && schema.Ref != "" {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok {
+			return nil, fmt.Errorf("unsupported schema reference: %s", schema.Ref)
+		}
+		resolved, ok := d.Components.Schemas.Values[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown schema: %s", name)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// ResolveParameter sigue las referencias #/components/parameters/...
+func (d *Document) ResolveParameter(param *Parameter) (*Parameter, error) {
+	if param.Ref == "" {
+		return param, nil
+	}
+	name, ok := strings.CutPrefix(param.Ref, "#/components/parameters/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported parameter reference: %s", param.Ref)
+	}
+	resolved, ok := d.Components.Parameters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter: %s", name)
+	}
+	return resolved, nil
+}
+
+// ResolveResponse sigue las referencias #/components/responses/...
+func (d *Document) ResolveResponse(response *Response) (*Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	name, ok := strings.CutPrefix(response.Ref, "#/components/responses/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported response reference: %s", response.Ref)
+	}
+	resolved, ok := d.Components.Responses[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown response: %s", name)
+	}
+	return resolved, nil
+}
+
+// OperationParameters combina los parámetros de la ruta y de la operación ya resueltos
+func (d *Document) OperationParameters(item *PathItem, op *Operation) ([]*Parameter, error) {
+	var params []*Parameter
+	for _, param := range append(append([]*Parameter{}, item.Parameters...), op.Parameters...) {
+		resolved, err := d.ResolveParameter(param)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, resolved)
+	}
+	return params, nil
+}
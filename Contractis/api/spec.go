@@ -0,0 +1,12 @@
+// Package api contiene la especificación OpenAPI de Contractis y un modelo mínimo
+// para recorrerla desde el generador del cliente y el chequeo de contrato.
+package api
+
+import (
+	_ "embed"
+)
+
+// OpenAPISpec es el documento OpenAPI 3.1 que se sirve en /api/openapi.json
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
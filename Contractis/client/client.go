@@ -0,0 +1,193 @@
+// Package client es el cliente Go tipado de la API de Contractis. Los tipos y
+// métodos de client_gen.go se generan desde api/openapi.json con go generate.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxErrorBody limita los bytes de un cuerpo de error que se conservan en APIError
+const maxErrorBody = 64 * 1024
+
+// Client invoca la API de Contractis
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	apiKey      string
+	workspaceID int64
+}
+
+// Option configura un Client
+type Option func(*Client)
+
+// WithAPIKey autentica las peticiones con una API key o un token de sesión
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithWorkspace fija el workspace de las operaciones (cabecera X-Workspace-ID)
+func WithWorkspace(id int64) Option {
+	return func(c *Client) {
+		c.workspaceID = id
+	}
+}
+
+// WithHTTPClient reemplaza el http.Client por defecto
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New crea un cliente para el servidor en baseURL (p. ej. http://localhost:8080)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError es una respuesta no exitosa de la API
+type APIError struct {
+	StatusCode int
+	// Problem está presente en las respuestas application/problem+json de /api/v1
+	Problem *Problem
+	// Message es el error de las respuestas JSON anteriores a v1 o el texto plano
+	Message string
+	Body    []byte
+}
+
+// Error implementa la interfaz error
+func (e *APIError) Error() string {
+	switch {
+	case e.Problem != nil && e.Problem.Detail != "":
+		return fmt.Sprintf("contractis: %d %s: %s", e.StatusCode, e.Problem.Title, e.Problem.Detail)
+	case e.Problem != nil:
+		return fmt.Sprintf("contractis: %d %s", e.StatusCode, e.Problem.Title)
+	case e.Message != "":
+		return fmt.Sprintf("contractis: %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("contractis: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// do envía la petición y decodifica la respuesta en out: *string recibe el cuerpo como
+// texto, nil lo descarta y cualquier otro valor se decodifica como JSON
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType, accept string, out any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.workspaceID > 0 {
+		req.Header.Set("X-Workspace-ID", strconv.FormatInt(c.workspaceID, 10))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+
+	switch out := out.(type) {
+	case nil:
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	case *string:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		*out = string(data)
+		return nil
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+// decodeError construye un APIError a partir de una respuesta no exitosa
+func decodeError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: data}
+
+	mediaType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	switch mediaType {
+	case "application/problem+json":
+		var problem Problem
+		if json.Unmarshal(data, &problem) == nil {
+			apiErr.Problem = &problem
+		}
+	case "application/json":
+		var legacy struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &legacy) == nil {
+			apiErr.Message = legacy.Error
+		}
+	default:
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+// FormFile es un archivo de un campo multipart que admite varios (p. ej. CreateBatchForm.Files)
+type FormFile struct {
+	Name    string
+	Content io.Reader
+}
+
+// encodeMultipart serializa un formulario multipart en memoria
+func encodeMultipart(write func(*multipart.Writer) error) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := write(mw); err != nil {
+		return nil, "", fmt.Errorf("failed to encode form: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, mw.FormDataContentType(), nil
+}
+
+// encodeJSON serializa el cuerpo JSON de una petición
+func encodeJSON(v any) (io.Reader, string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	return bytes.NewReader(data), "application/json", nil
+}
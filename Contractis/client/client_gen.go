@@ -0,0 +1,861 @@
+// Code generated by openapi-gen from api/openapi.json. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// LLMConfigRequest es el esquema LLMConfigRequest de la API: configuración del LLM (dto.LLMConfigRequest)
+type LLMConfigRequest struct {
+	Type              string             `json:"type"`
+	LocalURL          string             `json:"localUrl,omitempty"`
+	APIURL            string             `json:"apiUrl,omitempty"`
+	APIKey            string             `json:"apiKey,omitempty"`
+	ModelName         string             `json:"modelName"`
+	MaxTokens         int                `json:"maxTokens"`
+	Concurrency       int                `json:"concurrency,omitempty"`
+	RequestsPerMinute int                `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int                `json:"tokensPerMinute,omitempty"`
+	Fallbacks         []LLMConfigRequest `json:"fallbacks,omitempty"`
+	FailoverOn        []string           `json:"failoverOn,omitempty"`
+}
+
+// AnalyzeContractForm es el formulario multipart AnalyzeContractForm de la API
+type AnalyzeContractForm struct {
+	// PDF de hasta 10MB
+	File io.Reader
+	// FileName es el nombre de archivo con el que se envía File
+	FileName string
+	// Perfil LLM guardado en el servidor; tiene prioridad sobre llmConfig
+	ProfileID int64
+	LLMConfig *LLMConfigRequest
+}
+
+func (f *AnalyzeContractForm) writeMultipart(mw *multipart.Writer) error {
+	if f.File == nil {
+		return fmt.Errorf("file is required")
+	}
+	{
+		part, err := mw.CreateFormFile("file", f.FileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.File); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	if f.ProfileID != 0 {
+		if err := mw.WriteField("profileId", fmt.Sprint(f.ProfileID)); err != nil {
+			return err
+		}
+	}
+	if f.LLMConfig != nil {
+		data, err := json.Marshal(f.LLMConfig)
+		if err != nil {
+			return err
+		}
+		if err := mw.WriteField("llmConfig", string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EstimateTokensForm es el formulario multipart EstimateTokensForm de la API
+type EstimateTokensForm struct {
+	// PDF de hasta 10MB
+	File io.Reader
+	// FileName es el nombre de archivo con el que se envía File
+	FileName string
+	// Tokens de salida por petición (por defecto 800)
+	MaxTokens int
+}
+
+func (f *EstimateTokensForm) writeMultipart(mw *multipart.Writer) error {
+	if f.File == nil {
+		return fmt.Errorf("file is required")
+	}
+	{
+		part, err := mw.CreateFormFile("file", f.FileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.File); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	if f.MaxTokens != 0 {
+		if err := mw.WriteField("maxTokens", fmt.Sprint(f.MaxTokens)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnalysisResponse es el esquema AnalysisResponse de la API: resultado del análisis (dto.AnalysisResponse)
+type AnalysisResponse struct {
+	Success bool `json:"success"`
+	// Reporte del análisis
+	Data  string `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TokenEstimationResponse es el esquema TokenEstimationResponse de la API: estimación de tokens (dto.TokenEstimationResponse)
+type TokenEstimationResponse struct {
+	Success              bool   `json:"success"`
+	CharacterCount       int    `json:"characterCount"`
+	EstimatedTokens      int    `json:"estimatedTokens"`
+	Chunks               int    `json:"chunks"`
+	SystemPromptTokens   int    `json:"systemPromptTokens"`
+	Phase1Tokens         int    `json:"phase1Tokens"`
+	Phase2InputTokens    int    `json:"phase2InputTokens"`
+	Phase2OutputTokens   int    `json:"phase2OutputTokens"`
+	TotalTokens          int    `json:"totalTokens"`
+	RecommendedMaxTokens int    `json:"recommendedMaxTokens"`
+	Warning              string `json:"warning,omitempty"`
+	Error                string `json:"error,omitempty"`
+}
+
+// ContractRecord es el esquema ContractRecord de la API: contrato analizado (entities.ContractRecord)
+type ContractRecord struct {
+	ID          int64      `json:"id"`
+	WorkspaceID int64      `json:"workspace_id"`
+	Filename    string     `json:"filename"`
+	FileHash    string     `json:"file_hash"`
+	FileSize    int64      `json:"file_size"`
+	UploadedAt  time.Time  `json:"uploaded_at"`
+	AnalyzedAt  *time.Time `json:"analyzed_at,omitempty"`
+	Status      string     `json:"status"`
+	LLMType     string     `json:"llm_type"`
+	LLMModel    string     `json:"llm_model"`
+	MaxTokens   int        `json:"max_tokens"`
+	// Modelo y endpoint (modelo@host/ruta) que respondieron en cada fase (single, phase1, consolidation)
+	PhaseModels           map[string][]string `json:"phase_models,omitempty"`
+	AnalysisResult        string              `json:"analysis_result"`
+	CharacterCount        int                 `json:"character_count"`
+	EstimatedTokens       int                 `json:"estimated_tokens"`
+	ChunksCount           int                 `json:"chunks_count"`
+	ProcessingTimeSeconds float64             `json:"processing_time_seconds"`
+	ErrorMessage          string              `json:"error_message,omitempty"`
+	CreatedAt             time.Time           `json:"created_at"`
+	UpdatedAt             time.Time           `json:"updated_at"`
+	DeletedAt             *time.Time          `json:"deleted_at,omitempty"`
+	LegalHold             bool                `json:"legal_hold"`
+	LegalHoldReason       string              `json:"legal_hold_reason,omitempty"`
+}
+
+// ContractStats es el esquema ContractStats de la API: estadísticas (repositories.ContractStats)
+type ContractStats struct {
+	TotalContracts        int        `json:"TotalContracts"`
+	CompletedContracts    int        `json:"CompletedContracts"`
+	FailedContracts       int        `json:"FailedContracts"`
+	TotalProcessingTime   float64    `json:"TotalProcessingTime"`
+	AverageProcessingTime float64    `json:"AverageProcessingTime"`
+	LastAnalyzedAt        *time.Time `json:"LastAnalyzedAt,omitempty"`
+}
+
+// ContractListResponse es el esquema ContractListResponse de la API
+type ContractListResponse struct {
+	Success bool             `json:"success"`
+	Data    []ContractRecord `json:"data"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+// ContractSearchResponse es el esquema ContractSearchResponse de la API
+type ContractSearchResponse struct {
+	Success bool             `json:"success"`
+	Data    []ContractRecord `json:"data"`
+	Query   string           `json:"query"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
+
+// ContractsResponse es el esquema ContractsResponse de la API
+type ContractsResponse struct {
+	Success bool             `json:"success"`
+	Data    []ContractRecord `json:"data"`
+}
+
+// ContractResponse es el esquema ContractResponse de la API
+type ContractResponse struct {
+	Success bool           `json:"success"`
+	Data    ContractRecord `json:"data"`
+}
+
+// ContractStatsResponse es el esquema ContractStatsResponse de la API
+type ContractStatsResponse struct {
+	Success bool          `json:"success"`
+	Data    ContractStats `json:"data"`
+}
+
+// MessageResponse es el esquema MessageResponse de la API
+type MessageResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// CreateBatchForm es el formulario multipart CreateBatchForm de la API
+type CreateBatchForm struct {
+	// PDF de hasta 10MB o archivos ZIP con PDF; el campo se repite por archivo
+	Files []FormFile
+	// Nombre del lote; por defecto, el del primer archivo
+	Name string
+	// Perfil LLM guardado en el servidor; tiene prioridad sobre llmConfig
+	ProfileID int64
+	LLMConfig *LLMConfigRequest
+}
+
+func (f *CreateBatchForm) writeMultipart(mw *multipart.Writer) error {
+	if len(f.Files) == 0 {
+		return fmt.Errorf("files is required")
+	}
+	for _, file := range f.Files {
+		part, err := mw.CreateFormFile("files", file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return fmt.Errorf("failed to write files: %w", err)
+		}
+	}
+	if f.Name != "" {
+		if err := mw.WriteField("name", fmt.Sprint(f.Name)); err != nil {
+			return err
+		}
+	}
+	if f.ProfileID != 0 {
+		if err := mw.WriteField("profileId", fmt.Sprint(f.ProfileID)); err != nil {
+			return err
+		}
+	}
+	if f.LLMConfig != nil {
+		data, err := json.Marshal(f.LLMConfig)
+		if err != nil {
+			return err
+		}
+		if err := mw.WriteField("llmConfig", string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Batch es el esquema Batch de la API: lote de documentos (entities.Batch)
+type Batch struct {
+	ID          int64      `json:"id"`
+	WorkspaceID int64      `json:"workspace_id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	Stats       BatchStats `json:"stats"`
+	// Solo en createBatch y getBatch
+	Documents []BatchDocument `json:"documents,omitempty"`
+}
+
+// BatchStats es el esquema BatchStats de la API: avance del lote
+type BatchStats struct {
+	Total      int `json:"total"`
+	Pending    int `json:"pending"`
+	Analyzing  int `json:"analyzing"`
+	Completed  int `json:"completed"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+	Rejected   int `json:"rejected"`
+	// Porcentaje de documentos terminados (0 a 100)
+	Progress float64 `json:"progress"`
+	// Bytes de los documentos aceptados
+	TotalSize             int64   `json:"total_size"`
+	ProcessingTimeSeconds float64 `json:"processing_time_seconds"`
+}
+
+// BatchDocument es el esquema BatchDocument de la API: documento de un lote (entities.BatchDocument)
+type BatchDocument struct {
+	ID       int64 `json:"id"`
+	BatchID  int64 `json:"batch_id"`
+	Position int   `json:"position"`
+	// Nombre subido; dentro de un ZIP, archivo.zip/ruta/interna.pdf
+	Filename string `json:"filename"`
+	FileHash string `json:"file_hash,omitempty"`
+	FileSize int64  `json:"file_size"`
+	Status   string `json:"status"`
+	// Contrato con el análisis; los duplicados comparten el del primer documento igual
+	ContractID int64      `json:"contract_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// BatchResponse es el esquema BatchResponse de la API
+type BatchResponse struct {
+	Success bool  `json:"success"`
+	Data    Batch `json:"data"`
+}
+
+// BatchListResponse es el esquema BatchListResponse de la API
+type BatchListResponse struct {
+	Success bool    `json:"success"`
+	Data    []Batch `json:"data"`
+	Limit   int     `json:"limit"`
+	Offset  int     `json:"offset"`
+}
+
+// ContractPage es el esquema ContractPage de la API
+type ContractPage struct {
+	Data []ContractRecord `json:"data"`
+	// Ausente en la última página
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ContractEnvelope es el esquema ContractEnvelope de la API
+type ContractEnvelope struct {
+	Data ContractRecord `json:"data"`
+}
+
+// Webhook es el esquema Webhook de la API: suscripción de un workspace a eventos (entities.Webhook)
+type Webhook struct {
+	ID          int64     `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookRequest es el esquema WebhookRequest de la API: creación de un webhook (dto.WebhookRequest)
+type WebhookRequest struct {
+	// URL http(s) absoluta que recibe los POST firmados
+	URL         string   `json:"url"`
+	Description *string  `json:"description,omitempty"`
+	Events      []string `json:"events"`
+	// true por defecto
+	Active *bool `json:"active,omitempty"`
+}
+
+// WebhookUpdateRequest es el esquema WebhookUpdateRequest de la API: modificación de un webhook (dto.WebhookUpdateRequest); los campos omitidos no cambian
+type WebhookUpdateRequest struct {
+	URL         *string  `json:"url,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	// Genera un secreto de firma nuevo
+	RotateSecret *bool `json:"rotate_secret,omitempty"`
+}
+
+// WebhookEnvelope es el esquema WebhookEnvelope de la API
+type WebhookEnvelope struct {
+	Data Webhook `json:"data"`
+	// Secreto de firma; solo al crear el webhook o con rotate_secret
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookList es el esquema WebhookList de la API
+type WebhookList struct {
+	Data []Webhook `json:"data"`
+}
+
+// WebhookEventData es el esquema WebhookEventData de la API: datos del evento: los del contrato en analysis.* y contract.deleted, webhook_id y message en webhook.test
+type WebhookEventData struct {
+	ContractID            int64   `json:"contract_id,omitempty"`
+	Filename              string  `json:"filename,omitempty"`
+	FileHash              string  `json:"file_hash,omitempty"`
+	Status                string  `json:"status,omitempty"`
+	LLMModel              string  `json:"llm_model,omitempty"`
+	ChunksCount           int     `json:"chunks_count,omitempty"`
+	ProcessingTimeSeconds float64 `json:"processing_time_seconds,omitempty"`
+	Error                 string  `json:"error,omitempty"`
+	// En contract.deleted, true si el contrato se purgó
+	Permanent bool   `json:"permanent,omitempty"`
+	DeletedBy string `json:"deleted_by,omitempty"`
+	WebhookID int64  `json:"webhook_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// WebhookPayload es el esquema WebhookPayload de la API: cuerpo JSON de cada entrega
+type WebhookPayload struct {
+	ID          string           `json:"id"`
+	Event       string           `json:"event"`
+	CreatedAt   time.Time        `json:"created_at"`
+	WorkspaceID int64            `json:"workspace_id"`
+	Data        WebhookEventData `json:"data"`
+}
+
+// WebhookDelivery es el esquema WebhookDelivery de la API: entrega de un evento a un webhook; response_status y response_body son los del último intento
+type WebhookDelivery struct {
+	ID             int64  `json:"id"`
+	WebhookID      int64  `json:"webhook_id"`
+	WorkspaceID    int64  `json:"workspace_id"`
+	EventID        string `json:"event_id"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"response_status,omitempty"`
+	// Primeros 1024 bytes de la respuesta
+	ResponseBody  string         `json:"response_body,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	DurationMs    int64          `json:"duration_ms"`
+	Payload       WebhookPayload `json:"payload"`
+	CreatedAt     time.Time      `json:"created_at"`
+	LastAttemptAt *time.Time     `json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time     `json:"next_attempt_at,omitempty"`
+}
+
+// WebhookDeliveryEnvelope es el esquema WebhookDeliveryEnvelope de la API
+type WebhookDeliveryEnvelope struct {
+	Data WebhookDelivery `json:"data"`
+}
+
+// WebhookDeliveryPage es el esquema WebhookDeliveryPage de la API
+type WebhookDeliveryPage struct {
+	Data []WebhookDelivery `json:"data"`
+	// Ausente en la última página
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// HealthReport es el esquema HealthReport de la API: resultado de los chequeos de liveness y readiness (entities.HealthReport)
+type HealthReport struct {
+	Status        string            `json:"status"`
+	Timestamp     time.Time         `json:"timestamp"`
+	UptimeSeconds int64             `json:"uptimeSeconds"`
+	Build         BuildInfo         `json:"build"`
+	Components    []ComponentHealth `json:"components,omitempty"`
+}
+
+// BuildInfo es el esquema BuildInfo de la API: identifica el binario en ejecución
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// ComponentHealth es el esquema ComponentHealth de la API: estado de un componente del que depende el servicio
+type ComponentHealth struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Si el servicio deja de estar listo cuando el componente falla
+	Critical   bool           `json:"critical"`
+	DurationMs int64          `json:"durationMs"`
+	Error      string         `json:"error,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// ErrorResponse es el esquema ErrorResponse de la API: error de autenticación de las rutas anteriores a v1
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// Problem es el esquema Problem de la API: error RFC 7807 (application/problem+json)
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// AnalyzeContract analiza un contrato PDF (POST /upload)
+func (c *Client) AnalyzeContract(ctx context.Context, form *AnalyzeContractForm) (*AnalysisResponse, error) {
+	path := "/upload"
+	query := url.Values{}
+	body, contentType, err := encodeMultipart(form.writeMultipart)
+	if err != nil {
+		return nil, err
+	}
+	var out AnalysisResponse
+	if err := c.do(ctx, http.MethodPost, path, query, body, contentType, "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EstimateTokens estima los tokens que consumirá el análisis de un PDF (POST /estimate)
+func (c *Client) EstimateTokens(ctx context.Context, form *EstimateTokensForm) (*TokenEstimationResponse, error) {
+	path := "/estimate"
+	query := url.Values{}
+	body, contentType, err := encodeMultipart(form.writeMultipart)
+	if err != nil {
+		return nil, err
+	}
+	var out TokenEstimationResponse
+	if err := c.do(ctx, http.MethodPost, path, query, body, contentType, "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListContractsParams son los parámetros opcionales de ListContracts
+type ListContractsParams struct {
+	Limit  *int
+	Offset *int
+}
+
+// ListContracts lista los contratos con paginación por offset (GET /api/contracts)
+func (c *Client) ListContracts(ctx context.Context, params *ListContractsParams) (*ContractListResponse, error) {
+	path := "/api/contracts"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Offset != nil {
+			query.Set("offset", fmt.Sprint(*params.Offset))
+		}
+	}
+	var out ContractListResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchContractsParams son los parámetros opcionales de SearchContracts
+type SearchContractsParams struct {
+	Limit  *int
+	Offset *int
+}
+
+// SearchContracts busca contratos por nombre de archivo (GET /api/contracts/search)
+func (c *Client) SearchContracts(ctx context.Context, q string, params *SearchContractsParams) (*ContractSearchResponse, error) {
+	path := "/api/contracts/search"
+	query := url.Values{}
+	query.Set("q", fmt.Sprint(q))
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Offset != nil {
+			query.Set("offset", fmt.Sprint(*params.Offset))
+		}
+	}
+	var out ContractSearchResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRecentContractsParams son los parámetros opcionales de GetRecentContracts
+type GetRecentContractsParams struct {
+	Limit *int
+}
+
+// GetRecentContracts lista los contratos analizados más recientemente (GET /api/contracts/recent)
+func (c *Client) GetRecentContracts(ctx context.Context, params *GetRecentContractsParams) (*ContractsResponse, error) {
+	path := "/api/contracts/recent"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	var out ContractsResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetContractStats obtiene las estadísticas de los contratos del workspace (GET /api/contracts/stats)
+func (c *Client) GetContractStats(ctx context.Context) (*ContractStatsResponse, error) {
+	path := "/api/contracts/stats"
+	query := url.Values{}
+	var out ContractStatsResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetContract obtiene un contrato con su análisis (GET /api/contracts/get)
+func (c *Client) GetContract(ctx context.Context, id int64) (*ContractResponse, error) {
+	path := "/api/contracts/get"
+	query := url.Values{}
+	query.Set("id", fmt.Sprint(id))
+	var out ContractResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportContract descarga el análisis de un contrato como texto plano (GET /api/contracts/export)
+func (c *Client) ExportContract(ctx context.Context, id int64) (string, error) {
+	path := "/api/contracts/export"
+	query := url.Values{}
+	query.Set("id", fmt.Sprint(id))
+	var out string
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "text/plain", &out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// DeleteContract envía un contrato a la papelera (DELETE /api/contracts/delete)
+func (c *Client) DeleteContract(ctx context.Context, id int64) (*MessageResponse, error) {
+	path := "/api/contracts/delete"
+	query := url.Values{}
+	query.Set("id", fmt.Sprint(id))
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBatchesParams son los parámetros opcionales de ListBatches
+type ListBatchesParams struct {
+	Limit  *int
+	Offset *int
+}
+
+// ListBatches lista los lotes del workspace con su avance (GET /api/batches)
+func (c *Client) ListBatches(ctx context.Context, params *ListBatchesParams) (*BatchListResponse, error) {
+	path := "/api/batches"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Offset != nil {
+			query.Set("offset", fmt.Sprint(*params.Offset))
+		}
+	}
+	var out BatchListResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBatch crea un lote con varios PDF o archivos ZIP (POST /api/batches)
+func (c *Client) CreateBatch(ctx context.Context, form *CreateBatchForm) (*BatchResponse, error) {
+	path := "/api/batches"
+	query := url.Values{}
+	body, contentType, err := encodeMultipart(form.writeMultipart)
+	if err != nil {
+		return nil, err
+	}
+	var out BatchResponse
+	if err := c.do(ctx, http.MethodPost, path, query, body, contentType, "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBatch obtiene un lote con el estado de cada documento (GET /api/batches/get)
+func (c *Client) GetBatch(ctx context.Context, id int64) (*BatchResponse, error) {
+	path := "/api/batches/get"
+	query := url.Values{}
+	query.Set("id", fmt.Sprint(id))
+	var out BatchResponse
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportBatch descarga el resumen del lote y los análisis de sus documentos como texto plano (GET /api/batches/export)
+func (c *Client) ExportBatch(ctx context.Context, id int64) (string, error) {
+	path := "/api/batches/export"
+	query := url.Values{}
+	query.Set("id", fmt.Sprint(id))
+	var out string
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "text/plain", &out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// ListContractsV1Params son los parámetros opcionales de ListContractsV1
+type ListContractsV1Params struct {
+	Limit *int
+	// next_cursor de la página anterior
+	Cursor *string
+	// filtro por nombre de archivo
+	Q *string
+}
+
+// ListContractsV1 lista los contratos con paginación por cursor (GET /api/v1/contracts)
+func (c *Client) ListContractsV1(ctx context.Context, params *ListContractsV1Params) (*ContractPage, error) {
+	path := "/api/v1/contracts"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+		if params.Q != nil {
+			query.Set("q", fmt.Sprint(*params.Q))
+		}
+	}
+	var out ContractPage
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetContractV1 obtiene un contrato con su análisis (GET /api/v1/contracts/{id})
+func (c *Client) GetContractV1(ctx context.Context, id int64) (*ContractEnvelope, error) {
+	path := "/api/v1/contracts/" + url.PathEscape(fmt.Sprint(id))
+	query := url.Values{}
+	var out ContractEnvelope
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteContractV1 envía un contrato a la papelera (DELETE /api/v1/contracts/{id})
+func (c *Client) DeleteContractV1(ctx context.Context, id int64) error {
+	path := "/api/v1/contracts/" + url.PathEscape(fmt.Sprint(id))
+	query := url.Values{}
+	return c.do(ctx, http.MethodDelete, path, query, nil, "", "application/problem+json", nil)
+}
+
+// ExportContractV1 descarga el análisis de un contrato como texto plano (GET /api/v1/contracts/{id}/export)
+func (c *Client) ExportContractV1(ctx context.Context, id int64) (string, error) {
+	path := "/api/v1/contracts/" + url.PathEscape(fmt.Sprint(id)) + "/export"
+	query := url.Values{}
+	var out string
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "text/plain", &out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// ListWebhooks lista los webhooks del workspace (solo administradores) (GET /api/v1/webhooks)
+func (c *Client) ListWebhooks(ctx context.Context) (*WebhookList, error) {
+	path := "/api/v1/webhooks"
+	query := url.Values{}
+	var out WebhookList
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWebhook crea un webhook; la respuesta incluye el secreto de firma (POST /api/v1/webhooks)
+func (c *Client) CreateWebhook(ctx context.Context, request *WebhookRequest) (*WebhookEnvelope, error) {
+	path := "/api/v1/webhooks"
+	query := url.Values{}
+	body, contentType, err := encodeJSON(request)
+	if err != nil {
+		return nil, err
+	}
+	var out WebhookEnvelope
+	if err := c.do(ctx, http.MethodPost, path, query, body, contentType, "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhook obtiene un webhook (GET /api/v1/webhooks/{id})
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*WebhookEnvelope, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	query := url.Values{}
+	var out WebhookEnvelope
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWebhook modifica un webhook; con rotate_secret la respuesta incluye el secreto nuevo (PATCH /api/v1/webhooks/{id})
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, request *WebhookUpdateRequest) (*WebhookEnvelope, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	query := url.Values{}
+	body, contentType, err := encodeJSON(request)
+	if err != nil {
+		return nil, err
+	}
+	var out WebhookEnvelope
+	if err := c.do(ctx, http.MethodPatch, path, query, body, contentType, "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhook elimina un webhook y su log de entregas (DELETE /api/v1/webhooks/{id})
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id))
+	query := url.Values{}
+	return c.do(ctx, http.MethodDelete, path, query, nil, "", "application/problem+json", nil)
+}
+
+// ListWebhookDeliveriesParams son los parámetros opcionales de ListWebhookDeliveries
+type ListWebhookDeliveriesParams struct {
+	Limit *int
+	// next_cursor de la página anterior
+	Cursor *string
+}
+
+// ListWebhookDeliveries lista el log de entregas de un webhook con paginación por cursor (GET /api/v1/webhooks/{id}/deliveries)
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int64, params *ListWebhookDeliveriesParams) (*WebhookDeliveryPage, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id)) + "/deliveries"
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	var out WebhookDeliveryPage
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestWebhook envía un evento webhook.test y retorna el resultado de la entrega (POST /api/v1/webhooks/{id}/test)
+func (c *Client) TestWebhook(ctx context.Context, id int64) (*WebhookDeliveryEnvelope, error) {
+	path := "/api/v1/webhooks/" + url.PathEscape(fmt.Sprint(id)) + "/test"
+	query := url.Values{}
+	var out WebhookDeliveryEnvelope
+	if err := c.do(ctx, http.MethodPost, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLiveness responde mientras el proceso atiende peticiones, sin revisar sus dependencias (GET /healthz)
+func (c *Client) GetLiveness(ctx context.Context) (*HealthReport, error) {
+	path := "/healthz"
+	query := url.Values{}
+	var out HealthReport
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReadiness revisa la base de datos, el almacenamiento y los perfiles LLM (GET /readyz)
+func (c *Client) GetReadiness(ctx context.Context) (*HealthReport, error) {
+	path := "/readyz"
+	query := url.Values{}
+	var out HealthReport
+	if err := c.do(ctx, http.MethodGet, path, query, nil, "", "application/json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,426 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/api"
+	"github.com/rodascaar/contractis/client"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+)
+
+// TestContract valida el contrato de la API: levanta el router real sobre una base SQLite
+// temporal y un LLM simulado, invoca cada operación con el cliente generado y comprueba
+// que rutas, códigos de estado, tipos de contenido y cuerpos coinciden con api/openapi.json
+func TestContract(t *testing.T) {
+	ctx := context.Background()
+
+	doc, err := api.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := startServer(t)
+
+	checkRoutes(t, doc, server.api.URL)
+
+	rec := &recorder{next: http.DefaultTransport}
+	httpClient := &http.Client{Transport: rec}
+	c := client.New(server.api.URL, client.WithAPIKey(server.apiKey), client.WithWorkspace(1), client.WithHTTPClient(httpClient))
+	anonymous := client.New(server.api.URL, client.WithHTTPClient(httpClient))
+
+	exercise(t, ctx, c, anonymous, server.llm.URL)
+	exerciseBatches(t, ctx, c, &client.LLMConfigRequest{Type: "local", LocalURL: server.llm.URL, ModelName: "stub", MaxTokens: 800})
+	exerciseWebhooks(t, ctx, c)
+	exerciseHealth(t, ctx, anonymous)
+	checkTraces(t, ctx, server.tracer, server.spans)
+
+	spec := newContract(doc)
+	for _, ex := range rec.exchanges {
+		for _, failure := range spec.check(ex) {
+			t.Error(failure)
+		}
+	}
+	for _, missing := range spec.uncovered() {
+		t.Error(missing)
+	}
+	t.Logf("%d exchanges validated against %d paths", len(rec.exchanges), len(doc.Paths.Keys))
+}
+
+// checkRoutes verifica que cada operación documentada llega a su handler: sin credenciales
+// las operaciones protegidas deben responder 401, no el 404 de los archivos estáticos, y
+// las públicas no deben responder 404
+func checkRoutes(t *testing.T, doc *api.Document, baseURL string) {
+	t.Helper()
+	for _, template := range doc.Paths.Keys {
+		item := doc.Paths.Values[template]
+		for _, method := range api.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			target := baseURL + strings.ReplaceAll(template, "{id}", "1") + "?id=1&q=x"
+			req, _ := http.NewRequest(method, target, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Errorf("%s %s: %v", method, template, err)
+				continue
+			}
+			resp.Body.Close()
+			switch {
+			case op.Public() && resp.StatusCode == http.StatusNotFound:
+				t.Errorf("%s %s: public operation returned 404 (route not registered?)", method, template)
+			case !op.Public() && resp.StatusCode != http.StatusUnauthorized:
+				t.Errorf("%s %s: without credentials returned %d, want 401 (route not registered?)", method, template, resp.StatusCode)
+			}
+		}
+	}
+}
+
+// exercise invoca cada operación de contratos del cliente generado, incluidos los casos de error
+func exercise(t *testing.T, ctx context.Context, c, anonymous *client.Client, llmURL string) {
+	t.Helper()
+	llmConfig := &client.LLMConfigRequest{Type: "local", LocalURL: llmURL, ModelName: "stub", MaxTokens: 800}
+
+	estimate, err := c.EstimateTokens(ctx, &client.EstimateTokensForm{
+		File:     bytes.NewReader(samplePDF("Contrato de arrendamiento", "Clausula primera: objeto del contrato.")),
+		FileName: "arrendamiento.pdf",
+	})
+	if err != nil {
+		t.Errorf("EstimateTokens: %v", err)
+	} else if !estimate.Success || estimate.CharacterCount == 0 {
+		t.Errorf("EstimateTokens: empty estimate %+v", estimate)
+	}
+
+	for i, name := range []string{"arrendamiento.pdf", "servicios.pdf"} {
+		analysis, err := c.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+			File:      bytes.NewReader(samplePDF(fmt.Sprintf("Contrato %d", i+1), "Clausula primera: objeto del contrato.")),
+			FileName:  name,
+			LLMConfig: llmConfig,
+		})
+		if err != nil {
+			t.Errorf("AnalyzeContract(%s): %v", name, err)
+			continue
+		}
+		if !analysis.Success || !strings.Contains(analysis.Data, "Contrato de prueba") {
+			t.Errorf("AnalyzeContract(%s): unexpected analysis %+v", name, analysis)
+		}
+	}
+
+	var ids []int64
+	list, err := c.ListContracts(ctx, &client.ListContractsParams{Limit: intPtr(10)})
+	if err != nil {
+		t.Errorf("ListContracts: %v", err)
+	} else {
+		for _, contract := range list.Data {
+			ids = append(ids, contract.ID)
+		}
+	}
+	if len(ids) != 2 {
+		t.Errorf("ListContracts: got %d contracts, want 2", len(ids))
+		return
+	}
+
+	if result, err := c.SearchContracts(ctx, "servicios", nil); err != nil {
+		t.Errorf("SearchContracts: %v", err)
+	} else if len(result.Data) != 1 {
+		t.Errorf("SearchContracts: got %d results, want 1", len(result.Data))
+	}
+	if _, err := c.GetRecentContracts(ctx, &client.GetRecentContractsParams{Limit: intPtr(5)}); err != nil {
+		t.Errorf("GetRecentContracts: %v", err)
+	}
+	if stats, err := c.GetContractStats(ctx); err != nil {
+		t.Errorf("GetContractStats: %v", err)
+	} else if stats.Data.CompletedContracts != 2 {
+		t.Errorf("GetContractStats: %d completed, want 2", stats.Data.CompletedContracts)
+	}
+
+	if contract, err := c.GetContract(ctx, ids[0]); err != nil {
+		t.Errorf("GetContract: %v", err)
+	} else if contract.Data.AnalyzedAt == nil || contract.Data.Status != "completed" {
+		t.Errorf("GetContract: incomplete contract %+v", contract.Data)
+	}
+	if export, err := c.ExportContract(ctx, ids[0]); err != nil {
+		t.Errorf("ExportContract: %v", err)
+	} else if !strings.Contains(export, "Contrato de prueba") {
+		t.Error("ExportContract: export without the analysis")
+	}
+
+	page, err := c.ListContractsV1(ctx, &client.ListContractsV1Params{Limit: intPtr(1)})
+	if err != nil {
+		t.Errorf("ListContractsV1: %v", err)
+	} else if len(page.Data) != 1 || page.NextCursor == "" {
+		t.Error("ListContractsV1: want a one-item page with next_cursor")
+	} else if next, err := c.ListContractsV1(ctx, &client.ListContractsV1Params{Limit: intPtr(1), Cursor: &page.NextCursor}); err != nil {
+		t.Errorf("ListContractsV1 (cursor): %v", err)
+	} else if len(next.Data) != 1 || next.NextCursor != "" {
+		t.Error("ListContractsV1 (cursor): want the last page")
+	}
+	if _, err := c.GetContractV1(ctx, ids[1]); err != nil {
+		t.Errorf("GetContractV1: %v", err)
+	}
+	if _, err := c.ExportContractV1(ctx, ids[1]); err != nil {
+		t.Errorf("ExportContractV1: %v", err)
+	}
+
+	// Errores: cada estilo de la API debe decodificarse en APIError
+	expectError(t, "GetContractV1 missing", http.StatusNotFound, true, func() error {
+		_, err := c.GetContractV1(ctx, 999999)
+		return err
+	})
+	expectError(t, "GetContract missing", http.StatusNotFound, false, func() error {
+		_, err := c.GetContract(ctx, 999999)
+		return err
+	})
+	expectError(t, "EstimateTokens without a PDF", http.StatusUnsupportedMediaType, false, func() error {
+		_, err := c.EstimateTokens(ctx, &client.EstimateTokensForm{File: strings.NewReader("hola"), FileName: "nota.txt"})
+		return err
+	})
+	expectError(t, "ListContracts without credentials", http.StatusUnauthorized, false, func() error {
+		_, err := anonymous.ListContracts(ctx, nil)
+		return err
+	})
+	expectError(t, "ListContractsV1 without credentials", http.StatusUnauthorized, true, func() error {
+		_, err := anonymous.ListContractsV1(ctx, nil)
+		return err
+	})
+
+	if _, err := c.DeleteContract(ctx, ids[0]); err != nil {
+		t.Errorf("DeleteContract: %v", err)
+	}
+	if err := c.DeleteContractV1(ctx, ids[1]); err != nil {
+		t.Errorf("DeleteContractV1: %v", err)
+	}
+	expectError(t, "GetContractV1 in the trash", http.StatusNotFound, true, func() error {
+		_, err := c.GetContractV1(ctx, ids[1])
+		return err
+	})
+}
+
+// exerciseBatches sube un lote con un ZIP y un PDF suelto y espera a que termine
+func exerciseBatches(t *testing.T, ctx context.Context, c *client.Client, llmConfig *client.LLMConfigRequest) {
+	t.Helper()
+
+	compra := samplePDF("Contrato de compraventa", "Clausula primera: objeto del contrato.")
+	room, err := sampleZip(
+		zipEntry{"contratos/compraventa.pdf", compra},
+		zipEntry{"contratos/copia.pdf", compra},
+		zipEntry{"../fuera.pdf", compra},
+		zipEntry{"notas.txt", []byte("hola")},
+	)
+	if err != nil {
+		t.Fatalf("sampleZip: %v", err)
+	}
+
+	created, err := c.CreateBatch(ctx, &client.CreateBatchForm{
+		Files: []client.FormFile{
+			{Name: "data-room.zip", Content: bytes.NewReader(room)},
+			{Name: "locacion.pdf", Content: bytes.NewReader(samplePDF("Contrato de locacion", "Clausula primera: objeto."))},
+		},
+		Name:      "Data room",
+		LLMConfig: llmConfig,
+	})
+	if err != nil {
+		t.Errorf("CreateBatch: %v", err)
+		return
+	}
+	if created.Data.Stats.Total != 5 || created.Data.Stats.Rejected != 2 {
+		t.Errorf("CreateBatch: want 5 documents and 2 rejected, got %+v", created.Data.Stats)
+	}
+
+	var batch *client.Batch
+	for deadline := time.Now().Add(30 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		got, err := c.GetBatch(ctx, created.Data.ID)
+		if err != nil {
+			t.Errorf("GetBatch: %v", err)
+			return
+		}
+		if got.Data.Status == "completed" {
+			batch = &got.Data
+			break
+		}
+	}
+	if batch == nil {
+		t.Error("GetBatch: the batch did not finish in 30s")
+		return
+	}
+	if batch.Stats.Completed != 2 || batch.Stats.Duplicates != 1 || batch.Stats.Progress != 100 {
+		t.Errorf("GetBatch: unexpected stats %+v", batch.Stats)
+	}
+
+	if list, err := c.ListBatches(ctx, nil); err != nil {
+		t.Errorf("ListBatches: %v", err)
+	} else if len(list.Data) != 1 || len(list.Data[0].Documents) != 0 {
+		t.Error("ListBatches: want one batch without documents")
+	}
+	if export, err := c.ExportBatch(ctx, batch.ID); err != nil {
+		t.Errorf("ExportBatch: %v", err)
+	} else if !strings.Contains(export, "compraventa.pdf") || !strings.Contains(export, "Mismo contenido que") {
+		t.Error("ExportBatch: incomplete export")
+	}
+
+	expectError(t, "CreateBatch with a broken ZIP", http.StatusBadRequest, false, func() error {
+		_, err := c.CreateBatch(ctx, &client.CreateBatchForm{
+			Files:     []client.FormFile{{Name: "roto.zip", Content: strings.NewReader("no es un zip")}},
+			LLMConfig: llmConfig,
+		})
+		return err
+	})
+	expectError(t, "GetBatch missing", http.StatusNotFound, false, func() error {
+		_, err := c.GetBatch(ctx, 999999)
+		return err
+	})
+}
+
+// exerciseWebhooks recorre el ciclo de vida de un webhook contra un receptor local
+func exerciseWebhooks(t *testing.T, ctx context.Context, c *client.Client) {
+	t.Helper()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("recibido"))
+	}))
+	defer receiver.Close()
+
+	created, err := c.CreateWebhook(ctx, &client.WebhookRequest{
+		URL:    receiver.URL + "/hooks",
+		Events: []string{"analysis.completed", "contract.deleted"},
+	})
+	if err != nil {
+		t.Errorf("CreateWebhook: %v", err)
+		return
+	}
+	if created.Secret == "" || !created.Data.Active {
+		t.Errorf("CreateWebhook: want an active webhook and its secret, got %+v", created)
+	}
+	id := created.Data.ID
+
+	if list, err := c.ListWebhooks(ctx); err != nil {
+		t.Errorf("ListWebhooks: %v", err)
+	} else if len(list.Data) != 1 {
+		t.Errorf("ListWebhooks: got %d webhooks, want 1", len(list.Data))
+	}
+	if got, err := c.GetWebhook(ctx, id); err != nil {
+		t.Errorf("GetWebhook: %v", err)
+	} else if got.Secret != "" {
+		t.Error("GetWebhook: the secret is only returned on creation")
+	}
+
+	if delivery, err := c.TestWebhook(ctx, id); err != nil {
+		t.Errorf("TestWebhook: %v", err)
+	} else if delivery.Data.Status != "succeeded" || delivery.Data.ResponseStatus != http.StatusOK || delivery.Data.Payload.Event != "webhook.test" {
+		t.Errorf("TestWebhook: unexpected delivery %+v", delivery.Data)
+	}
+	if page, err := c.ListWebhookDeliveries(ctx, id, &client.ListWebhookDeliveriesParams{Limit: intPtr(10)}); err != nil {
+		t.Errorf("ListWebhookDeliveries: %v", err)
+	} else if len(page.Data) != 1 || page.NextCursor != "" {
+		t.Errorf("ListWebhookDeliveries: got %d deliveries, want 1", len(page.Data))
+	}
+
+	active, rotate := false, true
+	if updated, err := c.UpdateWebhook(ctx, id, &client.WebhookUpdateRequest{Active: &active, RotateSecret: &rotate}); err != nil {
+		t.Errorf("UpdateWebhook: %v", err)
+	} else if updated.Data.Active || updated.Secret == "" || updated.Secret == created.Secret {
+		t.Errorf("UpdateWebhook: want an inactive webhook with a new secret, got %+v", updated)
+	}
+
+	expectError(t, "CreateWebhook with an invalid URL", http.StatusBadRequest, true, func() error {
+		_, err := c.CreateWebhook(ctx, &client.WebhookRequest{URL: "ftp://example.com", Events: []string{"analysis.completed"}})
+		return err
+	})
+
+	if err := c.DeleteWebhook(ctx, id); err != nil {
+		t.Errorf("DeleteWebhook: %v", err)
+	}
+	expectError(t, "GetWebhook deleted", http.StatusNotFound, true, func() error {
+		_, err := c.GetWebhook(ctx, id)
+		return err
+	})
+}
+
+// exerciseHealth consulta liveness y readiness sin credenciales
+func exerciseHealth(t *testing.T, ctx context.Context, anonymous *client.Client) {
+	t.Helper()
+	if report, err := anonymous.GetLiveness(ctx); err != nil {
+		t.Errorf("GetLiveness: %v", err)
+	} else if report.Status != "up" {
+		t.Errorf("GetLiveness: status %q", report.Status)
+	}
+	if report, err := anonymous.GetReadiness(ctx); err != nil {
+		t.Errorf("GetReadiness: %v", err)
+	} else if report.Status != "up" || len(report.Components) == 0 {
+		t.Errorf("GetReadiness: unexpected report %+v", report)
+	}
+}
+
+// checkTraces verifica con los spans exportados en memoria que cada análisis exitoso
+// subido por /upload quedó trazado con sus etapas, colgando de la petición HTTP
+func checkTraces(t *testing.T, ctx context.Context, tracer *tracing.Tracer, exporter *tracing.InMemoryExporter) {
+	t.Helper()
+	if err := tracer.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	spans := exporter.Spans()
+
+	children := make(map[tracing.SpanID][]tracing.SpanData)
+	for _, span := range spans {
+		children[span.ParentSpanID] = append(children[span.ParentSpanID], span)
+	}
+	// descendants retorna los nombres de los spans bajo id, en cualquier nivel
+	var descendants func(id tracing.SpanID, names map[string]int)
+	descendants = func(id tracing.SpanID, names map[string]int) {
+		for _, child := range children[id] {
+			names[child.Name]++
+			descendants(child.SpanID, names)
+		}
+	}
+
+	uploads := 0
+	for _, span := range spans {
+		if span.Name != "POST /upload" || span.Kind != tracing.KindServer || span.Attributes["http.response.status_code"] != 200 {
+			continue
+		}
+		uploads++
+		names := make(map[string]int)
+		descendants(span.SpanID, names)
+		for _, want := range []string{
+			"analysis", "analysis.test_connection", "pdf.extract", "analysis.phase1",
+			"analysis.chunk", "analysis.consolidate", "chat stub", "UPDATE contracts",
+		} {
+			if names[want] == 0 {
+				t.Errorf("POST /upload has no %q span (spans: %v)", want, names)
+			}
+		}
+	}
+	if uploads == 0 {
+		t.Errorf("no POST /upload span was exported (%d spans)", len(spans))
+	}
+}
+
+// expectError comprueba que la llamada falla con un APIError del estado y estilo esperados
+func expectError(t *testing.T, name string, status int, wantProblem bool, call func() error) {
+	t.Helper()
+	var apiErr *client.APIError
+	if err := call(); !errors.As(err, &apiErr) {
+		t.Errorf("%s: want an APIError, got %v", name, err)
+		return
+	}
+	if apiErr.StatusCode != status {
+		t.Errorf("%s: status %d, want %d", name, apiErr.StatusCode, status)
+	}
+	if wantProblem != (apiErr.Problem != nil) {
+		t.Errorf("%s: problem+json = %v, want %v", name, apiErr.Problem != nil, wantProblem)
+	}
+	if apiErr.Error() == "" {
+		t.Errorf("%s: APIError without a message", name)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
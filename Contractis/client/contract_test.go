@@ -0,0 +1,276 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/api"
+)
+
+// exchange es una petición y su respuesta tal como pasaron por la red
+type exchange struct {
+	method      string
+	path        string
+	status      int
+	contentType string
+	body        []byte
+}
+
+// recorder es un http.RoundTripper que guarda cada intercambio
+type recorder struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	exchanges []exchange
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, exchange{
+		method:      req.Method,
+		path:        req.URL.Path,
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        body,
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// contract valida intercambios contra la especificación
+type contract struct {
+	doc *api.Document
+	// succeeded registra las operaciones con al menos una respuesta 2xx validada
+	succeeded map[string]bool
+}
+
+func newContract(doc *api.Document) *contract {
+	return &contract{doc: doc, succeeded: make(map[string]bool)}
+}
+
+// operation busca la operación cuya plantilla de ruta coincide con path
+func (c *contract) operation(method, path string) (string, *api.Operation) {
+	for _, template := range c.doc.Paths.Keys {
+		if !matchPath(template, path) {
+			continue
+		}
+		if op := c.doc.Paths.Values[template].Operation(method); op != nil {
+			return template, op
+		}
+	}
+	return "", nil
+}
+
+// check valida un intercambio y retorna los fallos encontrados
+func (c *contract) check(ex exchange) []string {
+	template, op := c.operation(ex.method, ex.path)
+	if op == nil {
+		return []string{fmt.Sprintf("%s %s: undocumented operation", ex.method, ex.path)}
+	}
+	where := fmt.Sprintf("%s %s (%s) → %d", ex.method, template, op.OperationID, ex.status)
+
+	ref, ok := op.Responses.Values[fmt.Sprint(ex.status)]
+	if !ok {
+		return []string{where + ": undocumented status code"}
+	}
+	response, err := c.doc.ResolveResponse(ref)
+	if err != nil {
+		return []string{where + ": " + err.Error()}
+	}
+
+	if len(response.Content) == 0 {
+		if len(bytes.TrimSpace(ex.body)) > 0 {
+			return []string{where + ": the documented response has no body"}
+		}
+		c.markSucceeded(ex, op)
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ex.contentType)
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return []string{fmt.Sprintf("%s: undocumented Content-Type %q", where, mediaType)}
+	}
+
+	var failures []string
+	if strings.HasSuffix(mediaType, "json") {
+		dec := json.NewDecoder(bytes.NewReader(ex.body))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return []string{fmt.Sprintf("%s: invalid JSON: %v", where, err)}
+		}
+		for _, problem := range c.validate(media.Schema, value, "$") {
+			failures = append(failures, where+": "+problem)
+		}
+	}
+	if len(failures) == 0 {
+		c.markSucceeded(ex, op)
+	}
+	return failures
+}
+
+func (c *contract) markSucceeded(ex exchange, op *api.Operation) {
+	if ex.status >= 200 && ex.status < 300 {
+		c.succeeded[op.OperationID] = true
+	}
+}
+
+// uncovered retorna las operaciones sin ninguna respuesta 2xx validada
+func (c *contract) uncovered() []string {
+	var missing []string
+	for _, template := range c.doc.Paths.Keys {
+		item := c.doc.Paths.Values[template]
+		for _, method := range api.Methods {
+			if op := item.Operation(method); op != nil && !c.succeeded[op.OperationID] {
+				missing = append(missing, fmt.Sprintf("%s %s (%s): no validated 2xx response", method, template, op.OperationID))
+			}
+		}
+	}
+	return missing
+}
+
+// validate comprueba un valor JSON contra el esquema; las propiedades no documentadas son fallos
+func (c *contract) validate(schema *api.Schema, value any, at string) []string {
+	schema, err := c.doc.ResolveSchema(schema)
+	if err != nil {
+		return []string{at + ": " + err.Error()}
+	}
+	if schema.IsAny() {
+		return nil
+	}
+	if value == nil {
+		if schema.Type.Nullable() {
+			return nil
+		}
+		return []string{at + ": null not allowed"}
+	}
+
+	switch schema.Type.Primary() {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{at + ": want an object"}
+		}
+		var failures []string
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				failures = append(failures, fmt.Sprintf("%s: missing required property %q", at, name))
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			propSchema, ok := schema.Properties.Values[key]
+			if !ok {
+				propSchema = schema.AdditionalProperties
+			}
+			if propSchema == nil {
+				failures = append(failures, fmt.Sprintf("%s: undocumented property %q", at, key))
+				continue
+			}
+			failures = append(failures, c.validate(propSchema, object[key], at+"."+key)...)
+		}
+		return failures
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return []string{at + ": want an array"}
+		}
+		var failures []string
+		for i, item := range items {
+			failures = append(failures, c.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return failures
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{at + ": want a string"}
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				return []string{fmt.Sprintf("%s: invalid date-time %q", at, text)}
+			}
+		}
+		return checkEnum(schema, text, at)
+
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return []string{at + ": want an integer"}
+		}
+		if _, err := number.Int64(); err != nil {
+			return []string{fmt.Sprintf("%s: %s is not an integer", at, number)}
+		}
+		return nil
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return []string{at + ": want a number"}
+		}
+		return nil
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{at + ": want a boolean"}
+		}
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: unsupported schema type %v", at, schema.Type)}
+}
+
+func checkEnum(schema *api.Schema, value string, at string) []string {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	for _, allowed := range schema.Enum {
+		if allowed == value {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s: value %q not in the enum", at, value)}
+}
+
+// matchPath compara una ruta con una plantilla OpenAPI como /api/v1/contracts/{id}
+func matchPath(template, path string) bool {
+	templateParts := strings.Split(template, "/")
+	pathParts := strings.Split(path, "/")
+	if len(templateParts) != len(pathParts) {
+		return false
+	}
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
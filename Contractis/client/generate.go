@@ -0,0 +1,3 @@
+package client
+
+//go:generate go run ../cmd/openapi-gen -o client_gen.go
@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/client"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// TestProfilesForAnalysts comprueba que una credencial sin scope admin (como la sesión de
+// la interfaz web) guarda su perfil LLM personal y analiza con él, y que un viewer no puede
+func TestProfilesForAnalysts(t *testing.T) {
+	ctx := context.Background()
+	server := startServer(t)
+
+	// Una sesión de analyst solo tiene los scopes read y analyze
+	user, err := server.workspaces.CreateUser(ctx, "analista@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.workspaces.SetMember(ctx, 1, user.Email, entities.RoleAnalyst); err != nil {
+		t.Fatal(err)
+	}
+	analystKey, _, err := server.auth.CreateAPIKey(ctx, user.ID, "web", []entities.Scope{entities.ScopeRead, entities.ScopeAnalyze})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profileJSON := fmt.Sprintf(`{"name": "web-analista", "type": "local", "localUrl": %q, "modelName": "stub", "maxTokens": 800}`, server.llm.URL)
+	status, body := profileRequest(t, server, analystKey, "POST", "/api/profiles", profileJSON)
+	if status != http.StatusCreated {
+		t.Fatalf("POST /api/profiles as analyst = %d %s, want 201", status, body)
+	}
+	var created struct {
+		Data entities.LLMProfile `json:"data"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Data.OwnerID != user.ID {
+		t.Errorf("profile created by an analyst: owner %d, want %d", created.Data.OwnerID, user.ID)
+	}
+
+	updateJSON := strings.Replace(profileJSON, `"maxTokens": 800`, `"maxTokens": 900`, 1)
+	if status, body := profileRequest(t, server, analystKey, "PUT", fmt.Sprintf("/api/profiles/update?id=%d", created.Data.ID), updateJSON); status != http.StatusOK {
+		t.Errorf("PUT /api/profiles/update as analyst = %d %s, want 200", status, body)
+	}
+
+	analyst := client.New(server.api.URL, client.WithAPIKey(analystKey), client.WithWorkspace(1))
+	analysis, err := analyst.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+		File:      bytes.NewReader(samplePDF("Contrato con perfil personal")),
+		FileName:  "personal.pdf",
+		ProfileID: created.Data.ID,
+	})
+	if err != nil || !analysis.Success {
+		t.Errorf("AnalyzeContract with the own profile = %+v, %v", analysis, err)
+	}
+
+	// El admin del workspace ve el perfil personal, pero no analiza con él
+	admin := client.New(server.api.URL, client.WithAPIKey(server.apiKey), client.WithWorkspace(1))
+	expectError(t, "AnalyzeContract with someone else's personal profile", http.StatusBadRequest, false, func() error {
+		_, err := admin.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+			File:      bytes.NewReader(samplePDF("Contrato del admin")),
+			FileName:  "admin.pdf",
+			ProfileID: created.Data.ID,
+		})
+		return err
+	})
+
+	// Un viewer no guarda perfiles aunque su credencial tenga scope analyze
+	viewerKey := server.addMember(t, 1, "lector@example.com", entities.RoleViewer)
+	if status, body := profileRequest(t, server, viewerKey, "POST", "/api/profiles", profileJSON); status != http.StatusForbidden {
+		t.Errorf("POST /api/profiles as viewer = %d %s, want 403", status, body)
+	}
+}
+
+// profileRequest llama a las rutas de perfiles LLM, que no forman parte del cliente generado
+func profileRequest(t *testing.T, server *testServer, apiKey, method, path, body string) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.api.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("X-Workspace-ID", "1")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	buf.ReadFrom(resp.Body)
+	return resp.StatusCode, buf.Bytes()
+}
@@ -0,0 +1,212 @@
+package client_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/handlers"
+	"github.com/rodascaar/contractis/internal/adapters/http/router"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/auth"
+	"github.com/rodascaar/contractis/internal/infrastructure/database"
+	"github.com/rodascaar/contractis/internal/infrastructure/health"
+	"github.com/rodascaar/contractis/internal/infrastructure/llm"
+	"github.com/rodascaar/contractis/internal/infrastructure/metrics"
+	"github.com/rodascaar/contractis/internal/infrastructure/pdf"
+	"github.com/rodascaar/contractis/internal/infrastructure/secrets"
+	"github.com/rodascaar/contractis/internal/infrastructure/text"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+	"github.com/rodascaar/contractis/internal/infrastructure/webhooks"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// stubAnalysis es el reporte que devuelve el LLM simulado
+const stubAnalysis = "## Resumen\nContrato de prueba sin cláusulas de riesgo."
+
+// testServer es una instancia del router real con sus dependencias temporales
+type testServer struct {
+	api        *httptest.Server
+	llm        *httptest.Server
+	apiKey     string
+	tracer     *tracing.Tracer
+	spans      *tracing.InMemoryExporter
+	workspaces *usecases.WorkspaceUseCase
+	profiles   *usecases.LLMProfilesUseCase
+	auth       *auth.Service
+}
+
+// startServer cablea el router igual que cmd/main.go sobre una base temporal; todo se
+// cierra al terminar la prueba
+func startServer(t *testing.T) *testServer {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	db, err := database.NewSQLiteDB(filepath.Join(dir, "contractis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	masterKey := make([]byte, 32)
+	if _, err := rand.Read(masterKey); err != nil {
+		t.Fatal(err)
+	}
+	secretBox, err := secrets.NewBox(masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kek, err := secrets.NewBox(secrets.DeriveKey(masterKey, "data-keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := secrets.NewEnvelope(ctx, database.NewDataKeyRepository(db), kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenSigner, err := auth.NewTokenSigner(secrets.DeriveKey(masterKey, "session-tokens"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authService := auth.NewService(database.NewAPIKeyRepository(db), tokenSigner)
+	workspaceUseCase := usecases.NewWorkspaceUseCase(database.NewUserRepository(db), database.NewWorkspaceRepository(db))
+
+	limits := entities.DefaultLimits()
+	pdfExtractor := pdf.NewExtractor()
+	llmScheduler := llm.NewScheduler(entities.LocalEndpointSlots, entities.OnlineEndpointSlots)
+	textProcessor := text.NewProcessor()
+	contractRepo := database.NewContractRepository(db, envelope)
+	profilesUseCase := usecases.NewLLMProfilesUseCase(database.NewLLMProfileRepository(db, secretBox))
+	auditUseCase := usecases.NewAuditUseCase(database.NewAuditRepository(db))
+	jobs := usecases.NewJobTracker()
+	appMetrics := metrics.New()
+	db.Observe(appMetrics.ObserveQuery)
+	spans := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(spans)
+	t.Cleanup(func() { tracer.Shutdown(context.Background()) })
+	db.Trace(tracer)
+
+	// El receptor de exerciseWebhooks escucha en 127.0.0.1, como un destino on-premise
+	webhookPolicy := entities.DefaultWebhookPolicy()
+	webhookPolicy.AllowPrivateNetworks = true
+	webhookUseCase := usecases.NewWebhookUseCase(database.NewWebhookRepository(db, secretBox), webhooks.NewSender(webhookPolicy.AllowPrivateNetworks), webhookPolicy)
+	analyzeUseCase := usecases.NewAnalyzeContractUseCase(
+		pdfExtractor,
+		llm.NewClient(llmScheduler, llm.ClientOptions{Observer: appMetrics.ObserveLLMRequest, Tracer: tracer}),
+		contractRepo,
+		textProcessor,
+		auditUseCase,
+		nil,
+		appMetrics,
+		tracer,
+		webhookUseCase,
+	)
+	estimateUseCase := usecases.NewEstimateTokensUseCase(pdfExtractor, textProcessor)
+
+	appRouter := router.NewRouter(
+		handlers.NewUploadHandler(analyzeUseCase, profilesUseCase, workspaceUseCase, jobs, limits),
+		handlers.NewEstimateHandler(estimateUseCase, limits),
+		handlers.NewHistoryHandler(contractRepo, workspaceUseCase, auditUseCase, webhookUseCase),
+		handlers.NewQueueHandler(llmScheduler),
+		handlers.NewProfileHandler(profilesUseCase, workspaceUseCase),
+		handlers.NewAuthHandler(authService),
+		handlers.NewWorkspaceHandler(workspaceUseCase),
+		nil,
+		handlers.NewAuditHandler(auditUseCase),
+		handlers.NewBatchHandler(
+			usecases.NewBatchUseCase(analyzeUseCase, database.NewBatchRepository(db), contractRepo, auditUseCase, jobs, limits),
+			profilesUseCase,
+			workspaceUseCase,
+			limits,
+		),
+		handlers.NewHealthHandler(usecases.NewHealthUseCase(health.Build(""), jobs, 5*time.Second,
+			health.NewDatabaseCheck(db),
+			health.NewStorageCheck("storage", dir),
+		)),
+		handlers.NewWebhookHandler(webhookUseCase, workspaceUseCase),
+		authService,
+		appMetrics,
+		tracer,
+		dir,
+	)
+
+	admin, err := workspaceUseCase.GetUserByEmail(ctx, "admin@localhost")
+	if err != nil {
+		t.Fatalf("default admin user: %v", err)
+	}
+	scopes := []entities.Scope{entities.ScopeRead, entities.ScopeAnalyze, entities.ScopeDelete, entities.ScopeAdmin}
+	apiKey, _, err := authService.CreateAPIKey(ctx, admin.ID, "contract-test", scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiServer := httptest.NewServer(appRouter.Setup())
+	t.Cleanup(apiServer.Close)
+	llmServer := httptest.NewServer(http.HandlerFunc(handleStubLLM))
+	t.Cleanup(llmServer.Close)
+	return &testServer{
+		api:        apiServer,
+		llm:        llmServer,
+		apiKey:     apiKey,
+		tracer:     tracer,
+		spans:      spans,
+		workspaces: workspaceUseCase,
+		profiles:   profilesUseCase,
+		auth:       authService,
+	}
+}
+
+// addMember crea un usuario con el rol dado en el workspace y retorna una API key suya con
+// todos los scopes, para que el acceso lo decida solo el rol
+func (s *testServer) addMember(t *testing.T, workspaceID int64, email string, role entities.Role) string {
+	t.Helper()
+	ctx := context.Background()
+	user, err := s.workspaces.CreateUser(ctx, email, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.workspaces.SetMember(ctx, workspaceID, email, role); err != nil {
+		t.Fatal(err)
+	}
+	scopes := []entities.Scope{entities.ScopeRead, entities.ScopeAnalyze, entities.ScopeDelete, entities.ScopeAdmin}
+	apiKey, _, err := s.auth.CreateAPIKey(ctx, user.ID, email, scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apiKey
+}
+
+// handleStubLLM responde como un endpoint OpenAI compatible, con o sin streaming
+func handleStubLLM(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Stream bool `json:"stream"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	if !req.Stream {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": stubAnalysis}},
+			},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, token := range []string{"## Resumen\n", "Contrato de prueba ", "sin cláusulas de riesgo."} {
+		chunk, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"delta": map[string]string{"content": token}}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
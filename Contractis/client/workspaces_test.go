@@ -0,0 +1,131 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/rodascaar/contractis/client"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// TestWorkspaceIsolation comprueba que un workspace no ve ni modifica los contratos de
+// otro ni usa sus perfiles LLM, que el rol viewer no puede eliminar y que X-Workspace-ID no da acceso a un
+// workspace del que el usuario no es miembro
+func TestWorkspaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	server := startServer(t)
+
+	legal := client.New(server.api.URL, client.WithAPIKey(server.apiKey), client.WithWorkspace(1))
+	analysis, err := legal.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+		File:      bytes.NewReader(samplePDF("Contrato reservado", "Clausula primera: confidencialidad.")),
+		FileName:  "reservado.pdf",
+		LLMConfig: &client.LLMConfigRequest{Type: "local", LocalURL: server.llm.URL, ModelName: "stub", MaxTokens: 800},
+	})
+	if err != nil || !analysis.Success {
+		t.Fatalf("AnalyzeContract: %+v, %v", analysis, err)
+	}
+	list, err := legal.ListContracts(ctx, nil)
+	if err != nil || len(list.Data) != 1 {
+		t.Fatalf("ListContracts: %+v, %v", list, err)
+	}
+	id := list.Data[0].ID
+
+	// Workspace B: un admin de otro equipo no ve el contrato de A
+	other, err := server.workspaces.CreateWorkspace(ctx, "Fiscal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fiscal := client.New(server.api.URL, client.WithAPIKey(server.addMember(t, other.ID, "fiscal@example.com", entities.RoleAdmin)), client.WithWorkspace(other.ID))
+
+	if list, err := fiscal.ListContracts(ctx, nil); err != nil || len(list.Data) != 0 {
+		t.Errorf("ListContracts from workspace B = %+v, %v, want no contracts", list, err)
+	}
+	if page, err := fiscal.ListContractsV1(ctx, nil); err != nil || len(page.Data) != 0 {
+		t.Errorf("ListContractsV1 from workspace B = %+v, %v, want no contracts", page, err)
+	}
+	if result, err := fiscal.SearchContracts(ctx, "reservado", nil); err != nil || len(result.Data) != 0 {
+		t.Errorf("SearchContracts from workspace B = %+v, %v, want no results", result, err)
+	}
+	expectError(t, "GetContract from workspace B", http.StatusNotFound, false, func() error {
+		_, err := fiscal.GetContract(ctx, id)
+		return err
+	})
+	expectError(t, "ExportContract from workspace B", http.StatusNotFound, false, func() error {
+		_, err := fiscal.ExportContract(ctx, id)
+		return err
+	})
+	expectError(t, "DeleteContract from workspace B", http.StatusNotFound, false, func() error {
+		_, err := fiscal.DeleteContract(ctx, id)
+		return err
+	})
+	expectError(t, "GetContractV1 from workspace B", http.StatusNotFound, true, func() error {
+		_, err := fiscal.GetContractV1(ctx, id)
+		return err
+	})
+	expectError(t, "ExportContractV1 from workspace B", http.StatusNotFound, true, func() error {
+		_, err := fiscal.ExportContractV1(ctx, id)
+		return err
+	})
+	expectError(t, "DeleteContractV1 from workspace B", http.StatusNotFound, true, func() error {
+		return fiscal.DeleteContractV1(ctx, id)
+	})
+
+	// Tampoco puede analizar con el perfil LLM (y la API key) de A
+	admin, err := server.workspaces.GetUserByEmail(ctx, "admin@localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := server.profiles.Create(ctx, &entities.Membership{WorkspaceID: 1, UserID: admin.ID, Role: entities.RoleAdmin}, &entities.LLMProfile{
+		Name: "legal", Type: "local", LocalUrl: server.llm.URL, ModelName: "stub", MaxTokens: 800,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectError(t, "AnalyzeContract with workspace A's profile", http.StatusBadRequest, false, func() error {
+		_, err := fiscal.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+			File:      bytes.NewReader(samplePDF("Contrato fiscal")),
+			FileName:  "fiscal.pdf",
+			ProfileID: profile.ID,
+		})
+		return err
+	})
+
+	// Un usuario que no es miembro no entra a A eligiendo su ID en X-Workspace-ID
+	intruder := client.New(server.api.URL, client.WithAPIKey(server.addMember(t, other.ID, "intruso@example.com", entities.RoleAdmin)), client.WithWorkspace(1))
+	expectError(t, "ListContracts with a foreign X-Workspace-ID", http.StatusNotFound, false, func() error {
+		_, err := intruder.ListContracts(ctx, nil)
+		return err
+	})
+	expectError(t, "GetContractV1 with a foreign X-Workspace-ID", http.StatusNotFound, true, func() error {
+		_, err := intruder.GetContractV1(ctx, id)
+		return err
+	})
+	expectError(t, "AnalyzeContract with a foreign X-Workspace-ID", http.StatusNotFound, false, func() error {
+		_, err := intruder.AnalyzeContract(ctx, &client.AnalyzeContractForm{
+			File:      bytes.NewReader(samplePDF("Contrato ajeno")),
+			FileName:  "ajeno.pdf",
+			LLMConfig: &client.LLMConfigRequest{Type: "local", LocalURL: server.llm.URL, ModelName: "stub", MaxTokens: 800},
+		})
+		return err
+	})
+
+	// Un viewer de A lee el contrato pero no puede eliminarlo, aunque su key tenga scope delete
+	viewer := client.New(server.api.URL, client.WithAPIKey(server.addMember(t, 1, "lectura@example.com", entities.RoleViewer)), client.WithWorkspace(1))
+	if _, err := viewer.GetContract(ctx, id); err != nil {
+		t.Errorf("GetContract as viewer: %v", err)
+	}
+	expectError(t, "DeleteContract as viewer", http.StatusForbidden, false, func() error {
+		_, err := viewer.DeleteContract(ctx, id)
+		return err
+	})
+	expectError(t, "DeleteContractV1 as viewer", http.StatusForbidden, true, func() error {
+		return viewer.DeleteContractV1(ctx, id)
+	})
+
+	// El contrato sigue intacto en A
+	if contract, err := legal.GetContract(ctx, id); err != nil || contract.Data.Filename != "reservado.pdf" {
+		t.Errorf("GetContract from workspace A after the attempts = %+v, %v", contract, err)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/dto"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/utils"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+const analyzeUsage = `Uso:
+  contractis analyze [opciones] ARCHIVO.pdf
+
+Opciones LLM (archivo -llm-config < opciones llm.* y variables CONTRACTIS_LLM_* < flags):
+  -profile ID | -llm-config archivo.json | -type local|online -url URL -api-key KEY -model M -max-tokens N`
+
+const estimateUsage = `Uso:
+  contractis estimate [-max-tokens N] [-output text|json] ARCHIVO.pdf`
+
+// analysisOutput es la salida JSON de contractis analyze
+type analysisOutput struct {
+	Success         bool    `json:"success"`
+	ContractID      string  `json:"contract_id,omitempty"`
+	Filename        string  `json:"filename"`
+	WorkspaceID     int64   `json:"workspace_id"`
+	Data            string  `json:"data,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Chunks          int     `json:"chunks"`
+}
+
+// runAnalyzeCommand analiza un PDF con AnalyzeContractUseCase, sin pasar por el servidor HTTP
+func runAnalyzeCommand(
+	ctx context.Context,
+	open func() *app,
+	limits entities.Limits,
+	llmDefaults config.LLMConfig,
+	args []string,
+) int {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, analyzeUsage)
+		fs.PrintDefaults()
+	}
+	opts := registerCLIFlags(fs)
+	llmOpts := registerLLMFlags(fs, llmDefaults)
+	stream := fs.Bool("stream", false, "con -output text, escribe el reporte a medida que llega")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	pdfPath := fs.Arg(0)
+	info, err := checkPDF(pdfPath, limits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	a := open()
+	ctx, membership, err := opts.authorize(ctx, a.workspaces, entities.RoleAnalyst)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	llmConfig, err := resolveCLIConfig(ctx, llmOpts, a.profiles, membership)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	fileHash, err := utils.CalculateFileHash(pdfPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Error calculando hash: %v\n", err)
+		fileHash = ""
+	}
+
+	// Mismos límites de tiempo que el servidor; Ctrl+C cancela el análisis
+	ctx, cancel := context.WithTimeout(ctx, limits.AnalysisTimeoutFor(llmConfig))
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	streamed := *stream && !opts.json()
+	if streamed {
+		ctx = entities.WithTokenSink(ctx, func(token string) {
+			fmt.Print(token)
+		})
+	}
+
+	filename := filepath.Base(pdfPath)
+	result, err := a.analyze.Execute(ctx, membership.WorkspaceID, pdfPath, filename, fileHash, info.Size(), llmConfig)
+	if err != nil {
+		if opts.json() {
+			printJSON(analysisOutput{Filename: filename, WorkspaceID: membership.WorkspaceID, Error: err.Error()})
+		} else {
+			if streamed {
+				fmt.Println()
+			}
+			fmt.Fprintf(os.Stderr, "❌ Error al analizar: %v\n", err)
+		}
+		return 1
+	}
+
+	if opts.json() {
+		printJSON(analysisOutput{
+			Success:         result.Success,
+			ContractID:      result.ContractID,
+			Filename:        filename,
+			WorkspaceID:     membership.WorkspaceID,
+			Data:            result.Content,
+			DurationSeconds: result.DurationSec,
+			Chunks:          result.ChunksCount,
+		})
+		return 0
+	}
+
+	if streamed {
+		fmt.Println()
+	} else {
+		fmt.Println(result.Content)
+	}
+	return 0
+}
+
+// runEstimateCommand estima los tokens de un PDF con EstimateTokensUseCase
+func runEstimateCommand(estimateUseCase *usecases.EstimateTokensUseCase, limits entities.Limits, args []string) int {
+	fs := flag.NewFlagSet("estimate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, estimateUsage)
+		fs.PrintDefaults()
+	}
+	output := fs.String("output", "text", "formato de salida: text o json")
+	maxTokens := fs.Int("max-tokens", 800, "tokens de salida por petición")
+	quiet := fs.Bool("quiet", false, "oculta los logs en stderr")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	opts := &cliOptions{output: output, quiet: quiet}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	pdfPath := fs.Arg(0)
+	if _, err := checkPDF(pdfPath, limits); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	estimation, err := estimateUseCase.Execute(pdfPath, *maxTokens)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error al estimar tokens: %v\n", err)
+		return 1
+	}
+
+	if opts.json() {
+		printJSON(dto.TokenEstimationResponse{
+			Success:              estimation.Success,
+			CharacterCount:       estimation.CharacterCount,
+			EstimatedTokens:      estimation.EstimatedTokens,
+			Chunks:               estimation.Chunks,
+			SystemPromptTokens:   estimation.SystemPromptTokens,
+			Phase1Tokens:         estimation.Phase1Tokens,
+			Phase2InputTokens:    estimation.Phase2InputTokens,
+			Phase2OutputTokens:   estimation.Phase2OutputTokens,
+			TotalTokens:          estimation.TotalTokens,
+			RecommendedMaxTokens: estimation.RecommendedMaxTokens,
+			Warning:              estimation.Warning,
+			Error:                estimation.Error,
+		})
+	} else if estimation.Success {
+		fmt.Printf("Caracteres:            %d\n", estimation.CharacterCount)
+		fmt.Printf("Tokens del documento:  %d\n", estimation.EstimatedTokens)
+		fmt.Printf("Fragmentos:            %d\n", estimation.Chunks)
+		fmt.Printf("Fase 1:                %d tokens\n", estimation.Phase1Tokens)
+		fmt.Printf("Fase 2:                %d entrada + %d salida\n", estimation.Phase2InputTokens, estimation.Phase2OutputTokens)
+		fmt.Printf("Total:                 %d tokens\n", estimation.TotalTokens)
+		fmt.Printf("max-tokens recomendado: %d\n", estimation.RecommendedMaxTokens)
+		if estimation.Warning != "" {
+			fmt.Printf("⚠️  %s\n", estimation.Warning)
+		}
+	}
+
+	if !estimation.Success {
+		if !opts.json() {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", estimation.Error)
+		}
+		return 1
+	}
+	return 0
+}
+
+// resolveCLIConfig obtiene la configuración LLM: un perfil guardado que el miembro pueda
+// usar o archivo/configuración/flags
+func resolveCLIConfig(ctx context.Context, llmOpts *llmFlags, profilesUseCase *usecases.LLMProfilesUseCase, membership *entities.Membership) (*entities.LLMConfig, error) {
+	if profileID := llmOpts.profile(); profileID > 0 {
+		config, err := profilesUseCase.ResolveConfig(ctx, membership, profileID)
+		if err != nil {
+			return nil, fmt.Errorf("perfil LLM no utilizable: %w", err)
+		}
+		return config, nil
+	}
+
+	req, err := llmOpts.request()
+	if err != nil {
+		return nil, err
+	}
+	config := req.ToEntity()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("configuración LLM inválida: %w", err)
+	}
+	return config, nil
+}
+
+// checkPDF aplica al archivo las mismas validaciones que la subida por HTTP
+func checkPDF(path string, limits entities.Limits) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s es un directorio", path)
+	}
+	if info.Size() > limits.MaxFileSize {
+		return nil, fmt.Errorf("archivo demasiado grande (máximo %dMB)", limits.FileSizeMB())
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".pdf") {
+		return nil, fmt.Errorf("solo se permiten archivos PDF")
+	}
+	return info, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/infrastructure/auth"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/database"
+	"github.com/rodascaar/contractis/internal/infrastructure/llm"
+	"github.com/rodascaar/contractis/internal/infrastructure/metrics"
+	"github.com/rodascaar/contractis/internal/infrastructure/pdf"
+	"github.com/rodascaar/contractis/internal/infrastructure/secrets"
+	"github.com/rodascaar/contractis/internal/infrastructure/text"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+	"github.com/rodascaar/contractis/internal/infrastructure/webhooks"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// app agrupa la base de datos y los componentes que dependen de ella, compartidos por
+// el servidor y los subcomandos
+type app struct {
+	db          *database.DB
+	metrics     *metrics.Metrics
+	tracer      *tracing.Tracer
+	masterKey   []byte
+	secretBox   *secrets.Box
+	dataKeyRepo repositories.DataKeyRepository
+	envelope    *secrets.Envelope
+
+	authService   *auth.Service
+	userRepo      repositories.UserRepository
+	workspaceRepo repositories.WorkspaceRepository
+	llmScheduler  *llm.Scheduler
+	contractRepo  repositories.ContractRepository
+	profileRepo   repositories.LLMProfileRepository
+
+	workspaces *usecases.WorkspaceUseCase
+	audit      *usecases.AuditUseCase
+	webhooks   *usecases.WebhookUseCase
+	analyze    *usecases.AnalyzeContractUseCase
+	profiles   *usecases.LLMProfilesUseCase
+	jobs       *usecases.JobTracker
+	batches    *usecases.BatchUseCase
+}
+
+// openApp abre (y migra) la base de datos y arma los componentes; ante un error termina
+// el proceso. Los subcomandos la llaman después de validar sus argumentos, para que
+// -h o un uso incorrecto no creen la base.
+func openApp(cfg *config.Config) *app {
+	a := &app{}
+	limits := cfg.EntityLimits()
+
+	// Database initialization
+	db, err := database.NewSQLiteDB(cfg.Database.Path)
+	if err != nil {
+		fatal("error inicializando base de datos", err)
+	}
+	a.db = db
+
+	// Métricas de Prometheus (HTTP, LLM, análisis, cola y base de datos), servidas en /metrics
+	a.metrics = metrics.New()
+	db.Observe(a.metrics.ObserveQuery)
+
+	// Trazas de OpenTelemetry por OTLP/HTTP, solo si hay un collector configurado
+	a.tracer, err = tracerFromConfig(cfg.Tracing)
+	if err != nil {
+		fatal("configuración de trazas inválida", err)
+	}
+	db.Trace(a.tracer)
+
+	// Clave maestra para cifrar las API keys de los perfiles LLM y envolver las claves de datos.
+	// Durante una rotación, CONTRACTIS_MASTER_KEY_PREVIOUS permite abrir lo sellado con la anterior.
+	a.masterKey, err = secrets.LoadMasterKey("CONTRACTIS_MASTER_KEY", cfg.MasterKeyPath())
+	if err != nil {
+		fatal("error cargando clave maestra", err)
+	}
+	previousKeys, err := secrets.LoadPreviousMasterKeys("CONTRACTIS_MASTER_KEY_PREVIOUS")
+	if err != nil {
+		fatal("error cargando claves maestras anteriores", err)
+	}
+	a.secretBox, err = secrets.NewBox(a.masterKey, previousKeys...)
+	if err != nil {
+		fatal("clave maestra inválida", err)
+	}
+
+	// Cifrado en reposo: claves de datos envueltas con una subclave de la maestra
+	previousKEKs := make([][]byte, len(previousKeys))
+	for i, key := range previousKeys {
+		previousKEKs[i] = secrets.DeriveKey(key, "data-keys")
+	}
+	kek, err := secrets.NewBox(secrets.DeriveKey(a.masterKey, "data-keys"), previousKEKs...)
+	if err != nil {
+		fatal("error inicializando cifrado en reposo", err)
+	}
+	a.dataKeyRepo = database.NewDataKeyRepository(db)
+	a.envelope, err = secrets.NewEnvelope(context.Background(), a.dataKeyRepo, kek)
+	if err != nil {
+		fatal("error cargando claves de datos (¿falta CONTRACTIS_MASTER_KEY_PREVIOUS tras rotar la clave maestra?)", err)
+	}
+	slog.Info("cifrado en reposo activo", "data_key_id", a.envelope.ActiveKeyID())
+
+	// Autenticación: API keys con hash y tokens de sesión firmados con una subclave de la maestra
+	tokenSigner, err := auth.NewTokenSigner(secrets.DeriveKey(a.masterKey, "session-tokens"))
+	if err != nil {
+		fatal("error inicializando firma de sesiones", err)
+	}
+	a.authService = auth.NewService(database.NewAPIKeyRepository(db), tokenSigner)
+	a.userRepo = database.NewUserRepository(db)
+	a.workspaceRepo = database.NewWorkspaceRepository(db)
+	a.workspaces = usecases.NewWorkspaceUseCase(a.userRepo, a.workspaceRepo)
+
+	// Infrastructure layer
+	a.llmScheduler = llm.NewScheduler(cfg.LLM.LocalSlots, cfg.LLM.OnlineSlots)
+	llmClient := llm.NewClient(a.llmScheduler, llm.ClientOptions{
+		LocalTimeout:  cfg.LLM.LocalTimeout,
+		OnlineTimeout: cfg.LLM.OnlineTimeout,
+		MaxRetries:    cfg.LLM.MaxRetries,
+		Observer:      a.metrics.ObserveLLMRequest,
+		Tracer:        a.tracer,
+	})
+	a.metrics.WatchQueue(func() []entities.QueueStatus { return a.llmScheduler.QueueStatus("") })
+	a.contractRepo = database.NewContractRepository(db, a.envelope)
+	a.profileRepo = database.NewLLMProfileRepository(db, a.secretBox)
+	a.audit = usecases.NewAuditUseCase(database.NewAuditRepository(db))
+
+	// Redacción de datos personales antes de enviar texto a LLMs online
+	redactor, err := redactorFromConfig(cfg.Redaction)
+	if err != nil {
+		fatal("configuración de redacción inválida", err)
+	}
+	if redactor == nil {
+		slog.Warn("redacción de datos personales desactivada (redaction.enabled=false)")
+	}
+
+	// Webhooks: los eventos se registran como entregas pendientes y el servidor las envía
+	a.webhooks = usecases.NewWebhookUseCase(database.NewWebhookRepository(db, a.secretBox), webhooks.NewSender(cfg.Webhooks.AllowPrivateNetworks), cfg.WebhookPolicy())
+
+	// Use cases layer
+	a.analyze = usecases.NewAnalyzeContractUseCase(
+		pdf.NewExtractor(),
+		llmClient,
+		a.contractRepo,
+		text.NewProcessor(),
+		a.audit,
+		redactor,
+		a.metrics,
+		a.tracer,
+		a.webhooks,
+	)
+	a.profiles = usecases.NewLLMProfilesUseCase(a.profileRepo)
+
+	// Análisis en curso del servidor (subidas, lotes, carpeta vigilada), para detenerlo sin cortarlos
+	a.jobs = usecases.NewJobTracker()
+	a.batches = usecases.NewBatchUseCase(a.analyze, database.NewBatchRepository(db), a.contractRepo, a.audit, a.jobs, limits)
+	return a
+}
+
+// close exporta las trazas pendientes y cierra la base de datos
+func (a *app) close() {
+	shutdownTracer(a.tracer)
+	if err := a.db.Close(); err != nil {
+		slog.Error("error cerrando la base de datos", "error", err)
+	}
+}
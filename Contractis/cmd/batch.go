@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/ingest"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+const batchUsage = `Uso:
+  contractis batch [opciones] CARPETA
+
+Analiza los PDF de la carpeta (recursivamente), sin repetir los ya analizados, y escribe
+un reporte NOMBRE.analisis.txt junto a cada uno o en -output-dir. Con -watch sigue
+revisando la carpeta y analiza los archivos nuevos hasta Ctrl+C.`
+
+// batchTimeout limita una pasada completa; cada análisis sigue sujeto al timeout del LLM
+const batchTimeout = 12 * time.Hour
+
+// runBatchCommand analiza una carpeta completa con IngestUseCase
+func runBatchCommand(
+	ctx context.Context,
+	open func() *app,
+	limits entities.Limits,
+	llmDefaults config.LLMConfig,
+	args []string,
+) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, batchUsage)
+		fs.PrintDefaults()
+	}
+	opts := registerCLIFlags(fs)
+	llmOpts := registerLLMFlags(fs, llmDefaults)
+	include := fs.String("include", strings.Join(ingest.DefaultInclude, ","), "patrones glob a incluir, separados por coma (\"**\" abarca subcarpetas)")
+	exclude := fs.String("exclude", "", "patrones glob a excluir, separados por coma")
+	outputDir := fs.String("output-dir", "", "carpeta de reportes (por defecto, junto a cada PDF)")
+	concurrency := fs.Int("concurrency", 2, "análisis simultáneos")
+	force := fs.Bool("force", false, "vuelve a analizar documentos ya analizados")
+	watch := fs.Bool("watch", false, "sigue revisando la carpeta y analiza los archivos nuevos")
+	interval := fs.Duration("interval", entities.DefaultIngestInterval, "con -watch, frecuencia de revisión")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	a := open()
+	scanner, ingestUseCase, err := newIngester(a.analyze, a.contractRepo, limits, fs.Arg(0), config.SplitPatterns(*include), config.SplitPatterns(*exclude), *outputDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	ctx, membership, err := opts.authorize(ctx, a.workspaces, entities.RoleAnalyst)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	llmConfig, err := resolveCLIConfig(ctx, llmOpts, a.profiles, membership)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	ingestOpts := entities.IngestOptions{
+		WorkspaceID: membership.WorkspaceID,
+		Concurrency: *concurrency,
+		Force:       *force,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	if *watch {
+		slog.Info("vigilando carpeta (Ctrl+C para terminar)", "dir", scanner.Root(), "interval", *interval)
+		watcher := ingest.NewWatcher(scanner, *interval)
+		err := watcher.Run(ctx, func(ctx context.Context, docs []entities.IngestDocument) {
+			printIngestSummary(opts, ingestUseCase.Process(ctx, docs, llmConfig, ingestOpts))
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, batchTimeout)
+	defer cancel()
+
+	docs, err := scanner.Scan(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error recorriendo %s: %v\n", scanner.Root(), err)
+		return 1
+	}
+	summary := ingestUseCase.Process(ctx, docs, llmConfig, ingestOpts)
+	printIngestSummary(opts, summary)
+	if summary.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newIngester arma el scanner de la carpeta y el caso de uso con sus reportes
+func newIngester(
+	analyzeUseCase *usecases.AnalyzeContractUseCase,
+	contractRepo repositories.ContractRepository,
+	limits entities.Limits,
+	root string,
+	include, exclude []string,
+	outputDir string,
+) (*ingest.Scanner, *usecases.IngestUseCase, error) {
+	scanner, err := ingest.NewScanner(root, include, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	if outputDir != "" {
+		if outputDir, err = filepath.Abs(outputDir); err != nil {
+			return nil, nil, err
+		}
+		// Si la carpeta de reportes está dentro de la de entrada no se vuelve a escanear
+		scanner.SkipDir(outputDir)
+	}
+	reports := ingest.NewReportWriter(outputDir)
+	return scanner, usecases.NewIngestUseCase(analyzeUseCase, contractRepo, reports, limits), nil
+}
+
+// startWatch vigila watch.dir mientras corre el servidor, en nombre de watch.user y con el
+// LLM de las opciones llm.* como en la CLI (ver config.WatchConfig)
+func startWatch(
+	ctx context.Context,
+	cfg config.WatchConfig,
+	llmDefaults config.LLMConfig,
+	analyzeUseCase *usecases.AnalyzeContractUseCase,
+	profilesUseCase *usecases.LLMProfilesUseCase,
+	workspaces *usecases.WorkspaceUseCase,
+	contractRepo repositories.ContractRepository,
+	jobs *usecases.JobTracker,
+	limits entities.Limits,
+) error {
+	if cfg.Dir == "" {
+		return nil
+	}
+
+	scanner, ingestUseCase, err := newIngester(analyzeUseCase, contractRepo, limits, cfg.Dir,
+		config.SplitPatterns(cfg.Include), config.SplitPatterns(cfg.Exclude), cfg.Output)
+	if err != nil {
+		return err
+	}
+
+	user, workspaceID := cfg.User, int64(cfg.Workspace)
+	opts := &cliOptions{user: &user, workspace: &workspaceID}
+	ctx, membership, err := opts.authorize(ctx, workspaces, entities.RoleAnalyst)
+	if err != nil {
+		return err
+	}
+
+	// Sin flags: solo las opciones llm.* (archivo, perfil o valores sueltos)
+	llmConfig, err := resolveCLIConfig(ctx, registerLLMFlags(flag.NewFlagSet("watch", flag.ContinueOnError), llmDefaults), profilesUseCase, membership)
+	if err != nil {
+		return err
+	}
+
+	ingestOpts := entities.IngestOptions{WorkspaceID: membership.WorkspaceID, Concurrency: 1}
+	watcher := ingest.NewWatcher(scanner, cfg.Interval)
+	go watcher.Run(ctx, func(ctx context.Context, docs []entities.IngestDocument) {
+		// Cada pasada es un trabajo: al detenerse el servidor se espera a que termine
+		jobCtx, done, err := jobs.Begin(ctx)
+		if err != nil {
+			return
+		}
+		defer done()
+		summary := ingestUseCase.Process(jobCtx, docs, llmConfig, ingestOpts)
+		slog.InfoContext(jobCtx, "ingesta",
+			"dir", scanner.Root(), "analyzed", summary.Analyzed, "skipped", summary.Skipped, "duplicates", summary.Duplicates, "failed", summary.Failed)
+	})
+	slog.Info("vigilando carpeta", "dir", scanner.Root(), "interval", cfg.Interval, "workspace_id", membership.WorkspaceID)
+	return nil
+}
+
+// printIngestSummary muestra el resultado de una pasada en el formato elegido
+func printIngestSummary(opts *cliOptions, summary *entities.IngestSummary) {
+	if opts.json() {
+		printJSON(summary)
+		return
+	}
+
+	icons := map[entities.IngestStatus]string{
+		entities.IngestAnalyzed:  "✅",
+		entities.IngestSkipped:   "⏭️ ",
+		entities.IngestDuplicate: "🔁",
+		entities.IngestFailed:    "❌",
+	}
+	for _, result := range summary.Results {
+		line := fmt.Sprintf("%s %-9s %s", icons[result.Status], result.Status, result.Path)
+		if result.Report != "" {
+			line += " → " + result.Report
+		}
+		if result.Error != "" {
+			line += ": " + result.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("Total: %d analizados, %d ya analizados, %d duplicados, %d con error\n",
+		summary.Analyzed, summary.Skipped, summary.Duplicates, summary.Failed)
+}
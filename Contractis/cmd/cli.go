@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// cliOptions son las opciones comunes de los subcomandos analyze, estimate, batch e history
+type cliOptions struct {
+	output    *string
+	user      *string
+	workspace *int64
+	quiet     *bool
+}
+
+// registerCLIFlags agrega las opciones comunes al FlagSet
+func registerCLIFlags(fs *flag.FlagSet) *cliOptions {
+	return &cliOptions{
+		output:    fs.String("output", "text", "formato de salida: text o json"),
+		user:      fs.String("user", "admin@localhost", "usuario en cuyo nombre se opera"),
+		workspace: fs.Int64("workspace", 0, "ID del workspace (por defecto, el primero del usuario)"),
+		quiet:     fs.Bool("quiet", false, "oculta los logs en stderr"),
+	}
+}
+
+// validate comprueba el formato de salida y aplica -quiet
+func (o *cliOptions) validate() error {
+	if *o.output != "text" && *o.output != "json" {
+		return fmt.Errorf("-output must be text or json")
+	}
+	if *o.quiet {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+	}
+	return nil
+}
+
+func (o *cliOptions) json() bool {
+	return *o.output == "json"
+}
+
+// authorize resuelve el usuario y su workspace con el rol requerido y retorna un
+// contexto con su identidad, para que la auditoría atribuya las acciones a la CLI
+func (o *cliOptions) authorize(ctx context.Context, workspaces *usecases.WorkspaceUseCase, required entities.Role) (context.Context, *entities.Membership, error) {
+	user, err := workspaces.GetUserByEmail(ctx, *o.user)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("usuario %s: %w", *o.user, err)
+	}
+
+	principal := &entities.Principal{
+		Subject: "cli:" + user.Email,
+		UserID:  user.ID,
+		Scopes:  []entities.Scope{entities.ScopeAdmin},
+	}
+	membership, err := workspaces.Authorize(ctx, principal, *o.workspace, required)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	ctx = entities.WithPrincipal(ctx, principal)
+	ctx = entities.WithRequester(ctx, principal.Requester())
+	return ctx, membership, nil
+}
+
+// quietRequested detecta -quiet en los subcomandos de la CLI antes de abrir la base,
+// para que tampoco se muestren los logs de arranque
+func quietRequested(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "analyze", "estimate", "batch", "history":
+	default:
+		return false
+	}
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		if arg == "-quiet" || arg == "--quiet" || arg == "-quiet=true" || arg == "--quiet=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// printJSON escribe value en stdout como JSON indentado
+func printJSON(value any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rodascaar/contractis/internal/infrastructure/database"
+	"github.com/rodascaar/contractis/internal/infrastructure/secrets"
+)
+
+const encryptionUsage = `Uso:
+  contractis encryption status   claves de datos y valores cifrados con cada una
+  contractis encryption rotate   genera una clave de datos nueva y vuelve a cifrar los datos
+  contractis encryption reseal   cifra con las claves actuales lo que esté en texto plano o con claves anteriores`
+
+// runEncryptionCommand gestiona el cifrado en reposo desde la línea de comandos y retorna el código de salida
+func runEncryptionCommand(
+	ctx context.Context,
+	open func() *app,
+	args []string,
+) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, encryptionUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "status":
+		a := open()
+		keys, err := a.dataKeyRepo.List(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error listando claves de datos: %v\n", err)
+			return 1
+		}
+
+		counts := make(map[int64]int)
+		plaintext := 0
+		err = database.ForEachSealedValue(ctx, a.db, func(stored string) {
+			if id, ok := a.envelope.KeyID(stored); ok {
+				counts[id]++
+			} else {
+				plaintext++
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tESTADO\tCREADA\tRETIRADA\tVALORES")
+		for _, key := range keys {
+			status, retired := "retirada", "-"
+			if key.Active {
+				status = "activa"
+			}
+			if key.RetiredAt != nil {
+				retired = key.RetiredAt.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\n", key.ID, status, key.CreatedAt.Format("2006-01-02 15:04"), retired, counts[key.ID])
+		}
+		tw.Flush()
+
+		if plaintext > 0 {
+			fmt.Printf("⚠️  %d valores en texto plano: ejecuta 'contractis encryption reseal'\n", plaintext)
+		}
+		return 0
+
+	case "rotate":
+		a := open()
+		id, err := a.envelope.Rotate(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error rotando la clave de datos: %v\n", err)
+			return 1
+		}
+		fmt.Printf("🔑 Clave de datos %d activa\n", id)
+		return resealAll(ctx, a.db, a.envelope, a.secretBox)
+
+	case "reseal":
+		a := open()
+		return resealAll(ctx, a.db, a.envelope, a.secretBox)
+
+	default:
+		fmt.Fprintln(os.Stderr, encryptionUsage)
+		return 2
+	}
+}
+
+// resealAll vuelve a cifrar los contratos con la clave de datos activa, y las API keys
+// de los perfiles y los secretos de los webhooks con la clave maestra actual
+func resealAll(ctx context.Context, db *database.DB, envelope *secrets.Envelope, secretBox *secrets.Box) int {
+	contracts, err := database.ResealContracts(ctx, db, envelope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error cifrando contratos (%d actualizados): %v\n", contracts, err)
+		return 1
+	}
+	profiles, err := database.ResealProfiles(ctx, db, secretBox)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error cifrando perfiles LLM (%d actualizados): %v\n", profiles, err)
+		return 1
+	}
+	webhooks, err := database.ResealWebhooks(ctx, db, secretBox)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error cifrando webhooks (%d actualizados): %v\n", webhooks, err)
+		return 1
+	}
+	fmt.Printf("✅ %d contratos, %d perfiles LLM y %d webhooks cifrados de nuevo\n", contracts, profiles, webhooks)
+	return 0
+}
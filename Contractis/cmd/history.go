@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+const historyUsage = `Uso:
+  contractis history list [-limit N] [-offset N] [-q TEXTO] [-output text|json]
+  contractis history show [-output text|json] ID
+  contractis history delete ID
+
+Opciones comunes: -user EMAIL -workspace ID`
+
+// runHistoryCommand consulta y gestiona el historial de contratos desde la línea de comandos
+func runHistoryCommand(
+	ctx context.Context,
+	open func() *app,
+	args []string,
+) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, historyUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("history "+args[0], flag.ContinueOnError)
+	opts := registerCLIFlags(fs)
+
+	switch args[0] {
+	case "list":
+		limit := fs.Int("limit", 20, "cantidad máxima de contratos")
+		offset := fs.Int("offset", 0, "contratos a saltear")
+		query := fs.String("q", "", "filtra por nombre de archivo")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if err := opts.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 2
+		}
+
+		a := open()
+		ctx, membership, err := opts.authorize(ctx, a.workspaces, entities.RoleViewer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+
+		var contracts []*entities.ContractRecord
+		if *query != "" {
+			contracts, err = a.contractRepo.Search(ctx, membership.WorkspaceID, *query, *limit, *offset)
+		} else {
+			contracts, err = a.contractRepo.List(ctx, membership.WorkspaceID, *limit, *offset)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error obteniendo historial: %v\n", err)
+			return 1
+		}
+
+		if opts.json() {
+			if contracts == nil {
+				contracts = []*entities.ContractRecord{}
+			}
+			printJSON(contracts)
+			return 0
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tARCHIVO\tESTADO\tMODELO\tANALIZADO\tDURACIÓN")
+		for _, contract := range contracts {
+			analyzed := "-"
+			if contract.AnalyzedAt != nil {
+				analyzed = contract.AnalyzedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%.1fs\n",
+				contract.ID, contract.Filename, contract.Status, contract.LLMModel, analyzed, contract.ProcessingTimeSeconds)
+		}
+		tw.Flush()
+		return 0
+
+	case "show":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		id, ok := contractIDArg(fs)
+		if !ok {
+			return 2
+		}
+		if err := opts.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 2
+		}
+
+		a := open()
+		ctx, membership, err := opts.authorize(ctx, a.workspaces, entities.RoleViewer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+
+		contract, err := a.contractRepo.GetByID(ctx, membership.WorkspaceID, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Contrato %d no encontrado: %v\n", id, err)
+			return 1
+		}
+		a.audit.Record(ctx, entities.AuditView, membership.WorkspaceID, contract.ID, contract.Filename)
+
+		if opts.json() {
+			printJSON(contract)
+			return 0
+		}
+
+		fmt.Printf("Archivo: %s\nEstado: %s\nModelo: %s (%s)\n", contract.Filename, contract.Status, contract.LLMModel, contract.LLMType)
+		if contract.AnalyzedAt != nil {
+			fmt.Printf("Fecha: %s\n", contract.AnalyzedAt.Format("2006-01-02 15:04"))
+		}
+		if contract.LegalHold {
+			fmt.Printf("Legal hold: %s\n", contract.LegalHoldReason)
+		}
+		if contract.ErrorMessage != "" {
+			fmt.Printf("Error: %s\n", contract.ErrorMessage)
+		}
+		fmt.Printf("\n%s\n", contract.AnalysisResult)
+		return 0
+
+	case "delete":
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		id, ok := contractIDArg(fs)
+		if !ok {
+			return 2
+		}
+		if err := opts.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 2
+		}
+
+		a := open()
+		ctx, membership, err := opts.authorize(ctx, a.workspaces, entities.RoleAdmin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+
+		// El nombre se lee antes de borrar para que la auditoría lo conserve
+		var filename string
+		if contract, err := a.contractRepo.GetByID(ctx, membership.WorkspaceID, id); err == nil {
+			filename = contract.Filename
+		}
+
+		if err := a.contractRepo.Delete(ctx, membership.WorkspaceID, id); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error eliminando contrato %d: %v\n", id, err)
+			return 1
+		}
+		a.audit.Record(ctx, entities.AuditDelete, membership.WorkspaceID, id, filename)
+		// La entrega queda pendiente: la envía el servidor (contractis serve)
+		a.webhooks.Publish(ctx, membership.WorkspaceID, entities.EventContractDeleted,
+			entities.NewContractDeletedEvent(ctx, id, filename, false))
+
+		if opts.json() {
+			printJSON(map[string]interface{}{"success": true, "id": id})
+			return 0
+		}
+		fmt.Printf("✅ Contrato %d enviado a la papelera\n", id)
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, historyUsage)
+		return 2
+	}
+}
+
+// contractIDArg lee el ID de contrato posicional
+func contractIDArg(fs *flag.FlagSet) (int64, bool) {
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, historyUsage)
+		return 0, false
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "❌ ID inválido: %s\n", fs.Arg(0))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+const keysUsage = `Uso:
+  contractis keys create -name NOMBRE [-user EMAIL] [-scopes read,analyze,delete,admin]
+  contractis keys list
+  contractis keys revoke ID`
+
+// runKeysCommand gestiona las API keys desde la línea de comandos y retorna el código de salida
+func runKeysCommand(ctx context.Context, open func() *app, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, keysUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("keys create", flag.ContinueOnError)
+		name := fs.String("name", "", "nombre descriptivo de la key")
+		userEmail := fs.String("user", "admin@localhost", "email del usuario dueño de la key")
+		scopesFlag := fs.String("scopes", "read,analyze", "permisos separados por comas: read, analyze, delete, admin")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		scopes, err := entities.ParseScopes(*scopesFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 2
+		}
+
+		a := open()
+		user, err := a.workspaces.GetUserByEmail(ctx, *userEmail)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Usuario %s: %v\n", *userEmail, err)
+			return 1
+		}
+
+		plaintext, key, err := a.authService.CreateAPIKey(ctx, user.ID, *name, scopes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error creando API key: %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("✅ API key creada para %s (ID %d, scopes: %s)\n", user.Email, key.ID, joinScopes(key.Scopes))
+		fmt.Printf("   %s\n", plaintext)
+		fmt.Println("⚠️  Guárdala ahora: no se puede volver a mostrar.")
+		return 0
+
+	case "list":
+		keys, err := open().authService.ListAPIKeys(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error listando API keys: %v\n", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tUSUARIO\tNOMBRE\tPREFIJO\tSCOPES\tCREADA\tÚLTIMO USO\tESTADO")
+		for _, key := range keys {
+			lastUsed := "-"
+			if key.LastUsedAt != nil {
+				lastUsed = key.LastUsedAt.Format("2006-01-02 15:04")
+			}
+			status := "activa"
+			if key.IsRevoked() {
+				status = "revocada"
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s…\t%s\t%s\t%s\t%s\n",
+				key.ID, key.UserID, key.Name, key.Prefix, joinScopes(key.Scopes),
+				key.CreatedAt.Format("2006-01-02 15:04"), lastUsed, status)
+		}
+		tw.Flush()
+		return 0
+
+	case "revoke":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, keysUsage)
+			return 2
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ ID inválido: %s\n", args[1])
+			return 2
+		}
+
+		if err := open().authService.RevokeAPIKey(ctx, id); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error revocando API key: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ API key %d revocada\n", id)
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, keysUsage)
+		return 2
+	}
+}
+
+func joinScopes(scopes []entities.Scope) string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/dto"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+)
+
+// defaultLocalURL es el endpoint de LM Studio que también propone el cliente web
+const defaultLocalURL = "http://localhost:1234/v1/chat/completions"
+
+// llmFlags son las opciones de LLM de la línea de comandos. Se resuelven en orden
+// archivo llm.config_file < opciones llm.* (archivo de configuración o CONTRACTIS_LLM_*)
+// < flags explícitos.
+type llmFlags struct {
+	fs         *flag.FlagSet
+	defaults   config.LLMConfig
+	configPath *string
+	profileID  *int64
+	llmType    *string
+	url        *string
+	apiKey     *string
+	model      *string
+	maxTokens  *int
+}
+
+// registerLLMFlags agrega las opciones de LLM al FlagSet; defaults son las opciones llm.*
+// de la configuración, que los flags sobrescriben
+func registerLLMFlags(fs *flag.FlagSet, defaults config.LLMConfig) *llmFlags {
+	return &llmFlags{
+		fs:         fs,
+		defaults:   defaults,
+		configPath: fs.String("llm-config", "", "archivo JSON con la configuración LLM (formato de llmConfig; env CONTRACTIS_LLM_CONFIG)"),
+		profileID:  fs.Int64("profile", 0, "ID de un perfil LLM guardado; tiene prioridad sobre el resto (env CONTRACTIS_LLM_PROFILE)"),
+		llmType:    fs.String("type", "", "local u online (env CONTRACTIS_LLM_TYPE, por defecto local)"),
+		url:        fs.String("url", "", "endpoint del LLM (env CONTRACTIS_LLM_URL)"),
+		apiKey:     fs.String("api-key", "", "API key del proveedor online (env CONTRACTIS_LLM_API_KEY)"),
+		model:      fs.String("model", "", "modelo (env CONTRACTIS_LLM_MODEL)"),
+		maxTokens:  fs.Int("max-tokens", 0, "tokens de salida por petición (env CONTRACTIS_LLM_MAX_TOKENS, por defecto 800)"),
+	}
+}
+
+// profile retorna el perfil LLM elegido por flag o por llm.profile (cero si ninguno)
+func (f *llmFlags) profile() int64 {
+	if f.isSet("profile") {
+		return *f.profileID
+	}
+	return int64(f.defaults.Profile)
+}
+
+// request combina archivo, configuración y flags en una configuración LLM
+func (f *llmFlags) request() (dto.LLMConfigRequest, error) {
+	req := dto.LLMConfigRequest{Type: "local", MaxTokens: 800}
+
+	path := f.defaults.ConfigFile
+	if f.isSet("llm-config") {
+		path = *f.configPath
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return req, fmt.Errorf("failed to read LLM config: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return req, fmt.Errorf("invalid LLM config %s: %w", path, err)
+		}
+	}
+
+	if f.defaults.Type != "" {
+		req.Type = f.defaults.Type
+	}
+	url := f.defaults.URL
+	if f.defaults.APIKey != "" {
+		req.ApiKey = f.defaults.APIKey
+	}
+	if f.defaults.Model != "" {
+		req.ModelName = f.defaults.Model
+	}
+	if f.defaults.MaxTokens > 0 {
+		req.MaxTokens = f.defaults.MaxTokens
+	}
+
+	if f.isSet("type") {
+		req.Type = *f.llmType
+	}
+	if f.isSet("url") {
+		url = *f.url
+	}
+	if f.isSet("api-key") {
+		req.ApiKey = *f.apiKey
+	}
+	if f.isSet("model") {
+		req.ModelName = *f.model
+	}
+	if f.isSet("max-tokens") {
+		req.MaxTokens = *f.maxTokens
+	}
+
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	if url != "" {
+		if req.Type == "online" {
+			req.ApiUrl = url
+		} else {
+			req.LocalUrl = url
+		}
+	}
+	if req.Type == "local" && req.LocalUrl == "" {
+		req.LocalUrl = defaultLocalURL
+	}
+	return req, nil
+}
+
+func (f *llmFlags) isSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
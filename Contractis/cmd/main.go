@@ -1,65 +1,253 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	httpAdapter "github.com/rodascaar/contractis/internal/adapters/http"
 	"github.com/rodascaar/contractis/internal/adapters/http/handlers"
 	"github.com/rodascaar/contractis/internal/adapters/http/router"
-	"github.com/rodascaar/contractis/internal/infrastructure/database"
-	"github.com/rodascaar/contractis/internal/infrastructure/llm"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/health"
+	"github.com/rodascaar/contractis/internal/infrastructure/logging"
 	"github.com/rodascaar/contractis/internal/infrastructure/pdf"
 	"github.com/rodascaar/contractis/internal/infrastructure/text"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
 	"github.com/rodascaar/contractis/internal/usecases"
 )
 
+// version es la versión publicada en /healthz y /readyz; se fija al compilar con
+// -ldflags "-X main.version=1.2.0" (vacía usa la del módulo)
+var version string
+
 func main() {
-	// Database initialization
-	db, err := database.NewSQLiteDB("./contractis.db")
+	// Las opciones de configuración van antes del subcomando: contractis [-config FILE] [-db PATH] [subcomando] ...
+	globalFlags := flag.NewFlagSet("contractis", flag.ExitOnError)
+	globalConfig := config.RegisterFlags(globalFlags)
+	globalFlags.Parse(os.Args[1:])
+
+	// Subcomandos: contractis [serve|analyze|estimate|batch|history|keys|users|workspaces|encryption] ...
+	// Sin subcomando se inicia el servidor, como antes de existir la CLI
+	command, args := "serve", []string{}
+	if rest := globalFlags.Args(); len(rest) > 0 {
+		command, args = rest[0], rest[1:]
+	}
+	logOutput := io.Writer(os.Stderr)
+	if quietRequested(globalFlags.Args()) {
+		logOutput = io.Discard
+		log.SetOutput(io.Discard)
+	}
+
+	// Configuración: valores por defecto < archivo < variables de entorno < flags.
+	// serve también acepta las opciones después del subcomando (contractis serve -port 9090)
+	var sources config.Sources
+	globalConfig.Collect(&sources)
+	if command == "serve" {
+		serveFlags := flag.NewFlagSet("serve", flag.ExitOnError)
+		serveConfig := config.RegisterFlags(serveFlags)
+		serveFlags.Parse(args)
+		serveConfig.Collect(&sources)
+	}
+	cfg, err := config.Load(sources)
 	if err != nil {
-		log.Fatalf("❌ Error inicializando base de datos: %v", err)
-	}
-	defer db.Close()
-	log.Printf("✅ Base de datos SQLite inicializada: ./contractis.db")
-
-	// Infrastructure layer
-	pdfExtractor := pdf.NewExtractor()
-	llmClient := llm.NewClient()
-	textProcessor := text.NewProcessor()
-	contractRepo := database.NewContractRepository(db)
-
-	// Use cases layer
-	analyzeUseCase := usecases.NewAnalyzeContractUseCase(
-		pdfExtractor,
-		llmClient,
-		contractRepo,
-		textProcessor,
-	)
+		log.Fatalf("❌ Configuración inválida: %v", err)
+	}
+	// Logs estructurados (texto o JSON) con el nivel configurado; desde aquí todo pasa por slog
+	if err := logging.Setup(logOutput, cfg.Log.Format, cfg.Log.Level); err != nil {
+		log.Fatalf("❌ Configuración de logs inválida: %v", err)
+	}
+	if cfg.File != "" {
+		slog.Info("configuración cargada", "file", cfg.File)
+	}
+	limits := cfg.EntityLimits()
 
-	estimateUseCase := usecases.NewEstimateTokensUseCase(
-		pdfExtractor,
-		textProcessor,
-	)
+	if command != "serve" {
+		// Cada subcomando valida sus argumentos antes de abrir la base: -h o un uso
+		// incorrecto no crean ni migran contractis.db
+		var opened *app
+		open := func() *app {
+			if opened == nil {
+				opened = openApp(cfg)
+			}
+			return opened
+		}
+		var code int
+		switch command {
+		case "analyze":
+			code = runAnalyzeCommand(context.Background(), open, limits, cfg.LLM, args)
+		case "estimate":
+			code = runEstimateCommand(usecases.NewEstimateTokensUseCase(pdf.NewExtractor(), text.NewProcessor()), limits, args)
+		case "batch":
+			code = runBatchCommand(context.Background(), open, limits, cfg.LLM, args)
+		case "history":
+			code = runHistoryCommand(context.Background(), open, args)
+		case "keys":
+			code = runKeysCommand(context.Background(), open, args)
+		case "users":
+			code = runUsersCommand(context.Background(), open, args)
+		case "workspaces":
+			code = runWorkspacesCommand(context.Background(), open, args)
+		case "encryption":
+			code = runEncryptionCommand(context.Background(), open, args)
+		default:
+			slog.Error("subcomando desconocido (serve, analyze, estimate, batch, history, keys, users, workspaces, encryption)", "command", command)
+			os.Exit(1)
+		}
+		if opened != nil {
+			opened.close()
+		}
+		os.Exit(code)
+	}
+
+	// Base de datos, cifrado, autenticación y casos de uso (ver openApp)
+	a := openApp(cfg)
+	estimateUseCase := usecases.NewEstimateTokensUseCase(pdf.NewExtractor(), text.NewProcessor())
+
+	// SIGINT o SIGTERM detienen el servidor ordenadamente (ver serveUntilSignal)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Retención: barrido periódico de la papelera y de contratos vencidos
+	retentionPolicy := cfg.RetentionPolicy()
+	retentionUseCase := usecases.NewRetentionUseCase(a.contractRepo, a.audit, retentionPolicy, a.webhooks)
+	go retentionUseCase.Run(ctx, entities.RetentionSweepInterval)
+	slog.Info("retención (0 = sin límite)",
+		"trash", retentionPolicy.TrashRetention, "contracts", retentionPolicy.ContractRetention)
+
+	// Entregas de webhooks; se detienen después del servidor para enviar los eventos de
+	// los análisis que terminen durante el apagado
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		a.webhooks.Run(webhookCtx)
+	}()
+
+	// Los lotes a medias de una ejecución anterior perdieron sus archivos temporales; los
+	// contratos que se estaban analizando quedan interrumpidos y se retoman al volver a subirlos
+	a.batches.RecoverInterrupted(ctx)
+	if count, err := a.contractRepo.InterruptStale(ctx, "análisis interrumpido por un reinicio del servidor: vuelva a subir el archivo para retomarlo"); err != nil {
+		slog.Error("error marcando análisis interrumpidos", "error", err)
+	} else if count > 0 {
+		slog.Warn("análisis interrumpidos por un reinicio", "count", count)
+	}
+
+	// Ingesta opcional de una carpeta vigilada (watch.dir)
+	if err := startWatch(ctx, cfg.Watch, cfg.LLM, a.analyze, a.profiles, a.workspaces, a.contractRepo, a.jobs, limits); err != nil {
+		fatal("error configurando la carpeta vigilada", err)
+	}
+
+	// Readiness: base de datos, carpeta temporal de subidas y lotes, reportes de la carpeta
+	// vigilada y, si se habilitó, el alcance de los perfiles LLM
+	healthChecks := []services.HealthCheck{
+		health.NewDatabaseCheck(a.db),
+		health.NewStorageCheck("storage", os.TempDir()),
+	}
+	if dir := cfg.Watch.ReportsDir(); dir != "" {
+		healthChecks = append(healthChecks, health.NewStorageCheck("reports", dir))
+	}
+	if cfg.Health.LLMInterval > 0 {
+		healthChecks = append(healthChecks, health.NewLLMCheck(a.profileRepo, cfg.Health.LLMInterval))
+	}
+	healthUseCase := usecases.NewHealthUseCase(health.Build(version), a.jobs, cfg.Health.Timeout, healthChecks...)
 
 	// HTTP handlers (adapters layer)
-	uploadHandler := handlers.NewUploadHandler(analyzeUseCase)
-	estimateHandler := handlers.NewEstimateHandler(estimateUseCase)
-	historyHandler := handlers.NewHistoryHandler(contractRepo)
+	uploadHandler := handlers.NewUploadHandler(a.analyze, a.profiles, a.workspaces, a.jobs, limits)
+	estimateHandler := handlers.NewEstimateHandler(estimateUseCase, limits)
+	historyHandler := handlers.NewHistoryHandler(a.contractRepo, a.workspaces, a.audit, a.webhooks)
+	queueHandler := handlers.NewQueueHandler(a.llmScheduler)
+	profileHandler := handlers.NewProfileHandler(a.profiles, a.workspaces)
+	authHandler := handlers.NewAuthHandler(a.authService)
+	workspaceHandler := handlers.NewWorkspaceHandler(a.workspaces)
+	auditHandler := handlers.NewAuditHandler(a.audit)
+	batchHandler := handlers.NewBatchHandler(a.batches, a.profiles, a.workspaces, limits)
+	healthHandler := handlers.NewHealthHandler(healthUseCase)
+	webhookHandler := handlers.NewWebhookHandler(a.webhooks, a.workspaces)
+
+	// Login SSO opcional con un proveedor OpenID Connect
+	oidcHandler, err := oidcHandlerFromConfig(cfg.OIDC, a.masterKey, a.userRepo, a.workspaceRepo, a.authService)
+	if err != nil {
+		fatal("error configurando OIDC", err)
+	}
+	if oidcHandler != nil {
+		slog.Info("login OIDC habilitado", "issuer", cfg.OIDC.Issuer)
+	}
 
 	// Router setup
 	appRouter := router.NewRouter(
 		uploadHandler,
 		estimateHandler,
 		historyHandler,
-		"./static",
+		queueHandler,
+		profileHandler,
+		authHandler,
+		workspaceHandler,
+		oidcHandler,
+		auditHandler,
+		batchHandler,
+		healthHandler,
+		webhookHandler,
+		a.authService,
+		a.metrics,
+		a.tracer,
+		cfg.Server.StaticDir,
 	)
 
 	// HTTP server
-	server := httpAdapter.NewServer("8080", appRouter)
+	server := httpAdapter.NewServer(cfg.Addr(), appRouter, httpAdapter.ServerOptions{
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	})
 
 	// Start server
-	log.Printf("🚀 Contractis servidor iniciado en http://localhost:8080")
-	if err := server.Start(); err != nil {
-		log.Fatalf("❌ Error iniciando servidor: %v", err)
+	slog.Info("servidor iniciado", "url", cfg.URL(), "environment", cfg.Environment)
+	if err := serveUntilSignal(ctx, server, a.jobs, cfg.Server.ShutdownTimeout); err != nil {
+		fatal("error iniciando servidor", err)
+	}
+
+	// Las entregas pendientes quedan en la base y se retoman al iniciar de nuevo
+	stopWebhooks()
+	<-webhooksDone
+
+	a.close()
+	slog.Info("servidor detenido")
+}
+
+// tracerFromConfig crea el tracer que exporta al collector configurado; sin endpoint
+// retorna nil y el tracing queda desactivado
+func tracerFromConfig(cfg config.TracingConfig) (*tracing.Tracer, error) {
+	if cfg.Endpoint == "" {
+		return nil, nil
+	}
+	exporter, err := tracing.NewOTLPExporter(cfg.Endpoint, cfg.ServiceName, cfg.Headers)
+	if err != nil {
+		return nil, err
 	}
+	slog.Info("trazas OpenTelemetry activas", "endpoint", exporter.URL(), "service", cfg.ServiceName)
+	return tracing.NewTracer(exporter), nil
+}
+
+// shutdownTracer exporta las trazas pendientes antes de salir
+func shutdownTracer(tracer *tracing.Tracer) {
+	ctx, cancel := context.WithTimeout(context.Background(), closeGrace)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		slog.Warn("error exportando las últimas trazas", "error", err)
+	}
+}
+
+// fatal registra el error y termina el proceso
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
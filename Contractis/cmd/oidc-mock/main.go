@@ -0,0 +1,49 @@
+// oidc-mock arranca un proveedor OpenID Connect de pruebas que aprueba cada login
+// sin pedir credenciales. Solo para desarrollo local: nunca exponerlo en producción.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/infrastructure/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9000", "dirección de escucha")
+	issuerURL := flag.String("issuer", "", "URL pública del emisor (por defecto http://<addr>)")
+	clientID := flag.String("client-id", "contractis", "client_id aceptado")
+	clientSecret := flag.String("client-secret", "", "client_secret requerido (vacío: cliente público)")
+	email := flag.String("email", "user@example.com", "email del usuario autenticado")
+	name := flag.String("name", "Mock User", "nombre del usuario autenticado")
+	groups := flag.String("groups", "", "grupos del usuario, separados por comas")
+	flag.Parse()
+
+	if *issuerURL == "" {
+		*issuerURL = "http://" + *addr
+	}
+
+	issuer, err := oidctest.NewIssuer(*issuerURL, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("❌ Error creando emisor: %v", err)
+	}
+	issuer.DefaultUser = oidctest.User{
+		Subject:       "mock|" + *email,
+		Email:         *email,
+		EmailVerified: true,
+		Name:          *name,
+	}
+	for _, group := range strings.Split(*groups, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			issuer.DefaultUser.Groups = append(issuer.DefaultUser.Groups, group)
+		}
+	}
+
+	log.Printf("🧪 Emisor OIDC de pruebas en %s (client_id=%s, usuario=%s, grupos=%v)",
+		issuer.URL, *clientID, *email, issuer.DefaultUser.Groups)
+	if err := http.ListenAndServe(*addr, issuer); err != nil {
+		log.Fatalf("❌ Error iniciando emisor: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/handlers"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/oidc"
+	"github.com/rodascaar/contractis/internal/infrastructure/secrets"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// oidcHandlerFromConfig configura el login OIDC. Retorna nil si no hay oidc.issuer.
+func oidcHandlerFromConfig(
+	cfg config.OIDCConfig,
+	masterKey []byte,
+	userRepo repositories.UserRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	sessions services.SessionIssuer,
+) (*handlers.OIDCHandler, error) {
+	if cfg.Issuer == "" {
+		return nil, nil
+	}
+
+	mappings, err := entities.ParseRoleMappings(cfg.RoleMap)
+	if err != nil {
+		return nil, fmt.Errorf("oidc.role_map: %w", err)
+	}
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       strings.Fields(strings.ReplaceAll(cfg.Scopes, ",", " ")),
+		GroupsClaim:  cfg.GroupsClaim,
+	}, secrets.DeriveKey(masterKey, "oidc-state"))
+	if err != nil {
+		return nil, err
+	}
+
+	login := usecases.NewOIDCLoginUseCase(userRepo, workspaceRepo, mappings)
+	return handlers.NewOIDCHandler(provider, login, sessions), nil
+}
@@ -0,0 +1,561 @@
+// Command openapi-gen genera el cliente Go tipado de Contractis a partir de la
+// especificación OpenAPI embebida en el paquete api.
+//
+// Uso: go run ./cmd/openapi-gen -o client/client_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rodascaar/contractis/api"
+)
+
+// initialisms se escriben en mayúsculas en los identificadores Go
+var initialisms = map[string]bool{
+	"id": true, "url": true, "api": true, "llm": true, "http": true, "json": true, "pdf": true,
+}
+
+func main() {
+	output := flag.String("o", "client/client_gen.go", "archivo de salida")
+	pkg := flag.String("package", "client", "nombre del paquete generado")
+	flag.Parse()
+
+	doc, err := api.Load()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	src, err := generate(doc, *pkg)
+	if err != nil {
+		log.Fatalf("❌ Error generando el cliente: %v", err)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatalf("❌ Error escribiendo %s: %v", *output, err)
+	}
+	log.Printf("✅ Cliente generado en %s", *output)
+}
+
+// generator acumula el código generado y los imports que necesita
+type generator struct {
+	doc     *api.Document
+	buf     bytes.Buffer
+	imports map[string]bool
+	forms   map[string]bool
+	// requests son los esquemas usados como cuerpo application/json
+	requests map[string]bool
+}
+
+func generate(doc *api.Document, pkg string) ([]byte, error) {
+	g := &generator{doc: doc, imports: make(map[string]bool), forms: make(map[string]bool), requests: make(map[string]bool)}
+
+	// Los esquemas usados como multipart/form-data se generan como formularios; los
+	// usados como cuerpo JSON, como structs con punteros en los campos opcionales
+	for _, path := range doc.Paths.Keys {
+		item := doc.Paths.Values[path]
+		for _, method := range api.Methods {
+			op := item.Operation(method)
+			if op == nil || op.RequestBody == nil {
+				continue
+			}
+			if media, ok := op.RequestBody.Content["multipart/form-data"]; ok && media.Schema != nil {
+				g.forms[refName(media.Schema.Ref)] = true
+			}
+			if media, ok := op.RequestBody.Content["application/json"]; ok && media.Schema != nil {
+				g.requests[refName(media.Schema.Ref)] = true
+			}
+		}
+	}
+
+	for _, name := range doc.Components.Schemas.Keys {
+		schema := doc.Components.Schemas.Values[name]
+		var err error
+		if g.forms[name] {
+			err = g.writeForm(name, schema)
+		} else {
+			err = g.writeStruct(name, schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	for _, path := range doc.Paths.Keys {
+		item := doc.Paths.Values[path]
+		for _, method := range api.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			if err := g.writeOperation(path, method, item, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by openapi-gen from api/openapi.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		out.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %w\n%s", err, out.String())
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// writeStruct genera un struct JSON para un esquema de objeto
+func (g *generator) writeStruct(name string, schema *api.Schema) error {
+	g.writeDoc(name, "es el esquema "+name+" de la API", schema.Description)
+	g.printf("type %s struct {\n", name)
+	for _, prop := range schema.Properties.Keys {
+		propSchema := schema.Properties.Values[prop]
+		required := schema.IsRequired(prop)
+		goType, err := g.goType(propSchema, required)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop, err)
+		}
+		// En los cuerpos de petición un campo opcional omitido no equivale a su valor cero
+		// (PATCH con "active": false), así que los escalares opcionales son punteros
+		if g.requests[name] && !required && isScalar(goType) {
+			goType = "*" + goType
+		}
+		tag := prop
+		if !required {
+			tag += ",omitempty"
+		}
+		if propSchema.Description != "" {
+			g.printf("// %s\n", propSchema.Description)
+		}
+		g.printf("%s %s `json:%q`\n", goName(prop), goType, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// writeForm genera un formulario multipart con su método de serialización
+func (g *generator) writeForm(name string, schema *api.Schema) error {
+	g.imports["mime/multipart"] = true
+	g.writeDoc(name, "es el formulario multipart "+name+" de la API", schema.Description)
+	g.printf("type %s struct {\n", name)
+	for _, prop := range schema.Properties.Keys {
+		propSchema := schema.Properties.Values[prop]
+		field := goName(prop)
+		if propSchema.Description != "" {
+			g.printf("// %s\n", propSchema.Description)
+		}
+		if propSchema.Format == "binary" {
+			g.imports["io"] = true
+			g.printf("%s io.Reader\n", field)
+			g.printf("// %sName es el nombre de archivo con el que se envía %s\n", field, field)
+			g.printf("%sName string\n", field)
+			continue
+		}
+		if isBinaryList(propSchema) {
+			g.printf("%s []FormFile\n", field)
+			continue
+		}
+		goType, err := g.goType(propSchema, false)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop, err)
+		}
+		g.printf("%s %s\n", field, goType)
+	}
+	g.printf("}\n\n")
+
+	g.printf("func (f *%s) writeMultipart(mw *multipart.Writer) error {\n", name)
+	for _, prop := range schema.Properties.Keys {
+		propSchema := schema.Properties.Values[prop]
+		field := goName(prop)
+		switch {
+		case propSchema.Format == "binary":
+			g.imports["fmt"] = true
+			if schema.IsRequired(prop) {
+				g.printf("if f.%s == nil {\nreturn fmt.Errorf(\"%s is required\")\n}\n{\n", field, prop)
+			} else {
+				g.printf("if f.%s != nil {\n", field)
+			}
+			g.printf("part, err := mw.CreateFormFile(%q, f.%sName)\nif err != nil {\nreturn err\n}\n", prop, field)
+			g.printf("if _, err := io.Copy(part, f.%s); err != nil {\nreturn fmt.Errorf(\"failed to write %s: %%w\", err)\n}\n", field, prop)
+			g.printf("}\n")
+		case isBinaryList(propSchema):
+			g.imports["fmt"] = true
+			if schema.IsRequired(prop) {
+				g.printf("if len(f.%s) == 0 {\nreturn fmt.Errorf(\"%s is required\")\n}\n", field, prop)
+			}
+			g.printf("for _, file := range f.%s {\n", field)
+			g.printf("part, err := mw.CreateFormFile(%q, file.Name)\nif err != nil {\nreturn err\n}\n", prop)
+			g.printf("if _, err := io.Copy(part, file.Content); err != nil {\nreturn fmt.Errorf(\"failed to write %s: %%w\", err)\n}\n", prop)
+			g.printf("}\n")
+		case propSchema.Ref != "":
+			g.imports["encoding/json"] = true
+			g.printf("if f.%s != nil {\n", field)
+			g.printf("data, err := json.Marshal(f.%s)\nif err != nil {\nreturn err\n}\n", field)
+			g.printf("if err := mw.WriteField(%q, string(data)); err != nil {\nreturn err\n}\n", prop)
+			g.printf("}\n")
+		default:
+			goType, _ := g.goType(propSchema, false)
+			zero := zeroValue(goType)
+			g.imports["fmt"] = true
+			g.printf("if f.%s != %s {\n", field, zero)
+			g.printf("if err := mw.WriteField(%q, fmt.Sprint(f.%s)); err != nil {\nreturn err\n}\n", prop, field)
+			g.printf("}\n")
+		}
+	}
+	g.printf("return nil\n}\n\n")
+	return nil
+}
+
+// isScalar indica si el tipo Go es un string, número o booleano
+func isScalar(goType string) bool {
+	switch goType {
+	case "string", "int", "int64", "float64", "bool":
+		return true
+	}
+	return false
+}
+
+// isBinaryList indica si la propiedad es un campo multipart que se repite con varios archivos
+func isBinaryList(schema *api.Schema) bool {
+	return schema.Type.Primary() == "array" && schema.Items != nil && schema.Items.Format == "binary"
+}
+
+// writeOperation genera el método del cliente para una operación
+func (g *generator) writeOperation(path, method string, item *api.PathItem, op *api.Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("missing operationId")
+	}
+	name := goName(op.OperationID)
+	params, err := g.doc.OperationParameters(item, op)
+	if err != nil {
+		return err
+	}
+
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+	g.imports["net/url"] = true
+
+	args := []string{"ctx context.Context"}
+	var optional []*api.Parameter
+	for _, param := range params {
+		switch {
+		case param.In == "header":
+			// Las cabeceras (X-Workspace-ID) las fija el Client
+			continue
+		case param.In == "path" || param.Required:
+			goType, err := g.goType(param.Schema, true)
+			if err != nil {
+				return err
+			}
+			args = append(args, lowerFirst(goName(param.Name))+" "+goType)
+		default:
+			optional = append(optional, param)
+		}
+	}
+
+	var form, request string
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["multipart/form-data"]; ok && media.Schema != nil && media.Schema.Ref != "" {
+			form = refName(media.Schema.Ref)
+			args = append(args, "form *"+form)
+		} else if media, ok := op.RequestBody.Content["application/json"]; ok && media.Schema != nil && media.Schema.Ref != "" {
+			request = refName(media.Schema.Ref)
+			args = append(args, "request *"+request)
+		} else {
+			return fmt.Errorf("only multipart/form-data and application/json request bodies referencing a component schema are supported")
+		}
+	}
+
+	if len(optional) > 0 {
+		paramsType := name + "Params"
+		g.printf("// %s son los parámetros opcionales de %s\n", paramsType, name)
+		g.printf("type %s struct {\n", paramsType)
+		for _, param := range optional {
+			goType, err := g.goType(param.Schema, true)
+			if err != nil {
+				return err
+			}
+			if param.Description != "" {
+				g.printf("// %s\n", param.Description)
+			}
+			g.printf("%s *%s\n", goName(param.Name), goType)
+		}
+		g.printf("}\n\n")
+		args = append(args, "params *"+paramsType)
+	}
+
+	result, accept, err := g.successResult(op)
+	if err != nil {
+		return err
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = "invoca " + op.OperationID
+	}
+	g.printf("// %s %s (%s %s)\n", name, lowerFirst(summary), method, path)
+	returns := "error"
+	if result != "" {
+		returns = "(" + result + ", error)"
+	}
+	g.printf("func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	g.printf("path := %s\n", g.pathExpr(path))
+	g.printf("query := url.Values{}\n")
+	for _, param := range params {
+		if param.In == "query" && param.Required {
+			g.imports["fmt"] = true
+			g.printf("query.Set(%q, fmt.Sprint(%s))\n", param.Name, lowerFirst(goName(param.Name)))
+		}
+	}
+	if len(optional) > 0 {
+		g.imports["fmt"] = true
+		g.printf("if params != nil {\n")
+		for _, param := range optional {
+			field := goName(param.Name)
+			g.printf("if params.%s != nil {\nquery.Set(%q, fmt.Sprint(*params.%s))\n}\n", field, param.Name, field)
+		}
+		g.printf("}\n")
+	}
+
+	errReturn := "return err"
+	if result != "" {
+		errReturn = "return " + zeroValue(result) + ", err"
+	}
+
+	bodyExpr, contentType := "nil", `""`
+	switch {
+	case form != "":
+		g.printf("body, contentType, err := encodeMultipart(form.writeMultipart)\nif err != nil {\n%s\n}\n", errReturn)
+		bodyExpr, contentType = "body", "contentType"
+	case request != "":
+		g.printf("body, contentType, err := encodeJSON(request)\nif err != nil {\n%s\n}\n", errReturn)
+		bodyExpr, contentType = "body", "contentType"
+	}
+
+	switch {
+	case result == "":
+		g.printf("return c.do(ctx, %s, path, query, %s, %s, %q, nil)\n", httpMethod(method), bodyExpr, contentType, accept)
+	case result == "string":
+		g.printf("var out string\n")
+		g.printf("if err := c.do(ctx, %s, path, query, %s, %s, %q, &out); err != nil {\n%s\n}\n", httpMethod(method), bodyExpr, contentType, accept, errReturn)
+		g.printf("return out, nil\n")
+	default:
+		g.printf("var out %s\n", strings.TrimPrefix(result, "*"))
+		g.printf("if err := c.do(ctx, %s, path, query, %s, %s, %q, &out); err != nil {\n%s\n}\n", httpMethod(method), bodyExpr, contentType, accept, errReturn)
+		g.printf("return &out, nil\n")
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// successResult retorna el tipo Go de la primera respuesta 2xx y el Accept a enviar
+func (g *generator) successResult(op *api.Operation) (string, string, error) {
+	for _, status := range op.Responses.Keys {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		response, err := g.doc.ResolveResponse(op.Responses.Values[status])
+		if err != nil {
+			return "", "", err
+		}
+		if len(response.Content) == 0 {
+			return "", "application/problem+json", nil
+		}
+		if media, ok := response.Content["application/json"]; ok {
+			if media.Schema == nil || media.Schema.Ref == "" {
+				return "", "", fmt.Errorf("JSON responses must reference a component schema")
+			}
+			return "*" + refName(media.Schema.Ref), "application/json", nil
+		}
+		if _, ok := response.Content["text/plain"]; ok {
+			return "string", "text/plain", nil
+		}
+		return "", "", fmt.Errorf("unsupported response content for %s", status)
+	}
+	return "", "", fmt.Errorf("no 2xx response")
+}
+
+// pathExpr construye la expresión Go de la ruta con sus parámetros escapados
+func (g *generator) pathExpr(path string) string {
+	var parts []string
+	rest := path
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}") + start
+		if rest[:start] != "" {
+			parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+		}
+		g.imports["fmt"] = true
+		parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", lowerFirst(goName(rest[start+1:end]))))
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goType traduce un esquema a un tipo Go
+func (g *generator) goType(schema *api.Schema, required bool) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("missing schema")
+	}
+	if schema.IsAny() {
+		return "any", nil
+	}
+	if schema.Ref != "" {
+		name := refName(schema.Ref)
+		if required {
+			return name, nil
+		}
+		return "*" + name, nil
+	}
+	switch schema.Type.Primary() {
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			if required {
+				return "time.Time", nil
+			}
+			return "*time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		if schema.Format == "int64" {
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		elem, err := g.goType(schema.Items, true)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if schema.AdditionalProperties != nil {
+			elem, err := g.goType(schema.AdditionalProperties, true)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+		return "map[string]any", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %v", schema.Type)
+}
+
+// writeDoc escribe el comentario de un tipo, con la descripción del esquema si la tiene
+func (g *generator) writeDoc(name, summary, description string) {
+	if description == "" {
+		g.printf("// %s %s\n", name, summary)
+		return
+	}
+	g.printf("// %s %s: %s\n", name, summary, lowerFirst(description))
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func httpMethod(method string) string {
+	return "http.Method" + method[:1] + strings.ToLower(method[1:])
+}
+
+func zeroValue(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["), goType == "any":
+		return "nil"
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case goType == "int", goType == "int64", goType == "float64":
+		return "0"
+	}
+	return goType + "{}"
+}
+
+// goName convierte snake_case, kebab-case o camelCase a un identificador Go exportado
+func goName(name string) string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	var out strings.Builder
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if initialisms[lower] {
+			out.WriteString(strings.ToUpper(word))
+			continue
+		}
+		out.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return out.String()
+}
+
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// Los initialisms completos ("ID") pasan enteros a minúsculas; en "LLMConfig" se conserva la C
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/config"
+	"github.com/rodascaar/contractis/internal/infrastructure/redaction"
+)
+
+// redactorFromConfig construye el redactor de datos personales. redaction.enabled=false lo
+// desactiva; redaction.detectors elige los detectores (por defecto, todos).
+func redactorFromConfig(cfg config.RedactionConfig) (services.Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	detectors := redaction.DefaultDetectors()
+	if cfg.Detectors != "" {
+		var err error
+		if detectors, err = redaction.DetectorsByName(cfg.Detectors); err != nil {
+			return nil, err
+		}
+	}
+	return redaction.NewRedactor(detectors...), nil
+}
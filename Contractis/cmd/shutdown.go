@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	httpAdapter "github.com/rodascaar/contractis/internal/adapters/http"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// closeGrace es cuánto se espera, tras interrumpir los análisis, a que sus solicitudes
+// respondan antes de cerrar las conexiones
+const closeGrace = 5 * time.Second
+
+// serveUntilSignal atiende solicitudes hasta que ctx se cancela (SIGINT o SIGTERM). Entonces
+// deja de aceptar conexiones y análisis nuevos, espera a los análisis en curso hasta timeout
+// y, si no terminaron, los interrumpe: quedan con status interrupted y se retoman desde su
+// checkpoint al volver a subir el archivo.
+func serveUntilSignal(ctx context.Context, server *httpAdapter.Server, jobs *usecases.JobTracker, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Start()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("deteniendo el servidor: no se aceptan análisis nuevos", "timeout", timeout)
+	deadline, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- jobs.Shutdown(deadline)
+	}()
+
+	err := server.Shutdown(deadline)
+	if jobsErr := <-drained; jobsErr != nil {
+		slog.Warn("los análisis que no terminaron quedaron interrumpidos y se retomarán al volver a subirlos")
+	}
+	if err != nil {
+		// Las solicitudes de los análisis interrumpidos ya están respondiendo
+		grace, cancelGrace := context.WithTimeout(context.Background(), closeGrace)
+		defer cancelGrace()
+		if err := server.Shutdown(grace); err != nil {
+			slog.Warn("cerrando conexiones que siguen activas", "error", err)
+			server.Close()
+		}
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
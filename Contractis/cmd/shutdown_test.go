@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	httpAdapter "github.com/rodascaar/contractis/internal/adapters/http"
+	"github.com/rodascaar/contractis/internal/adapters/http/router"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// testServer arma un servidor que solo sirve archivos estáticos en un puerto libre
+func testServer(t *testing.T) (*httpAdapter.Server, string) {
+	t.Helper()
+	static := t.TempDir()
+	if err := os.WriteFile(filepath.Join(static, "index.html"), []byte("<html></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	appRouter := router.NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, static)
+	return httpAdapter.NewServer(addr, appRouter, httpAdapter.ServerOptions{}), "http://" + addr + "/"
+}
+
+// waitServing espera a que el servidor responda
+func waitServing(t *testing.T, url string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// serve ejecuta serveUntilSignal en segundo plano y retorna la señal y su resultado
+func serve(t *testing.T, server *httpAdapter.Server, jobs *usecases.JobTracker, timeout time.Duration) (context.CancelFunc, <-chan error) {
+	t.Helper()
+	ctx, signal := context.WithCancel(context.Background())
+	t.Cleanup(signal)
+	result := make(chan error, 1)
+	go func() {
+		result <- serveUntilSignal(ctx, server, jobs, timeout)
+	}()
+	return signal, result
+}
+
+func TestServeUntilSignalWaitsForJobs(t *testing.T) {
+	server, url := testServer(t)
+	jobs := usecases.NewJobTracker()
+	signal, result := serve(t, server, jobs, 5*time.Second)
+	waitServing(t, url)
+
+	jobCtx, done, err := jobs.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signal()
+
+	// Con la señal recibida no se aceptan análisis nuevos ni conexiones
+	deadline := time.Now().Add(5 * time.Second)
+	for !jobs.Draining() {
+		if time.Now().After(deadline) {
+			t.Fatal("the server did not start draining after the signal")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, _, err := jobs.Begin(context.Background()); !errors.Is(err, entities.ErrShuttingDown) {
+		t.Errorf("Begin after the signal = %v, want ErrShuttingDown", err)
+	}
+	select {
+	case err := <-result:
+		t.Fatalf("serveUntilSignal returned %v with an analysis still running", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if jobCtx.Err() != nil {
+		t.Errorf("the analysis was cancelled before the timeout: %v", jobCtx.Err())
+	}
+
+	done()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("serveUntilSignal = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveUntilSignal did not return after the analysis finished")
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("the server still accepts connections after shutting down")
+	}
+}
+
+// TestServeUntilSignalInterruptsAtDeadline cancela los análisis que siguen al vencer el plazo
+func TestServeUntilSignalInterruptsAtDeadline(t *testing.T) {
+	server, url := testServer(t)
+	jobs := usecases.NewJobTracker()
+	signal, result := serve(t, server, jobs, 50*time.Millisecond)
+	waitServing(t, url)
+
+	jobCtx, done, err := jobs.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	interrupted := make(chan error, 1)
+	go func() {
+		defer done()
+		<-jobCtx.Done()
+		interrupted <- jobCtx.Err()
+	}()
+
+	start := time.Now()
+	signal()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("serveUntilSignal = %v, want nil after interrupting the analysis", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveUntilSignal did not return after the timeout")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("serveUntilSignal returned after %v, before the timeout", elapsed)
+	}
+	select {
+	case err := <-interrupted:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("analysis context error = %v, want Canceled", err)
+		}
+	default:
+		t.Error("the running analysis was not cancelled at the deadline")
+	}
+}
+
+func TestServeUntilSignalReturnsStartError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	appRouter := router.NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, t.TempDir())
+	server := httpAdapter.NewServer(listener.Addr().String(), appRouter, httpAdapter.ServerOptions{})
+
+	err = serveUntilSignal(context.Background(), server, usecases.NewJobTracker(), time.Second)
+	if err == nil || errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("serveUntilSignal on a busy port = %v, want the listen error", err)
+	}
+}
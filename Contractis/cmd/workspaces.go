@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+const usersUsage = `Uso:
+  contractis users create -email EMAIL [-name NOMBRE]
+  contractis users list`
+
+const workspacesUsage = `Uso:
+  contractis workspaces create -name NOMBRE
+  contractis workspaces list
+  contractis workspaces add-member -workspace ID -email EMAIL -role viewer|analyst|admin
+  contractis workspaces remove-member -workspace ID -email EMAIL`
+
+// runUsersCommand gestiona los usuarios desde la línea de comandos y retorna el código de salida
+func runUsersCommand(ctx context.Context, open func() *app, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usersUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("users create", flag.ContinueOnError)
+		email := fs.String("email", "", "email del usuario")
+		name := fs.String("name", "", "nombre visible")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		user, err := open().workspaces.CreateUser(ctx, *email, *name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error creando usuario: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Usuario creado: %s (ID %d)\n", user.Email, user.ID)
+		return 0
+
+	case "list":
+		users, err := open().workspaces.ListUsers(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error listando usuarios: %v\n", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tEMAIL\tNOMBRE\tCREADO")
+		for _, user := range users {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", user.ID, user.Email, user.Name, user.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		tw.Flush()
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, usersUsage)
+		return 2
+	}
+}
+
+// runWorkspacesCommand gestiona workspaces y miembros desde la línea de comandos
+func runWorkspacesCommand(ctx context.Context, open func() *app, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, workspacesUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("workspaces create", flag.ContinueOnError)
+		name := fs.String("name", "", "nombre del workspace")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		workspace, err := open().workspaces.CreateWorkspace(ctx, *name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error creando workspace: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Workspace creado: %s (ID %d)\n", workspace.Name, workspace.ID)
+		return 0
+
+	case "list":
+		list, err := open().workspaces.ListWorkspaces(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error listando workspaces: %v\n", err)
+			return 1
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNOMBRE\tCREADO")
+		for _, workspace := range list {
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", workspace.ID, workspace.Name, workspace.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		tw.Flush()
+		return 0
+
+	case "add-member", "remove-member":
+		fs := flag.NewFlagSet("workspaces "+args[0], flag.ContinueOnError)
+		workspaceID := fs.Int64("workspace", 0, "ID del workspace")
+		email := fs.String("email", "", "email del usuario")
+		role := fs.String("role", string(entities.RoleViewer), "rol: viewer, analyst o admin")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+
+		workspaces := open().workspaces
+		var err error
+		if args[0] == "add-member" {
+			err = workspaces.SetMember(ctx, *workspaceID, *email, entities.Role(*role))
+		} else {
+			err = workspaces.RemoveMember(ctx, *workspaceID, *email)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error actualizando miembros: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Workspace %s actualizado: %s\n", strconv.FormatInt(*workspaceID, 10), *email)
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, workspacesUsage)
+		return 2
+	}
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/rodascaar/contractis/internal/domain/entities"
+
 // LLMConfigRequest representa la configuración del LLM en las peticiones HTTP
 type LLMConfigRequest struct {
 	Type      string `json:"type"`
@@ -8,4 +10,122 @@ type LLMConfigRequest struct {
 	ApiKey    string `json:"apiKey"`
 	ModelName string `json:"modelName"`
 	MaxTokens int    `json:"maxTokens"`
+
+	// Concurrency es opcional; cero usa el valor por defecto del proveedor
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// Límites opcionales por minuto para la API key
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int `json:"tokensPerMinute,omitempty"`
+
+	// Fallbacks son endpoints alternativos en orden de preferencia
+	Fallbacks []LLMConfigRequest `json:"fallbacks,omitempty"`
+
+	// FailoverOn: "connection", "timeout", "rate_limit" (vacío = todas)
+	FailoverOn []string `json:"failoverOn,omitempty"`
+}
+
+// ToEntity convierte el DTO (incluidos los fallbacks) a la entidad de dominio
+func (req LLMConfigRequest) ToEntity() *entities.LLMConfig {
+	config := entities.NewLLMConfig(
+		req.Type,
+		req.LocalUrl,
+		req.ApiUrl,
+		req.ApiKey,
+		req.ModelName,
+		req.MaxTokens,
+	)
+	config.Concurrency = req.Concurrency
+	config.RequestsPerMinute = req.RequestsPerMinute
+	config.TokensPerMinute = req.TokensPerMinute
+
+	for _, condition := range req.FailoverOn {
+		config.FailoverOn = append(config.FailoverOn, entities.FailoverCondition(condition))
+	}
+	for _, fallback := range req.Fallbacks {
+		config.Fallbacks = append(config.Fallbacks, fallback.ToEntity())
+	}
+
+	return config
+}
+
+// LLMProfileRequest representa la creación o actualización de un perfil LLM.
+// En una actualización, un apiKey vacío conserva la key guardada salvo que clearApiKey sea true.
+// Personal solo cuenta al crear: el perfil queda para quien lo crea (los de un analyst siempre lo son).
+type LLMProfileRequest struct {
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	LocalUrl          string   `json:"localUrl"`
+	ApiUrl            string   `json:"apiUrl"`
+	ApiKey            string   `json:"apiKey"`
+	ClearApiKey       bool     `json:"clearApiKey,omitempty"`
+	ModelName         string   `json:"modelName"`
+	MaxTokens         int      `json:"maxTokens"`
+	Concurrency       int      `json:"concurrency,omitempty"`
+	RequestsPerMinute int      `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int      `json:"tokensPerMinute,omitempty"`
+	FailoverOn        []string `json:"failoverOn,omitempty"`
+	FallbackIDs       []int64  `json:"fallbackIds,omitempty"`
+	Personal          bool     `json:"personal,omitempty"`
+}
+
+// LegalHoldRequest activa (hold=true) o libera el legal hold de un contrato
+type LegalHoldRequest struct {
+	Hold   bool   `json:"hold"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// WebhookRequest representa la creación de un webhook; active es opcional (true por defecto)
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description,omitempty"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// ToEntity convierte el DTO al webhook del workspace indicado
+func (req WebhookRequest) ToEntity(workspaceID int64) *entities.Webhook {
+	webhook := &entities.Webhook{
+		WorkspaceID: workspaceID,
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      webhookEvents(req.Events),
+		Active:      true,
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	return webhook
+}
+
+// WebhookUpdateRequest representa un PATCH de un webhook: los campos omitidos no cambian y
+// rotate_secret genera un secreto de firma nuevo
+type WebhookUpdateRequest struct {
+	URL          *string  `json:"url,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	Events       []string `json:"events,omitempty"`
+	Active       *bool    `json:"active,omitempty"`
+	RotateSecret bool     `json:"rotate_secret,omitempty"`
+}
+
+// ToEntity convierte el DTO a los cambios de dominio
+func (req WebhookUpdateRequest) ToEntity() entities.WebhookUpdate {
+	return entities.WebhookUpdate{
+		URL:          req.URL,
+		Description:  req.Description,
+		Events:       webhookEvents(req.Events),
+		Active:       req.Active,
+		RotateSecret: req.RotateSecret,
+	}
+}
+
+func webhookEvents(events []string) []entities.WebhookEvent {
+	if events == nil {
+		return nil
+	}
+	converted := make([]entities.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, entities.WebhookEvent(event))
+	}
+	return converted
 }
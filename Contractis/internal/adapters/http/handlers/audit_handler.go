@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// AuditHandler expone el log de auditoría a los administradores (scope admin)
+type AuditHandler struct {
+	auditUseCase *usecases.AuditUseCase
+}
+
+// NewAuditHandler crea una nueva instancia de AuditHandler
+func NewAuditHandler(auditUseCase *usecases.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// HandleList consulta el log. Filtros: workspace, user, contract, action, since, until, limit, offset.
+func (h *AuditHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := auditFilterFromQuery(r, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.auditUseCase.List(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error consultando auditoría", "error", err)
+		http.Error(w, "Error al consultar la auditoría", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    entries,
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	})
+}
+
+// HandleExport descarga el log filtrado como CSV (por defecto) o JSON (?format=json).
+// Sin limit se exportan todas las entradas que cumplen el filtro.
+func (h *AuditHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := auditFilterFromQuery(r, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.auditUseCase.List(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error exportando auditoría", "error", err)
+		http.Error(w, "Error al exportar la auditoría", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("auditoria-%s.%s", time.Now().Format("20060102-150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "timestamp", "actor", "user_id", "workspace_id", "action", "contract_id", "ip", "details", "prev_hash", "hash"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Timestamp.Format(time.RFC3339Nano),
+			entry.Actor,
+			strconv.FormatInt(entry.UserID, 10),
+			strconv.FormatInt(entry.WorkspaceID, 10),
+			string(entry.Action),
+			strconv.FormatInt(entry.ContractID, 10),
+			entry.IP,
+			entry.Details,
+			entry.PrevHash,
+			entry.Hash,
+		})
+	}
+	writer.Flush()
+}
+
+// HandleVerify recorre la cadena de hashes y reporta la primera entrada alterada
+func (h *AuditHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := h.auditUseCase.Verify(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error verificando auditoría", "error", err)
+		http.Error(w, "Error al verificar la auditoría", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// auditFilterFromQuery interpreta los filtros de la query. since/until aceptan
+// RFC 3339 o una fecha YYYY-MM-DD; until es exclusivo.
+func auditFilterFromQuery(r *http.Request, defaultLimit int) (entities.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := entities.AuditFilter{
+		Action: entities.AuditAction(query.Get("action")),
+		Limit:  defaultLimit,
+	}
+
+	ids := map[string]*int64{
+		"workspace": &filter.WorkspaceID,
+		"user":      &filter.UserID,
+		"contract":  &filter.ContractID,
+	}
+	for name, target := range ids {
+		if value := query.Get(name); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return filter, fmt.Errorf("invalid %s", name)
+			}
+			*target = id
+		}
+	}
+
+	times := map[string]*time.Time{
+		"since": &filter.Since,
+		"until": &filter.Until,
+	}
+	for name, target := range times {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				t, err = time.ParseInLocation("2006-01-02", value, time.Local)
+			}
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s (use RFC 3339 or YYYY-MM-DD)", name)
+			}
+			*target = t
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = min(limit, 10000)
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
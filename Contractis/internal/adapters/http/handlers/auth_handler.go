@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// AuthHandler maneja las sesiones del cliente web: intercambia una API key por un
+// token de sesión firmado que se guarda en una cookie HttpOnly
+type AuthHandler struct {
+	sessions services.SessionIssuer
+}
+
+// NewAuthHandler crea una nueva instancia de AuthHandler
+func NewAuthHandler(sessions services.SessionIssuer) *AuthHandler {
+	return &AuthHandler{
+		sessions: sessions,
+	}
+}
+
+// HandleSession crea una sesión para el principal autenticado. Debe montarse detrás del middleware Auth.
+func (h *AuthHandler) HandleSession(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := entities.PrincipalFromContext(r.Context())
+	token, expiresAt, err := h.sessions.IssueSession(principal)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error emitiendo sesión", "error", err)
+		http.Error(w, "Error al crear sesión", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     entities.SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	slog.InfoContext(r.Context(), "sesión iniciada", "subject", principal.Subject)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":   true,
+		"token":     token,
+		"expiresAt": expiresAt,
+		"principal": principal,
+	})
+}
+
+// HandleLogout borra la cookie de sesión. No requiere autenticación para poder
+// limpiar también sesiones expiradas.
+func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "POST" && r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     entities.SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleMe retorna el principal autenticado
+func (h *AuthHandler) HandleMe(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    entities.PrincipalFromContext(r.Context()),
+	})
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/archive"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// BatchHandler maneja la subida por lotes: varios PDF o archivos ZIP en una sola
+// petición, analizados en segundo plano. Crear un lote requiere rol analyst y
+// consultarlo o exportarlo, viewer.
+type BatchHandler struct {
+	batchUseCase    *usecases.BatchUseCase
+	profilesUseCase *usecases.LLMProfilesUseCase
+	workspaces      *usecases.WorkspaceUseCase
+	limits          entities.Limits
+}
+
+// NewBatchHandler crea una nueva instancia de BatchHandler
+func NewBatchHandler(
+	batchUseCase *usecases.BatchUseCase,
+	profilesUseCase *usecases.LLMProfilesUseCase,
+	workspaces *usecases.WorkspaceUseCase,
+	limits entities.Limits,
+) *BatchHandler {
+	return &BatchHandler{
+		batchUseCase:    batchUseCase,
+		profilesUseCase: profilesUseCase,
+		workspaces:      workspaces,
+		limits:          limits,
+	}
+}
+
+// HandleCreate recibe los archivos del campo files (PDF o ZIP) con la misma configuración
+// LLM que /upload (profileId o llmConfig) y responde 202 con el lote creado
+func (h *BatchHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.limits.MaxBatchUploadSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("El lote supera el tamaño máximo de subida (%dMB)",
+				h.limits.MaxBatchUploadSize/(1024*1024)), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Se esperaba un formulario multipart con los archivos en 'files'", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	uploads := r.MultipartForm.File["files"]
+	if len(uploads) == 0 {
+		http.Error(w, "No se recibieron archivos en 'files'", http.StatusBadRequest)
+		return
+	}
+
+	llmConfig, err := resolveLLMConfig(r, h.profilesUseCase, membership)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "contractis-lote-*")
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error creando el directorio del lote", "error", err)
+		http.Error(w, "Error al crear el directorio temporal", http.StatusInternalServerError)
+		return
+	}
+
+	collector := archive.NewCollector(dir, h.limits)
+	for _, upload := range uploads {
+		if err := addUpload(collector, upload); err != nil {
+			os.RemoveAll(dir)
+			h.sendUploadError(w, r, upload.Filename, err)
+			return
+		}
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = uploads[0].Filename
+		if len(uploads) > 1 {
+			name += fmt.Sprintf(" y %d más", len(uploads)-1)
+		}
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+
+	ctx := entities.WithRequester(r.Context(), requesterFromRequest(r))
+	batch, err := h.batchUseCase.Start(ctx, membership.WorkspaceID, name, collector.Documents(), dir, llmConfig)
+	if err != nil {
+		os.RemoveAll(dir)
+		if errors.Is(err, entities.ErrShuttingDown) {
+			w.Header().Set("Retry-After", shutdownRetryAfter)
+			http.Error(w, "El servidor se está deteniendo; reintente en unos segundos", http.StatusServiceUnavailable)
+			return
+		}
+		slog.ErrorContext(r.Context(), "error creando el lote", "error", err)
+		http.Error(w, "Error al crear el lote", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/api/batches/get?id=%d", batch.ID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    batch,
+	})
+}
+
+// addUpload agrega un archivo del formulario: los ZIP se expanden, el resto se trata como PDF
+func addUpload(collector *archive.Collector, upload *multipart.FileHeader) error {
+	file, err := upload.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(strings.ToLower(upload.Filename), ".zip") {
+		return collector.AddZip(upload.Filename, file, upload.Size)
+	}
+	return collector.AddFile(upload.Filename, file)
+}
+
+func (h *BatchHandler) sendUploadError(w http.ResponseWriter, r *http.Request, filename string, err error) {
+	slog.WarnContext(r.Context(), "lote rechazado", "file", filename, "error", err)
+	switch {
+	case errors.Is(err, entities.ErrTooManyDocuments):
+		http.Error(w, fmt.Sprintf("El lote supera el máximo de %d documentos o %d entradas por ZIP",
+			h.limits.MaxBatchDocuments, h.limits.MaxBatchEntries), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, entities.ErrBatchTooLarge):
+		http.Error(w, fmt.Sprintf("El lote descomprimido supera el máximo de %dMB",
+			h.limits.MaxBatchExtractedSize/(1024*1024)), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, entities.ErrInvalidArchive):
+		http.Error(w, "ZIP inválido o dañado: "+filename, http.StatusBadRequest)
+	default:
+		http.Error(w, "Error al procesar "+filename, http.StatusInternalServerError)
+	}
+}
+
+// HandleList lista los lotes del workspace con su avance
+func (h *BatchHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	limit := 20
+	offset := 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	batches, err := h.batchUseCase.List(r.Context(), membership.WorkspaceID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listando lotes", "error", err)
+		http.Error(w, "Error al obtener los lotes", http.StatusInternalServerError)
+		return
+	}
+	if batches == nil {
+		batches = []*entities.Batch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    batches,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// HandleGetByID obtiene un lote con el estado de cada documento
+func (h *BatchHandler) HandleGetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	batch, err := h.batchUseCase.Get(r.Context(), membership.WorkspaceID, id)
+	if err != nil {
+		h.sendLookupError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    batch,
+	})
+}
+
+// HandleExport descarga en un solo texto el resumen del lote y los análisis de sus documentos
+func (h *BatchHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	batch, export, err := h.batchUseCase.Export(r.Context(), membership.WorkspaceID, id)
+	if err != nil {
+		h.sendLookupError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"lote-%d.txt\"", batch.ID))
+	io.WriteString(w, export)
+}
+
+func (h *BatchHandler) sendLookupError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, entities.ErrBatchNotFound) {
+		http.Error(w, "Lote no encontrado", http.StatusNotFound)
+		return
+	}
+	slog.ErrorContext(r.Context(), "error obteniendo el lote", "error", err)
+	http.Error(w, "Error al obtener el lote", http.StatusInternalServerError)
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Límites de página de la API v1
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// HandleV1List lista los contratos del workspace con paginación por cursor:
+// GET /api/v1/contracts?limit=&cursor=&q=. La respuesta incluye next_cursor (y un
+// header Link rel="next") mientras queden contratos.
+func (h *HistoryHandler) HandleV1List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, "limit debe estar entre 1 y "+strconv.Itoa(maxPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	var beforeID int64
+	if cursor := query.Get("cursor"); cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidCursor, "Cursor inválido")
+			return
+		}
+		beforeID = id
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	// Se pide un contrato extra para saber si hay página siguiente
+	contracts, err := h.contractRepo.ListPage(r.Context(), membership.WorkspaceID, strings.TrimSpace(query.Get("q")), beforeID, limit+1)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listando contratos", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "Error al obtener contratos")
+		return
+	}
+
+	response := map[string]interface{}{
+		"data": contracts,
+	}
+	if len(contracts) > limit {
+		contracts = contracts[:limit]
+		next := encodeCursor(contracts[len(contracts)-1].ID)
+		response["data"] = contracts
+		response["next_cursor"] = next
+
+		nextQuery := url.Values{}
+		for key, values := range query {
+			nextQuery[key] = values
+		}
+		nextQuery.Set("cursor", next)
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+nextQuery.Encode()+`>; rel="next"`)
+	}
+	if contracts == nil {
+		response["data"] = []interface{}{}
+	}
+
+	writeJSONWithETag(w, r, response)
+}
+
+// HandleV1Get obtiene un contrato: GET /api/v1/contracts/{id}. Responde 304 si el
+// cliente ya tiene la versión actual (If-None-Match).
+func (h *HistoryHandler) HandleV1Get(w http.ResponseWriter, r *http.Request) {
+	contract, workspaceID, ok := h.contractFromPath(w, r, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	// Una revalidación que responde 304 no entrega el análisis, así que no se audita
+	if writeJSONWithETag(w, r, map[string]interface{}{"data": contract}) {
+		h.auditor.Record(r.Context(), entities.AuditView, workspaceID, contract.ID, contract.Filename)
+	}
+}
+
+// HandleV1Export descarga el análisis: GET /api/v1/contracts/{id}/export
+func (h *HistoryHandler) HandleV1Export(w http.ResponseWriter, r *http.Request) {
+	contract, workspaceID, ok := h.contractFromPath(w, r, entities.RoleViewer)
+	if !ok {
+		return
+	}
+	if contract.Status != entities.StatusCompleted {
+		problem.Write(w, r, http.StatusConflict, "El contrato no tiene un análisis completado")
+		return
+	}
+
+	h.auditor.Record(r.Context(), entities.AuditExport, workspaceID, contract.ID, contract.Filename)
+	writeExport(w, contract)
+}
+
+// HandleV1Delete envía un contrato a la papelera: DELETE /api/v1/contracts/{id} → 204
+func (h *HistoryHandler) HandleV1Delete(w http.ResponseWriter, r *http.Request) {
+	contract, workspaceID, ok := h.contractFromPath(w, r, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	if err := h.contractRepo.Delete(r.Context(), workspaceID, contract.ID); err != nil {
+		slog.ErrorContext(r.Context(), "error eliminando contrato", "contract_id", contract.ID, "error", err)
+		writeContractProblem(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "contrato enviado a la papelera", "contract_id", contract.ID)
+	h.auditor.Record(r.Context(), entities.AuditDelete, workspaceID, contract.ID, contract.Filename)
+	h.publishDeleted(r, workspaceID, contract.ID, contract.Filename, false)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleV1NotFound responde 404 problem+json para rutas inexistentes bajo /api/v1/
+func HandleV1NotFound(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, http.StatusNotFound, "Recurso inexistente")
+}
+
+// contractFromPath resuelve el contrato {id} de la ruta en el workspace del usuario
+func (h *HistoryHandler) contractFromPath(w http.ResponseWriter, r *http.Request, role entities.Role) (*entities.ContractRecord, int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, "ID de contrato inválido")
+		return nil, 0, false
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, role)
+	if !ok {
+		return nil, 0, false
+	}
+
+	contract, err := h.contractRepo.GetByID(r.Context(), membership.WorkspaceID, id)
+	if err != nil {
+		if !errors.Is(err, entities.ErrContractNotFound) {
+			slog.ErrorContext(r.Context(), "error obteniendo contrato", "error", err)
+		}
+		writeContractProblem(w, r, err)
+		return nil, 0, false
+	}
+	return contract, membership.WorkspaceID, true
+}
+
+// writeContractProblem traduce los errores del repositorio de contratos a problem+json
+func writeContractProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, entities.ErrContractNotFound):
+		problem.Write(w, r, http.StatusNotFound, "Contrato no encontrado")
+	case errors.Is(err, entities.ErrLegalHold):
+		problem.WriteType(w, r, http.StatusConflict, problem.TypeLegalHold, "El contrato está bajo legal hold")
+	default:
+		problem.Write(w, r, http.StatusInternalServerError, "Error al acceder al contrato")
+	}
+}
+
+// writeJSONWithETag responde v como JSON con un ETag fuerte calculado sobre el cuerpo.
+// Si el cliente envía If-None-Match con ese ETag responde 304 sin cuerpo y retorna false.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := json.Marshal(v)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error codificando la respuesta", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "Error al generar la respuesta")
+		return false
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	// Las respuestas dependen de la credencial: solo caché privada y siempre revalidada
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Vary", "Authorization, X-API-Key, Cookie, X-Workspace-ID")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+	return true
+}
+
+// etagMatches compara If-None-Match (lista de ETags o "*") con comparación débil, como indica RFC 9110
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCursor y decodeCursor hacen opaco el cursor de paginación (el último ID entregado)
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("id:" + strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	idStr, ok := strings.CutPrefix(string(data), "id:")
+	if !ok {
+		return 0, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return id, nil
+}
@@ -2,8 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -16,12 +17,14 @@ import (
 // EstimateHandler maneja las solicitudes de estimación de tokens
 type EstimateHandler struct {
 	estimateUseCase *usecases.EstimateTokensUseCase
+	limits          entities.Limits
 }
 
 // NewEstimateHandler crea una nueva instancia de EstimateHandler
-func NewEstimateHandler(estimateUseCase *usecases.EstimateTokensUseCase) *EstimateHandler {
+func NewEstimateHandler(estimateUseCase *usecases.EstimateTokensUseCase, limits entities.Limits) *EstimateHandler {
 	return &EstimateHandler{
 		estimateUseCase: estimateUseCase,
+		limits:          limits,
 	}
 }
 
@@ -29,7 +32,7 @@ func NewEstimateHandler(estimateUseCase *usecases.EstimateTokensUseCase) *Estima
 func (h *EstimateHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 	if r.Method == "OPTIONS" {
 		w.WriteHeader(http.StatusOK)
@@ -37,27 +40,27 @@ func (h *EstimateHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.Method != "POST" {
-		h.sendError(w, "Method not allowed")
+		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Obtener archivo
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		h.sendError(w, "Error al obtener el archivo")
+		h.sendError(w, http.StatusBadRequest, "Error al obtener el archivo")
 		return
 	}
 	defer file.Close()
 
 	// Validar tamaño
-	if header.Size > entities.MaxFileSize {
-		h.sendError(w, "Archivo demasiado grande (máximo 10MB)")
+	if header.Size > h.limits.MaxFileSize {
+		h.sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Archivo demasiado grande (máximo %dMB)", h.limits.FileSizeMB()))
 		return
 	}
 
 	// Validar tipo
 	if !strings.HasSuffix(strings.ToLower(header.Filename), ".pdf") {
-		h.sendError(w, "Solo se permiten archivos PDF")
+		h.sendError(w, http.StatusUnsupportedMediaType, "Solo se permiten archivos PDF")
 		return
 	}
 
@@ -73,7 +76,7 @@ func (h *EstimateHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// Guardar archivo temporal
 	tempFile, err := os.CreateTemp("", "estimate-*.pdf")
 	if err != nil {
-		h.sendError(w, "Error al crear archivo temporal")
+		h.sendError(w, http.StatusInternalServerError, "Error al crear archivo temporal")
 		return
 	}
 	defer os.Remove(tempFile.Name())
@@ -81,20 +84,20 @@ func (h *EstimateHandler) Handle(w http.ResponseWriter, r *http.Request) {
 
 	_, err = io.Copy(tempFile, file)
 	if err != nil {
-		h.sendError(w, "Error al guardar el archivo")
+		h.sendError(w, http.StatusInternalServerError, "Error al guardar el archivo")
 		return
 	}
 
 	// Ejecutar estimación
 	estimation, err := h.estimateUseCase.Execute(tempFile.Name(), maxTokens)
 	if err != nil {
-		log.Printf("Error en estimación: %v", err)
-		h.sendError(w, "Error al extraer texto del PDF")
+		slog.WarnContext(r.Context(), "error en estimación", "error", err)
+		h.sendError(w, http.StatusUnprocessableEntity, "Error al extraer texto del PDF")
 		return
 	}
 
-	log.Printf("Estimación: %d tokens totales, %d chunks, maxTokens recomendado: %d",
-		estimation.TotalTokens, estimation.Chunks, estimation.RecommendedMaxTokens)
+	slog.InfoContext(r.Context(), "estimación",
+		"tokens", estimation.TotalTokens, "chunks", estimation.Chunks, "recommended_max_tokens", estimation.RecommendedMaxTokens)
 
 	// Convertir a DTO de respuesta
 	response := dto.TokenEstimationResponse{
@@ -112,10 +115,13 @@ func (h *EstimateHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		Error:                estimation.Error,
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *EstimateHandler) sendError(w http.ResponseWriter, message string) {
+func (h *EstimateHandler) sendError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(dto.TokenEstimationResponse{
 		Success: false,
 		Error:   message,
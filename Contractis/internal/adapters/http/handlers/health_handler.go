@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// HealthHandler expone los chequeos de liveness y readiness para orquestadores y
+// balanceadores (Kubernetes, Docker, etc.)
+type HealthHandler struct {
+	healthUseCase *usecases.HealthUseCase
+}
+
+// NewHealthHandler crea una nueva instancia de HealthHandler
+func NewHealthHandler(healthUseCase *usecases.HealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// HandleLiveness responde 200 mientras el proceso atiende peticiones, sin revisar sus
+// dependencias: un fallo de la base no debe hacer que el orquestador reinicie el proceso
+func (h *HealthHandler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.healthUseCase.Liveness())
+}
+
+// HandleReadiness revisa la base de datos, el almacenamiento y (si está habilitado) los
+// perfiles LLM. Responde 200 si el servicio puede recibir tráfico (up o degraded) y 503 si no.
+func (h *HealthHandler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.healthUseCase.Readiness(r.Context()))
+}
+
+func writeHealthReport(w http.ResponseWriter, report *entities.HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !report.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/database"
+	"github.com/rodascaar/contractis/internal/infrastructure/health"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// fakeCheck implementa services.HealthCheck con un resultado fijo; slow espera al plazo
+type fakeCheck struct {
+	name     string
+	critical bool
+	err      error
+	slow     bool
+}
+
+func (c fakeCheck) Name() string   { return c.name }
+func (c fakeCheck) Critical() bool { return c.critical }
+
+func (c fakeCheck) Check(ctx context.Context) (map[string]any, error) {
+	if c.slow {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, c.err
+}
+
+const checkTimeout = 50 * time.Millisecond
+
+func readiness(t *testing.T, jobs *usecases.JobTracker, checks ...services.HealthCheck) (int, *entities.HealthReport) {
+	t.Helper()
+	handler := NewHealthHandler(usecases.NewHealthUseCase(entities.BuildInfo{Version: "test"}, jobs, checkTimeout, checks...))
+	w := httptest.NewRecorder()
+	handler.HandleReadiness(w, httptest.NewRequest("GET", "/readyz", nil))
+
+	var report entities.HealthReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v\n%s", err, w.Body.String())
+	}
+	return w.Code, &report
+}
+
+func component(report *entities.HealthReport, name string) entities.ComponentHealth {
+	for _, c := range report.Components {
+		if c.Name == name {
+			return c
+		}
+	}
+	return entities.ComponentHealth{}
+}
+
+// TestReadinessFailsOnAnyCriticalCheck responde 503 si un solo chequeo crítico falla o no
+// responde a tiempo, y 200 (degraded) si solo falla uno no crítico
+func TestReadinessFailsOnAnyCriticalCheck(t *testing.T) {
+	db, err := database.NewSQLiteDB(filepath.Join(t.TempDir(), "contractis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dbCheck := health.NewDatabaseCheck(db)
+	uploads := health.NewStorageCheck("uploads", t.TempDir())
+
+	// Una carpeta bajo un archivo no se puede crear
+	file := filepath.Join(t.TempDir(), "archivo")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unwritable := health.NewStorageCheck("reports", filepath.Join(file, "reportes"))
+
+	tests := []struct {
+		name      string
+		checks    []services.HealthCheck
+		code      int
+		status    entities.HealthStatus
+		failing   string
+		errorText string
+	}{
+		{
+			name:   "all checks pass",
+			checks: []services.HealthCheck{dbCheck, uploads},
+			code:   http.StatusOK,
+			status: entities.HealthUp,
+		},
+		{
+			name:      "storage not writable",
+			checks:    []services.HealthCheck{dbCheck, uploads, unwritable},
+			code:      http.StatusServiceUnavailable,
+			status:    entities.HealthDown,
+			failing:   "reports",
+			errorText: "not writable",
+		},
+		{
+			name:      "slow critical check",
+			checks:    []services.HealthCheck{dbCheck, fakeCheck{name: "queue", critical: true, slow: true}, uploads},
+			code:      http.StatusServiceUnavailable,
+			status:    entities.HealthDown,
+			failing:   "queue",
+			errorText: "no response within " + checkTimeout.String(),
+		},
+		{
+			name:      "non-critical failure only degrades",
+			checks:    []services.HealthCheck{dbCheck, fakeCheck{name: "llm", err: errors.New("connection refused")}},
+			code:      http.StatusOK,
+			status:    entities.HealthDegraded,
+			failing:   "llm",
+			errorText: "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			code, report := readiness(t, nil, tt.checks...)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("readiness took %v, checks were not bounded by the timeout", elapsed)
+			}
+			if code != tt.code || report.Status != tt.status {
+				t.Errorf("readiness = %d %s, want %d %s", code, report.Status, tt.code, tt.status)
+			}
+			if len(report.Components) != len(tt.checks) {
+				t.Errorf("report has %d components, want %d", len(report.Components), len(tt.checks))
+			}
+			for _, c := range report.Components {
+				if c.Name == tt.failing {
+					if c.Status != entities.HealthDown || !strings.Contains(c.Error, tt.errorText) {
+						t.Errorf("%s = %s %q, want down with %q", c.Name, c.Status, c.Error, tt.errorText)
+					}
+				} else if c.Status != entities.HealthUp {
+					t.Errorf("%s = %s %q, want up", c.Name, c.Status, c.Error)
+				}
+			}
+		})
+	}
+
+	// La base informa la versión del esquema
+	_, report := readiness(t, nil, dbCheck)
+	if details := component(report, "database").Details; details["schemaVersion"] != details["expectedSchemaVersion"] {
+		t.Errorf("database details = %v, want matching schema versions", details)
+	}
+}
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	jobs := usecases.NewJobTracker()
+	if code, report := readiness(t, jobs); code != http.StatusOK || component(report, "server").Status != entities.HealthUp {
+		t.Fatalf("readiness before shutdown = %d %+v", code, report)
+	}
+
+	jobs.Shutdown(context.Background())
+	code, report := readiness(t, jobs)
+	if code != http.StatusServiceUnavailable || report.Status != entities.HealthDown {
+		t.Errorf("readiness while draining = %d %s, want 503 down", code, report.Status)
+	}
+	if server := component(report, "server"); server.Error != entities.ErrShuttingDown.Error() {
+		t.Errorf("server component error = %q", server.Error)
+	}
+
+	// Liveness sigue respondiendo mientras se detiene
+	handler := NewHealthHandler(usecases.NewHealthUseCase(entities.BuildInfo{}, jobs, checkTimeout))
+	w := httptest.NewRecorder()
+	handler.HandleLiveness(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("liveness while draining = %d, want 200", w.Code)
+	}
+}
@@ -2,23 +2,46 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/rodascaar/contractis/internal/adapters/http/dto"
+	"github.com/rodascaar/contractis/internal/domain/entities"
 	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/usecases"
 )
 
-// HistoryHandler maneja las solicitudes de historial de contratos
+// HistoryHandler maneja las solicitudes de historial de contratos. Cada petición se
+// acota al workspace del usuario: viewer puede consultar y admin además eliminar,
+// gestionar la papelera y el legal hold.
+// Las consultas, exportaciones y eliminaciones de un contrato quedan auditadas, y las
+// eliminaciones se notifican además a los webhooks (contract.deleted).
 type HistoryHandler struct {
 	contractRepo repositories.ContractRepository
+	workspaces   *usecases.WorkspaceUseCase
+	auditor      services.AuditLogger
+	events       services.EventPublisher
 }
 
-// NewHistoryHandler crea una nueva instancia de HistoryHandler
-func NewHistoryHandler(contractRepo repositories.ContractRepository) *HistoryHandler {
+// NewHistoryHandler crea una nueva instancia de HistoryHandler; events es opcional
+func NewHistoryHandler(
+	contractRepo repositories.ContractRepository,
+	workspaces *usecases.WorkspaceUseCase,
+	auditor services.AuditLogger,
+	events services.EventPublisher,
+) *HistoryHandler {
 	return &HistoryHandler{
 		contractRepo: contractRepo,
+		workspaces:   workspaces,
+		auditor:      auditor,
+		events:       events,
 	}
 }
 
@@ -32,6 +55,11 @@ func (h *HistoryHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
 	// Parsear parámetros de paginación
 	limit := 20
 	offset := 0
@@ -49,9 +77,9 @@ func (h *HistoryHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Obtener contratos
-	contracts, err := h.contractRepo.List(r.Context(), limit, offset)
+	contracts, err := h.contractRepo.List(r.Context(), membership.WorkspaceID, limit, offset)
 	if err != nil {
-		log.Printf("Error listing contracts: %v", err)
+		slog.ErrorContext(r.Context(), "error listando contratos", "error", err)
 		http.Error(w, "Error al obtener historial", http.StatusInternalServerError)
 		return
 	}
@@ -80,6 +108,11 @@ func (h *HistoryHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
 	limit := 20
 	offset := 0
 
@@ -95,9 +128,9 @@ func (h *HistoryHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	contracts, err := h.contractRepo.Search(r.Context(), query, limit, offset)
+	contracts, err := h.contractRepo.Search(r.Context(), membership.WorkspaceID, query, limit, offset)
 	if err != nil {
-		log.Printf("Error searching contracts: %v", err)
+		slog.ErrorContext(r.Context(), "error buscando contratos", "error", err)
 		http.Error(w, "Error al buscar contratos", http.StatusInternalServerError)
 		return
 	}
@@ -133,19 +166,67 @@ func (h *HistoryHandler) HandleGetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contract, err := h.contractRepo.GetByID(r.Context(), id)
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	contract, err := h.contractRepo.GetByID(r.Context(), membership.WorkspaceID, id)
 	if err != nil {
-		log.Printf("Error getting contract: %v", err)
+		slog.ErrorContext(r.Context(), "error obteniendo contrato", "error", err)
 		http.Error(w, "Contrato no encontrado", http.StatusNotFound)
 		return
 	}
 
+	h.auditor.Record(r.Context(), entities.AuditView, membership.WorkspaceID, contract.ID, contract.Filename)
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
 		"data":    contract,
 	})
 }
 
+// HandleExport descarga el análisis de un contrato como texto plano
+func (h *HistoryHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	contract, err := h.contractRepo.GetByID(r.Context(), membership.WorkspaceID, id)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error obteniendo contrato", "error", err)
+		http.Error(w, "Contrato no encontrado", http.StatusNotFound)
+		return
+	}
+	if contract.Status != entities.StatusCompleted {
+		http.Error(w, "El contrato no tiene un análisis completado", http.StatusConflict)
+		return
+	}
+
+	h.auditor.Record(r.Context(), entities.AuditExport, membership.WorkspaceID, contract.ID, contract.Filename)
+	writeExport(w, contract)
+}
+
+// writeExport escribe el análisis de un contrato como adjunto de texto plano
+func writeExport(w http.ResponseWriter, contract *entities.ContractRecord) {
+	name := strings.TrimSuffix(filepath.Base(contract.Filename), filepath.Ext(contract.Filename))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "analisis-"+sanitizeFilename(name)+".txt"))
+	io.WriteString(w, contract.ExportText())
+}
+
 // HandleGetRecent obtiene los contratos más recientes
 func (h *HistoryHandler) HandleGetRecent(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -156,6 +237,11 @@ func (h *HistoryHandler) HandleGetRecent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
 	limit := 10
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
@@ -163,9 +249,9 @@ func (h *HistoryHandler) HandleGetRecent(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	contracts, err := h.contractRepo.GetRecent(r.Context(), limit)
+	contracts, err := h.contractRepo.GetRecent(r.Context(), membership.WorkspaceID, limit)
 	if err != nil {
-		log.Printf("Error getting recent contracts: %v", err)
+		slog.ErrorContext(r.Context(), "error obteniendo contratos recientes", "error", err)
 		http.Error(w, "Error al obtener contratos recientes", http.StatusInternalServerError)
 		return
 	}
@@ -186,9 +272,14 @@ func (h *HistoryHandler) HandleGetStats(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	stats, err := h.contractRepo.GetStats(r.Context())
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleViewer)
+	if !ok {
+		return
+	}
+
+	stats, err := h.contractRepo.GetStats(r.Context(), membership.WorkspaceID)
 	if err != nil {
-		log.Printf("Error getting stats: %v", err)
+		slog.ErrorContext(r.Context(), "error obteniendo estadísticas", "error", err)
 		http.Error(w, "Error al obtener estadísticas", http.StatusInternalServerError)
 		return
 	}
@@ -203,7 +294,7 @@ func (h *HistoryHandler) HandleGetStats(w http.ResponseWriter, r *http.Request)
 func (h *HistoryHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "DELETE, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Workspace-ID")
 	w.Header().Set("Content-Type", "application/json")
 
 	// Handle preflight
@@ -213,37 +304,238 @@ func (h *HistoryHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.Method != "DELETE" && r.Method != "POST" {
-		log.Printf("Delete handler: Method not allowed: %s", r.Method)
+		slog.DebugContext(r.Context(), "eliminación con método no permitido", "method", r.Method)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	idStr := r.URL.Query().Get("id")
 	if idStr == "" {
-		log.Printf("Delete handler: Missing ID parameter")
+		slog.DebugContext(r.Context(), "eliminación sin parámetro id")
 		http.Error(w, "ID parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		log.Printf("Delete handler: Invalid ID format: %s", idStr)
+		slog.DebugContext(r.Context(), "eliminación con id inválido", "id", idStr)
 		http.Error(w, "Invalid ID", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("Attempting to delete contract with ID: %d", id)
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	slog.DebugContext(r.Context(), "eliminando contrato", "contract_id", id, "workspace_id", membership.WorkspaceID)
+
+	// El nombre se lee antes de borrar para que la auditoría lo conserve
+	var filename string
+	if contract, err := h.contractRepo.GetByID(r.Context(), membership.WorkspaceID, id); err == nil {
+		filename = contract.Filename
+	}
 
-	if err := h.contractRepo.Delete(r.Context(), id); err != nil {
-		log.Printf("Error deleting contract ID %d: %v", id, err)
-		http.Error(w, "Error al eliminar contrato", http.StatusInternalServerError)
+	if err := h.contractRepo.Delete(r.Context(), membership.WorkspaceID, id); err != nil {
+		slog.ErrorContext(r.Context(), "error eliminando contrato", "contract_id", id, "error", err)
+		sendTrashError(w, err, "Error al eliminar contrato")
 		return
 	}
 
-	log.Printf("Successfully deleted contract ID: %d", id)
+	slog.InfoContext(r.Context(), "contrato enviado a la papelera", "contract_id", id)
+	h.auditor.Record(r.Context(), entities.AuditDelete, membership.WorkspaceID, id, filename)
+	h.publishDeleted(r, membership.WorkspaceID, id, filename, false)
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
-		"message": "Contrato eliminado exitosamente",
+		"message": "Contrato enviado a la papelera",
 	})
 }
+
+// HandleTrash lista los contratos de la papelera
+func (h *HistoryHandler) HandleTrash(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	offset := 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	contracts, err := h.contractRepo.ListTrash(r.Context(), membership.WorkspaceID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listando la papelera", "error", err)
+		http.Error(w, "Error al obtener la papelera", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    contracts,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// HandleRestore saca un contrato de la papelera
+func (h *HistoryHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	if err := h.contractRepo.Restore(r.Context(), membership.WorkspaceID, id); err != nil {
+		slog.ErrorContext(r.Context(), "error restaurando contrato", "contract_id", id, "error", err)
+		sendTrashError(w, err, "Error al restaurar contrato")
+		return
+	}
+
+	h.auditor.Record(r.Context(), entities.AuditRestore, membership.WorkspaceID, id, "")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Contrato restaurado",
+	})
+}
+
+// HandlePurge borra definitivamente un contrato de la papelera
+func (h *HistoryHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "DELETE" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	if err := h.contractRepo.Purge(r.Context(), membership.WorkspaceID, id); err != nil {
+		slog.ErrorContext(r.Context(), "error purgando contrato", "contract_id", id, "error", err)
+		sendTrashError(w, err, "Error al purgar contrato")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "contrato purgado definitivamente", "contract_id", id)
+	h.auditor.Record(r.Context(), entities.AuditPurge, membership.WorkspaceID, id, "")
+	h.publishDeleted(r, membership.WorkspaceID, id, "", true)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Contrato eliminado definitivamente",
+	})
+}
+
+// HandleLegalHold activa o libera el legal hold de un contrato
+func (h *HistoryHandler) HandleLegalHold(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "POST" && r.Method != "PUT" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.LegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if err := h.contractRepo.SetLegalHold(r.Context(), membership.WorkspaceID, id, req.Hold, reason); err != nil {
+		slog.ErrorContext(r.Context(), "error cambiando la retención legal", "contract_id", id, "error", err)
+		sendTrashError(w, err, "Error al cambiar el legal hold")
+		return
+	}
+
+	action := entities.AuditRelease
+	if req.Hold {
+		action = entities.AuditHold
+	}
+	h.auditor.Record(r.Context(), action, membership.WorkspaceID, id, reason)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":   true,
+		"legalHold": req.Hold,
+	})
+}
+
+// publishDeleted notifica contract.deleted a los webhooks del workspace
+func (h *HistoryHandler) publishDeleted(r *http.Request, workspaceID, id int64, filename string, permanent bool) {
+	if h.events != nil {
+		event := entities.NewContractDeletedEvent(r.Context(), id, filename, permanent)
+		h.events.Publish(r.Context(), workspaceID, entities.EventContractDeleted, event)
+	}
+}
+
+// sendTrashError traduce los errores de papelera y legal hold a códigos HTTP
+func sendTrashError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entities.ErrContractNotFound):
+		http.Error(w, "Contrato no encontrado", http.StatusNotFound)
+	case errors.Is(err, entities.ErrLegalHold):
+		http.Error(w, "El contrato está bajo legal hold", http.StatusConflict)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// sanitizeFilename deja solo caracteres seguros para el header Content-Disposition
+func sanitizeFilename(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+	if sanitized == "" {
+		return "contrato"
+	}
+	return sanitized
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// oidcCookieName guarda el estado firmado del login en curso
+const oidcCookieName = "contractis_oidc"
+
+// OIDCLogin provisiona al usuario de una identidad OIDC verificada
+type OIDCLogin interface {
+	Login(ctx context.Context, claims *entities.IdentityClaims) (*entities.Principal, error)
+}
+
+// OIDCHandler maneja el login del cliente web contra un proveedor OpenID Connect
+type OIDCHandler struct {
+	provider services.OIDCProvider
+	login    OIDCLogin
+	sessions services.SessionIssuer
+}
+
+// NewOIDCHandler crea una nueva instancia de OIDCHandler
+func NewOIDCHandler(provider services.OIDCProvider, login OIDCLogin, sessions services.SessionIssuer) *OIDCHandler {
+	return &OIDCHandler{
+		provider: provider,
+		login:    login,
+		sessions: sessions,
+	}
+}
+
+// HandleLogin redirige al proveedor de identidad. ?return= indica la ruta local
+// a la que volver tras el login.
+func (h *OIDCHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authURL, transaction, err := h.provider.BeginLogin(r.Context(), safeReturnPath(r.URL.Query().Get("return")))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error iniciando login OIDC", "error", err)
+		http.Error(w, "El proveedor de identidad no está disponible", http.StatusBadGateway)
+		return
+	}
+
+	// Lax: la cookie debe viajar en la redirección de vuelta desde el proveedor
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    transaction,
+		Path:     "/auth",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// HandleCallback recibe el código del proveedor, crea la sesión y vuelve a la UI
+func (h *OIDCHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// El estado es de un solo uso
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    "",
+		Path:     "/auth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		slog.WarnContext(r.Context(), "login OIDC rechazado por el proveedor", "error", providerErr, "description", query.Get("error_description"))
+		http.Error(w, "El proveedor de identidad rechazó el login", http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(oidcCookieName)
+	if err != nil {
+		http.Error(w, "Login expirado o iniciado en otro navegador, vuelve a intentarlo", http.StatusBadRequest)
+		return
+	}
+
+	claims, returnTo, err := h.provider.CompleteLogin(r.Context(), cookie.Value, query.Get("state"), query.Get("code"))
+	if err != nil {
+		slog.WarnContext(r.Context(), "login OIDC fallido", "error", err)
+		if errors.Is(err, entities.ErrOIDCLogin) {
+			http.Error(w, "No se pudo verificar el login", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Error verificando el login", http.StatusInternalServerError)
+		return
+	}
+
+	principal, err := h.login.Login(r.Context(), claims)
+	if err != nil {
+		slog.WarnContext(r.Context(), "login OIDC rechazado", "email", claims.Email, "error", err)
+		switch {
+		case errors.Is(err, entities.ErrForbidden):
+			http.Error(w, "Tu usuario no tiene acceso a ningún workspace", http.StatusForbidden)
+		case errors.Is(err, entities.ErrOIDCLogin):
+			http.Error(w, "Identidad no válida para Contractis", http.StatusUnauthorized)
+		default:
+			http.Error(w, "Error al iniciar sesión", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	token, expiresAt, err := h.sessions.IssueSession(principal)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error emitiendo sesión", "error", err)
+		http.Error(w, "Error al crear sesión", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     entities.SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	slog.InfoContext(r.Context(), "sesión OIDC iniciada", "subject", principal.Subject)
+	http.Redirect(w, r, returnTo, http.StatusFound)
+}
+
+// HandleConfig indica al cliente web qué métodos de login están disponibles.
+// Es pública y también se monta con el OIDC desactivado (handler nil).
+func (h *OIDCHandler) HandleConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"oidc":    h != nil,
+	})
+}
+
+// safeReturnPath acepta solo rutas locales para evitar redirecciones abiertas
+func safeReturnPath(value string) string {
+	if value == "" || !strings.HasPrefix(value, "/") || strings.HasPrefix(value, "//") || strings.Contains(value, `\`) {
+		return "/"
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" {
+		return "/"
+	}
+	return value
+}
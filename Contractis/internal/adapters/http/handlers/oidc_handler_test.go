@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/oidc"
+	"github.com/rodascaar/contractis/internal/infrastructure/oidc/oidctest"
+)
+
+// fakeOIDCLogin provisiona cualquier identidad verificada como el usuario 1
+type fakeOIDCLogin struct{ claims *entities.IdentityClaims }
+
+func (f *fakeOIDCLogin) Login(ctx context.Context, claims *entities.IdentityClaims) (*entities.Principal, error) {
+	f.claims = claims
+	return &entities.Principal{Subject: "user:1:" + claims.Email, UserID: 1, Scopes: []entities.Scope{entities.ScopeRead}}, nil
+}
+
+type fakeSessions struct{}
+
+func (fakeSessions) IssueSession(principal *entities.Principal) (string, time.Time, error) {
+	return "session-for-" + principal.Subject, time.Now().Add(time.Hour), nil
+}
+
+func newOIDCTest(t *testing.T) (*OIDCHandler, *fakeOIDCLogin) {
+	t.Helper()
+	issuer, server, err := oidctest.NewServer("contractis", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:      issuer.URL,
+		ClientID:    "contractis",
+		RedirectURL: "http://contractis.test/auth/callback",
+	}, bytes.Repeat([]byte{5}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := &fakeOIDCLogin{}
+	return NewOIDCHandler(provider, login, fakeSessions{}), login
+}
+
+// startLogin ejecuta /auth/login y la aprobación del emisor; retorna la cookie de la
+// transacción y la URL de callback a la que vuelve el navegador
+func startLogin(t *testing.T, h *OIDCHandler) (*http.Cookie, *url.URL) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.HandleLogin(w, httptest.NewRequest("GET", "/auth/login?return=/history", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login = %d: %s", w.Code, w.Body)
+	}
+	var transaction *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcCookieName {
+			transaction = cookie
+		}
+	}
+	if transaction == nil || !transaction.HttpOnly {
+		t.Fatalf("login cookie = %+v, want an HttpOnly transaction cookie", transaction)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transaction, callback
+}
+
+func callbackRequest(transaction *http.Cookie, callback *url.URL) *http.Request {
+	r := httptest.NewRequest("GET", "/auth/callback?"+callback.RawQuery, nil)
+	if transaction != nil {
+		r.AddCookie(transaction)
+	}
+	return r
+}
+
+func TestOIDCCallbackCreatesSession(t *testing.T) {
+	h, login := newOIDCTest(t)
+	transaction, callback := startLogin(t, h)
+
+	w := httptest.NewRecorder()
+	h.HandleCallback(w, callbackRequest(transaction, callback))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/history" {
+		t.Fatalf("callback = %d → %q: %s", w.Code, w.Header().Get("Location"), w.Body)
+	}
+	if login.claims == nil || login.claims.Email != "user@example.com" {
+		t.Errorf("login claims = %+v", login.claims)
+	}
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	session := cookies[entities.SessionCookieName]
+	if session == nil || session.Value != "session-for-user:1:user@example.com" || !session.HttpOnly {
+		t.Errorf("session cookie = %+v", session)
+	}
+	if cleared := cookies[oidcCookieName]; cleared == nil || cleared.MaxAge >= 0 {
+		t.Errorf("transaction cookie = %+v, want it cleared", cleared)
+	}
+}
+
+func TestOIDCCallbackRejectsInvalidState(t *testing.T) {
+	h, login := newOIDCTest(t)
+
+	tests := map[string]func(transaction *http.Cookie, callback *url.URL) *http.Request{
+		"wrong state": func(transaction *http.Cookie, callback *url.URL) *http.Request {
+			query := callback.Query()
+			query.Set("state", "forged")
+			callback.RawQuery = query.Encode()
+			return callbackRequest(transaction, callback)
+		},
+		"missing transaction cookie": func(_ *http.Cookie, callback *url.URL) *http.Request {
+			return callbackRequest(nil, callback)
+		},
+		"provider error": func(transaction *http.Cookie, _ *url.URL) *http.Request {
+			return callbackRequest(transaction, &url.URL{RawQuery: "error=access_denied"})
+		},
+	}
+	want := map[string]int{
+		"wrong state":                http.StatusUnauthorized,
+		"missing transaction cookie": http.StatusBadRequest,
+		"provider error":             http.StatusUnauthorized,
+	}
+	for name, request := range tests {
+		t.Run(name, func(t *testing.T) {
+			transaction, callback := startLogin(t, h)
+			w := httptest.NewRecorder()
+			h.HandleCallback(w, request(transaction, callback))
+			if w.Code != want[name] {
+				t.Errorf("callback = %d, want %d", w.Code, want[name])
+			}
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == entities.SessionCookieName {
+					t.Error("a session cookie was issued")
+				}
+			}
+		})
+	}
+	if login.claims != nil {
+		t.Errorf("login was called with %+v", login.claims)
+	}
+}
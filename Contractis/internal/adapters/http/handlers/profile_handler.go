@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/dto"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// ProfileHandler maneja el CRUD de perfiles LLM del workspace de la petición. Un analyst
+// administra sus perfiles personales y un admin, además, los compartidos del workspace.
+// Las API keys nunca se devuelven.
+type ProfileHandler struct {
+	profilesUseCase *usecases.LLMProfilesUseCase
+	workspaces      *usecases.WorkspaceUseCase
+}
+
+// NewProfileHandler crea una nueva instancia de ProfileHandler
+func NewProfileHandler(profilesUseCase *usecases.LLMProfilesUseCase, workspaces *usecases.WorkspaceUseCase) *ProfileHandler {
+	return &ProfileHandler{
+		profilesUseCase: profilesUseCase,
+		workspaces:      workspaces,
+	}
+}
+
+// HandleCollection lista los perfiles (GET) o crea uno nuevo (POST)
+func (h *ProfileHandler) HandleCollection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		profiles, err := h.profilesUseCase.List(r.Context(), membership)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "error listando perfiles LLM", "error", err)
+			http.Error(w, "Error al obtener perfiles", http.StatusInternalServerError)
+			return
+		}
+		if profiles == nil {
+			profiles = []*entities.LLMProfile{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    profiles,
+		})
+
+	case "POST":
+		var req dto.LLMProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		profile := toLLMProfile(req)
+		if req.Personal {
+			profile.OwnerID = membership.UserID
+		}
+		profile, err := h.profilesUseCase.Create(r.Context(), membership, profile)
+		if err != nil {
+			h.sendProfileError(w, r, "Error al crear perfil", err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "perfil LLM creado", "profile_id", profile.ID, "name", profile.Name, "workspace_id", profile.WorkspaceID, "personal", !profile.Shared())
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    profile,
+		})
+	}
+}
+
+// HandleGetByID obtiene un perfil por ID
+func (h *ProfileHandler) HandleGetByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := profileIDFromQuery(w, r)
+	if !ok {
+		return
+	}
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
+		return
+	}
+
+	profile, err := h.profilesUseCase.Get(r.Context(), membership, id)
+	if err != nil {
+		h.sendProfileError(w, r, "Error al obtener perfil", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// HandleUpdate reemplaza un perfil (PUT o POST)
+func (h *ProfileHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "PUT" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := profileIDFromQuery(w, r)
+	if !ok {
+		return
+	}
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
+		return
+	}
+
+	var req dto.LLMProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	profile := toLLMProfile(req)
+	profile.ID = id
+
+	updated, err := h.profilesUseCase.Update(r.Context(), membership, profile, !req.ClearApiKey)
+	if err != nil {
+		h.sendProfileError(w, r, "Error al actualizar perfil", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "perfil LLM actualizado", "profile_id", updated.ID, "name", updated.Name)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    updated,
+	})
+}
+
+// HandleDelete elimina un perfil (DELETE o POST)
+func (h *ProfileHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "DELETE" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := profileIDFromQuery(w, r)
+	if !ok {
+		return
+	}
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
+		return
+	}
+
+	if err := h.profilesUseCase.Delete(r.Context(), membership, id); err != nil {
+		h.sendProfileError(w, r, "Error al eliminar perfil", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "perfil LLM eliminado", "profile_id", id)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Perfil eliminado exitosamente",
+	})
+}
+
+// sendProfileError traduce los errores del caso de uso a códigos HTTP
+func (h *ProfileHandler) sendProfileError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	slog.WarnContext(r.Context(), message, "error", err)
+
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, entities.ErrProfileNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, entities.ErrProfileInUse):
+		status = http.StatusConflict
+	case errors.Is(err, entities.ErrForbidden):
+		status = http.StatusForbidden
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   message + ": " + err.Error(),
+	})
+}
+
+// profileIDFromQuery lee el parámetro ?id=; escribe el error si falta o es inválido
+func profileIDFromQuery(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		http.Error(w, "ID parameter is required", http.StatusBadRequest)
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// toLLMProfile convierte el DTO a la entidad de dominio
+func toLLMProfile(req dto.LLMProfileRequest) *entities.LLMProfile {
+	profile := &entities.LLMProfile{
+		Name:              req.Name,
+		Type:              req.Type,
+		LocalUrl:          req.LocalUrl,
+		ApiUrl:            req.ApiUrl,
+		ApiKey:            req.ApiKey,
+		ModelName:         req.ModelName,
+		MaxTokens:         req.MaxTokens,
+		Concurrency:       req.Concurrency,
+		RequestsPerMinute: req.RequestsPerMinute,
+		TokensPerMinute:   req.TokensPerMinute,
+		FallbackIDs:       req.FallbackIDs,
+	}
+	for _, condition := range req.FailoverOn {
+		profile.FailoverOn = append(profile.FailoverOn, entities.FailoverCondition(condition))
+	}
+	return profile
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// QueueHandler expone el estado de las colas del LLM
+type QueueHandler struct {
+	scheduler services.LLMScheduler
+}
+
+// NewQueueHandler crea una nueva instancia de QueueHandler
+func NewQueueHandler(scheduler services.LLMScheduler) *QueueHandler {
+	return &QueueHandler{
+		scheduler: scheduler,
+	}
+}
+
+// Handle retorna el estado de cada endpoint y la posición del solicitante en su cola
+func (h *QueueHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requester := requesterFromRequest(r)
+	statuses := h.scheduler.QueueStatus(requester)
+
+	// Posición global: la peor posición entre los endpoints donde hay turnos en espera
+	position := 0
+	for _, status := range statuses {
+		if status.Position > position {
+			position = status.Position
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":   true,
+		"position":  position,
+		"endpoints": statuses,
+	})
+}
+
+// requesterFromRequest identifica al solicitante para el reparto justo de la cola. Se usa
+// el usuario autenticado, no un header del cliente, para que nadie pueda rotar
+// identificadores y acaparar turnos; sin principal se usa la IP remota.
+func requesterFromRequest(r *http.Request) string {
+	if principal := entities.PrincipalFromContext(r.Context()); principal != nil {
+		return principal.Requester()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+func TestRequesterFromRequestUsesPrincipal(t *testing.T) {
+	principal := &entities.Principal{Subject: "key:3:ci", UserID: 7}
+
+	// Rotar un header del cliente no cambia el turno del usuario
+	var requesters []string
+	for _, clientID := range []string{"a", "b", ""} {
+		r := httptest.NewRequest("GET", "/api/queue", nil)
+		r.Header.Set("X-Client-ID", clientID)
+		r = r.WithContext(entities.WithPrincipal(r.Context(), principal))
+		requesters = append(requesters, requesterFromRequest(r))
+	}
+	for _, requester := range requesters {
+		if requester != "user:7" {
+			t.Errorf("requester = %q, want user:7", requester)
+		}
+	}
+
+	// Sin usuario se usa el subject del principal
+	r := httptest.NewRequest("GET", "/api/queue", nil)
+	r = r.WithContext(entities.WithPrincipal(r.Context(), &entities.Principal{Subject: "system:retention"}))
+	if got := requesterFromRequest(r); got != "system:retention" {
+		t.Errorf("requester = %q, want the subject", got)
+	}
+
+	// Sin autenticación, la IP remota
+	r = httptest.NewRequest("GET", "/api/queue", nil)
+	r.RemoteAddr = "192.0.2.4:5555"
+	r.Header.Set("X-Client-ID", "spoofed")
+	if got := requesterFromRequest(r); got != "ip:192.0.2.4" {
+		t.Errorf("requester = %q, want ip:192.0.2.4", got)
+	}
+}
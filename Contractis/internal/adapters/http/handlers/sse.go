@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sseKeepAliveInterval evita que proxies corten la conexión durante fases largas sin eventos
+const sseKeepAliveInterval = 15 * time.Second
+
+// sseWriter escribe eventos Server-Sent Events de forma segura entre goroutines
+type sseWriter struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	done       chan struct{}
+}
+
+// wantsEventStream indica si el cliente pidió la respuesta como text/event-stream
+func wantsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// newSSEWriter envía los headers del stream e inicia los keep-alive; llamar Close al terminar
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	s := &sseWriter{
+		w:          w,
+		controller: http.NewResponseController(w),
+		done:       make(chan struct{}),
+	}
+	s.controller.Flush()
+
+	go s.keepAlive()
+	return s
+}
+
+// Event envía un evento con data serializada como JSON
+func (s *sseWriter) Event(name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+// Close detiene los keep-alive
+func (s *sseWriter) Close() {
+	close(s.done)
+}
+
+func (s *sseWriter) keepAlive() {
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			fmt.Fprint(s.w, ": keep-alive\n\n")
+			s.controller.Flush()
+			s.mu.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
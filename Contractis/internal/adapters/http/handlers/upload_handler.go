@@ -3,13 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/rodascaar/contractis/internal/adapters/http/dto"
@@ -18,16 +19,32 @@ import (
 	"github.com/rodascaar/contractis/internal/usecases"
 )
 
+// shutdownRetryAfter es el Retry-After (segundos) de las subidas rechazadas mientras el servidor se detiene
+const shutdownRetryAfter = "30"
+
 // UploadHandler maneja las solicitudes de carga y análisis de contratos
 type UploadHandler struct {
 	analyzeUseCase  *usecases.AnalyzeContractUseCase
-	processingMutex sync.Mutex
+	profilesUseCase *usecases.LLMProfilesUseCase
+	workspaces      *usecases.WorkspaceUseCase
+	jobs            *usecases.JobTracker
+	limits          entities.Limits
 }
 
 // NewUploadHandler crea una nueva instancia de UploadHandler
-func NewUploadHandler(analyzeUseCase *usecases.AnalyzeContractUseCase) *UploadHandler {
+func NewUploadHandler(
+	analyzeUseCase *usecases.AnalyzeContractUseCase,
+	profilesUseCase *usecases.LLMProfilesUseCase,
+	workspaces *usecases.WorkspaceUseCase,
+	jobs *usecases.JobTracker,
+	limits entities.Limits,
+) *UploadHandler {
 	return &UploadHandler{
-		analyzeUseCase: analyzeUseCase,
+		analyzeUseCase:  analyzeUseCase,
+		profilesUseCase: profilesUseCase,
+		workspaces:      workspaces,
+		jobs:            jobs,
+		limits:          limits,
 	}
 }
 
@@ -36,7 +53,7 @@ func (h *UploadHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// Configurar CORS con restricciones de seguridad
 	w.Header().Set("Access-Control-Allow-Origin", "http://localhost:8080")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Workspace-ID")
 	w.Header().Set("Access-Control-Max-Age", "86400") // Cache preflight for 24 hours
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("X-Frame-Options", "DENY")
@@ -48,67 +65,51 @@ func (h *UploadHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.Method != "POST" {
-		h.sendError(w, "Method not allowed")
+		h.sendError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// El contrato queda registrado en el workspace del usuario (rol analyst o superior)
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAnalyst)
+	if !ok {
 		return
 	}
 
 	// Obtener archivo
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		h.sendError(w, "Error al obtener el archivo")
+		h.sendError(w, r, http.StatusBadRequest, "Error al obtener el archivo")
 		return
 	}
 	defer file.Close()
 
 	// Validar tamaño
-	if header.Size > entities.MaxFileSize {
-		h.sendError(w, "Archivo demasiado grande (máximo 10MB)")
+	if header.Size > h.limits.MaxFileSize {
+		h.sendError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("Archivo demasiado grande (máximo %dMB)", h.limits.FileSizeMB()))
 		return
 	}
 
 	// Validar tipo
 	if !strings.HasSuffix(strings.ToLower(header.Filename), ".pdf") {
-		h.sendError(w, "Solo se permiten archivos PDF")
+		h.sendError(w, r, http.StatusUnsupportedMediaType, "Solo se permiten archivos PDF")
 		return
 	}
 
-	// Parsear configuración LLM
-	llmConfigStr := r.FormValue("llmConfig")
-	var llmConfigReq dto.LLMConfigRequest
-	if err := json.Unmarshal([]byte(llmConfigStr), &llmConfigReq); err != nil {
-		h.sendError(w, "Error al parsear configuración LLM")
-		return
-	}
-
-	// Evitar procesamiento concurrente para modelos locales (pueden ser lentos)
-	if llmConfigReq.Type == "local" {
-		h.processingMutex.Lock()
-		defer h.processingMutex.Unlock()
-		log.Printf("🔒 Procesamiento secuencial activado para modelo local")
-	}
-
-	// Convertir a entidad de dominio
-	llmConfig := entities.NewLLMConfig(
-		llmConfigReq.Type,
-		llmConfigReq.LocalUrl,
-		llmConfigReq.ApiUrl,
-		llmConfigReq.ApiKey,
-		llmConfigReq.ModelName,
-		llmConfigReq.MaxTokens,
-	)
-
-	if err := llmConfig.Validate(); err != nil {
-		log.Printf("❌ Configuración LLM inválida: %v", err)
-		h.sendError(w, fmt.Sprintf("Configuración LLM inválida: %v", err))
+	// Configuración LLM: un perfil guardado en el servidor (profileId) o, por
+	// compatibilidad, la configuración completa en llmConfig
+	llmConfig, err := resolveLLMConfig(r, h.profilesUseCase, membership)
+	if err != nil {
+		h.sendError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	log.Printf("🔧 Configuración LLM recibida - Tipo: %s, URL: %s", llmConfig.Type, llmConfig.GetEndpointURL())
+	slog.InfoContext(r.Context(), "configuración LLM recibida",
+		"type", llmConfig.Type, "endpoint", llmConfig.GetEndpointURL(), "fallbacks", len(llmConfig.Fallbacks))
 
 	// Guardar archivo temporal
 	tempFile, err := os.CreateTemp("", "contrato-*.pdf")
 	if err != nil {
-		h.sendError(w, "Error al crear archivo temporal")
+		h.sendError(w, r, http.StatusInternalServerError, "Error al crear archivo temporal")
 		return
 	}
 	defer os.Remove(tempFile.Name())
@@ -116,30 +117,50 @@ func (h *UploadHandler) Handle(w http.ResponseWriter, r *http.Request) {
 
 	_, err = io.Copy(tempFile, file)
 	if err != nil {
-		h.sendError(w, "Error al guardar el archivo")
+		h.sendError(w, r, http.StatusInternalServerError, "Error al guardar el archivo")
 		return
 	}
 
 	// Calcular hash del archivo para caché
 	fileHash, err := utils.CalculateFileHash(tempFile.Name())
 	if err != nil {
-		log.Printf("⚠️  Error calculando hash: %v", err)
+		slog.WarnContext(r.Context(), "error calculando hash", "error", err)
 		fileHash = "" // Continuar sin hash
 	}
 
-	// Crear contexto con timeout más largo para modelos locales
-	timeout := 30 * time.Minute
-	if llmConfig.Type == "local" {
-		timeout = 45 * time.Minute // Más tiempo para modelos locales
+	// El análisis es un trabajo del servidor: sigue aunque el cliente se desconecte y, al
+	// detenerse el servidor, se espera a que termine (o queda interrumpido y retomable)
+	jobCtx, done, err := h.jobs.Begin(r.Context())
+	if err != nil {
+		w.Header().Set("Retry-After", shutdownRetryAfter)
+		h.sendError(w, r, http.StatusServiceUnavailable, "El servidor se está deteniendo; reintente en unos segundos")
+		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer done()
+
+	// Crear contexto con timeout más largo para modelos locales.
+	// La concurrencia hacia el LLM la regula el scheduler global por endpoint
+	analysisTimeout := h.limits.AnalysisTimeoutFor(llmConfig)
+	ctx, cancel := context.WithTimeout(jobCtx, analysisTimeout)
 	defer cancel()
+	ctx = entities.WithRequester(ctx, requesterFromRequest(r))
+
+	// La respuesta puede tardar más que server.write_timeout: se extiende el plazo de escritura
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(analysisTimeout + time.Minute)); err != nil {
+		slog.WarnContext(ctx, "no se pudo extender el plazo de escritura", "error", err)
+	}
+
+	// Con Accept: text/event-stream se reenvían los tokens del reporte final a medida que llegan
+	if wantsEventStream(r) {
+		h.streamAnalysis(ctx, w, membership.WorkspaceID, tempFile.Name(), header.Filename, fileHash, header.Size, llmConfig)
+		return
+	}
 
 	// Ejecutar análisis
-	result, err := h.analyzeUseCase.Execute(ctx, tempFile.Name(), header.Filename, fileHash, header.Size, llmConfig)
+	result, err := h.analyzeUseCase.Execute(ctx, membership.WorkspaceID, tempFile.Name(), header.Filename, fileHash, header.Size, llmConfig)
 	if err != nil {
-		log.Printf("Error en análisis: %v", err)
-		h.sendError(w, fmt.Sprintf("Error al analizar: %v", err))
+		slog.ErrorContext(ctx, "error en análisis", "error", err)
+		h.sendError(w, r, analysisErrorStatus(err), fmt.Sprintf("Error al analizar: %v", err))
 		return
 	}
 
@@ -149,14 +170,103 @@ func (h *UploadHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		Data:    result.Content,
 	}
 
-	log.Printf("✅ Análisis completado exitosamente, enviando respuesta (longitud: %d caracteres)", len(result.Content))
+	slog.InfoContext(ctx, "análisis completado, enviando respuesta", "contract_id", result.ContractID, "chars", len(result.Content))
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *UploadHandler) sendError(w http.ResponseWriter, message string) {
-	log.Printf("❌ Error en upload handler: %s", message)
+// streamAnalysis ejecuta el análisis respondiendo con eventos SSE:
+// "token" ({"token": "..."}) por cada fragmento del reporte final y al terminar
+// "result" o "error" con el mismo cuerpo que la respuesta JSON (dto.AnalysisResponse).
+func (h *UploadHandler) streamAnalysis(
+	ctx context.Context,
+	w http.ResponseWriter,
+	workspaceID int64,
+	pdfPath string,
+	filename string,
+	fileHash string,
+	fileSize int64,
+	llmConfig *entities.LLMConfig,
+) {
+	stream := newSSEWriter(w)
+	defer stream.Close()
+
+	ctx = entities.WithTokenSink(ctx, func(token string) {
+		stream.Event("token", map[string]string{"token": token})
+	})
+
+	result, err := h.analyzeUseCase.Execute(ctx, workspaceID, pdfPath, filename, fileHash, fileSize, llmConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "error en análisis", "error", err)
+		stream.Event("error", dto.AnalysisResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Error al analizar: %v", err),
+		})
+		return
+	}
+
+	slog.InfoContext(ctx, "análisis completado, enviando resultado por stream", "contract_id", result.ContractID, "chars", len(result.Content))
+	stream.Event("result", dto.AnalysisResponse{
+		Success: result.Success,
+		Data:    result.Content,
+	})
+}
+
+// resolveLLMConfig obtiene la configuración LLM del formulario: profileId tiene prioridad
+// sobre llmConfig y debe ser un perfil que el miembro pueda usar en su workspace
+func resolveLLMConfig(r *http.Request, profilesUseCase *usecases.LLMProfilesUseCase, membership *entities.Membership) (*entities.LLMConfig, error) {
+	if profileIDStr := strings.TrimSpace(r.FormValue("profileId")); profileIDStr != "" {
+		profileID, err := strconv.ParseInt(profileIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("profileId inválido")
+		}
+
+		llmConfig, err := profilesUseCase.ResolveConfig(r.Context(), membership, profileID)
+		if err != nil {
+			slog.WarnContext(r.Context(), "perfil LLM no utilizable", "profile_id", profileID, "workspace_id", membership.WorkspaceID, "error", err)
+			return nil, fmt.Errorf("Perfil LLM no utilizable: %v", err)
+		}
+		return llmConfig, nil
+	}
+
+	var llmConfigReq dto.LLMConfigRequest
+	if err := json.Unmarshal([]byte(r.FormValue("llmConfig")), &llmConfigReq); err != nil {
+		return nil, fmt.Errorf("Error al parsear configuración LLM")
+	}
+
+	// Convertir a entidad de dominio
+	llmConfig := llmConfigReq.ToEntity()
+
+	if err := llmConfig.Validate(); err != nil {
+		slog.WarnContext(r.Context(), "configuración LLM inválida", "error", err)
+		return nil, fmt.Errorf("Configuración LLM inválida: %v", err)
+	}
+	return llmConfig, nil
+}
+
+func (h *UploadHandler) sendError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	slog.WarnContext(r.Context(), "error en upload", "status", status, "message", message)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(dto.AnalysisResponse{
 		Success: false,
 		Error:   message,
 	})
 }
+
+// analysisErrorStatus elige el status HTTP de un análisis fallido: los fallos del
+// proveedor LLM son errores de gateway, no del servidor
+func analysisErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrRateLimited), errors.Is(err, entities.ErrQuotaExceeded), errors.Is(err, entities.ErrInterrupted):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, entities.ErrLLMTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, entities.ErrLLMConnectionFailed), errors.Is(err, entities.ErrInvalidLLMResponse):
+		return http.StatusBadGateway
+	case errors.Is(err, entities.ErrExtractionFailed):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
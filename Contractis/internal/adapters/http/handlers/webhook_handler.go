@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/dto"
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// maxWebhookRequestSize acota el cuerpo JSON de creación y actualización de un webhook
+const maxWebhookRequestSize = 64 << 10
+
+// WebhookHandler maneja los webhooks de la API v1 (/api/v1/webhooks). Solo los admin del
+// workspace pueden gestionarlos, porque el secreto de firma y el log de entregas exponen
+// datos de los contratos.
+type WebhookHandler struct {
+	webhooks   *usecases.WebhookUseCase
+	workspaces *usecases.WorkspaceUseCase
+}
+
+// NewWebhookHandler crea una nueva instancia de WebhookHandler
+func NewWebhookHandler(webhooks *usecases.WebhookUseCase, workspaces *usecases.WorkspaceUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks:   webhooks,
+		workspaces: workspaces,
+	}
+}
+
+// HandleList lista los webhooks del workspace: GET /api/v1/webhooks
+func (h *WebhookHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhooks.List(r.Context(), membership.WorkspaceID)
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+	if webhooks == nil {
+		webhooks = []*entities.Webhook{}
+	}
+	writeJSONWithETag(w, r, map[string]interface{}{"data": webhooks})
+}
+
+// HandleCreate crea un webhook: POST /api/v1/webhooks → 201. El secreto de firma solo se
+// incluye en esta respuesta.
+func (h *WebhookHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req dto.WebhookRequest
+	if !decodeWebhookRequest(w, r, &req) {
+		return
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.Create(r.Context(), req.ToEntity(membership.WorkspaceID))
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook creado", "webhook_id", webhook.ID, "workspace_id", membership.WorkspaceID)
+	w.Header().Set("Location", "/api/v1/webhooks/"+strconv.FormatInt(webhook.ID, 10))
+	writeWebhookJSON(w, http.StatusCreated, map[string]interface{}{
+		"data":   webhook,
+		"secret": webhook.Secret,
+	})
+}
+
+// HandleGet obtiene un webhook: GET /api/v1/webhooks/{id}
+func (h *WebhookHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, workspaceID, ok := h.webhookFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.Get(r.Context(), workspaceID, id)
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+	writeJSONWithETag(w, r, map[string]interface{}{"data": webhook})
+}
+
+// HandleUpdate modifica un webhook: PATCH /api/v1/webhooks/{id}. Con rotate_secret la
+// respuesta incluye el secreto nuevo.
+func (h *WebhookHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, workspaceID, ok := h.webhookFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.WebhookUpdateRequest
+	if !decodeWebhookRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhooks.Update(r.Context(), workspaceID, id, req.ToEntity())
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook actualizado", "webhook_id", id, "rotate_secret", req.RotateSecret)
+	response := map[string]interface{}{"data": webhook}
+	if req.RotateSecret {
+		response["secret"] = webhook.Secret
+	}
+	writeWebhookJSON(w, http.StatusOK, response)
+}
+
+// HandleDelete elimina un webhook y su log de entregas: DELETE /api/v1/webhooks/{id} → 204
+func (h *WebhookHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	id, workspaceID, ok := h.webhookFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Delete(r.Context(), workspaceID, id); err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "webhook eliminado", "webhook_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDeliveries lista el log de entregas de un webhook con paginación por cursor:
+// GET /api/v1/webhooks/{id}/deliveries?limit=&cursor=
+func (h *WebhookHandler) HandleDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, "limit debe estar entre 1 y "+strconv.Itoa(maxPageSize))
+			return
+		}
+		limit = parsed
+	}
+
+	var beforeID int64
+	if cursor := query.Get("cursor"); cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidCursor, "Cursor inválido")
+			return
+		}
+		beforeID = id
+	}
+
+	id, workspaceID, ok := h.webhookFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Se pide una entrega extra para saber si hay página siguiente
+	deliveries, err := h.webhooks.Deliveries(r.Context(), workspaceID, id, beforeID, limit+1)
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"data": deliveries,
+	}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		next := encodeCursor(deliveries[len(deliveries)-1].ID)
+		response["data"] = deliveries
+		response["next_cursor"] = next
+
+		nextQuery := url.Values{}
+		for key, values := range query {
+			nextQuery[key] = values
+		}
+		nextQuery.Set("cursor", next)
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+nextQuery.Encode()+`>; rel="next"`)
+	}
+	if deliveries == nil {
+		response["data"] = []interface{}{}
+	}
+
+	writeJSONWithETag(w, r, response)
+}
+
+// HandleTest envía un evento webhook.test y responde con el resultado de la entrega:
+// POST /api/v1/webhooks/{id}/test. Una entrega fallida no es un error de la petición.
+func (h *WebhookHandler) HandleTest(w http.ResponseWriter, r *http.Request) {
+	id, workspaceID, ok := h.webhookFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhooks.Test(r.Context(), workspaceID, id)
+	if err != nil {
+		writeWebhookProblem(w, r, err)
+		return
+	}
+	writeWebhookJSON(w, http.StatusOK, map[string]interface{}{"data": delivery})
+}
+
+// webhookFromPath resuelve el {id} de la ruta y el workspace del admin
+func (h *WebhookHandler) webhookFromPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, "ID de webhook inválido")
+		return 0, 0, false
+	}
+
+	membership, ok := authorizeWorkspace(w, r, h.workspaces, entities.RoleAdmin)
+	if !ok {
+		return 0, 0, false
+	}
+	return id, membership.WorkspaceID, true
+}
+
+// decodeWebhookRequest lee el cuerpo JSON de la petición; los campos desconocidos se
+// rechazan para que un error de tipeo no pase inadvertido
+func decodeWebhookRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, "JSON inválido: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeWebhookProblem traduce los errores del caso de uso de webhooks a problem+json
+func writeWebhookProblem(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, entities.ErrWebhookNotFound):
+		problem.Write(w, r, http.StatusNotFound, "Webhook no encontrado")
+	case errors.Is(err, entities.ErrInvalidWebhook):
+		problem.WriteType(w, r, http.StatusBadRequest, problem.TypeInvalidRequest, err.Error())
+	default:
+		slog.ErrorContext(r.Context(), "error en webhooks", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, "Error al acceder a los webhooks")
+	}
+}
+
+// writeWebhookJSON responde sin ETag ni caché: estas respuestas pueden incluir el secreto
+func writeWebhookJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
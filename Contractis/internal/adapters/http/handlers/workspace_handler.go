@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// WorkspaceHandler expone los workspaces del usuario autenticado
+type WorkspaceHandler struct {
+	workspaces *usecases.WorkspaceUseCase
+}
+
+// NewWorkspaceHandler crea una nueva instancia de WorkspaceHandler
+func NewWorkspaceHandler(workspaces *usecases.WorkspaceUseCase) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaces: workspaces,
+	}
+}
+
+// HandleList lista los workspaces del usuario con su rol en cada uno
+func (h *WorkspaceHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := entities.PrincipalFromContext(r.Context())
+	if principal == nil || principal.UserID == 0 {
+		http.Error(w, "La credencial no está asociada a un usuario", http.StatusForbidden)
+		return
+	}
+
+	workspaces, err := h.workspaces.ListForUser(r.Context(), principal.UserID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listando workspaces", "error", err)
+		http.Error(w, "Error al obtener workspaces", http.StatusInternalServerError)
+		return
+	}
+	if workspaces == nil {
+		workspaces = []*entities.Workspace{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    workspaces,
+	})
+}
+
+// authorizeWorkspace resuelve el workspace de la petición (header X-Workspace-ID o
+// parámetro ?workspace=, por defecto el primero del usuario) y exige el rol indicado.
+// Si no hay acceso escribe la respuesta de error y retorna false.
+func authorizeWorkspace(
+	w http.ResponseWriter,
+	r *http.Request,
+	workspaces *usecases.WorkspaceUseCase,
+	required entities.Role,
+) (*entities.Membership, bool) {
+	requested := strings.TrimSpace(r.Header.Get("X-Workspace-ID"))
+	if requested == "" {
+		requested = strings.TrimSpace(r.URL.Query().Get("workspace"))
+	}
+
+	var workspaceID int64
+	if requested != "" {
+		id, err := strconv.ParseInt(requested, 10, 64)
+		if err != nil || id <= 0 {
+			writeHTTPError(w, r, http.StatusBadRequest, "Invalid workspace ID")
+			return nil, false
+		}
+		workspaceID = id
+	}
+
+	principal := entities.PrincipalFromContext(r.Context())
+	membership, err := workspaces.Authorize(r.Context(), principal, workspaceID, required)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrWorkspaceNotFound):
+			writeHTTPError(w, r, http.StatusNotFound, "Workspace no encontrado")
+		case errors.Is(err, entities.ErrForbidden):
+			slog.WarnContext(r.Context(), "acceso denegado al workspace", "workspace_id", workspaceID, "error", err)
+			writeHTTPError(w, r, http.StatusForbidden, "Permiso insuficiente en el workspace: se requiere rol "+string(required))
+		default:
+			slog.ErrorContext(r.Context(), "error autorizando workspace", "error", err)
+			writeHTTPError(w, r, http.StatusInternalServerError, "Error verificando permisos")
+		}
+		return nil, false
+	}
+
+	return membership, true
+}
+
+// writeHTTPError responde un error en texto plano o, en la API v1, como problem+json
+func writeHTTPError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if problem.Enabled(r) {
+		problem.Write(w, r, status, message)
+		return
+	}
+	http.Error(w, message, status)
+}
@@ -1,30 +1,56 @@
 package http
 
 import (
-	"fmt"
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/rodascaar/contractis/internal/adapters/http/router"
 )
 
+// ServerOptions son los timeouts por conexión del servidor HTTP (0 = sin límite)
+type ServerOptions struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+}
+
 // Server representa el servidor HTTP
 type Server struct {
-	port   string
+	addr   string
 	router *router.Router
+	server *http.Server
 }
 
-// NewServer crea una nueva instancia de Server
-func NewServer(port string, router *router.Router) *Server {
+// NewServer crea una nueva instancia de Server que escucha en addr (host:puerto)
+func NewServer(addr string, router *router.Router, options ServerOptions) *Server {
 	return &Server{
-		port:   port,
+		addr:   addr,
 		router: router,
+		server: &http.Server{
+			Addr:              addr,
+			ReadHeaderTimeout: options.ReadHeaderTimeout,
+			ReadTimeout:       options.ReadTimeout,
+			WriteTimeout:      options.WriteTimeout,
+			IdleTimeout:       options.IdleTimeout,
+		},
 	}
 }
 
-// Start inicia el servidor HTTP
+// Start inicia el servidor HTTP; tras Shutdown o Close retorna http.ErrServerClosed
 func (s *Server) Start() error {
-	mux := s.router.Setup()
+	s.server.Handler = s.router.Setup()
+	return s.server.ListenAndServe()
+}
+
+// Shutdown deja de aceptar conexiones y espera a que terminen las solicitudes en curso
+// hasta que venza ctx
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
 
-	fmt.Printf("🚀 Contractis servidor iniciado en http://localhost:%s\n", s.port)
-	return http.ListenAndServe(":"+s.port, mux)
+// Close cierra de inmediato todas las conexiones, incluidas las que siguen activas
+func (s *Server) Close() error {
+	return s.server.Close()
 }
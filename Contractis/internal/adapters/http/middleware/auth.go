@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// Auth es un middleware que exige una credencial válida con el scope indicado
+// (un scope vacío acepta cualquier principal autenticado).
+// La credencial se lee de "Authorization: Bearer ...", del header X-API-Key o de la
+// cookie de sesión; el principal autenticado queda en el contexto de la petición.
+func Auth(authenticator services.Authenticator, scope entities.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Los preflight CORS no llevan credenciales
+		if r.Method == "OPTIONS" {
+			next(w, r)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(r.Context(), CredentialFromRequest(r))
+		if err != nil {
+			status := http.StatusUnauthorized
+			message := "Autenticación requerida"
+			if !errors.Is(err, entities.ErrUnauthenticated) &&
+				!errors.Is(err, entities.ErrInvalidAPIKey) &&
+				!errors.Is(err, entities.ErrInvalidToken) {
+				slog.ErrorContext(r.Context(), "error autenticando la petición", "method", r.Method, "path", r.URL.Path, "error", err)
+				status = http.StatusInternalServerError
+				message = "Error verificando credenciales"
+			} else if !errors.Is(err, entities.ErrUnauthenticated) {
+				message = "Credencial inválida o expirada"
+			}
+
+			w.Header().Set("WWW-Authenticate", `Bearer realm="contractis"`)
+			writeAuthError(w, r, status, message)
+			return
+		}
+
+		if scope != "" && !principal.HasScope(scope) {
+			slog.WarnContext(r.Context(), "permiso insuficiente",
+				"subject", principal.Subject, "scope", scope, "method", r.Method, "path", r.URL.Path)
+			writeAuthError(w, r, http.StatusForbidden, "Permiso insuficiente: se requiere "+string(scope))
+			return
+		}
+
+		next(w, r.WithContext(entities.WithPrincipal(r.Context(), principal)))
+	}
+}
+
+// CredentialFromRequest extrae la credencial de la petición (header o cookie de sesión)
+func CredentialFromRequest(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if scheme, value, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(value)
+		}
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+	if cookie, err := r.Cookie(entities.SessionCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func writeAuthError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if problem.Enabled(r) {
+		problem.Write(w, r, status, message)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{
+		Success: false,
+		Error:   message,
+	})
+}
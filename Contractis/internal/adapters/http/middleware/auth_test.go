@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/auth"
+)
+
+// memoryKeys implementa repositories.APIKeyRepository en memoria
+type memoryKeys struct {
+	keys map[int64]*entities.APIKey
+	err  error
+}
+
+func (m *memoryKeys) Create(ctx context.Context, key *entities.APIKey) (int64, error) {
+	key.ID = int64(len(m.keys) + 1)
+	m.keys[key.ID] = key
+	return key.ID, nil
+}
+
+func (m *memoryKeys) GetByID(ctx context.Context, id int64) (*entities.APIKey, error) {
+	if key, ok := m.keys[id]; ok {
+		return key, nil
+	}
+	return nil, entities.ErrInvalidAPIKey
+}
+
+func (m *memoryKeys) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, key := range m.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, entities.ErrInvalidAPIKey
+}
+
+func (m *memoryKeys) List(ctx context.Context) ([]*entities.APIKey, error) { return nil, nil }
+
+func (m *memoryKeys) Revoke(ctx context.Context, id int64) error {
+	now := time.Now()
+	m.keys[id].RevokedAt = &now
+	return nil
+}
+
+func (m *memoryKeys) TouchLastUsed(ctx context.Context, id int64) error { return nil }
+
+type authFixture struct {
+	service *auth.Service
+	keys    *memoryKeys
+	signer  *auth.TokenSigner
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	signer, err := auth.NewTokenSigner(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := &memoryKeys{keys: make(map[int64]*entities.APIKey)}
+	return &authFixture{service: auth.NewService(keys, signer), keys: keys, signer: signer}
+}
+
+func (f *authFixture) createKey(t *testing.T, scopes ...entities.Scope) (string, *entities.APIKey) {
+	t.Helper()
+	plaintext, key, err := f.service.CreateAPIKey(context.Background(), 1, "ci", scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plaintext, key
+}
+
+// serve ejecuta una petición contra un handler protegido y retorna la respuesta y el
+// principal que llegó al handler (nil si no se llamó)
+func serve(authenticator *auth.Service, scope entities.Scope, r *http.Request) (*httptest.ResponseRecorder, *entities.Principal) {
+	var principal *entities.Principal
+	handler := Auth(authenticator, scope, func(w http.ResponseWriter, r *http.Request) {
+		principal = entities.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w, principal
+}
+
+func TestAuthAcceptsValidCredentials(t *testing.T) {
+	f := newAuthFixture(t)
+	plaintext, key := f.createKey(t, entities.ScopeRead)
+	session, _, err := f.service.IssueSession(&entities.Principal{Subject: "user:1:ana", UserID: 1, Scopes: []entities.Scope{entities.ScopeRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]func(r *http.Request){
+		"bearer api key": func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+plaintext) },
+		"x-api-key":      func(r *http.Request) { r.Header.Set("X-API-Key", plaintext) },
+		"session cookie": func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: entities.SessionCookieName, Value: session})
+		},
+	}
+	for name, setCredential := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/contracts", nil)
+			setCredential(r)
+			w, principal := serve(f.service, entities.ScopeRead, r)
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			if principal == nil || principal.UserID != 1 {
+				t.Errorf("principal = %+v, want user 1 in the context", principal)
+			}
+		})
+	}
+
+	// La key se identifica en el subject del principal
+	r := httptest.NewRequest("GET", "/api/contracts", nil)
+	r.Header.Set("X-API-Key", plaintext)
+	if _, principal := serve(f.service, "", r); principal == nil || principal.KeyID != key.ID {
+		t.Errorf("principal = %+v, want key %d", principal, key.ID)
+	}
+}
+
+func TestAuthRejectsInvalidCredentials(t *testing.T) {
+	f := newAuthFixture(t)
+	readKey, _ := f.createKey(t, entities.ScopeRead)
+	revokedKey, revoked := f.createKey(t, entities.ScopeAdmin)
+	if err := f.service.RevokeAPIKey(context.Background(), revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	principal := &entities.Principal{Subject: "user:1:ana", UserID: 1, Scopes: []entities.Scope{entities.ScopeAdmin}}
+	expired, _, err := f.signer.Issue(principal, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, _, err := f.signer.Issue(principal, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := valid[:strings.LastIndex(valid, ".")+1] + "Zm9yZ2Vk"
+
+	tests := []struct {
+		name       string
+		credential string
+		cookie     bool
+		status     int
+		message    string
+	}{
+		{"missing credential", "", false, http.StatusUnauthorized, "Autenticación requerida"},
+		{"unknown api key", "ctr_unknown", false, http.StatusUnauthorized, "Credencial inválida"},
+		{"revoked api key", revokedKey, false, http.StatusUnauthorized, "Credencial inválida"},
+		{"bad signature", forged, true, http.StatusUnauthorized, "Credencial inválida"},
+		{"expired session", expired, true, http.StatusUnauthorized, "Credencial inválida"},
+		{"insufficient scope", readKey, false, http.StatusForbidden, "se requiere delete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("DELETE", "/api/contracts/delete", nil)
+			if tt.cookie {
+				r.AddCookie(&http.Cookie{Name: entities.SessionCookieName, Value: tt.credential})
+			} else if tt.credential != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.credential)
+			}
+
+			w, reached := serve(f.service, entities.ScopeDelete, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.status, w.Body)
+			}
+			if reached != nil {
+				t.Error("the protected handler was called")
+			}
+			if !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("body = %s, want %q", w.Body, tt.message)
+			}
+			if tt.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestAuthSessionOfRevokedKeyIsRejected(t *testing.T) {
+	f := newAuthFixture(t)
+	plaintext, key := f.createKey(t, entities.ScopeRead)
+
+	// Una sesión emitida a partir de la key deja de valer al revocarla
+	principal, err := f.service.Authenticate(context.Background(), plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _, err := f.service.IssueSession(principal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.service.RevokeAPIKey(context.Background(), key.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/api/contracts", nil)
+	r.AddCookie(&http.Cookie{Name: entities.SessionCookieName, Value: session})
+	if w, _ := serve(f.service, entities.ScopeRead, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuthErrorResponses(t *testing.T) {
+	f := newAuthFixture(t)
+	plaintext, _ := f.createKey(t, entities.ScopeRead)
+
+	// Un fallo del repositorio no es una credencial inválida
+	f.keys.err = errors.New("database is locked")
+	r := httptest.NewRequest("GET", "/api/contracts", nil)
+	r.Header.Set("X-API-Key", plaintext)
+	if w, _ := serve(f.service, entities.ScopeRead, r); w.Code != http.StatusInternalServerError {
+		t.Errorf("repository error: status = %d, want 500", w.Code)
+	}
+	f.keys.err = nil
+
+	// Los preflight CORS pasan sin credencial
+	r = httptest.NewRequest("OPTIONS", "/upload", nil)
+	if w, _ := serve(f.service, entities.ScopeAnalyze, r); w.Code != http.StatusNoContent {
+		t.Errorf("preflight: status = %d, want the handler's 204", w.Code)
+	}
+
+	// En la API v1 los errores son problem+json
+	w := httptest.NewRecorder()
+	problem.Enable(Auth(f.service, entities.ScopeRead, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the protected handler was called")
+	}))(w, httptest.NewRequest("GET", "/api/v1/contracts", nil))
+	if w.Code != http.StatusUnauthorized || w.Header().Get("Content-Type") != problem.ContentType {
+		t.Errorf("v1: status = %d, Content-Type = %q, want 401 problem+json", w.Code, w.Header().Get("Content-Type"))
+	}
+}
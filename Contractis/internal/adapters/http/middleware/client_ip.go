@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// ClientIP es un middleware que guarda en el contexto la IP del cliente para la
+// auditoría. Se usa la dirección de la conexión: X-Forwarded-For no se considera
+// porque cualquier cliente puede falsificarlo.
+func ClientIP(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		next(w, r.WithContext(entities.WithClientIP(r.Context(), host)))
+	}
+}
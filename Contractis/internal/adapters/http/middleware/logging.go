@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -21,39 +21,51 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Logging es un middleware que registra las peticiones HTTP con más detalle
-func Logging(next http.HandlerFunc) http.HandlerFunc {
+// Unwrap permite a http.ResponseController acceder a Flush del writer original (streaming)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// RequestObserver recibe cada petición atendida, por ejemplo para las métricas.
+// route es el patrón de la ruta que la atendió ("GET /api/v1/contracts/{id}").
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// Logging es un middleware que registra cada petición HTTP (el inicio en debug, el final
+// con status y duración) y la informa a observer (opcional)
+func Logging(observer RequestObserver, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		ctx := r.Context()
 
 		// Wrap response writer to capture status code
 		rw := newResponseWriter(w)
 
-		// Log request start
-		log.Printf("→ [%s] %s %s | User-Agent: %s", r.Method, r.URL.Path, r.RemoteAddr, r.Header.Get("User-Agent"))
+		slog.DebugContext(ctx, "petición recibida",
+			"method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr, "user_agent", r.Header.Get("User-Agent"))
 
 		// Call next handler
 		next(rw, r)
 
-		// Log request completion with status and duration
 		duration := time.Since(start)
-		statusEmoji := getStatusEmoji(rw.statusCode)
-		log.Printf("← [%s] %s | Status: %d %s | Duration: %v", r.Method, r.URL.Path, rw.statusCode, statusEmoji, duration)
+		slog.Log(ctx, statusLevel(rw.statusCode), "petición atendida",
+			"method", r.Method, "path", r.URL.Path, "status", rw.statusCode,
+			"duration_ms", duration.Milliseconds(), "remote", r.RemoteAddr)
+		if observer != nil {
+			observer.ObserveRequest(r.Method, r.Pattern, rw.statusCode, duration)
+		}
 	}
 }
 
-// getStatusEmoji returns an emoji based on HTTP status code
-func getStatusEmoji(statusCode int) string {
+// statusLevel retorna el nivel de log según el status HTTP
+func statusLevel(statusCode int) slog.Level {
 	switch {
-	case statusCode >= 200 && statusCode < 300:
-		return "✅"
-	case statusCode >= 300 && statusCode < 400:
-		return "↪️"
-	case statusCode >= 400 && statusCode < 500:
-		return "⚠️"
 	case statusCode >= 500:
-		return "❌"
+		return slog.LevelError
+	case statusCode >= 400:
+		return slog.LevelWarn
 	default:
-		return "❓"
+		return slog.LevelInfo
 	}
 }
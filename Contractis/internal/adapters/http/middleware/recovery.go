@@ -2,8 +2,9 @@ package middleware
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 )
 
 type errorResponse struct {
@@ -16,7 +17,9 @@ func Recovery(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("🚨 PANIC RECOVERED: %v | Request: %s %s | Remote: %s", err, r.Method, r.URL.Path, r.RemoteAddr)
+				slog.ErrorContext(r.Context(), "panic recuperado",
+					"panic", err, "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr,
+					"stack", string(debug.Stack()))
 
 				// Asegurar que no se haya enviado respuesta ya
 				if w.Header().Get("Content-Type") == "" {
@@ -27,7 +30,7 @@ func Recovery(next http.HandlerFunc) http.HandlerFunc {
 						Error:   "Internal server error occurred",
 					})
 				} else {
-					log.Printf("⚠️  Response already started, cannot send error response")
+					slog.WarnContext(r.Context(), "la respuesta ya había comenzado, no se puede enviar el error")
 				}
 			}
 		}()
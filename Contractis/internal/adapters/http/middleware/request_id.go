@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// RequestIDHeader es el header con el identificador de la petición
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limita el identificador aceptado del cliente
+const maxRequestIDLength = 128
+
+// RequestID es un middleware que asigna a cada petición un identificador, guardado en
+// el contexto para los logs y devuelto en X-Request-ID. Si el cliente (o un proxy) envía
+// un X-Request-ID válido se reutiliza, para correlacionar con sus propios logs.
+func RequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next(w, r.WithContext(entities.WithRequestID(r.Context(), id)))
+	}
+}
+
+// validRequestID acepta identificadores cortos de caracteres visibles, sin espacios
+// ni comillas que ensucien los logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+)
+
+// Tracing es un middleware que crea un span por petición, continuando la traza del
+// cliente si envía el header traceparent. Con tracer nil no hace nada.
+func Tracing(tracer *tracing.Tracer, next http.HandlerFunc) http.HandlerFunc {
+	if tracer == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		route := r.Pattern
+		if _, path, ok := strings.Cut(route, " "); ok {
+			route = path
+		}
+		name := r.Method + " " + route
+		if route == "" {
+			name = r.Method
+		}
+
+		ctx := tracing.Extract(r.Context(), r.Header.Get("traceparent"))
+		ctx, span := tracer.StartSpan(ctx, name, tracing.KindServer)
+		defer span.End()
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("url.path", r.URL.Path)
+		if id := entities.RequestIDFromContext(ctx); id != "" {
+			span.SetAttribute("contractis.request_id", id)
+		}
+
+		rw := newResponseWriter(w)
+		next(rw, r.WithContext(ctx))
+
+		span.SetAttribute("http.response.status_code", rw.statusCode)
+		if rw.statusCode >= 500 {
+			span.RecordError(fmt.Errorf("status %d", rw.statusCode))
+		}
+	}
+}
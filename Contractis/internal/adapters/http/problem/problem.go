@@ -0,0 +1,62 @@
+// Package problem implementa los errores RFC 7807 (application/problem+json) de la API v1.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType es el media type de los errores RFC 7807
+const ContentType = "application/problem+json"
+
+// Tipos de problema propios; el resto usa "about:blank" con el título del status HTTP
+const (
+	TypeLegalHold      = "urn:contractis:problem:legal-hold"
+	TypeInvalidCursor  = "urn:contractis:problem:invalid-cursor"
+	TypeInvalidRequest = "urn:contractis:problem:invalid-request"
+)
+
+// Details es el cuerpo de un error RFC 7807
+type Details struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+type contextKey struct{}
+
+// Enable marca la petición para que los errores de middlewares y handlers compartidos
+// se respondan como problem+json en lugar del formato de la API anterior
+func Enable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, true)))
+	}
+}
+
+// Enabled indica si la petición pertenece a la API v1
+func Enabled(r *http.Request) bool {
+	enabled, _ := r.Context().Value(contextKey{}).(bool)
+	return enabled
+}
+
+// Write responde un problema genérico del status indicado
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	WriteType(w, r, status, "about:blank", detail)
+}
+
+// WriteType responde un problema con un tipo propio
+func WriteType(w http.ResponseWriter, r *http.Request, status int, problemType, detail string) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Del("ETag")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Details{
+		Type:     problemType,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
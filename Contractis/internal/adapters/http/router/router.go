@@ -5,14 +5,34 @@ import (
 
 	"github.com/rodascaar/contractis/internal/adapters/http/handlers"
 	"github.com/rodascaar/contractis/internal/adapters/http/middleware"
+	"github.com/rodascaar/contractis/internal/adapters/http/problem"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/metrics"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
 )
 
 // Router configura y retorna el router HTTP
 type Router struct {
-	uploadHandler   *handlers.UploadHandler
-	estimateHandler *handlers.EstimateHandler
-	historyHandler  *handlers.HistoryHandler
-	staticPath      string
+	uploadHandler    *handlers.UploadHandler
+	estimateHandler  *handlers.EstimateHandler
+	historyHandler   *handlers.HistoryHandler
+	queueHandler     *handlers.QueueHandler
+	profileHandler   *handlers.ProfileHandler
+	authHandler      *handlers.AuthHandler
+	workspaceHandler *handlers.WorkspaceHandler
+	oidcHandler      *handlers.OIDCHandler
+	auditHandler     *handlers.AuditHandler
+	batchHandler     *handlers.BatchHandler
+	healthHandler    *handlers.HealthHandler
+	webhookHandler   *handlers.WebhookHandler
+	authenticator    services.Authenticator
+	metrics          *metrics.Metrics
+	tracer           *tracing.Tracer
+	staticPath       string
+
+	// patterns son los patrones registrados por Setup, en orden
+	patterns []string
 }
 
 // NewRouter crea una nueva instancia de Router
@@ -20,49 +40,193 @@ func NewRouter(
 	uploadHandler *handlers.UploadHandler,
 	estimateHandler *handlers.EstimateHandler,
 	historyHandler *handlers.HistoryHandler,
+	queueHandler *handlers.QueueHandler,
+	profileHandler *handlers.ProfileHandler,
+	authHandler *handlers.AuthHandler,
+	workspaceHandler *handlers.WorkspaceHandler,
+	oidcHandler *handlers.OIDCHandler,
+	auditHandler *handlers.AuditHandler,
+	batchHandler *handlers.BatchHandler,
+	healthHandler *handlers.HealthHandler,
+	webhookHandler *handlers.WebhookHandler,
+	authenticator services.Authenticator,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 	staticPath string,
 ) *Router {
 	return &Router{
-		uploadHandler:   uploadHandler,
-		estimateHandler: estimateHandler,
-		historyHandler:  historyHandler,
-		staticPath:      staticPath,
+		uploadHandler:    uploadHandler,
+		estimateHandler:  estimateHandler,
+		historyHandler:   historyHandler,
+		queueHandler:     queueHandler,
+		profileHandler:   profileHandler,
+		authHandler:      authHandler,
+		workspaceHandler: workspaceHandler,
+		oidcHandler:      oidcHandler,
+		auditHandler:     auditHandler,
+		batchHandler:     batchHandler,
+		healthHandler:    healthHandler,
+		webhookHandler:   webhookHandler,
+		authenticator:    authenticator,
+		metrics:          metrics,
+		tracer:           tracer,
+		staticPath:       staticPath,
 	}
 }
 
 // Setup configura las rutas del servidor
 func (r *Router) Setup() *http.ServeMux {
-	mux := http.NewServeMux()
+	mux := &routeMux{ServeMux: http.NewServeMux()}
 
-	// Health check endpoint (sin middleware para máxima disponibilidad)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy","timestamp":"` + http.TimeFormat + `"}`))
-	})
+	// Liveness y readiness (públicos y sin middleware: los orquestadores los consultan
+	// seguido y no deben llenar los logs ni las métricas). /health se mantiene por compatibilidad.
+	mux.HandleFunc("GET /healthz", middleware.Recovery(r.healthHandler.HandleLiveness))
+	mux.HandleFunc("GET /health", middleware.Recovery(r.healthHandler.HandleLiveness))
+	mux.HandleFunc("GET /readyz", middleware.Recovery(r.healthHandler.HandleReadiness))
+
+	// Métricas de Prometheus (solo con métricas habilitadas; requiere una API key de lectura)
+	if r.metrics != nil {
+		mux.HandleFunc("GET /metrics", r.protect(entities.ScopeRead, r.metrics.Handler()))
+	}
+
+	// Especificación OpenAPI (pública, describe las rutas pero no expone datos)
+	mux.HandleFunc("/api/openapi.json", r.applyMiddleware(handlers.HandleOpenAPI))
+
+	// Sesiones del cliente web
+	mux.HandleFunc("/api/auth/session", r.protect("", r.authHandler.HandleSession))
+	mux.HandleFunc("/api/auth/me", r.protect("", r.authHandler.HandleMe))
+	mux.HandleFunc("/api/auth/logout", r.applyMiddleware(r.authHandler.HandleLogout))
+	mux.HandleFunc("/api/auth/config", r.applyMiddleware(r.oidcHandler.HandleConfig))
+
+	// Login OIDC (solo si hay un proveedor configurado)
+	if r.oidcHandler != nil {
+		mux.HandleFunc("/auth/login", r.applyMiddleware(r.oidcHandler.HandleLogin))
+		mux.HandleFunc("/auth/callback", r.applyMiddleware(r.oidcHandler.HandleCallback))
+	}
+
+	// Workspaces del usuario autenticado
+	mux.HandleFunc("/api/workspaces", r.protect(entities.ScopeRead, r.workspaceHandler.HandleList))
 
 	// API endpoints con middleware
-	mux.HandleFunc("/upload", r.applyMiddleware(r.uploadHandler.Handle))
-	mux.HandleFunc("/estimate", r.applyMiddleware(r.estimateHandler.Handle))
+	mux.HandleFunc("/upload", r.protect(entities.ScopeAnalyze, r.uploadHandler.Handle))
+	mux.HandleFunc("/estimate", r.protect(entities.ScopeAnalyze, r.estimateHandler.Handle))
+
+	// Estado de la cola del LLM
+	mux.HandleFunc("/api/queue", r.protect(entities.ScopeRead, r.queueHandler.Handle))
+
+	// Perfiles LLM (las API keys quedan en el servidor). Quien puede analizar administra sus
+	// perfiles personales; los compartidos del workspace requieren el rol admin en el workspace
+	mux.HandleFunc("/api/profiles", r.protect(entities.ScopeAnalyze, r.profileHandler.HandleCollection))
+	mux.HandleFunc("/api/profiles/get", r.protect(entities.ScopeAnalyze, r.profileHandler.HandleGetByID))
+	mux.HandleFunc("/api/profiles/update", r.protect(entities.ScopeAnalyze, r.profileHandler.HandleUpdate))
+	mux.HandleFunc("/api/profiles/delete", r.protect(entities.ScopeAnalyze, r.profileHandler.HandleDelete))
 
 	// History endpoints
-	mux.HandleFunc("/api/contracts", r.applyMiddleware(r.historyHandler.HandleList))
-	mux.HandleFunc("/api/contracts/search", r.applyMiddleware(r.historyHandler.HandleSearch))
-	mux.HandleFunc("/api/contracts/recent", r.applyMiddleware(r.historyHandler.HandleGetRecent))
-	mux.HandleFunc("/api/contracts/stats", r.applyMiddleware(r.historyHandler.HandleGetStats))
-	mux.HandleFunc("/api/contracts/get", r.applyMiddleware(r.historyHandler.HandleGetByID))
-	mux.HandleFunc("/api/contracts/delete", r.applyMiddleware(r.historyHandler.HandleDelete))
-
-	// Archivos estáticos con restricciones de seguridad
+	mux.HandleFunc("/api/contracts", r.protect(entities.ScopeRead, r.historyHandler.HandleList))
+	mux.HandleFunc("/api/contracts/search", r.protect(entities.ScopeRead, r.historyHandler.HandleSearch))
+	mux.HandleFunc("/api/contracts/recent", r.protect(entities.ScopeRead, r.historyHandler.HandleGetRecent))
+	mux.HandleFunc("/api/contracts/stats", r.protect(entities.ScopeRead, r.historyHandler.HandleGetStats))
+	mux.HandleFunc("/api/contracts/get", r.protect(entities.ScopeRead, r.historyHandler.HandleGetByID))
+	mux.HandleFunc("/api/contracts/export", r.protect(entities.ScopeRead, r.historyHandler.HandleExport))
+	mux.HandleFunc("/api/contracts/delete", r.protect(entities.ScopeDelete, r.historyHandler.HandleDelete))
+
+	// Papelera y legal hold
+	mux.HandleFunc("/api/contracts/trash", r.protect(entities.ScopeRead, r.historyHandler.HandleTrash))
+	mux.HandleFunc("/api/contracts/restore", r.protect(entities.ScopeDelete, r.historyHandler.HandleRestore))
+	mux.HandleFunc("/api/contracts/purge", r.protect(entities.ScopeDelete, r.historyHandler.HandlePurge))
+	mux.HandleFunc("/api/contracts/legal-hold", r.protect(entities.ScopeDelete, r.historyHandler.HandleLegalHold))
+
+	// Subida por lotes (varios PDF o archivos ZIP)
+	mux.HandleFunc("POST /api/batches", r.protect(entities.ScopeAnalyze, r.batchHandler.HandleCreate))
+	mux.HandleFunc("GET /api/batches", r.protect(entities.ScopeRead, r.batchHandler.HandleList))
+	mux.HandleFunc("/api/batches", r.applyMiddleware(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}))
+	mux.HandleFunc("/api/batches/get", r.protect(entities.ScopeRead, r.batchHandler.HandleGetByID))
+	mux.HandleFunc("/api/batches/export", r.protect(entities.ScopeRead, r.batchHandler.HandleExport))
+
+	// Log de auditoría (solo administradores)
+	mux.HandleFunc("/api/audit", r.protect(entities.ScopeAdmin, r.auditHandler.HandleList))
+	mux.HandleFunc("/api/audit/export", r.protect(entities.ScopeAdmin, r.auditHandler.HandleExport))
+	mux.HandleFunc("/api/audit/verify", r.protect(entities.ScopeAdmin, r.auditHandler.HandleVerify))
+
+	// API v1: rutas orientadas a recursos, errores RFC 7807, ETag y paginación por cursor
+	mux.HandleFunc("GET /api/v1/contracts", r.protectV1(entities.ScopeRead, r.historyHandler.HandleV1List))
+	mux.HandleFunc("GET /api/v1/contracts/{id}", r.protectV1(entities.ScopeRead, r.historyHandler.HandleV1Get))
+	mux.HandleFunc("DELETE /api/v1/contracts/{id}", r.protectV1(entities.ScopeDelete, r.historyHandler.HandleV1Delete))
+	mux.HandleFunc("GET /api/v1/contracts/{id}/export", r.protectV1(entities.ScopeRead, r.historyHandler.HandleV1Export))
+	mux.HandleFunc("/api/v1/contracts", methodNotAllowed("GET"))
+	mux.HandleFunc("/api/v1/contracts/{id}", methodNotAllowed("GET, DELETE"))
+	mux.HandleFunc("/api/v1/contracts/{id}/export", methodNotAllowed("GET"))
+
+	// Webhooks del workspace (solo administradores)
+	mux.HandleFunc("GET /api/v1/webhooks", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleList))
+	mux.HandleFunc("POST /api/v1/webhooks", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleCreate))
+	mux.HandleFunc("GET /api/v1/webhooks/{id}", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleGet))
+	mux.HandleFunc("PATCH /api/v1/webhooks/{id}", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleUpdate))
+	mux.HandleFunc("DELETE /api/v1/webhooks/{id}", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleDelete))
+	mux.HandleFunc("GET /api/v1/webhooks/{id}/deliveries", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleDeliveries))
+	mux.HandleFunc("POST /api/v1/webhooks/{id}/test", r.protectV1(entities.ScopeAdmin, r.webhookHandler.HandleTest))
+	mux.HandleFunc("/api/v1/webhooks", methodNotAllowed("GET, POST"))
+	mux.HandleFunc("/api/v1/webhooks/{id}", methodNotAllowed("GET, PATCH, DELETE"))
+	mux.HandleFunc("/api/v1/webhooks/{id}/deliveries", methodNotAllowed("GET"))
+	mux.HandleFunc("/api/v1/webhooks/{id}/test", methodNotAllowed("POST"))
+	mux.HandleFunc("/api/v1/", r.applyMiddleware(problem.Enable(handlers.HandleV1NotFound)))
+
+	// Archivos estáticos con restricciones de seguridad. Son públicos a propósito: la
+	// interfaz web no contiene datos y los pide a las rutas protegidas de arriba con la
+	// sesión del usuario.
 	fileServer := http.FileServer(http.Dir(r.staticPath))
 	mux.Handle("/", r.secureStaticFileServer(fileServer))
 
-	return mux
+	r.patterns = mux.patterns
+	return mux.ServeMux
+}
+
+// routeMux recuerda los patrones registrados para que las pruebas los comparen con
+// api/openapi.json
+type routeMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
 }
 
 // applyMiddleware aplica los middlewares a un handler
 func (r *Router) applyMiddleware(handler http.HandlerFunc) http.HandlerFunc {
-	return middleware.Recovery(middleware.Logging(handler))
+	var observer middleware.RequestObserver
+	if r.metrics != nil {
+		observer = r.metrics
+	}
+	return middleware.RequestID(middleware.Recovery(middleware.Tracing(r.tracer, middleware.Logging(observer, middleware.ClientIP(handler)))))
+}
+
+// protect aplica los middlewares y exige autenticación con el scope indicado
+func (r *Router) protect(scope entities.Scope, handler http.HandlerFunc) http.HandlerFunc {
+	return r.applyMiddleware(middleware.Auth(r.authenticator, scope, handler))
+}
+
+// protectV1 es como protect pero los errores se responden como problem+json
+func (r *Router) protectV1(scope entities.Scope, handler http.HandlerFunc) http.HandlerFunc {
+	return r.applyMiddleware(problem.Enable(middleware.Auth(r.authenticator, scope, handler)))
+}
+
+// methodNotAllowed responde 405 problem+json; ServeMux lo usa para los métodos que no
+// tienen una ruta más específica registrada
+func methodNotAllowed(allow string) http.HandlerFunc {
+	return problem.Enable(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Allow", allow)
+		problem.Write(w, req, http.StatusMethodNotAllowed, "Método no permitido, usar "+allow)
+	})
 }
 
 // secureStaticFileServer añade headers de seguridad a los archivos estáticos
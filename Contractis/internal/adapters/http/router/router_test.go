@@ -0,0 +1,119 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/api"
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/metrics"
+)
+
+// denyAll rechaza toda credencial, como un servidor sin API keys
+type denyAll struct{}
+
+func (denyAll) Authenticate(ctx context.Context, credential string) (*entities.Principal, error) {
+	return nil, entities.ErrUnauthenticated
+}
+
+// Los archivos estáticos de la interfaz web son públicos a propósito: no contienen datos
+// y la interfaz los pide a la API con la sesión del usuario. Todo lo que expone datos exige
+// una credencial.
+func TestStaticFilesArePublicAndDataRoutesProtected(t *testing.T) {
+	static := t.TempDir()
+	for name, content := range map[string]string{"index.html": "<html></html>", "app.js": "init();"} {
+		if err := os.WriteFile(filepath.Join(static, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mux := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, denyAll{}, nil, nil, static).Setup()
+
+	for _, path := range []string{"/", "/app.js"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200 without credentials", path, w.Code)
+		}
+	}
+
+	protected := []string{
+		"/upload", "/estimate", "/api/queue", "/api/workspaces", "/api/profiles",
+		"/api/contracts", "/api/contracts/get?id=1", "/api/batches", "/api/audit",
+		"/api/auth/me", "/api/v1/contracts", "/api/v1/webhooks",
+	}
+	for _, path := range protected {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s = %d, want 401 without credentials", path, w.Code)
+		}
+	}
+}
+
+// undocumented son las rutas que a propósito no figuran en api/openapi.json. La
+// especificación describe la API de servicio; estas rutas son de la interfaz web, de
+// administración o de infraestructura. Una ruta nueva debe documentarse o agregarse aquí.
+var undocumented = map[string]string{
+	"/":                         "archivos estáticos de la interfaz web",
+	"/health":                   "alias de /healthz por compatibilidad",
+	"/metrics":                  "métricas de Prometheus",
+	"/api/openapi.json":         "la propia especificación",
+	"/api/auth/session":         "sesión del cliente web",
+	"/api/auth/me":              "sesión del cliente web",
+	"/api/auth/logout":          "sesión del cliente web",
+	"/api/auth/config":          "sesión del cliente web",
+	"/auth/login":               "login OIDC del navegador",
+	"/auth/callback":            "login OIDC del navegador",
+	"/api/workspaces":           "selector de workspace de la interfaz web",
+	"/api/queue":                "estado de la cola en la interfaz web",
+	"/api/profiles":             "perfiles LLM de la interfaz web",
+	"/api/profiles/get":         "perfiles LLM de la interfaz web",
+	"/api/profiles/update":      "perfiles LLM de la interfaz web",
+	"/api/profiles/delete":      "perfiles LLM de la interfaz web",
+	"/api/contracts/trash":      "papelera de la interfaz web",
+	"/api/contracts/restore":    "papelera de la interfaz web",
+	"/api/contracts/purge":      "papelera de la interfaz web",
+	"/api/contracts/legal-hold": "legal hold desde la interfaz web",
+	"/api/audit":                "log de auditoría para administradores",
+	"/api/audit/export":         "log de auditoría para administradores",
+	"/api/audit/verify":         "log de auditoría para administradores",
+	"/api/v1/":                  "404 problem+json de las rutas v1 inexistentes",
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	doc, err := api.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, denyAll{}, metrics.New(), nil, t.TempDir())
+	r.Setup()
+	if len(r.patterns) == 0 {
+		t.Fatal("Setup registered no patterns")
+	}
+
+	for _, pattern := range r.patterns {
+		method, path, found := strings.Cut(pattern, " ")
+		if !found {
+			method, path = "", pattern
+		}
+		if _, ok := undocumented[path]; ok {
+			continue
+		}
+
+		item, ok := doc.Paths.Values[path]
+		if !ok {
+			t.Errorf("%s is registered but missing from api/openapi.json", pattern)
+			continue
+		}
+		// Los patrones sin método (p. ej. los 405 de v1) solo exigen que la ruta exista
+		if method != "" && item.Operation(method) == nil {
+			t.Errorf("%s is registered but api/openapi.json has no %s operation for %s", pattern, method, path)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scope es un permiso otorgado a una API key o sesión
+type Scope string
+
+const (
+	// ScopeRead permite consultar el historial de contratos y el estado de la cola
+	ScopeRead Scope = "read"
+	// ScopeAnalyze permite subir contratos para análisis, estimar tokens y gestionar perfiles LLM
+	// (los compartidos requieren además el rol admin del workspace)
+	ScopeAnalyze Scope = "analyze"
+	// ScopeDelete permite eliminar contratos
+	ScopeDelete Scope = "delete"
+	// ScopeAdmin incluye todos los permisos, la auditoría y los webhooks
+	ScopeAdmin Scope = "admin"
+)
+
+// AllScopes lista los scopes conocidos
+var AllScopes = []Scope{ScopeRead, ScopeAnalyze, ScopeDelete, ScopeAdmin}
+
+// ParseScopes convierte una lista separada por comas ("read,analyze") en scopes válidos
+func ParseScopes(value string) ([]Scope, error) {
+	var scopes []Scope
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		scope := Scope(part)
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("unknown scope %q", part)
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+// IsValid indica si el scope es uno de los conocidos
+func (s Scope) IsValid() bool {
+	for _, known := range AllScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey representa una API key emitida. Solo se guarda el hash; la key en claro
+// se muestra una única vez al crearla.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IsRevoked indica si la key fue revocada
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Principal es la identidad autenticada de una petición
+type Principal struct {
+	Subject string  `json:"subject"`
+	UserID  int64   `json:"userId,omitempty"`
+	KeyID   int64   `json:"keyId,omitempty"`
+	Scopes  []Scope `json:"scopes"`
+}
+
+// Requester identifica al principal en el reparto justo de la cola del LLM: todas las
+// credenciales y sesiones de un usuario comparten un mismo turno
+func (p *Principal) Requester() string {
+	if p == nil {
+		return AnonymousRequester
+	}
+	if p.UserID > 0 {
+		return "user:" + strconv.FormatInt(p.UserID, 10)
+	}
+	return p.Subject
+}
+
+// HasScope indica si el principal tiene el permiso; admin los incluye todos
+func (p *Principal) HasScope(scope Scope) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
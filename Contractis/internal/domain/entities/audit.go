@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditAction es una acción registrada en el log de auditoría
+type AuditAction string
+
+const (
+	AuditUpload    AuditAction = "upload"
+	AuditReanalyze AuditAction = "reanalyze"
+	AuditView      AuditAction = "view"
+	AuditExport    AuditAction = "export"
+	AuditDelete    AuditAction = "delete"
+	AuditRestore   AuditAction = "restore"
+	AuditPurge     AuditAction = "purge"
+	AuditHold      AuditAction = "legal_hold"
+	AuditRelease   AuditAction = "legal_hold_release"
+
+	// Los lotes registran su ID en Details; cada análisis del lote queda además como upload
+	AuditBatchUpload AuditAction = "batch_upload"
+	AuditBatchExport AuditAction = "batch_export"
+)
+
+// AuditGenesisHash es el hash previo de la primera entrada de la cadena
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditEntry es una entrada inmutable del log de auditoría. Cada entrada incluye el
+// hash de la anterior, de modo que modificar, borrar o reordenar entradas rompe la cadena.
+type AuditEntry struct {
+	ID          int64       `json:"id"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Actor       string      `json:"actor"`
+	UserID      int64       `json:"userId,omitempty"`
+	WorkspaceID int64       `json:"workspaceId,omitempty"`
+	Action      AuditAction `json:"action"`
+	ContractID  int64       `json:"contractId,omitempty"`
+	IP          string      `json:"ip,omitempty"`
+	Details     string      `json:"details,omitempty"`
+	PrevHash    string      `json:"prevHash"`
+	Hash        string      `json:"hash"`
+}
+
+// ComputeHash calcula el hash SHA-256 de la entrada encadenado con PrevHash.
+// El timestamp se normaliza a UTC con precisión de nanosegundos.
+func (e *AuditEntry) ComputeHash() string {
+	fields := []string{
+		e.PrevHash,
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		e.Actor,
+		strconv.FormatInt(e.UserID, 10),
+		strconv.FormatInt(e.WorkspaceID, 10),
+		string(e.Action),
+		strconv.FormatInt(e.ContractID, 10),
+		e.IP,
+		e.Details,
+	}
+	// \x1f (separador de unidad) no aparece en los valores, evita ambigüedad al concatenar
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditFilter acota una consulta del log de auditoría; los campos vacíos no filtran
+type AuditFilter struct {
+	WorkspaceID int64
+	UserID      int64
+	ContractID  int64
+	Action      AuditAction
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+	Offset      int
+}
+
+// AuditVerification es el resultado de recorrer y verificar la cadena de hashes
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
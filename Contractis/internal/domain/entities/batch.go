@@ -0,0 +1,138 @@
+package entities
+
+import "time"
+
+// Límites de una subida por lotes. Un ZIP se descomprime entrada por entrada contando los
+// bytes realmente escritos, así que los tamaños declarados en el archivo no alcanzan para
+// saltearlos (zip bombs).
+const (
+	// MaxBatchUploadSize es el tamaño máximo del cuerpo de POST /api/batches
+	MaxBatchUploadSize = 100 * 1024 * 1024
+	// MaxBatchDocuments es la cantidad máxima de PDF de un lote
+	MaxBatchDocuments = 100
+	// MaxBatchEntries es la cantidad máxima de entradas de un ZIP, PDF o no
+	MaxBatchEntries = 1000
+	// MaxBatchExtractedSize es el tamaño máximo del lote ya descomprimido
+	MaxBatchExtractedSize = 200 * 1024 * 1024
+	// MaxCompressionRatio es la relación máxima entre tamaño descomprimido y comprimido de una entrada
+	MaxCompressionRatio = 100
+	// BatchConcurrency es la cantidad de documentos de un lote que se analizan a la vez
+	BatchConcurrency = 2
+)
+
+// BatchStatus es el estado de un lote
+type BatchStatus string
+
+const (
+	BatchProcessing BatchStatus = "processing"
+	BatchCompleted  BatchStatus = "completed"
+)
+
+// BatchDocumentStatus es el estado de un documento dentro de un lote
+type BatchDocumentStatus string
+
+const (
+	BatchDocPending   BatchDocumentStatus = "pending"
+	BatchDocAnalyzing BatchDocumentStatus = "analyzing"
+	BatchDocCompleted BatchDocumentStatus = "completed"
+	// BatchDocDuplicate indica que otro documento del lote tiene el mismo contenido
+	BatchDocDuplicate BatchDocumentStatus = "duplicate"
+	BatchDocFailed    BatchDocumentStatus = "failed"
+	// BatchDocRejected indica que el archivo no se aceptó (no es PDF, excede límites, ruta inválida)
+	BatchDocRejected BatchDocumentStatus = "rejected"
+)
+
+// Done indica si el documento ya no cambiará de estado
+func (s BatchDocumentStatus) Done() bool {
+	return s != BatchDocPending && s != BatchDocAnalyzing
+}
+
+// Batch es una subida de varios documentos (sueltos o en ZIP) analizados en conjunto
+type Batch struct {
+	ID          int64            `json:"id"`
+	WorkspaceID int64            `json:"workspace_id"`
+	Name        string           `json:"name"`
+	Status      BatchStatus      `json:"status"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+	Stats       BatchStats       `json:"stats"`
+	Documents   []*BatchDocument `json:"documents,omitempty"`
+}
+
+// BatchDocument es un documento de un lote y el estado de su análisis
+type BatchDocument struct {
+	ID       int64 `json:"id"`
+	BatchID  int64 `json:"batch_id"`
+	Position int   `json:"position"`
+	// Filename es el nombre subido; dentro de un ZIP, "archivo.zip/ruta/interna.pdf"
+	Filename   string              `json:"filename"`
+	FileHash   string              `json:"file_hash,omitempty"`
+	FileSize   int64               `json:"file_size"`
+	Status     BatchDocumentStatus `json:"status"`
+	ContractID int64               `json:"contract_id,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	StartedAt  *time.Time          `json:"started_at,omitempty"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+
+	// Path es la copia temporal del archivo mientras se procesa el lote
+	Path string `json:"-"`
+}
+
+// BatchStats resume el avance de un lote
+type BatchStats struct {
+	Total      int `json:"total"`
+	Pending    int `json:"pending"`
+	Analyzing  int `json:"analyzing"`
+	Completed  int `json:"completed"`
+	Duplicates int `json:"duplicates"`
+	Failed     int `json:"failed"`
+	Rejected   int `json:"rejected"`
+	// Progress es el porcentaje de documentos terminados (0 a 100)
+	Progress float64 `json:"progress"`
+	// TotalSize suma el tamaño de los documentos aceptados
+	TotalSize             int64   `json:"total_size"`
+	ProcessingTimeSeconds float64 `json:"processing_time_seconds"`
+}
+
+// Count suma count documentos en el estado indicado, con su tamaño y tiempo de proceso
+func (s *BatchStats) Count(status BatchDocumentStatus, count int, size int64, seconds float64) {
+	s.Total += count
+	if status != BatchDocRejected {
+		s.TotalSize += size
+	}
+	s.ProcessingTimeSeconds += seconds
+	switch status {
+	case BatchDocPending:
+		s.Pending += count
+	case BatchDocAnalyzing:
+		s.Analyzing += count
+	case BatchDocCompleted:
+		s.Completed += count
+	case BatchDocDuplicate:
+		s.Duplicates += count
+	case BatchDocFailed:
+		s.Failed += count
+	case BatchDocRejected:
+		s.Rejected += count
+	}
+	if s.Total > 0 {
+		s.Progress = float64(s.Total-s.Pending-s.Analyzing) * 100 / float64(s.Total)
+	}
+}
+
+// ComputeStats recalcula Stats a partir de Documents
+func (b *Batch) ComputeStats() {
+	b.Stats = BatchStats{}
+	for _, doc := range b.Documents {
+		b.Stats.Count(doc.Status, 1, doc.FileSize, doc.Duration())
+	}
+}
+
+// Duration retorna los segundos que tomó procesar el documento (cero si no terminó)
+func (d *BatchDocument) Duration() float64 {
+	if d.StartedAt == nil || d.FinishedAt == nil {
+		return 0
+	}
+	return d.FinishedAt.Sub(*d.StartedAt).Seconds()
+}
@@ -25,8 +25,29 @@ const (
 	HTTPTimeoutOnline     = 5 * time.Minute   // Para modelos online (necesitan tiempo para procesar chunks grandes)
 	TestConnectionTimeout = 10 * time.Second  // Para pruebas de conexión
 	ConsolidationTimeout  = 10 * time.Minute
+	AnalysisTimeout       = 30 * time.Minute // Análisis completo de un contrato
+	LocalAnalysisTimeout  = 45 * time.Minute // Más tiempo para modelos locales
+	StreamIdleTimeout     = 90 * time.Second // Máximo entre fragmentos de una respuesta en streaming
 
 	// Retry configuration
-	MaxRetries = 3
-	RetryDelay = 2 * time.Second
+	MaxRetries    = 3
+	RetryDelay    = 2 * time.Second  // Base del backoff exponencial
+	MaxRetryDelay = 60 * time.Second // Tope del backoff y de Retry-After
+
+	// Concurrency configuration (fase 1, chunks simultáneos por proveedor)
+	DefaultLocalConcurrency  = 1 // Servidores locales (Ollama, LM Studio) procesan de a una petición
+	DefaultOnlineConcurrency = 8 // APIs online soportan peticiones concurrentes
+	MaxConcurrency           = 32
+
+	// Scheduler global: peticiones simultáneas por endpoint LLM (todas las fuentes)
+	LocalEndpointSlots  = 1
+	OnlineEndpointSlots = 16
+
+	// Autenticación
+	SessionTTL        = 12 * time.Hour
+	SessionCookieName = "contractis_session"
+
+	// Retención: los contratos eliminados quedan en la papelera este tiempo antes de purgarse
+	DefaultTrashRetention  = 30 * 24 * time.Hour
+	RetentionSweepInterval = time.Hour
 )
@@ -0,0 +1,91 @@
+package entities
+
+import "context"
+
+type contextKey string
+
+const (
+	requesterKey contextKey = "requester"
+	tokenSinkKey contextKey = "token_sink"
+	principalKey contextKey = "principal"
+	clientIPKey  contextKey = "client_ip"
+	requestIDKey contextKey = "request_id"
+	contractKey  contextKey = "contract_id"
+)
+
+// TokenSink recibe los fragmentos parciales del reporte final a medida que el LLM los genera
+type TokenSink func(token string)
+
+// AnonymousRequester identifica peticiones sin un solicitante conocido
+const AnonymousRequester = "anonymous"
+
+// WithRequester asocia al contexto el identificador de quien origina la petición.
+// El scheduler del LLM lo usa para repartir los turnos de forma justa entre usuarios.
+func WithRequester(ctx context.Context, requester string) context.Context {
+	return context.WithValue(ctx, requesterKey, requester)
+}
+
+// RequesterFromContext retorna el solicitante asociado al contexto o AnonymousRequester
+func RequesterFromContext(ctx context.Context) string {
+	if requester, ok := ctx.Value(requesterKey).(string); ok && requester != "" {
+		return requester
+	}
+	return AnonymousRequester
+}
+
+// WithTokenSink asocia al contexto un receptor de tokens parciales del reporte final
+func WithTokenSink(ctx context.Context, sink TokenSink) context.Context {
+	return context.WithValue(ctx, tokenSinkKey, sink)
+}
+
+// TokenSinkFromContext retorna el receptor de tokens del contexto, o nil si no hay
+func TokenSinkFromContext(ctx context.Context) TokenSink {
+	sink, _ := ctx.Value(tokenSinkKey).(TokenSink)
+	return sink
+}
+
+// WithPrincipal asocia al contexto la identidad autenticada de la petición
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext retorna la identidad autenticada, o nil si la petición no pasó por autenticación
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey).(*Principal)
+	return principal
+}
+
+// WithClientIP asocia al contexto la dirección IP del cliente que originó la petición
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ClientIPFromContext retorna la IP del cliente, o "" si no se conoce
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+// WithRequestID asocia al contexto el identificador de la petición HTTP, que acompaña a
+// todos los logs emitidos con ese contexto
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext retorna el identificador de la petición, o "" si no hay
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithContractID asocia al contexto el contrato que se está analizando, para
+// correlacionar los logs del análisis
+func WithContractID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, contractKey, id)
+}
+
+// ContractIDFromContext retorna el contrato del contexto, o 0 si no hay
+func ContractIDFromContext(ctx context.Context) int64 {
+	id, _ := ctx.Value(contractKey).(int64)
+	return id
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ContractStatus representa el estado de un análisis de contrato
 type ContractStatus string
@@ -10,23 +14,30 @@ const (
 	StatusAnalyzing ContractStatus = "analyzing"
 	StatusCompleted ContractStatus = "completed"
 	StatusFailed    ContractStatus = "failed"
+	// StatusInterrupted indica un análisis cortado al detenerse el servidor; volver a
+	// analizar el mismo archivo lo retoma desde su checkpoint
+	StatusInterrupted ContractStatus = "interrupted"
 )
 
 // ContractRecord representa un registro de contrato analizado en la base de datos
 type ContractRecord struct {
-	ID         int64          `json:"id"`
-	Filename   string         `json:"filename"`
-	FileHash   string         `json:"file_hash"`
-	FileSize   int64          `json:"file_size"`
-	UploadedAt time.Time      `json:"uploaded_at"`
-	AnalyzedAt *time.Time     `json:"analyzed_at,omitempty"`
-	Status     ContractStatus `json:"status"`
+	ID          int64          `json:"id"`
+	WorkspaceID int64          `json:"workspace_id"`
+	Filename    string         `json:"filename"`
+	FileHash    string         `json:"file_hash"`
+	FileSize    int64          `json:"file_size"`
+	UploadedAt  time.Time      `json:"uploaded_at"`
+	AnalyzedAt  *time.Time     `json:"analyzed_at,omitempty"`
+	Status      ContractStatus `json:"status"`
 
 	// Metadata del análisis
 	LLMType   string `json:"llm_type"`
 	LLMModel  string `json:"llm_model"`
 	MaxTokens int    `json:"max_tokens"`
 
+	// PhaseModels registra qué modelo produjo cada fase (puede variar por failover)
+	PhaseModels map[string][]string `json:"phase_models,omitempty"`
+
 	// Resultados
 	AnalysisResult        string  `json:"analysis_result"`
 	CharacterCount        int     `json:"character_count"`
@@ -38,22 +49,28 @@ type ContractRecord struct {
 	ErrorMessage string    `json:"error_message,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Papelera y retención: un contrato con legal hold no se puede eliminar ni purgar
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	LegalHold       bool       `json:"legal_hold"`
+	LegalHoldReason string     `json:"legal_hold_reason,omitempty"`
 }
 
 // NewContractRecord crea un nuevo registro de contrato
-func NewContractRecord(filename, fileHash string, fileSize int64, llmType, llmModel string, maxTokens int) *ContractRecord {
+func NewContractRecord(workspaceID int64, filename, fileHash string, fileSize int64, llmType, llmModel string, maxTokens int) *ContractRecord {
 	now := time.Now()
 	return &ContractRecord{
-		Filename:   filename,
-		FileHash:   fileHash,
-		FileSize:   fileSize,
-		UploadedAt: now,
-		Status:     StatusPending,
-		LLMType:    llmType,
-		LLMModel:   llmModel,
-		MaxTokens:  maxTokens,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		WorkspaceID: workspaceID,
+		Filename:    filename,
+		FileHash:    fileHash,
+		FileSize:    fileSize,
+		UploadedAt:  now,
+		Status:      StatusPending,
+		LLMType:     llmType,
+		LLMModel:    llmModel,
+		MaxTokens:   maxTokens,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 }
 
@@ -82,3 +99,30 @@ func (cr *ContractRecord) MarkFailed(errorMsg string) {
 	cr.ErrorMessage = errorMsg
 	cr.UpdatedAt = time.Now()
 }
+
+// MarkInterrupted marca el análisis como interrumpido (se puede retomar)
+func (cr *ContractRecord) MarkInterrupted(reason string) {
+	cr.Status = StatusInterrupted
+	cr.ErrorMessage = reason
+	cr.UpdatedAt = time.Now()
+}
+
+// AnalysisCheckpoint guarda los fragmentos de la fase 1 ya analizados para que un
+// análisis interrumpido o fallido no vuelva a enviarlos al LLM
+type AnalysisCheckpoint struct {
+	// Key identifica el texto y su división en chunks: si cambia, el checkpoint no sirve
+	Key string `json:"key"`
+	// Fragments son los fragmentos analizados, por índice de chunk
+	Fragments map[int]string `json:"fragments"`
+}
+
+// ExportText retorna el análisis en el formato de texto plano de las exportaciones y reportes
+func (cr *ContractRecord) ExportText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Análisis de Contrato\nArchivo: %s\nModelo: %s (%s)\n", cr.Filename, cr.LLMModel, cr.LLMType)
+	if cr.AnalyzedAt != nil {
+		fmt.Fprintf(&b, "Fecha: %s\n", cr.AnalyzedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(&b, "\n%s\n", cr.AnalysisResult)
+	return b.String()
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// DataKey es una clave de cifrado de datos (DEK) guardada envuelta con la clave maestra.
+// Los datos sensibles se cifran con la DEK activa; las anteriores se conservan para descifrar.
+type DataKey struct {
+	ID         int64      `json:"id"`
+	WrappedKey string     `json:"-"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty"`
+}
@@ -15,8 +15,40 @@ var (
 	ErrLLMConnectionFailed = errors.New("failed to connect to LLM")
 	ErrLLMTimeout          = errors.New("LLM request timeout")
 	ErrInvalidLLMResponse  = errors.New("invalid LLM response")
+	ErrRateLimited         = errors.New("LLM rate limit exceeded")
+	ErrQuotaExceeded       = errors.New("LLM quota exhausted")
+
+	// Profile errors
+	ErrProfileNotFound = errors.New("LLM profile not found")
+	ErrProfileInUse    = errors.New("LLM profile is used as fallback by another profile")
+
+	// Auth errors
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrInvalidAPIKey   = errors.New("invalid or revoked API key")
+	ErrInvalidToken    = errors.New("invalid or expired session token")
+	ErrForbidden       = errors.New("insufficient permissions")
+	ErrOIDCLogin       = errors.New("OIDC login failed")
+
+	// Workspace errors
+	ErrUserNotFound      = errors.New("user not found")
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrContractNotFound  = errors.New("contract not found")
+	ErrLegalHold         = errors.New("contract is under legal hold")
+
+	// Batch errors
+	ErrBatchNotFound    = errors.New("batch not found")
+	ErrTooManyDocuments = errors.New("too many documents in batch")
+	ErrBatchTooLarge    = errors.New("batch exceeds the maximum extracted size")
+	ErrInvalidArchive   = errors.New("invalid ZIP archive")
+	ErrEmptyBatch       = errors.New("batch has no files")
+
+	// Webhook errors
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook")
 
 	// Processing errors
 	ErrProcessingFailed = errors.New("processing failed")
 	ErrExtractionFailed = errors.New("text extraction failed")
+	ErrShuttingDown     = errors.New("server is shutting down")
+	ErrInterrupted      = errors.New("analysis interrupted, upload the file again to resume it")
 )
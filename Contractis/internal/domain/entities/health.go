@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// HealthStatus es el estado de un componente o del servicio completo
+type HealthStatus string
+
+const (
+	// HealthUp indica que todo funciona
+	HealthUp HealthStatus = "up"
+	// HealthDegraded indica que falla un componente no crítico (p. ej. un LLM); el servicio
+	// sigue listo
+	HealthDegraded HealthStatus = "degraded"
+	// HealthDown indica que falla un componente crítico y el servicio no está listo
+	HealthDown HealthStatus = "down"
+)
+
+// ComponentHealth es el resultado de revisar un componente del que depende el servicio
+type ComponentHealth struct {
+	Name   string       `json:"name"`
+	Status HealthStatus `json:"status"`
+	// Critical indica si el servicio deja de estar listo cuando el componente falla
+	Critical   bool           `json:"critical"`
+	DurationMs int64          `json:"durationMs"`
+	Error      string         `json:"error,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// BuildInfo identifica el binario en ejecución
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// HealthReport es la respuesta de los chequeos de liveness y readiness
+type HealthReport struct {
+	Status        HealthStatus      `json:"status"`
+	Timestamp     time.Time         `json:"timestamp"`
+	UptimeSeconds int64             `json:"uptimeSeconds"`
+	Build         BuildInfo         `json:"build"`
+	Components    []ComponentHealth `json:"components,omitempty"`
+}
+
+// Ready indica si el servicio puede recibir tráfico
+func (r *HealthReport) Ready() bool {
+	return r.Status != HealthDown
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IdentityClaims son los datos de identidad extraídos de un ID token OIDC ya verificado
+type IdentityClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool
+	Name          string
+	Groups        []string
+}
+
+// RoleMapping asigna un rol en un workspace a los miembros de un grupo del proveedor de identidad
+type RoleMapping struct {
+	Group       string
+	WorkspaceID int64
+	Role        Role
+}
+
+// ParseRoleMappings interpreta "grupo=workspace:rol" separados por comas,
+// por ejemplo "legal-admins=1:admin,legal=1:analyst,auditores=2:viewer"
+func ParseRoleMappings(value string) ([]RoleMapping, error) {
+	var mappings []RoleMapping
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, target, ok := strings.Cut(entry, "=")
+		workspace, role, ok2 := strings.Cut(target, ":")
+		if !ok || !ok2 || strings.TrimSpace(group) == "" {
+			return nil, fmt.Errorf("invalid role mapping %q (expected group=workspace:role)", entry)
+		}
+
+		workspaceID, err := strconv.ParseInt(strings.TrimSpace(workspace), 10, 64)
+		if err != nil || workspaceID <= 0 {
+			return nil, fmt.Errorf("invalid workspace ID in role mapping %q", entry)
+		}
+
+		mapping := RoleMapping{
+			Group:       strings.TrimSpace(group),
+			WorkspaceID: workspaceID,
+			Role:        Role(strings.TrimSpace(role)),
+		}
+		if !mapping.Role.IsValid() {
+			return nil, fmt.Errorf("unknown role in role mapping %q", entry)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// ScopesForRole retorna los scopes de sesión equivalentes a un rol de workspace.
+// El scope admin (perfiles LLM) no se otorga por rol: queda reservado a API keys.
+func ScopesForRole(role Role) []Scope {
+	switch role {
+	case RoleAdmin:
+		return []Scope{ScopeRead, ScopeAnalyze, ScopeDelete}
+	case RoleAnalyst:
+		return []Scope{ScopeRead, ScopeAnalyze}
+	case RoleViewer:
+		return []Scope{ScopeRead}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package entities
+
+import "time"
+
+// DefaultIngestInterval es la frecuencia con que el modo watch revisa la carpeta de entrada
+const DefaultIngestInterval = 10 * time.Second
+
+// IngestDocument es un archivo encontrado al escanear una carpeta de entrada
+type IngestDocument struct {
+	Path    string    `json:"path"`
+	RelPath string    `json:"rel_path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash,omitempty"`
+}
+
+// IngestStatus es el resultado de procesar un documento de la carpeta
+type IngestStatus string
+
+const (
+	// IngestAnalyzed indica que el documento se analizó en esta pasada
+	IngestAnalyzed IngestStatus = "analyzed"
+	// IngestSkipped indica que ya existía un análisis completado con el mismo hash
+	IngestSkipped IngestStatus = "skipped"
+	// IngestDuplicate indica que otro archivo de la misma pasada tiene el mismo contenido
+	IngestDuplicate IngestStatus = "duplicate"
+	// IngestFailed indica que el análisis o la escritura del reporte fallaron
+	IngestFailed IngestStatus = "failed"
+)
+
+// IngestOptions configura una pasada de ingesta
+type IngestOptions struct {
+	WorkspaceID int64
+	// Concurrency es la cantidad de análisis simultáneos (el scheduler del LLM sigue regulando el endpoint)
+	Concurrency int
+	// Force vuelve a analizar documentos que ya tienen un análisis completado
+	Force bool
+}
+
+// IngestResult es el resultado de un documento
+type IngestResult struct {
+	Path       string       `json:"path"`
+	Hash       string       `json:"hash,omitempty"`
+	Status     IngestStatus `json:"status"`
+	ContractID int64        `json:"contract_id,omitempty"`
+	Report     string       `json:"report,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// IngestSummary resume una pasada de ingesta
+type IngestSummary struct {
+	Results    []IngestResult `json:"results"`
+	Analyzed   int            `json:"analyzed"`
+	Skipped    int            `json:"skipped"`
+	Duplicates int            `json:"duplicates"`
+	Failed     int            `json:"failed"`
+}
+
+// Add incorpora un resultado y actualiza los contadores
+func (s *IngestSummary) Add(result IngestResult) {
+	s.Results = append(s.Results, result)
+	switch result.Status {
+	case IngestAnalyzed:
+		s.Analyzed++
+	case IngestSkipped:
+		s.Skipped++
+	case IngestDuplicate:
+		s.Duplicates++
+	case IngestFailed:
+		s.Failed++
+	}
+}
@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// Limits agrupa los límites de subida y análisis que se pueden configurar. Las constantes
+// de este paquete son sus valores por defecto.
+type Limits struct {
+	// MaxFileSize es el tamaño máximo de un PDF
+	MaxFileSize int64
+	// MaxBatchUploadSize es el tamaño máximo del cuerpo de POST /api/batches
+	MaxBatchUploadSize int64
+	// MaxBatchDocuments es la cantidad máxima de PDF de un lote
+	MaxBatchDocuments int
+	// MaxBatchEntries es la cantidad máxima de entradas de un ZIP
+	MaxBatchEntries int
+	// MaxBatchExtractedSize es el tamaño máximo del lote ya descomprimido
+	MaxBatchExtractedSize int64
+	// BatchConcurrency es la cantidad de documentos de un lote que se analizan a la vez
+	BatchConcurrency int
+	// AnalysisTimeout limita el análisis completo de un contrato con un LLM online
+	AnalysisTimeout time.Duration
+	// LocalAnalysisTimeout limita el análisis completo con un LLM local
+	LocalAnalysisTimeout time.Duration
+}
+
+// DefaultLimits retorna los límites por defecto
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFileSize:           MaxFileSize,
+		MaxBatchUploadSize:    MaxBatchUploadSize,
+		MaxBatchDocuments:     MaxBatchDocuments,
+		MaxBatchEntries:       MaxBatchEntries,
+		MaxBatchExtractedSize: MaxBatchExtractedSize,
+		BatchConcurrency:      BatchConcurrency,
+		AnalysisTimeout:       AnalysisTimeout,
+		LocalAnalysisTimeout:  LocalAnalysisTimeout,
+	}
+}
+
+// AnalysisTimeoutFor retorna el tiempo máximo de análisis según el tipo de LLM
+func (l Limits) AnalysisTimeoutFor(config *LLMConfig) time.Duration {
+	if config != nil && config.Type == "local" {
+		return l.LocalAnalysisTimeout
+	}
+	return l.AnalysisTimeout
+}
+
+// FileSizeMB retorna MaxFileSize en megabytes, para los mensajes de error
+func (l Limits) FileSizeMB() int64 {
+	return l.MaxFileSize / (1024 * 1024)
+}
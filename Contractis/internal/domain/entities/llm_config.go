@@ -1,6 +1,26 @@
 package entities
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// FailoverCondition indica qué tipo de error habilita pasar al siguiente endpoint
+type FailoverCondition string
+
+const (
+	FailoverOnConnection FailoverCondition = "connection"
+	FailoverOnTimeout    FailoverCondition = "timeout"
+	FailoverOnRateLimit  FailoverCondition = "rate_limit"
+)
+
+// DefaultFailoverConditions se aplican cuando la configuración no define reglas
+var DefaultFailoverConditions = []FailoverCondition{
+	FailoverOnConnection,
+	FailoverOnTimeout,
+	FailoverOnRateLimit,
+}
 
 // LLMConfig representa la configuración del modelo de lenguaje
 type LLMConfig struct {
@@ -10,6 +30,20 @@ type LLMConfig struct {
 	ApiKey    string
 	ModelName string
 	MaxTokens int
+
+	// Concurrency es la cantidad máxima de chunks de fase 1 procesados en paralelo.
+	// Cero usa el valor por defecto del proveedor (ver GetConcurrency).
+	Concurrency int
+
+	// Límites por minuto de la API key (cero = sin límite local; se respetan igual los headers del proveedor)
+	RequestsPerMinute int
+	TokensPerMinute   int
+
+	// Fallbacks son endpoints/modelos alternativos, en orden de preferencia
+	Fallbacks []*LLMConfig
+
+	// FailoverOn define qué errores habilitan el failover (vacío = DefaultFailoverConditions)
+	FailoverOn []FailoverCondition
 }
 
 // NewLLMConfig crea una nueva configuración de LLM
@@ -46,9 +80,76 @@ func (cfg *LLMConfig) Validate() error {
 	if cfg.MaxTokens <= 0 {
 		return errors.New("maxTokens must be positive")
 	}
+	if cfg.Concurrency < 0 || cfg.Concurrency > MaxConcurrency {
+		return fmt.Errorf("concurrency must be between 0 and %d", MaxConcurrency)
+	}
+	if cfg.RequestsPerMinute < 0 || cfg.TokensPerMinute < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+	for _, condition := range cfg.FailoverOn {
+		switch condition {
+		case FailoverOnConnection, FailoverOnTimeout, FailoverOnRateLimit:
+		default:
+			return fmt.Errorf("unknown failover condition %q", condition)
+		}
+	}
+	for i, fallback := range cfg.Candidates()[1:] {
+		if len(fallback.Fallbacks) > 0 {
+			return fmt.Errorf("fallback %d: nested fallbacks are not supported", i+1)
+		}
+		if err := fallback.Validate(); err != nil {
+			return fmt.Errorf("fallback %d: %w", i+1, err)
+		}
+	}
 	return nil
 }
 
+// Candidates retorna la cadena de failover: la configuración principal seguida de
+// los fallbacks. Los fallbacks heredan maxTokens y concurrencia si no los definen.
+func (cfg *LLMConfig) Candidates() []*LLMConfig {
+	primary := *cfg
+	primary.Fallbacks = nil
+
+	candidates := []*LLMConfig{&primary}
+	for _, fallback := range cfg.Fallbacks {
+		candidate := *fallback
+		if candidate.MaxTokens <= 0 {
+			candidate.MaxTokens = cfg.MaxTokens
+		}
+		if candidate.Concurrency == 0 {
+			candidate.Concurrency = cfg.Concurrency
+		}
+		candidates = append(candidates, &candidate)
+	}
+	return candidates
+}
+
+// ShouldFailover indica si el error habilita probar el siguiente endpoint de la cadena
+func (cfg *LLMConfig) ShouldFailover(err error) bool {
+	conditions := cfg.FailoverOn
+	if len(conditions) == 0 {
+		conditions = DefaultFailoverConditions
+	}
+
+	for _, condition := range conditions {
+		switch condition {
+		case FailoverOnConnection:
+			if errors.Is(err, ErrLLMConnectionFailed) {
+				return true
+			}
+		case FailoverOnTimeout:
+			if errors.Is(err, ErrLLMTimeout) {
+				return true
+			}
+		case FailoverOnRateLimit:
+			if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrQuotaExceeded) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GetEndpointURL retorna la URL del endpoint según el tipo de LLM
 func (cfg *LLMConfig) GetEndpointURL() string {
 	if cfg.Type == "local" {
@@ -57,6 +158,16 @@ func (cfg *LLMConfig) GetEndpointURL() string {
 	return cfg.ApiUrl
 }
 
+// Label identifica al candidato como "modelo@host/ruta" para registrar qué endpoint
+// respondió; omite credenciales y query del endpoint
+func (cfg *LLMConfig) Label() string {
+	endpoint := cfg.GetEndpointURL()
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		endpoint = u.Host + u.Path
+	}
+	return cfg.ModelName + "@" + endpoint
+}
+
 // IsOnline verifica si el LLM está configurado para uso online
 func (cfg *LLMConfig) IsOnline() bool {
 	return cfg.Type == "online"
@@ -69,3 +180,14 @@ func (cfg *LLMConfig) GetAuthorizationHeader() string {
 	}
 	return ""
 }
+
+// GetConcurrency retorna la cantidad de workers para la fase 1 según el proveedor
+func (cfg *LLMConfig) GetConcurrency() int {
+	if cfg.Concurrency > 0 {
+		return cfg.Concurrency
+	}
+	if cfg.IsOnline() {
+		return DefaultOnlineConcurrency
+	}
+	return DefaultLocalConcurrency
+}
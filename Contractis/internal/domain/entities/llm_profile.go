@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// LLMProfile representa una configuración LLM con nombre guardada en el servidor, propia
+// de un workspace: su API key solo se usa (y se factura) en los análisis de ese workspace.
+// Sin OwnerID el perfil es compartido por el workspace y lo administran sus admin; con
+// OwnerID es personal y solo lo usa y modifica ese usuario.
+// La API key nunca se serializa hacia los clientes; solo se informa si existe.
+type LLMProfile struct {
+	ID                int64               `json:"id"`
+	WorkspaceID       int64               `json:"workspaceId"`
+	OwnerID           int64               `json:"ownerId,omitempty"`
+	Name              string              `json:"name"`
+	Type              string              `json:"type"`
+	LocalUrl          string              `json:"localUrl,omitempty"`
+	ApiUrl            string              `json:"apiUrl,omitempty"`
+	ApiKey            string              `json:"-"`
+	HasApiKey         bool                `json:"hasApiKey"`
+	ModelName         string              `json:"modelName"`
+	MaxTokens         int                 `json:"maxTokens"`
+	Concurrency       int                 `json:"concurrency,omitempty"`
+	RequestsPerMinute int                 `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int                 `json:"tokensPerMinute,omitempty"`
+	FailoverOn        []FailoverCondition `json:"failoverOn,omitempty"`
+	FallbackIDs       []int64             `json:"fallbackIds,omitempty"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	UpdatedAt         time.Time           `json:"updatedAt"`
+}
+
+// Shared indica si el perfil es del workspace y no de un usuario
+func (p *LLMProfile) Shared() bool {
+	return p.OwnerID == 0
+}
+
+// VisibleTo indica si el miembro ve el perfil: los compartidos y los propios, o todos si es admin
+func (p *LLMProfile) VisibleTo(membership *Membership) bool {
+	return p.UsableBy(membership.UserID) || membership.Role.Allows(RoleAdmin)
+}
+
+// UsableBy indica si el usuario puede analizar con el perfil (y su API key): los
+// compartidos y los propios. Un admin ve los perfiles personales pero no los usa.
+func (p *LLMProfile) UsableBy(userID int64) bool {
+	return p.Shared() || p.OwnerID == userID
+}
+
+// EditableBy indica si el miembro puede modificar el perfil: los compartidos solo un admin
+// y los personales solo su dueño, para que nadie redirija la API key de otro
+func (p *LLMProfile) EditableBy(membership *Membership) bool {
+	if p.Shared() {
+		return membership.Role.Allows(RoleAdmin)
+	}
+	return p.OwnerID == membership.UserID
+}
+
+// DeletableBy indica si el miembro puede eliminar el perfil: además de quien puede
+// modificarlo, un admin puede eliminar los perfiles personales
+func (p *LLMProfile) DeletableBy(membership *Membership) bool {
+	return p.EditableBy(membership) || membership.Role.Allows(RoleAdmin)
+}
+
+// ToLLMConfig convierte el perfil a configuración LLM (sin resolver los fallbacks)
+func (p *LLMProfile) ToLLMConfig() *LLMConfig {
+	config := NewLLMConfig(p.Type, p.LocalUrl, p.ApiUrl, p.ApiKey, p.ModelName, p.MaxTokens)
+	config.Concurrency = p.Concurrency
+	config.RequestsPerMinute = p.RequestsPerMinute
+	config.TokensPerMinute = p.TokensPerMinute
+	config.FailoverOn = p.FailoverOn
+	return config
+}
+
+// Validate valida el perfil
+func (p *LLMProfile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("name is required")
+	}
+	for _, id := range p.FallbackIDs {
+		if id == p.ID && p.ID != 0 {
+			return errors.New("a profile cannot be its own fallback")
+		}
+	}
+	return p.ToLLMConfig().Validate()
+}
@@ -0,0 +1,13 @@
+package entities
+
+// QueueStatus representa el estado de la cola de un endpoint LLM
+type QueueStatus struct {
+	Endpoint string `json:"endpoint"`
+	Limit    int    `json:"limit"`
+	Active   int    `json:"active"`
+	Waiting  int    `json:"waiting"`
+
+	// Position es la posición (1-based) del primer turno en espera del solicitante,
+	// o cero si el solicitante no tiene peticiones encoladas en este endpoint
+	Position int `json:"position"`
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+)
+
+// Redaction guarda la correspondencia reversible entre los marcadores enviados al LLM
+// (por ejemplo "[EMAIL_1]") y los valores originales. Vive solo en memoria durante el análisis.
+type Redaction struct {
+	originals map[string]string // marcador → valor original
+	counts    map[string]int    // tipo → cantidad de valores distintos
+}
+
+// NewRedaction crea un mapeo vacío
+func NewRedaction() *Redaction {
+	return &Redaction{
+		originals: make(map[string]string),
+		counts:    make(map[string]int),
+	}
+}
+
+// Add registra un valor original con su marcador. Si el marcador ya existe se
+// conserva la primera forma vista del valor.
+func (r *Redaction) Add(kind, placeholder, original string) {
+	if _, exists := r.originals[placeholder]; exists {
+		return
+	}
+	r.originals[placeholder] = original
+	r.counts[kind]++
+}
+
+// Len retorna cuántos valores distintos se ocultaron
+func (r *Redaction) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.originals)
+}
+
+// Counts retorna la cantidad de valores ocultados por tipo
+func (r *Redaction) Counts() map[string]int {
+	counts := make(map[string]int, len(r.counts))
+	for kind, n := range r.counts {
+		counts[kind] = n
+	}
+	return counts
+}
+
+// Restore reemplaza los marcadores del texto por los valores originales
+func (r *Redaction) Restore(text string) string {
+	if r.Len() == 0 {
+		return text
+	}
+
+	// Orden determinista; los corchetes evitan que un marcador sea prefijo de otro
+	placeholders := make([]string, 0, len(r.originals))
+	for placeholder := range r.originals {
+		placeholders = append(placeholders, placeholder)
+	}
+	sort.Strings(placeholders)
+
+	pairs := make([]string, 0, 2*len(placeholders))
+	for _, placeholder := range placeholders {
+		pairs = append(pairs, placeholder, r.originals[placeholder])
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// RetentionPolicy define cuánto se conservan los contratos. Un valor cero desactiva el plazo.
+type RetentionPolicy struct {
+	// TrashRetention es el tiempo que un contrato eliminado permanece en la papelera
+	TrashRetention time.Duration
+
+	// ContractRetention es la antigüedad (desde la carga) a partir de la cual un
+	// contrato se envía a la papelera
+	ContractRetention time.Duration
+}
+
+// ShouldPurge indica si un contrato en la papelera cumplió su plazo
+func (p RetentionPolicy) ShouldPurge(record *ContractRecord, now time.Time) bool {
+	return !record.LegalHold &&
+		record.DeletedAt != nil &&
+		p.TrashRetention > 0 &&
+		now.Sub(*record.DeletedAt) >= p.TrashRetention
+}
+
+// ShouldTrash indica si un contrato activo superó el plazo de retención
+func (p RetentionPolicy) ShouldTrash(record *ContractRecord, now time.Time) bool {
+	return !record.LegalHold &&
+		record.DeletedAt == nil &&
+		p.ContractRetention > 0 &&
+		now.Sub(record.UploadedAt) >= p.ContractRetention
+}
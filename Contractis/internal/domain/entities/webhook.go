@@ -0,0 +1,245 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebhookEvent es un evento que se notifica a los webhooks suscritos
+type WebhookEvent string
+
+const (
+	EventAnalysisCompleted WebhookEvent = "analysis.completed"
+	EventAnalysisFailed    WebhookEvent = "analysis.failed"
+	EventContractDeleted   WebhookEvent = "contract.deleted"
+	// EventWebhookTest es el evento de las entregas de prueba; llega aunque el webhook no
+	// esté suscrito a él y no admite suscripción
+	EventWebhookTest WebhookEvent = "webhook.test"
+)
+
+// WebhookEvents lista los eventos a los que se puede suscribir un webhook
+var WebhookEvents = []WebhookEvent{EventAnalysisCompleted, EventAnalysisFailed, EventContractDeleted}
+
+// IsValid indica si se puede suscribir un webhook al evento
+func (e WebhookEvent) IsValid() bool {
+	for _, known := range WebhookEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Valores por defecto de la entrega de webhooks
+const (
+	// WebhookTimeout es el tiempo máximo de cada intento de entrega
+	WebhookTimeout = 10 * time.Second
+	// WebhookMaxAttempts es la cantidad de intentos antes de dar una entrega por fallida
+	WebhookMaxAttempts = 8
+	// WebhookRetryBackoff es la espera antes del primer reintento; se duplica en cada uno
+	WebhookRetryBackoff = 30 * time.Second
+	// WebhookMaxBackoff acota la espera entre dos intentos
+	WebhookMaxBackoff = time.Hour
+	// WebhookResponseLimit es la cantidad de bytes de la respuesta que se guardan en el log
+	WebhookResponseLimit = 1024
+	// WebhookDeliveryRetention es cuánto se conservan las entregas terminadas en el log
+	WebhookDeliveryRetention = 30 * 24 * time.Hour
+	// WebhookPollInterval es cada cuánto se buscan entregas pendientes cuyo reintento venció
+	WebhookPollInterval = 5 * time.Second
+)
+
+// WebhookPolicy define los plazos y reintentos de las entregas
+type WebhookPolicy struct {
+	Timeout      time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// AllowPrivateNetworks permite URLs en direcciones privadas, de loopback o link-local
+	// (instalaciones on-premise); por defecto se rechazan para que un admin de workspace
+	// no pueda alcanzar servicios internos desde el servidor
+	AllowPrivateNetworks bool
+}
+
+// DefaultWebhookPolicy retorna la política de entrega por defecto
+func DefaultWebhookPolicy() WebhookPolicy {
+	return WebhookPolicy{
+		Timeout:      WebhookTimeout,
+		MaxAttempts:  WebhookMaxAttempts,
+		RetryBackoff: WebhookRetryBackoff,
+	}
+}
+
+// Backoff retorna la espera tras el intento fallido número attempt (1 = el primero):
+// RetryBackoff, luego el doble en cada reintento, hasta WebhookMaxBackoff
+func (p WebhookPolicy) Backoff(attempt int) time.Duration {
+	delay := p.RetryBackoff
+	for i := 1; i < attempt && delay < WebhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, WebhookMaxBackoff)
+}
+
+// sharedAddressSpace es el rango de NAT de operadores (RFC 6598), que algunas nubes usan
+// para servicios internos
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPrivateAddress indica si una IP no es un destino público para un webhook: loopback,
+// redes privadas, link-local (incluida la metadata de las nubes), multicast o sin especificar
+func IsPrivateAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// Webhook es la suscripción de un workspace a eventos. Cada evento se entrega con un
+// POST a URL firmado con HMAC-SHA256 usando Secret.
+type Webhook struct {
+	ID          int64          `json:"id"`
+	WorkspaceID int64          `json:"workspace_id"`
+	URL         string         `json:"url"`
+	Description string         `json:"description,omitempty"`
+	Events      []WebhookEvent `json:"events"`
+	Active      bool           `json:"active"`
+	CreatedBy   string         `json:"created_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+
+	// Secret firma las entregas; solo se muestra al crear el webhook o rotar el secreto
+	Secret string `json:"-"`
+}
+
+// Validate comprueba la URL y los eventos del webhook
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: url must not include credentials", ErrInvalidWebhook)
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	for _, event := range w.Events {
+		if !event.IsValid() {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// Subscribed indica si el webhook recibe el evento
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookUpdate son los cambios de un webhook; los campos nil no se modifican
+type WebhookUpdate struct {
+	URL          *string
+	Description  *string
+	Events       []WebhookEvent
+	Active       *bool
+	RotateSecret bool
+}
+
+// DeliveryStatus es el estado de una entrega de webhook
+type DeliveryStatus string
+
+const (
+	// DeliveryPending espera su primer intento o un reintento
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed agotó sus intentos o el webhook se desactivó antes de entregarla
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookDelivery es la entrega de un evento a un webhook, con el resultado de su último intento
+type WebhookDelivery struct {
+	ID          int64          `json:"id"`
+	WebhookID   int64          `json:"webhook_id"`
+	WorkspaceID int64          `json:"workspace_id"`
+	EventID     string         `json:"event_id"`
+	Event       WebhookEvent   `json:"event"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	// ResponseStatus y ResponseBody (recortado a WebhookResponseLimit) son los del último intento
+	ResponseStatus int             `json:"response_status,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	DurationMs     int64           `json:"duration_ms"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+}
+
+// WebhookPayload es el cuerpo JSON de cada entrega
+type WebhookPayload struct {
+	ID          string       `json:"id"`
+	Event       WebhookEvent `json:"event"`
+	CreatedAt   time.Time    `json:"created_at"`
+	WorkspaceID int64        `json:"workspace_id"`
+	Data        any          `json:"data"`
+}
+
+// ContractEvent son los datos de los eventos de un contrato. El análisis no se incluye:
+// el receptor lo obtiene con GET /api/v1/contracts/{id}. Filename falta al purgar un
+// contrato desde la papelera.
+type ContractEvent struct {
+	ContractID            int64          `json:"contract_id"`
+	Filename              string         `json:"filename,omitempty"`
+	FileHash              string         `json:"file_hash,omitempty"`
+	Status                ContractStatus `json:"status,omitempty"`
+	LLMModel              string         `json:"llm_model,omitempty"`
+	ChunksCount           int            `json:"chunks_count,omitempty"`
+	ProcessingTimeSeconds float64        `json:"processing_time_seconds,omitempty"`
+	Error                 string         `json:"error,omitempty"`
+
+	// Permanent indica, en contract.deleted, una purga (ya no se puede restaurar) en vez
+	// del envío a la papelera
+	Permanent bool   `json:"permanent,omitempty"`
+	DeletedBy string `json:"deleted_by,omitempty"`
+}
+
+// NewAnalysisEvent arma los datos de analysis.completed o analysis.failed. El error solo
+// se incluye si el análisis falló: un reintento exitoso conserva el del intento anterior.
+func NewAnalysisEvent(record *ContractRecord) *ContractEvent {
+	event := &ContractEvent{
+		ContractID:            record.ID,
+		Filename:              record.Filename,
+		FileHash:              record.FileHash,
+		Status:                record.Status,
+		LLMModel:              record.LLMModel,
+		ChunksCount:           record.ChunksCount,
+		ProcessingTimeSeconds: record.ProcessingTimeSeconds,
+	}
+	if record.Status == StatusFailed {
+		event.Error = record.ErrorMessage
+	}
+	return event
+}
+
+// NewContractDeletedEvent arma los datos de contract.deleted; quien elimina se toma del
+// principal del contexto
+func NewContractDeletedEvent(ctx context.Context, contractID int64, filename string, permanent bool) *ContractEvent {
+	deletedBy := AnonymousRequester
+	if principal := PrincipalFromContext(ctx); principal != nil && principal.Subject != "" {
+		deletedBy = principal.Subject
+	}
+	return &ContractEvent{
+		ContractID: contractID,
+		Filename:   strings.TrimSpace(filename),
+		Permanent:  permanent,
+		DeletedBy:  deletedBy,
+	}
+}
@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// DefaultWorkspaceID es el workspace creado por la migración, dueño de los contratos previos
+const DefaultWorkspaceID int64 = 1
+
+// Role es el rol de un usuario dentro de un workspace
+type Role string
+
+const (
+	// RoleViewer puede consultar el historial del workspace
+	RoleViewer Role = "viewer"
+	// RoleAnalyst además puede analizar contratos
+	RoleAnalyst Role = "analyst"
+	// RoleAdmin además puede eliminar contratos y gestionar miembros
+	RoleAdmin Role = "admin"
+)
+
+// roleRank ordena los roles: cada rol incluye los permisos de los anteriores
+var roleRank = map[Role]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// IsValid indica si el rol es uno de los conocidos
+func (r Role) IsValid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Allows indica si el rol alcanza el rol requerido
+func (r Role) Allows(required Role) bool {
+	return r.IsValid() && roleRank[r] >= roleRank[required]
+}
+
+// User representa a un usuario del sistema
+type User struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Workspace agrupa los contratos de un equipo
+type Workspace struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Role es el rol del usuario consultante (solo en listados por usuario)
+	Role Role `json:"role,omitempty"`
+}
+
+// Membership es la pertenencia de un usuario a un workspace
+type Membership struct {
+	WorkspaceID int64 `json:"workspaceId"`
+	UserID      int64 `json:"userId"`
+	Role        Role  `json:"role"`
+}
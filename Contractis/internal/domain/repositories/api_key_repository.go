@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// APIKeyRepository define la interfaz para persistencia de API keys (solo hashes)
+type APIKeyRepository interface {
+	// Create guarda una nueva API key
+	Create(ctx context.Context, key *entities.APIKey) (int64, error)
+
+	// GetByID obtiene una API key por su ID
+	GetByID(ctx context.Context, id int64) (*entities.APIKey, error)
+
+	// GetByHash obtiene una API key por el hash de la key en claro
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+
+	// List lista todas las API keys, incluidas las revocadas
+	List(ctx context.Context) ([]*entities.APIKey, error)
+
+	// Revoke marca una API key como revocada
+	Revoke(ctx context.Context, id int64) error
+
+	// TouchLastUsed actualiza la fecha de último uso
+	TouchLastUsed(ctx context.Context, id int64) error
+}
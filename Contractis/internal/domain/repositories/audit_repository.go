@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// AuditRepository define la interfaz del log de auditoría. Es de solo inserción:
+// no existen operaciones de actualización ni borrado.
+type AuditRepository interface {
+	// Append encadena la entrada con la última registrada (PrevHash, Hash) y la guarda
+	Append(ctx context.Context, entry *entities.AuditEntry) error
+
+	// List retorna las entradas que cumplen el filtro, de la más reciente a la más antigua
+	List(ctx context.Context, filter entities.AuditFilter) ([]*entities.AuditEntry, error)
+
+	// ListChain retorna hasta limit entradas con ID mayor a afterID en orden de inserción
+	ListChain(ctx context.Context, afterID int64, limit int) ([]*entities.AuditEntry, error)
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// BatchRepository define la interfaz para persistencia de lotes y sus documentos.
+// Las consultas están acotadas a un workspace.
+type BatchRepository interface {
+	// Create guarda el lote con todos sus documentos y les asigna ID
+	Create(ctx context.Context, batch *entities.Batch) error
+
+	// GetByID obtiene un lote con sus documentos y estadísticas
+	GetByID(ctx context.Context, workspaceID, id int64) (*entities.Batch, error)
+
+	// List lista los lotes del más reciente al más antiguo, con estadísticas y sin documentos
+	List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.Batch, error)
+
+	// UpdateDocument guarda el estado, hash, contrato y error de un documento
+	UpdateDocument(ctx context.Context, doc *entities.BatchDocument) error
+
+	// Finish marca el lote como completado
+	Finish(ctx context.Context, id int64) error
+
+	// FailInterrupted marca como fallidos los documentos sin terminar de los lotes en
+	// proceso (sus archivos temporales se pierden al reiniciar) y cierra esos lotes
+	FailInterrupted(ctx context.Context, reason string) (int, error)
+}
@@ -7,34 +7,68 @@ import (
 	"github.com/rodascaar/contractis/internal/domain/entities"
 )
 
-// ContractRepository define la interfaz para persistencia de contratos
+// ContractRepository define la interfaz para persistencia de contratos.
+// Todas las consultas están acotadas a un workspace y, salvo GetByHash y las
+// operaciones de papelera, ignoran los contratos eliminados.
 type ContractRepository interface {
-	// Create crea un nuevo registro de contrato
+	// Create crea un nuevo registro de contrato en record.WorkspaceID
 	Create(ctx context.Context, record *entities.ContractRecord) (int64, error)
 
 	// GetByID obtiene un contrato por su ID
-	GetByID(ctx context.Context, id int64) (*entities.ContractRecord, error)
+	GetByID(ctx context.Context, workspaceID, id int64) (*entities.ContractRecord, error)
 
-	// GetByHash obtiene un contrato por su hash (para caché)
-	GetByHash(ctx context.Context, hash string) (*entities.ContractRecord, error)
+	// GetByHash obtiene un contrato por su hash (para caché), incluso si está en la papelera
+	GetByHash(ctx context.Context, workspaceID int64, hash string) (*entities.ContractRecord, error)
 
-	// Update actualiza un registro de contrato
+	// Update actualiza un registro de contrato de record.WorkspaceID
 	Update(ctx context.Context, record *entities.ContractRecord) error
 
-	// List lista todos los contratos con paginación
-	List(ctx context.Context, limit, offset int) ([]*entities.ContractRecord, error)
+	// List lista los contratos con paginación
+	List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error)
 
 	// Search busca contratos por nombre de archivo
-	Search(ctx context.Context, query string, limit, offset int) ([]*entities.ContractRecord, error)
+	Search(ctx context.Context, workspaceID int64, query string, limit, offset int) ([]*entities.ContractRecord, error)
+
+	// ListPage lista por ID descendente los contratos con ID menor que beforeID (0 = desde
+	// el más reciente), filtrando por nombre de archivo si query no está vacío. Es la base
+	// de la paginación por cursor, estable aunque se agreguen contratos entre páginas.
+	ListPage(ctx context.Context, workspaceID int64, query string, beforeID int64, limit int) ([]*entities.ContractRecord, error)
 
 	// GetStats obtiene estadísticas de contratos
-	GetStats(ctx context.Context) (*ContractStats, error)
+	GetStats(ctx context.Context, workspaceID int64) (*ContractStats, error)
+
+	// Delete envía un contrato a la papelera. Retorna ErrLegalHold si está retenido.
+	Delete(ctx context.Context, workspaceID, id int64) error
+
+	// ListTrash lista los contratos de la papelera, del eliminado más recientemente al más antiguo
+	ListTrash(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error)
+
+	// Restore saca un contrato de la papelera
+	Restore(ctx context.Context, workspaceID, id int64) error
+
+	// Purge borra definitivamente un contrato de la papelera con su análisis.
+	// Retorna ErrLegalHold si está retenido.
+	Purge(ctx context.Context, workspaceID, id int64) error
 
-	// Delete elimina un contrato por ID
-	Delete(ctx context.Context, id int64) error
+	// SetLegalHold activa o libera el legal hold de un contrato (activo o en la papelera)
+	SetLegalHold(ctx context.Context, workspaceID, id int64, hold bool, reason string) error
+
+	// ListForRetention lista los contratos sin legal hold de todos los workspaces, con
+	// los campos necesarios para aplicar la política de retención (sin el análisis)
+	ListForRetention(ctx context.Context) ([]*entities.ContractRecord, error)
 
 	// GetRecent obtiene los contratos más recientes
-	GetRecent(ctx context.Context, limit int) ([]*entities.ContractRecord, error)
+	GetRecent(ctx context.Context, workspaceID int64, limit int) ([]*entities.ContractRecord, error)
+
+	// SaveCheckpoint guarda el checkpoint del análisis de un contrato; nil lo elimina
+	SaveCheckpoint(ctx context.Context, workspaceID, id int64, checkpoint *entities.AnalysisCheckpoint) error
+
+	// GetCheckpoint obtiene el checkpoint del análisis de un contrato (nil si no tiene)
+	GetCheckpoint(ctx context.Context, workspaceID, id int64) (*entities.AnalysisCheckpoint, error)
+
+	// InterruptStale marca como interrumpidos, en todos los workspaces, los contratos que
+	// quedaron pendientes o analizándose en una ejecución anterior. Retorna cuántos marcó.
+	InterruptStale(ctx context.Context, reason string) (int, error)
 }
 
 // ContractStats representa estadísticas de contratos
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// DataKeyRepository define la interfaz para persistencia de claves de datos envueltas
+type DataKeyRepository interface {
+	// Create guarda una clave nueva y la marca como activa, retirando la anterior
+	Create(ctx context.Context, wrappedKey string) (int64, error)
+
+	// List lista todas las claves, la activa incluida
+	List(ctx context.Context) ([]*entities.DataKey, error)
+
+	// Rewrap reemplaza la clave envuelta (tras rotar la clave maestra)
+	Rewrap(ctx context.Context, id int64, wrappedKey string) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// LLMProfileRepository define la interfaz para persistencia de perfiles LLM.
+// Las implementaciones deben guardar la API key cifrada. Todas las operaciones, salvo
+// ListAll, están acotadas al workspace indicado: un perfil de otro workspace no existe.
+type LLMProfileRepository interface {
+	// Create crea un nuevo perfil en profile.WorkspaceID
+	Create(ctx context.Context, profile *entities.LLMProfile) (int64, error)
+
+	// GetByID obtiene un perfil del workspace por su ID (con la API key descifrada)
+	GetByID(ctx context.Context, workspaceID, id int64) (*entities.LLMProfile, error)
+
+	// List lista los perfiles del workspace
+	List(ctx context.Context, workspaceID int64) ([]*entities.LLMProfile, error)
+
+	// ListAll lista los perfiles de todos los workspaces (chequeos de salud)
+	ListAll(ctx context.Context) ([]*entities.LLMProfile, error)
+
+	// Update actualiza un perfil de profile.WorkspaceID
+	Update(ctx context.Context, profile *entities.LLMProfile) error
+
+	// Delete elimina un perfil del workspace por ID
+	Delete(ctx context.Context, workspaceID, id int64) error
+}
@@ -10,6 +10,9 @@ type LLMRepository interface {
 	// SendChatRequest envía una solicitud de chat al LLM
 	SendChatRequest(ctx context.Context, config *entities.LLMConfig, messages []ChatMessage, maxTokens int) (string, error)
 
+	// StreamChatRequest envía una solicitud en streaming; onToken recibe los fragmentos parciales
+	StreamChatRequest(ctx context.Context, config *entities.LLMConfig, messages []ChatMessage, maxTokens int, onToken func(token string)) (string, error)
+
 	// TestConnection verifica la conexión con el LLM
 	TestConnection(ctx context.Context, config *entities.LLMConfig) error
 }
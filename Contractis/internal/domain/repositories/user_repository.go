@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// UserRepository define la interfaz para persistencia de usuarios
+type UserRepository interface {
+	// Create crea un nuevo usuario
+	Create(ctx context.Context, user *entities.User) (int64, error)
+
+	// GetByID obtiene un usuario por su ID
+	GetByID(ctx context.Context, id int64) (*entities.User, error)
+
+	// GetByEmail obtiene un usuario por su email
+	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+
+	// List lista todos los usuarios
+	List(ctx context.Context) ([]*entities.User, error)
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// WebhookRepository define la interfaz para persistencia de webhooks y su log de entregas.
+// Las consultas de la API están acotadas a un workspace.
+type WebhookRepository interface {
+	// Create crea un webhook
+	Create(ctx context.Context, webhook *entities.Webhook) (int64, error)
+
+	// GetByID obtiene un webhook con su secreto
+	GetByID(ctx context.Context, workspaceID, id int64) (*entities.Webhook, error)
+
+	// List lista los webhooks del workspace
+	List(ctx context.Context, workspaceID int64) ([]*entities.Webhook, error)
+
+	// ListSubscribed lista los webhooks activos del workspace suscritos al evento
+	ListSubscribed(ctx context.Context, workspaceID int64, event entities.WebhookEvent) ([]*entities.Webhook, error)
+
+	// Update guarda la URL, descripción, eventos, estado y secreto del webhook
+	Update(ctx context.Context, webhook *entities.Webhook) error
+
+	// Delete elimina el webhook y sus entregas
+	Delete(ctx context.Context, workspaceID, id int64) error
+
+	// CreateDelivery registra una entrega y le asigna ID
+	CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// UpdateDelivery guarda el resultado del último intento de una entrega
+	UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// ListDeliveries lista por ID descendente las entregas del webhook con ID menor que beforeID (0 = desde la última)
+	ListDeliveries(ctx context.Context, webhookID, beforeID int64, limit int) ([]*entities.WebhookDelivery, error)
+
+	// ListDue lista las entregas pendientes cuyo próximo intento venció, las más antiguas primero
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error)
+
+	// PruneDeliveries borra las entregas terminadas creadas antes de before
+	PruneDeliveries(ctx context.Context, before time.Time) (int, error)
+}
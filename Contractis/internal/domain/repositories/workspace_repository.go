@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// WorkspaceRepository define la interfaz para persistencia de workspaces y sus miembros
+type WorkspaceRepository interface {
+	// Create crea un nuevo workspace
+	Create(ctx context.Context, workspace *entities.Workspace) (int64, error)
+
+	// GetByID obtiene un workspace por su ID
+	GetByID(ctx context.Context, id int64) (*entities.Workspace, error)
+
+	// List lista todos los workspaces
+	List(ctx context.Context) ([]*entities.Workspace, error)
+
+	// ListForUser lista los workspaces del usuario con su rol en cada uno
+	ListForUser(ctx context.Context, userID int64) ([]*entities.Workspace, error)
+
+	// GetMembership obtiene el rol del usuario en el workspace
+	GetMembership(ctx context.Context, workspaceID, userID int64) (*entities.Membership, error)
+
+	// SetMember agrega un usuario al workspace o cambia su rol
+	SetMember(ctx context.Context, membership *entities.Membership) error
+
+	// RemoveMember quita un usuario del workspace
+	RemoveMember(ctx context.Context, workspaceID, userID int64) error
+}
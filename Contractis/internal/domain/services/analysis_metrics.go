@@ -0,0 +1,17 @@
+package services
+
+import (
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// AnalysisMetrics registra métricas de los análisis de contratos (en curso, chunks y duración)
+type AnalysisMetrics interface {
+	// AnalysisStarted se llama al comenzar un análisis
+	AnalysisStarted()
+
+	// AnalysisFinished se llama al terminar, con el status final, la cantidad de chunks
+	// (0 si el análisis no se completó) y la duración
+	AnalysisFinished(status entities.ContractStatus, chunks int, duration time.Duration)
+}
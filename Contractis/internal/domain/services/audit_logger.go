@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// AuditLogger registra acciones sobre contratos. El actor y la IP se toman del contexto
+// (principal autenticado y dirección del cliente). Un fallo al registrar no interrumpe la acción.
+type AuditLogger interface {
+	Record(ctx context.Context, action entities.AuditAction, workspaceID, contractID int64, details string)
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Authenticator valida una credencial (API key o token de sesión) y retorna la identidad
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*entities.Principal, error)
+}
+
+// SessionIssuer emite tokens de sesión firmados para un principal ya autenticado
+type SessionIssuer interface {
+	IssueSession(principal *entities.Principal) (token string, expiresAt time.Time, err error)
+}
+
+// OIDCProvider ejecuta el flujo authorization code con PKCE contra un proveedor OpenID Connect.
+// El estado de la transacción (state, nonce, code verifier) viaja firmado en un valor opaco
+// que el adaptador HTTP guarda en una cookie.
+type OIDCProvider interface {
+	// BeginLogin retorna la URL de autorización y el estado opaco de la transacción
+	BeginLogin(ctx context.Context, returnTo string) (authURL string, transaction string, err error)
+
+	// CompleteLogin valida el callback, canjea el código y verifica el ID token
+	CompleteLogin(ctx context.Context, transaction, state, code string) (claims *entities.IdentityClaims, returnTo string, err error)
+}
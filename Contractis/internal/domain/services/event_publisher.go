@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// EventPublisher notifica un evento de un workspace a los webhooks suscritos. Publicar no
+// bloquea ni hace fallar la acción que originó el evento: la entrega es asíncrona y un
+// error solo se registra en el log.
+type EventPublisher interface {
+	Publish(ctx context.Context, workspaceID int64, event entities.WebhookEvent, data any)
+}
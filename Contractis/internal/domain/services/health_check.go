@@ -0,0 +1,14 @@
+package services
+
+import "context"
+
+// HealthCheck revisa un componente del que depende el servicio (base de datos,
+// almacenamiento, LLM) para el chequeo de readiness
+type HealthCheck interface {
+	// Name identifica el componente en el reporte
+	Name() string
+	// Critical indica si el servicio deja de estar listo cuando el componente falla
+	Critical() bool
+	// Check revisa el componente y retorna detalles para el reporte; un error lo marca caído
+	Check(ctx context.Context) (map[string]any, error)
+}
@@ -0,0 +1,9 @@
+package services
+
+import "github.com/rodascaar/contractis/internal/domain/entities"
+
+// LLMScheduler define la interfaz para consultar las colas de peticiones al LLM
+type LLMScheduler interface {
+	// QueueStatus retorna el estado de cada endpoint desde la perspectiva del solicitante
+	QueueStatus(requester string) []entities.QueueStatus
+}
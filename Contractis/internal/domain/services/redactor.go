@@ -0,0 +1,9 @@
+package services
+
+import "github.com/rodascaar/contractis/internal/domain/entities"
+
+// Redactor reemplaza datos personales y sensibles por marcadores antes de enviar el
+// texto a un LLM de terceros. El mapeo retornado permite reinsertar los valores originales.
+type Redactor interface {
+	Redact(text string) (string, *entities.Redaction)
+}
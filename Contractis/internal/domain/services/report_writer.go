@@ -0,0 +1,11 @@
+package services
+
+import "github.com/rodascaar/contractis/internal/domain/entities"
+
+// ReportWriter guarda el reporte de un contrato analizado junto al documento de origen
+// o en una carpeta de salida
+type ReportWriter interface {
+	// ReportPath retorna dónde se guarda el reporte del documento y si ya existe
+	ReportPath(doc entities.IngestDocument) (string, bool)
+	WriteReport(doc entities.IngestDocument, record *entities.ContractRecord) (string, error)
+}
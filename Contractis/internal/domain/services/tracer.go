@@ -0,0 +1,21 @@
+package services
+
+import "context"
+
+// Tracer crea spans para medir en qué etapa de un análisis se va el tiempo. El span
+// queda en el contexto retornado, así las etapas internas cuelgan de él.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span es una operación medida dentro de una traza
+type Span interface {
+	// SetAttribute agrega un atributo (string, bool, int, int64 o float64)
+	SetAttribute(key string, value any)
+
+	// RecordError marca el span como fallido con el error indicado (nil no hace nada)
+	RecordError(err error)
+
+	// End termina el span; las llamadas posteriores no tienen efecto
+	End()
+}
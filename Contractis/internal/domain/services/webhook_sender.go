@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// WebhookSender hace un intento de entrega: POST del payload a la URL del webhook,
+// firmado con su secreto. Retorna el status y el comienzo del cuerpo de la respuesta;
+// err indica que no hubo respuesta (red, timeout).
+type WebhookSender interface {
+	Send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (status int, body string, err error)
+}
@@ -0,0 +1,219 @@
+package archive
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Collector guarda en un directorio temporal los PDF de una subida por lotes, sueltos o
+// dentro de archivos ZIP. Protecciones:
+//   - los archivos se guardan como 0001.pdf, 0002.pdf...: el nombre de una entrada nunca
+//     se usa como ruta en disco, y las rutas absolutas o con ".." se rechazan igual
+//   - cada entrada se copia con un límite de bytes reales, sin confiar en el tamaño
+//     declarado en el ZIP, y se rechazan las de relación de compresión sospechosa
+//   - el total descomprimido, la cantidad de PDF y de entradas por ZIP están acotados
+//   - no se expanden ZIP anidados, enlaces simbólicos ni entradas cifradas
+//
+// Un archivo inválido queda registrado como documento rechazado; superar un límite
+// global aborta la subida completa.
+type Collector struct {
+	dir      string
+	limits   entities.Limits
+	docs     []*entities.BatchDocument
+	accepted int
+	total    int64
+}
+
+// NewCollector crea un Collector que guarda los archivos en dir con los límites indicados
+func NewCollector(dir string, limits entities.Limits) *Collector {
+	return &Collector{dir: dir, limits: limits}
+}
+
+// Documents retorna los documentos recibidos, aceptados (pending) o rechazados
+func (c *Collector) Documents() []*entities.BatchDocument {
+	return c.docs
+}
+
+// AddFile agrega un archivo subido directamente
+func (c *Collector) AddFile(name string, r io.Reader) error {
+	name = displayName(name)
+	if !isPDF(name) {
+		c.reject(name, 0, "solo se permiten archivos PDF")
+		return nil
+	}
+	return c.store(name, r)
+}
+
+// AddZip agrega los PDF contenidos en un ZIP de size bytes
+func (c *Collector) AddZip(name string, r io.ReaderAt, size int64) error {
+	name = displayName(name)
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", entities.ErrInvalidArchive, name, err)
+	}
+	if len(reader.File) > c.limits.MaxBatchEntries {
+		return fmt.Errorf("%w: %s has %d entries (maximum %d)", entities.ErrTooManyDocuments, name, len(reader.File), c.limits.MaxBatchEntries)
+	}
+
+	for _, file := range reader.File {
+		if err := c.addEntry(name, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Collector) addEntry(archiveName string, file *zip.File) error {
+	if file.FileInfo().IsDir() || ignoredEntry(file.Name) {
+		return nil
+	}
+
+	entry, ok := safeEntryName(file.Name)
+	name := archiveName + "/" + entry
+	size := int64(file.UncompressedSize64)
+	switch {
+	case !ok:
+		c.reject(archiveName+"/"+displayName(file.Name), 0, "ruta no permitida dentro del ZIP")
+	case file.Mode()&fs.ModeSymlink != 0:
+		c.reject(name, 0, "los enlaces simbólicos no se admiten")
+	case strings.EqualFold(path.Ext(entry), ".zip"):
+		c.reject(name, size, "no se admiten ZIP anidados")
+	case !isPDF(entry):
+		c.reject(name, size, "solo se permiten archivos PDF")
+	case file.Flags&0x1 != 0:
+		c.reject(name, size, "las entradas cifradas no se admiten")
+	case file.UncompressedSize64 > uint64(c.limits.MaxFileSize):
+		c.reject(name, size, c.tooLarge())
+	case file.CompressedSize64 > 0 && file.UncompressedSize64/file.CompressedSize64 > entities.MaxCompressionRatio:
+		c.reject(name, size, "relación de compresión sospechosa")
+	default:
+		rc, err := file.Open()
+		if err != nil {
+			c.reject(name, size, fmt.Sprintf("no se pudo descomprimir: %v", err))
+			return nil
+		}
+		defer rc.Close()
+		return c.store(name, rc)
+	}
+	return nil
+}
+
+// store copia un PDF al directorio aplicando los límites de tamaño y calcula su hash
+func (c *Collector) store(name string, r io.Reader) error {
+	if c.accepted >= c.limits.MaxBatchDocuments {
+		return fmt.Errorf("%w (maximum %d PDF files)", entities.ErrTooManyDocuments, c.limits.MaxBatchDocuments)
+	}
+
+	target := filepath.Join(c.dir, fmt.Sprintf("%04d.pdf", len(c.docs)+1))
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(f, hasher), io.LimitReader(r, c.limits.MaxFileSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	switch {
+	case err != nil:
+		os.Remove(target)
+		c.reject(name, written, fmt.Sprintf("no se pudo leer el archivo: %v", err))
+		return nil
+	case written > c.limits.MaxFileSize:
+		os.Remove(target)
+		c.reject(name, written, c.tooLarge())
+		return nil
+	case written == 0:
+		os.Remove(target)
+		c.reject(name, 0, "archivo vacío")
+		return nil
+	}
+
+	c.total += written
+	if c.total > c.limits.MaxBatchExtractedSize {
+		return fmt.Errorf("%w (maximum %d MB)", entities.ErrBatchTooLarge, c.limits.MaxBatchExtractedSize/(1024*1024))
+	}
+
+	c.accepted++
+	c.docs = append(c.docs, &entities.BatchDocument{
+		Position: len(c.docs) + 1,
+		Filename: name,
+		FileHash: hex.EncodeToString(hasher.Sum(nil)),
+		FileSize: written,
+		Status:   entities.BatchDocPending,
+		Path:     target,
+	})
+	return nil
+}
+
+func (c *Collector) tooLarge() string {
+	return fmt.Sprintf("archivo demasiado grande (máximo %dMB)", c.limits.FileSizeMB())
+}
+
+func (c *Collector) reject(name string, size int64, reason string) {
+	c.docs = append(c.docs, &entities.BatchDocument{
+		Position: len(c.docs) + 1,
+		Filename: name,
+		FileSize: size,
+		Status:   entities.BatchDocRejected,
+		Error:    reason,
+	})
+}
+
+// safeEntryName normaliza el nombre de una entrada y rechaza rutas absolutas o que
+// salen de la raíz del ZIP
+func safeEntryName(name string) (string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if name == "" || strings.HasPrefix(name, "/") || (len(name) > 1 && name[1] == ':') {
+		return "", false
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return "", false
+		}
+	}
+	clean := path.Clean(name)
+	if clean == "." || strings.IndexFunc(clean, unicode.IsControl) >= 0 {
+		return "", false
+	}
+	return clean, true
+}
+
+// ignoredEntry descarta los metadatos que agregan macOS y otros compresores
+func ignoredEntry(name string) bool {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "__MACOSX/") {
+		return true
+	}
+	return strings.HasPrefix(path.Base(name), ".")
+}
+
+// displayName deja el nombre apto para mostrarse: sin caracteres de control ni longitud excesiva
+func displayName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+func isPDF(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".pdf")
+}
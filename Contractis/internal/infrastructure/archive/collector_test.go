@@ -0,0 +1,314 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// testLimits son límites pequeños para que los casos no necesiten archivos grandes
+func testLimits() entities.Limits {
+	limits := entities.DefaultLimits()
+	limits.MaxFileSize = 64 * 1024
+	limits.MaxBatchDocuments = 10
+	limits.MaxBatchEntries = 20
+	limits.MaxBatchExtractedSize = 1024 * 1024
+	return limits
+}
+
+// zipEntry es una entrada de un ZIP de prueba; con raw, data ya está comprimida y los
+// tamaños y el CRC del encabezado se escriben tal cual
+type zipEntry struct {
+	header *zip.FileHeader
+	data   []byte
+	raw    bool
+}
+
+func file(name, content string) zipEntry {
+	return zipEntry{header: &zip.FileHeader{Name: name, Method: zip.Deflate}, data: []byte(content)}
+}
+
+func buildZip(t *testing.T, entries ...zipEntry) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		var (
+			fw  io.Writer
+			err error
+		)
+		if entry.raw {
+			fw, err = w.CreateRaw(entry.header)
+		} else {
+			fw, err = w.CreateHeader(entry.header)
+		}
+		if err != nil {
+			t.Fatalf("zip entry %s: %v", entry.header.Name, err)
+		}
+		if _, err := fw.Write(entry.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func deflate(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(data)
+	fw.Close()
+	return buf.Bytes()
+}
+
+// newTestCollector crea el Collector en un subdirectorio, para poder comprobar que nada
+// se escribió fuera de él
+func newTestCollector(t *testing.T, limits entities.Limits) (*Collector, string, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "batch")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return NewCollector(dir, limits), root, dir
+}
+
+func addZip(t *testing.T, c *Collector, name string, zr *bytes.Reader) error {
+	t.Helper()
+	return c.AddZip(name, zr, zr.Size())
+}
+
+func rejection(t *testing.T, c *Collector, name string) string {
+	t.Helper()
+	for _, doc := range c.Documents() {
+		if doc.Filename == name {
+			if doc.Status != entities.BatchDocRejected {
+				t.Errorf("%s: status %s, want rejected", name, doc.Status)
+			}
+			return doc.Error
+		}
+	}
+	t.Errorf("%s: not in Documents()", name)
+	return ""
+}
+
+func TestCollectorStoresPDFs(t *testing.T) {
+	c, _, dir := newTestCollector(t, testLimits())
+	zr := buildZip(t,
+		file("contratos/a.pdf", "%PDF-1.4 a"),
+		file("B.PDF", "%PDF-1.4 b"),
+		file("__MACOSX/contratos/._a.pdf", "metadatos"),
+		file(".DS_Store", "metadatos"),
+		zipEntry{header: &zip.FileHeader{Name: "contratos/"}},
+	)
+	if err := addZip(t, c, "lote.zip", zr); err != nil {
+		t.Fatalf("AddZip: %v", err)
+	}
+	if err := c.AddFile("suelto.pdf", strings.NewReader("%PDF-1.4 c")); err != nil {
+		t.Fatalf("AddFile: %v", err)
+	}
+
+	docs := c.Documents()
+	want := []string{"lote.zip/contratos/a.pdf", "lote.zip/B.PDF", "suelto.pdf"}
+	if len(docs) != len(want) {
+		t.Fatalf("got %d documents, want %d (metadata and directories are skipped)", len(docs), len(want))
+	}
+	for i, doc := range docs {
+		if doc.Filename != want[i] || doc.Status != entities.BatchDocPending || doc.Position != i+1 {
+			t.Errorf("doc %d = %q %s #%d, want %q pending #%d", i, doc.Filename, doc.Status, doc.Position, want[i], i+1)
+		}
+		if doc.Path != filepath.Join(dir, []string{"0001.pdf", "0002.pdf", "0003.pdf"}[i]) {
+			t.Errorf("doc %d stored at %s", i, doc.Path)
+		}
+	}
+
+	content, err := os.ReadFile(docs[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("%PDF-1.4 a"))
+	if string(content) != "%PDF-1.4 a" || docs[0].FileHash != hex.EncodeToString(sum[:]) || docs[0].FileSize != int64(len(content)) {
+		t.Errorf("doc 0: content %q, hash %s, size %d", content, docs[0].FileHash, docs[0].FileSize)
+	}
+}
+
+func TestCollectorRejectsUnsafeEntries(t *testing.T) {
+	c, root, dir := newTestCollector(t, testLimits())
+
+	symlink := &zip.FileHeader{Name: "enlace.pdf", Method: zip.Store}
+	symlink.SetMode(fs.ModeSymlink | 0o777)
+	encrypted := &zip.FileHeader{Name: "cifrado.pdf", Method: zip.Store, Flags: 0x1}
+
+	zr := buildZip(t,
+		file("../escape.pdf", "%PDF"),
+		file("contratos/../../escape2.pdf", "%PDF"),
+		file("/etc/absoluto.pdf", "%PDF"),
+		file(`C:\windows\unidad.pdf`, "%PDF"),
+		file(`..\windows.pdf`, "%PDF"),
+		zipEntry{header: symlink, data: []byte("../../etc/passwd")},
+		file("interno.zip", "PK"),
+		zipEntry{header: encrypted, data: []byte("%PDF cifrado")},
+		file("notas.txt", "texto"),
+	)
+	if err := addZip(t, c, "lote.zip", zr); err != nil {
+		t.Fatalf("AddZip: %v", err)
+	}
+
+	cases := map[string]string{
+		"lote.zip/../escape.pdf":               "ruta no permitida",
+		"lote.zip/contratos/../../escape2.pdf": "ruta no permitida",
+		"lote.zip//etc/absoluto.pdf":           "ruta no permitida",
+		`lote.zip/C:\windows\unidad.pdf`:       "ruta no permitida",
+		`lote.zip/..\windows.pdf`:              "ruta no permitida",
+		"lote.zip/enlace.pdf":                  "enlaces simbólicos",
+		"lote.zip/interno.zip":                 "ZIP anidados",
+		"lote.zip/cifrado.pdf":                 "cifradas",
+		"lote.zip/notas.txt":                   "solo se permiten archivos PDF",
+	}
+	for name, reason := range cases {
+		if got := rejection(t, c, name); !strings.Contains(got, reason) {
+			t.Errorf("%s: rejected with %q, want %q", name, got, reason)
+		}
+	}
+	if len(c.Documents()) != len(cases) {
+		t.Errorf("got %d documents, want %d", len(c.Documents()), len(cases))
+	}
+
+	// Nada se escribió dentro ni fuera del directorio del lote
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("batch dir has %d files, want none", len(entries))
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 1 {
+		t.Errorf("parent dir has %d entries, want only the batch dir", len(entries))
+	}
+}
+
+func TestCollectorDoesNotTrustDeclaredSizes(t *testing.T) {
+	limits := testLimits()
+	c, _, dir := newTestCollector(t, limits)
+
+	// Declara 100 bytes pero descomprime 4 veces el máximo por archivo
+	large := append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("x"), int(4*limits.MaxFileSize))...)
+	compressed := deflate(t, large)
+	lying := &zip.FileHeader{
+		Name:               "pequeno.pdf",
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE(large[:100]),
+		CompressedSize64:   uint64(len(compressed)),
+		UncompressedSize64: 100,
+	}
+
+	// Tamaños honestos, pero más de 100 veces más chico comprimido
+	bomb := bytes.Repeat([]byte{0}, int(limits.MaxFileSize)-1)
+
+	zr := buildZip(t,
+		zipEntry{header: lying, data: compressed, raw: true},
+		zipEntry{header: &zip.FileHeader{Name: "bomba.pdf", Method: zip.Deflate}, data: bomb},
+		zipEntry{header: &zip.FileHeader{Name: "grande.pdf", Method: zip.Store}, data: large},
+	)
+	if err := addZip(t, c, "lote.zip", zr); err != nil {
+		t.Fatalf("AddZip: %v", err)
+	}
+
+	if got := rejection(t, c, "lote.zip/pequeno.pdf"); got == "" {
+		t.Error("an entry larger than its declared size was accepted")
+	}
+	if size := c.Documents()[0].FileSize; size > limits.MaxFileSize+1 {
+		t.Errorf("pequeno.pdf: read %d bytes, more than the limit", size)
+	}
+	if got := rejection(t, c, "lote.zip/bomba.pdf"); !strings.Contains(got, "compresión") {
+		t.Errorf("bomba.pdf rejected with %q, want a compression ratio error", got)
+	}
+	if got := rejection(t, c, "lote.zip/grande.pdf"); !strings.Contains(got, "demasiado grande") {
+		t.Errorf("grande.pdf rejected with %q, want a size error", got)
+	}
+	// Un archivo suelto también se corta al superar el límite real
+	endless := io.MultiReader(strings.NewReader("%PDF-1.4 "), zeros{})
+	if err := c.AddFile("infinito.pdf", endless); err != nil {
+		t.Fatalf("AddFile: %v", err)
+	}
+	if got := rejection(t, c, "infinito.pdf"); !strings.Contains(got, "demasiado grande") {
+		t.Errorf("infinito.pdf rejected with %q, want a size error", got)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("batch dir has %d files, want none", len(entries))
+	}
+}
+
+// zeros es un lector que nunca termina
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestCollectorLimits(t *testing.T) {
+	t.Run("entries per ZIP", func(t *testing.T) {
+		limits := testLimits()
+		limits.MaxBatchEntries = 2
+		c, _, _ := newTestCollector(t, limits)
+		zr := buildZip(t, file("a.pdf", "%PDF"), file("b.txt", "x"), file("c.txt", "x"))
+		if err := addZip(t, c, "lote.zip", zr); !errors.Is(err, entities.ErrTooManyDocuments) {
+			t.Errorf("AddZip = %v, want ErrTooManyDocuments", err)
+		}
+		if len(c.Documents()) != 0 {
+			t.Errorf("got %d documents, want none: the ZIP is refused before extracting", len(c.Documents()))
+		}
+	})
+
+	t.Run("documents per batch", func(t *testing.T) {
+		limits := testLimits()
+		limits.MaxBatchDocuments = 2
+		c, _, _ := newTestCollector(t, limits)
+		// Los rechazados no cuentan para el límite de PDF
+		zr := buildZip(t, file("a.pdf", "%PDF a"), file("notas.txt", "x"), file("b.pdf", "%PDF b"))
+		if err := addZip(t, c, "lote.zip", zr); err != nil {
+			t.Fatalf("AddZip: %v", err)
+		}
+		if err := c.AddFile("c.pdf", strings.NewReader("%PDF c")); !errors.Is(err, entities.ErrTooManyDocuments) {
+			t.Errorf("AddFile = %v, want ErrTooManyDocuments", err)
+		}
+	})
+
+	t.Run("extracted size", func(t *testing.T) {
+		limits := testLimits()
+		limits.MaxBatchExtractedSize = 2 * limits.MaxFileSize
+		c, _, _ := newTestCollector(t, limits)
+		content := "%PDF-1.4 " + strings.Repeat("x", int(limits.MaxFileSize)-9)
+		var err error
+		for i := 0; i < 3 && err == nil; i++ {
+			err = c.AddFile("contrato.pdf", strings.NewReader(content))
+		}
+		if !errors.Is(err, entities.ErrBatchTooLarge) {
+			t.Errorf("AddFile = %v, want ErrBatchTooLarge", err)
+		}
+	})
+
+	t.Run("invalid ZIP", func(t *testing.T) {
+		c, _, _ := newTestCollector(t, testLimits())
+		zr := bytes.NewReader([]byte("no es un zip"))
+		if err := addZip(t, c, "lote.zip", zr); !errors.Is(err, entities.ErrInvalidArchive) {
+			t.Errorf("AddZip = %v, want ErrInvalidArchive", err)
+		}
+	})
+}
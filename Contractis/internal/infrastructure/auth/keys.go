@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix distingue las API keys de los tokens de sesión
+const APIKeyPrefix = "ctr_"
+
+// prefixLength es la cantidad de caracteres visibles que se guardan para identificar la key
+const prefixLength = len(APIKeyPrefix) + 8
+
+// GenerateAPIKey crea una API key aleatoria y retorna la key en claro, su prefijo visible y su hash
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("error generating API key: %w", err)
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:prefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey calcula el hash con el que se guarda la key. Las keys tienen 256 bits
+// aleatorios, así que un SHA-256 sin sal es suficiente y permite buscarlas por hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey indica si la credencial tiene formato de API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// Service autentica API keys y tokens de sesión y gestiona el ciclo de vida de las keys
+type Service struct {
+	keyRepo repositories.APIKeyRepository
+	signer  *TokenSigner
+}
+
+// NewService crea una nueva instancia de Service
+func NewService(keyRepo repositories.APIKeyRepository, signer *TokenSigner) *Service {
+	return &Service{
+		keyRepo: keyRepo,
+		signer:  signer,
+	}
+}
+
+// Authenticate valida una API key ("ctr_...") o un token de sesión
+func (s *Service) Authenticate(ctx context.Context, credential string) (*entities.Principal, error) {
+	credential = strings.TrimSpace(credential)
+	if credential == "" {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	if IsAPIKey(credential) {
+		key, err := s.keyRepo.GetByHash(ctx, HashAPIKey(credential))
+		if err != nil {
+			return nil, err
+		}
+		if key.IsRevoked() {
+			return nil, entities.ErrInvalidAPIKey
+		}
+
+		if err := s.keyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+			slog.WarnContext(ctx, "no se pudo registrar el uso de la API key", "key_id", key.ID, "error", err)
+		}
+		return principalForKey(key), nil
+	}
+
+	principal, err := s.signer.Verify(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revocar una key invalida también las sesiones emitidas a partir de ella
+	if principal.KeyID != 0 {
+		key, err := s.keyRepo.GetByID(ctx, principal.KeyID)
+		if err != nil {
+			if errors.Is(err, entities.ErrInvalidAPIKey) {
+				return nil, entities.ErrInvalidToken
+			}
+			return nil, err
+		}
+		if key.IsRevoked() {
+			return nil, entities.ErrInvalidToken
+		}
+	}
+	return principal, nil
+}
+
+// IssueSession emite un token de sesión con los permisos del principal
+func (s *Service) IssueSession(principal *entities.Principal) (string, time.Time, error) {
+	return s.signer.Issue(principal, entities.SessionTTL)
+}
+
+// CreateAPIKey genera una nueva API key del usuario y retorna la key en claro (solo disponible ahora)
+func (s *Service) CreateAPIKey(ctx context.Context, userID int64, name string, scopes []entities.Scope) (string, *entities.APIKey, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, errors.New("name is required")
+	}
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return "", nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	plaintext, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &entities.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Scopes:  scopes,
+	}
+	id, err := s.keyRepo.Create(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	created, err := s.keyRepo.GetByID(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	return plaintext, created, nil
+}
+
+// ListAPIKeys lista las API keys (sin hashes)
+func (s *Service) ListAPIKeys(ctx context.Context) ([]*entities.APIKey, error) {
+	return s.keyRepo.List(ctx)
+}
+
+// RevokeAPIKey revoca una API key y las sesiones emitidas con ella
+func (s *Service) RevokeAPIKey(ctx context.Context, id int64) error {
+	return s.keyRepo.Revoke(ctx, id)
+}
+
+func principalForKey(key *entities.APIKey) *entities.Principal {
+	return &entities.Principal{
+		Subject: "key:" + strconv.FormatInt(key.ID, 10) + ":" + key.Name,
+		UserID:  key.UserID,
+		KeyID:   key.ID,
+		Scopes:  key.Scopes,
+	}
+}
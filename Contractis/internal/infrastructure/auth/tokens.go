@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// tokenVersion versiona el formato de los tokens de sesión
+const tokenVersion = "v1"
+
+// sessionClaims es el contenido firmado de un token de sesión
+type sessionClaims struct {
+	Subject   string           `json:"sub"`
+	UserID    int64            `json:"uid,omitempty"`
+	KeyID     int64            `json:"kid,omitempty"`
+	Scopes    []entities.Scope `json:"scopes"`
+	IssuedAt  int64            `json:"iat"`
+	ExpiresAt int64            `json:"exp"`
+}
+
+// TokenSigner emite y verifica tokens de sesión firmados con HMAC-SHA256:
+// "v1.<claims base64url>.<firma base64url>"
+type TokenSigner struct {
+	secret []byte
+}
+
+// NewTokenSigner crea un TokenSigner con la clave de firma dada
+func NewTokenSigner(secret []byte) (*TokenSigner, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("session token secret must be at least 32 bytes")
+	}
+	return &TokenSigner{secret: secret}, nil
+}
+
+// Issue emite un token para el principal con la duración dada
+func (s *TokenSigner) Issue(principal *entities.Principal, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	payload, err := json.Marshal(sessionClaims{
+		Subject:   principal.Subject,
+		UserID:    principal.UserID,
+		KeyID:     principal.KeyID,
+		Scopes:    principal.Scopes,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error encoding session claims: %w", err)
+	}
+
+	signed := tokenVersion + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + s.sign(signed), expiresAt, nil
+}
+
+// Verify valida firma y expiración y retorna el principal del token
+func (s *TokenSigner) Verify(token string) (*entities.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenVersion {
+		return nil, entities.ErrInvalidToken
+	}
+
+	signed := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(s.sign(signed)), []byte(parts[2])) {
+		return nil, entities.ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+
+	var claims sessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, entities.ErrInvalidToken
+	}
+
+	return &entities.Principal{
+		Subject: claims.Subject,
+		UserID:  claims.UserID,
+		KeyID:   claims.KeyID,
+		Scopes:  claims.Scopes,
+	}, nil
+}
+
+func (s *TokenSigner) sign(data string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
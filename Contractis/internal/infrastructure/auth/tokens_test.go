@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+var testPrincipal = &entities.Principal{
+	Subject: "user:7:ana",
+	UserID:  7,
+	KeyID:   3,
+	Scopes:  []entities.Scope{entities.ScopeRead, entities.ScopeAnalyze},
+}
+
+func newTestSigner(t *testing.T, seed byte) *TokenSigner {
+	t.Helper()
+	signer, err := NewTokenSigner(bytes.Repeat([]byte{seed}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestTokenSignerRoundTrip(t *testing.T) {
+	signer := newTestSigner(t, 1)
+	token, expiresAt, err := signer.Issue(testPrincipal, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if until := time.Until(expiresAt); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("expiresAt in %v, want about an hour", until)
+	}
+
+	principal, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if principal.Subject != testPrincipal.Subject || principal.UserID != 7 || principal.KeyID != 3 ||
+		!slices.Equal(principal.Scopes, testPrincipal.Scopes) {
+		t.Errorf("principal = %+v, want %+v", principal, testPrincipal)
+	}
+}
+
+func TestTokenSignerRejectsInvalidTokens(t *testing.T) {
+	signer := newTestSigner(t, 1)
+	token, _, err := signer.Issue(testPrincipal, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, _, err := newTestSigner(t, 2).Issue(testPrincipal, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _, err := signer.Issue(testPrincipal, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Elevar los scopes reescribiendo el payload invalida la firma
+	parts := strings.Split(token, ".")
+	claims := decodeSegment(t, parts[1])
+	escalated := strings.Replace(claims, `"read","analyze"`, `"admin"`, 1)
+	if escalated == claims {
+		t.Fatalf("claims %s do not list the expected scopes", claims)
+	}
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(escalated)) + "." + parts[2]
+
+	tests := map[string]string{
+		"empty":           "",
+		"malformed":       "not-a-token",
+		"wrong version":   "v0" + strings.TrimPrefix(token, tokenVersion),
+		"bad signature":   parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString([]byte("forged")),
+		"other secret":    otherToken,
+		"tampered claims": tampered,
+		"expired":         expired,
+		"api key":         "ctr_" + strings.Repeat("a", 40),
+	}
+	for name, candidate := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := signer.Verify(candidate); !errors.Is(err, entities.ErrInvalidToken) {
+				t.Errorf("Verify = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestNewTokenSignerRequiresLongSecret(t *testing.T) {
+	if _, err := NewTokenSigner(make([]byte, 16)); err == nil {
+		t.Error("NewTokenSigner accepted a 16-byte secret")
+	}
+}
+
+func decodeSegment(t *testing.T, segment string) string {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,573 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/redaction"
+)
+
+// Config es la configuración de Contractis. Cada valor se resuelve, de menor a mayor
+// prioridad, desde: valores por defecto < archivo YAML/TOML < variables de entorno < flags.
+type Config struct {
+	// Environment es development o production
+	Environment string
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Security    SecurityConfig
+	LLM         LLMConfig
+	Limits      LimitsConfig
+	Retention   RetentionConfig
+	Tracing     TracingConfig
+	Log         LogConfig
+	Health      HealthConfig
+	Webhooks    WebhookConfig
+	OIDC        OIDCConfig
+	Redaction   RedactionConfig
+	Watch       WatchConfig
+
+	// File es el archivo de configuración leído, vacío si no hay
+	File string
+}
+
+// ServerConfig configura el servidor HTTP. Los timeouts de lectura, escritura e inactividad
+// aplican por conexión (0 = sin límite); ShutdownTimeout es cuánto se espera a los análisis
+// en curso al recibir SIGTERM antes de interrumpirlos.
+type ServerConfig struct {
+	Host              string
+	Port              int
+	StaticDir         string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// DatabaseConfig configura la base SQLite
+type DatabaseConfig struct {
+	Path string
+}
+
+// SecurityConfig configura el origen de la clave maestra cuando no está en CONTRACTIS_MASTER_KEY
+type SecurityConfig struct {
+	// MasterKeyFile vacío usa master.key junto a la base de datos (ver MasterKeyPath)
+	MasterKeyFile string
+}
+
+// LLMConfig configura el scheduler y el cliente LLM compartidos por todos los análisis y
+// el LLM que usan la CLI y la carpeta vigilada (de ConfigFile a MaxTokens; vacío o cero =
+// sin definir, los flags de la CLI los sobrescriben)
+type LLMConfig struct {
+	LocalSlots           int
+	OnlineSlots          int
+	LocalTimeout         time.Duration
+	OnlineTimeout        time.Duration
+	MaxRetries           int
+	AnalysisTimeout      time.Duration
+	LocalAnalysisTimeout time.Duration
+
+	// ConfigFile es un archivo JSON con el formato de llmConfig; las demás opciones lo
+	// sobrescriben
+	ConfigFile string
+	// Profile es el ID de un perfil guardado; tiene prioridad sobre el resto
+	Profile   int
+	Type      string
+	URL       string
+	APIKey    string
+	Model     string
+	MaxTokens int
+}
+
+// LimitsConfig son los límites de subida, en bytes y documentos
+type LimitsConfig struct {
+	MaxFileSize           int64
+	MaxBatchUploadSize    int64
+	MaxBatchDocuments     int
+	MaxBatchEntries       int
+	MaxBatchExtractedSize int64
+	BatchConcurrency      int
+}
+
+// RetentionConfig es la política de retención en días (0 = sin límite)
+type RetentionConfig struct {
+	TrashDays    float64
+	ContractDays float64
+}
+
+// TracingConfig configura la exportación de trazas por OTLP/HTTP; sin endpoint el
+// tracing está desactivado
+type TracingConfig struct {
+	Endpoint    string
+	ServiceName string
+	Headers     string
+}
+
+// LogConfig configura los logs: nivel mínimo (debug, info, warn, error) y formato
+// (text o json)
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+// HealthConfig configura el chequeo de readiness (/readyz): el plazo de cada componente
+// y cada cuánto se revisan los perfiles LLM (0 = no se revisan)
+type HealthConfig struct {
+	Timeout     time.Duration
+	LLMInterval time.Duration
+}
+
+// WebhookConfig configura las entregas de webhooks: el plazo de cada intento, cuántos
+// intentos se hacen en total, la espera antes del primer reintento (se duplica en cada uno)
+// y si se admiten destinos en redes privadas
+type WebhookConfig struct {
+	Timeout              time.Duration
+	MaxAttempts          int
+	RetryBackoff         time.Duration
+	AllowPrivateNetworks bool
+}
+
+// OIDCConfig configura el login SSO con un proveedor OpenID Connect; sin Issuer está
+// desactivado. Scopes son los scopes pedidos (separados por espacios o comas) y RoleMap
+// asigna los grupos del token a roles de workspace (ver entities.ParseRoleMappings).
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       string
+	GroupsClaim  string
+	RoleMap      string
+}
+
+// RedactionConfig configura la redacción de datos personales antes de enviar texto a LLMs
+// online: Detectors elige los detectores separados por comas (vacío = todos)
+type RedactionConfig struct {
+	Enabled   bool
+	Detectors string
+}
+
+// WatchConfig configura la carpeta que vigila el servidor; sin Dir no hay ingesta.
+// Output vacío escribe los reportes junto a cada PDF, Include y Exclude son patrones glob
+// separados por comas y User y Workspace indican en nombre de quién se analiza
+// (Workspace 0 = el primero del usuario).
+type WatchConfig struct {
+	Dir       string
+	Output    string
+	Include   string
+	Exclude   string
+	Interval  time.Duration
+	User      string
+	Workspace int
+}
+
+// Sources indica de dónde leer la configuración además de las variables de entorno
+type Sources struct {
+	// File es el archivo YAML o TOML; vacío usa CONTRACTIS_CONFIG si está definida
+	File string
+	// Flags son los valores indicados por línea de comandos, por clave (server.port)
+	Flags map[string]string
+}
+
+// Default retorna la configuración por defecto, equivalente a no configurar nada
+func Default() *Config {
+	return &Config{
+		Environment: "development",
+		Server: ServerConfig{
+			Port:              8080,
+			StaticDir:         "./static",
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       5 * time.Minute,
+			WriteTimeout:      2 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{Path: "./contractis.db"},
+		LLM: LLMConfig{
+			LocalSlots:           entities.LocalEndpointSlots,
+			OnlineSlots:          entities.OnlineEndpointSlots,
+			LocalTimeout:         entities.HTTPTimeoutLocal,
+			OnlineTimeout:        entities.HTTPTimeoutOnline,
+			MaxRetries:           entities.MaxRetries,
+			AnalysisTimeout:      entities.AnalysisTimeout,
+			LocalAnalysisTimeout: entities.LocalAnalysisTimeout,
+		},
+		Limits: LimitsConfig{
+			MaxFileSize:           entities.MaxFileSize,
+			MaxBatchUploadSize:    entities.MaxBatchUploadSize,
+			MaxBatchDocuments:     entities.MaxBatchDocuments,
+			MaxBatchEntries:       entities.MaxBatchEntries,
+			MaxBatchExtractedSize: entities.MaxBatchExtractedSize,
+			BatchConcurrency:      entities.BatchConcurrency,
+		},
+		Retention: RetentionConfig{
+			TrashDays: entities.DefaultTrashRetention.Hours() / 24,
+		},
+		Tracing: TracingConfig{ServiceName: "contractis"},
+		Log:     LogConfig{Level: "info", Format: "text"},
+		Health:  HealthConfig{Timeout: 5 * time.Second},
+		Webhooks: WebhookConfig{
+			Timeout:      entities.WebhookTimeout,
+			MaxAttempts:  entities.WebhookMaxAttempts,
+			RetryBackoff: entities.WebhookRetryBackoff,
+		},
+		OIDC:      OIDCConfig{RedirectURL: "http://localhost:8080/auth/callback"},
+		Redaction: RedactionConfig{Enabled: true},
+		Watch: WatchConfig{
+			Interval: entities.DefaultIngestInterval,
+			User:     "admin@localhost",
+		},
+	}
+}
+
+// field describe una opción: su clave en el archivo, su variable de entorno y su flag (opcional)
+type field struct {
+	key   string
+	env   string
+	flag  string
+	usage string
+	value any // *string, *int, *int64 (tamaño), *float64, *bool o *time.Duration
+}
+
+// fields es la lista completa de opciones configurables
+func (c *Config) fields() []field {
+	return []field{
+		{"environment", "ENVIRONMENT", "environment", "entorno: development o production", &c.Environment},
+		{"server.host", "HOST", "host", "interfaz donde escucha el servidor (vacío = todas)", &c.Server.Host},
+		{"server.port", "PORT", "port", "puerto HTTP", &c.Server.Port},
+		{"server.static_dir", "CONTRACTIS_STATIC_DIR", "static", "carpeta de la interfaz web", &c.Server.StaticDir},
+		{"server.read_header_timeout", "CONTRACTIS_READ_HEADER_TIMEOUT", "", "", &c.Server.ReadHeaderTimeout},
+		{"server.read_timeout", "CONTRACTIS_READ_TIMEOUT", "", "", &c.Server.ReadTimeout},
+		{"server.write_timeout", "CONTRACTIS_WRITE_TIMEOUT", "", "", &c.Server.WriteTimeout},
+		{"server.idle_timeout", "CONTRACTIS_IDLE_TIMEOUT", "", "", &c.Server.IdleTimeout},
+		{"server.shutdown_timeout", "CONTRACTIS_SHUTDOWN_TIMEOUT", "", "", &c.Server.ShutdownTimeout},
+		{"database.path", "DB_PATH", "db", "archivo de la base SQLite", &c.Database.Path},
+		{"security.master_key_file", "CONTRACTIS_MASTER_KEY_FILE", "", "", &c.Security.MasterKeyFile},
+		{"llm.local_slots", "CONTRACTIS_LLM_LOCAL_SLOTS", "", "", &c.LLM.LocalSlots},
+		{"llm.online_slots", "CONTRACTIS_LLM_ONLINE_SLOTS", "", "", &c.LLM.OnlineSlots},
+		{"llm.local_timeout", "CONTRACTIS_LLM_LOCAL_TIMEOUT", "", "", &c.LLM.LocalTimeout},
+		{"llm.online_timeout", "CONTRACTIS_LLM_ONLINE_TIMEOUT", "", "", &c.LLM.OnlineTimeout},
+		{"llm.max_retries", "CONTRACTIS_LLM_MAX_RETRIES", "", "", &c.LLM.MaxRetries},
+		{"llm.analysis_timeout", "CONTRACTIS_ANALYSIS_TIMEOUT", "", "", &c.LLM.AnalysisTimeout},
+		{"llm.local_analysis_timeout", "CONTRACTIS_LOCAL_ANALYSIS_TIMEOUT", "", "", &c.LLM.LocalAnalysisTimeout},
+		{"llm.config_file", "CONTRACTIS_LLM_CONFIG", "", "", &c.LLM.ConfigFile},
+		{"llm.profile", "CONTRACTIS_LLM_PROFILE", "", "", &c.LLM.Profile},
+		{"llm.type", "CONTRACTIS_LLM_TYPE", "", "", &c.LLM.Type},
+		{"llm.url", "CONTRACTIS_LLM_URL", "", "", &c.LLM.URL},
+		{"llm.api_key", "CONTRACTIS_LLM_API_KEY", "", "", &c.LLM.APIKey},
+		{"llm.model", "CONTRACTIS_LLM_MODEL", "", "", &c.LLM.Model},
+		{"llm.max_tokens", "CONTRACTIS_LLM_MAX_TOKENS", "", "", &c.LLM.MaxTokens},
+		{"limits.max_file_size", "CONTRACTIS_MAX_FILE_SIZE", "", "", &c.Limits.MaxFileSize},
+		{"limits.max_batch_upload_size", "CONTRACTIS_MAX_BATCH_UPLOAD_SIZE", "", "", &c.Limits.MaxBatchUploadSize},
+		{"limits.max_batch_documents", "CONTRACTIS_MAX_BATCH_DOCUMENTS", "", "", &c.Limits.MaxBatchDocuments},
+		{"limits.max_batch_entries", "CONTRACTIS_MAX_BATCH_ENTRIES", "", "", &c.Limits.MaxBatchEntries},
+		{"limits.max_batch_extracted_size", "CONTRACTIS_MAX_BATCH_EXTRACTED_SIZE", "", "", &c.Limits.MaxBatchExtractedSize},
+		{"limits.batch_concurrency", "CONTRACTIS_BATCH_CONCURRENCY", "", "", &c.Limits.BatchConcurrency},
+		{"retention.trash_days", "CONTRACTIS_TRASH_RETENTION_DAYS", "", "", &c.Retention.TrashDays},
+		{"retention.contract_days", "CONTRACTIS_RETENTION_DAYS", "", "", &c.Retention.ContractDays},
+		{"tracing.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", "", "", &c.Tracing.Endpoint},
+		{"tracing.service_name", "OTEL_SERVICE_NAME", "", "", &c.Tracing.ServiceName},
+		{"tracing.headers", "OTEL_EXPORTER_OTLP_HEADERS", "", "", &c.Tracing.Headers},
+		{"log.level", "CONTRACTIS_LOG_LEVEL", "log-level", "nivel mínimo de los logs: debug, info, warn o error", &c.Log.Level},
+		{"log.format", "CONTRACTIS_LOG_FORMAT", "log-format", "formato de los logs: text o json", &c.Log.Format},
+		{"health.timeout", "CONTRACTIS_HEALTH_TIMEOUT", "", "", &c.Health.Timeout},
+		{"health.llm_interval", "CONTRACTIS_HEALTH_LLM_INTERVAL", "", "", &c.Health.LLMInterval},
+		{"webhooks.timeout", "CONTRACTIS_WEBHOOK_TIMEOUT", "", "", &c.Webhooks.Timeout},
+		{"webhooks.max_attempts", "CONTRACTIS_WEBHOOK_MAX_ATTEMPTS", "", "", &c.Webhooks.MaxAttempts},
+		{"webhooks.retry_backoff", "CONTRACTIS_WEBHOOK_RETRY_BACKOFF", "", "", &c.Webhooks.RetryBackoff},
+		{"webhooks.allow_private_networks", "CONTRACTIS_WEBHOOK_ALLOW_PRIVATE_NETWORKS", "", "", &c.Webhooks.AllowPrivateNetworks},
+		{"oidc.issuer", "CONTRACTIS_OIDC_ISSUER", "", "", &c.OIDC.Issuer},
+		{"oidc.client_id", "CONTRACTIS_OIDC_CLIENT_ID", "", "", &c.OIDC.ClientID},
+		{"oidc.client_secret", "CONTRACTIS_OIDC_CLIENT_SECRET", "", "", &c.OIDC.ClientSecret},
+		{"oidc.redirect_url", "CONTRACTIS_OIDC_REDIRECT_URL", "", "", &c.OIDC.RedirectURL},
+		{"oidc.scopes", "CONTRACTIS_OIDC_SCOPES", "", "", &c.OIDC.Scopes},
+		{"oidc.groups_claim", "CONTRACTIS_OIDC_GROUPS_CLAIM", "", "", &c.OIDC.GroupsClaim},
+		{"oidc.role_map", "CONTRACTIS_OIDC_ROLE_MAP", "", "", &c.OIDC.RoleMap},
+		{"redaction.enabled", "CONTRACTIS_REDACTION", "", "", &c.Redaction.Enabled},
+		{"redaction.detectors", "CONTRACTIS_REDACTION_DETECTORS", "", "", &c.Redaction.Detectors},
+		{"watch.dir", "CONTRACTIS_WATCH_DIR", "", "", &c.Watch.Dir},
+		{"watch.output", "CONTRACTIS_WATCH_OUTPUT", "", "", &c.Watch.Output},
+		{"watch.include", "CONTRACTIS_WATCH_INCLUDE", "", "", &c.Watch.Include},
+		{"watch.exclude", "CONTRACTIS_WATCH_EXCLUDE", "", "", &c.Watch.Exclude},
+		{"watch.interval", "CONTRACTIS_WATCH_INTERVAL", "", "", &c.Watch.Interval},
+		{"watch.user", "CONTRACTIS_WATCH_USER", "", "", &c.Watch.User},
+		{"watch.workspace", "CONTRACTIS_WATCH_WORKSPACE", "", "", &c.Watch.Workspace},
+	}
+}
+
+// Load resuelve la configuración desde todas las fuentes y la valida
+func Load(sources Sources) (*Config, error) {
+	cfg := Default()
+	byKey := make(map[string]field)
+	for _, f := range cfg.fields() {
+		byKey[f.key] = f
+	}
+
+	cfg.File = sources.File
+	if cfg.File == "" {
+		cfg.File = os.Getenv("CONTRACTIS_CONFIG")
+	}
+	if cfg.File != "" {
+		values, err := readFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			f, ok := byKey[v.key]
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unknown key %q", cfg.File, v.line, v.key)
+			}
+			if err := f.set(v.raw); err != nil {
+				return nil, fmt.Errorf("%s:%d: %s: %w", cfg.File, v.line, v.key, err)
+			}
+		}
+	}
+
+	for _, f := range cfg.fields() {
+		if raw := os.Getenv(f.env); raw != "" {
+			if err := f.set(raw); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.env, err)
+			}
+		}
+	}
+
+	for key, raw := range sources.Flags {
+		f, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown configuration key %q", key)
+		}
+		if err := f.set(raw); err != nil {
+			return nil, fmt.Errorf("-%s: %w", f.flag, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate comprueba que los valores sean utilizables
+func (c *Config) Validate() error {
+	switch c.Environment {
+	case "development", "production":
+	default:
+		return fmt.Errorf("environment must be development or production, got %q", c.Environment)
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be between 1 and 65535")
+	}
+	if c.Database.Path == "" {
+		return fmt.Errorf("database.path is required")
+	}
+
+	positive := map[string]int64{
+		"server.shutdown_timeout":         int64(c.Server.ShutdownTimeout),
+		"llm.local_slots":                 int64(c.LLM.LocalSlots),
+		"llm.online_slots":                int64(c.LLM.OnlineSlots),
+		"llm.local_timeout":               int64(c.LLM.LocalTimeout),
+		"llm.online_timeout":              int64(c.LLM.OnlineTimeout),
+		"llm.max_retries":                 int64(c.LLM.MaxRetries),
+		"llm.analysis_timeout":            int64(c.LLM.AnalysisTimeout),
+		"llm.local_analysis_timeout":      int64(c.LLM.LocalAnalysisTimeout),
+		"limits.max_file_size":            c.Limits.MaxFileSize,
+		"limits.max_batch_upload_size":    c.Limits.MaxBatchUploadSize,
+		"limits.max_batch_documents":      int64(c.Limits.MaxBatchDocuments),
+		"limits.max_batch_entries":        int64(c.Limits.MaxBatchEntries),
+		"limits.max_batch_extracted_size": c.Limits.MaxBatchExtractedSize,
+		"limits.batch_concurrency":        int64(c.Limits.BatchConcurrency),
+		"health.timeout":                  int64(c.Health.Timeout),
+		"webhooks.timeout":                int64(c.Webhooks.Timeout),
+		"webhooks.max_attempts":           int64(c.Webhooks.MaxAttempts),
+		"webhooks.retry_backoff":          int64(c.Webhooks.RetryBackoff),
+		"watch.interval":                  int64(c.Watch.Interval),
+	}
+	for _, f := range c.fields() {
+		if value, ok := positive[f.key]; ok && value <= 0 {
+			return fmt.Errorf("%s must be greater than zero", f.key)
+		}
+	}
+	if c.Server.ReadHeaderTimeout < 0 || c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return fmt.Errorf("server timeouts must not be negative")
+	}
+	if c.Limits.MaxBatchExtractedSize < c.Limits.MaxFileSize {
+		return fmt.Errorf("limits.max_batch_extracted_size must be at least limits.max_file_size")
+	}
+	if c.Retention.TrashDays < 0 || c.Retention.ContractDays < 0 {
+		return fmt.Errorf("retention days must not be negative")
+	}
+	if c.Health.LLMInterval < 0 {
+		return fmt.Errorf("health.llm_interval must not be negative")
+	}
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("log.format must be text or json, got %q", c.Log.Format)
+	}
+	if c.Tracing.Endpoint != "" {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tracing.endpoint must be an http(s) URL, got %q", c.Tracing.Endpoint)
+		}
+		if c.Tracing.ServiceName == "" {
+			return fmt.Errorf("tracing.service_name is required")
+		}
+	}
+	if err := c.validateLLM(); err != nil {
+		return err
+	}
+	if err := c.validateOIDC(); err != nil {
+		return err
+	}
+	if c.Redaction.Detectors != "" {
+		if _, err := redaction.DetectorsByName(c.Redaction.Detectors); err != nil {
+			return fmt.Errorf("redaction.detectors: %w", err)
+		}
+	}
+	return c.validateWatch()
+}
+
+// validateLLM comprueba las opciones del LLM de la CLI y la carpeta vigilada
+func (c *Config) validateLLM() error {
+	switch strings.ToLower(strings.TrimSpace(c.LLM.Type)) {
+	case "", "local", "online":
+	default:
+		return fmt.Errorf("llm.type must be local or online, got %q", c.LLM.Type)
+	}
+	if c.LLM.Profile < 0 {
+		return fmt.Errorf("llm.profile must be a profile ID")
+	}
+	if c.LLM.MaxTokens < 0 {
+		return fmt.Errorf("llm.max_tokens must not be negative")
+	}
+	return nil
+}
+
+// validateOIDC comprueba el login OIDC solo si está habilitado
+func (c *Config) validateOIDC() error {
+	if c.OIDC.Issuer == "" {
+		return nil
+	}
+	u, err := url.Parse(c.OIDC.Issuer)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("oidc.issuer must be an http(s) URL, got %q", c.OIDC.Issuer)
+	}
+	if c.OIDC.ClientID == "" {
+		return fmt.Errorf("oidc.client_id is required when oidc.issuer is set")
+	}
+	if _, err := url.ParseRequestURI(c.OIDC.RedirectURL); err != nil {
+		return fmt.Errorf("oidc.redirect_url must be a URL, got %q", c.OIDC.RedirectURL)
+	}
+	if _, err := entities.ParseRoleMappings(c.OIDC.RoleMap); err != nil {
+		return fmt.Errorf("oidc.role_map: %w", err)
+	}
+	return nil
+}
+
+// validateWatch comprueba la carpeta vigilada; que Dir exista se comprueba al iniciarla
+func (c *Config) validateWatch() error {
+	if c.Watch.Workspace < 0 {
+		return fmt.Errorf("watch.workspace must be a workspace ID")
+	}
+	for key, patterns := range map[string]string{"watch.include": c.Watch.Include, "watch.exclude": c.Watch.Exclude} {
+		for _, pattern := range SplitPatterns(patterns) {
+			if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+				return fmt.Errorf("%s: invalid glob pattern %q", key, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// Addr retorna la dirección host:puerto del servidor HTTP
+func (c *Config) Addr() string {
+	return net.JoinHostPort(c.Server.Host, strconv.Itoa(c.Server.Port))
+}
+
+// URL retorna la dirección del servidor para mostrar en los logs
+func (c *Config) URL() string {
+	host := c.Server.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(c.Server.Port))
+}
+
+// MasterKeyPath retorna el archivo de la clave maestra: el configurado o, si no hay,
+// master.key en el directorio de la base de datos, para que la clave viaje con los
+// datos que protege y no dependa del directorio de trabajo
+func (c *Config) MasterKeyPath() string {
+	if c.Security.MasterKeyFile != "" {
+		return c.Security.MasterKeyFile
+	}
+	return filepath.Join(filepath.Dir(c.Database.Path), "master.key")
+}
+
+// EntityLimits retorna los límites que reciben los casos de uso y handlers
+func (c *Config) EntityLimits() entities.Limits {
+	return entities.Limits{
+		MaxFileSize:           c.Limits.MaxFileSize,
+		MaxBatchUploadSize:    c.Limits.MaxBatchUploadSize,
+		MaxBatchDocuments:     c.Limits.MaxBatchDocuments,
+		MaxBatchEntries:       c.Limits.MaxBatchEntries,
+		MaxBatchExtractedSize: c.Limits.MaxBatchExtractedSize,
+		BatchConcurrency:      c.Limits.BatchConcurrency,
+		AnalysisTimeout:       c.LLM.AnalysisTimeout,
+		LocalAnalysisTimeout:  c.LLM.LocalAnalysisTimeout,
+	}
+}
+
+// RetentionPolicy retorna la política de retención para RetentionUseCase
+func (c *Config) RetentionPolicy() entities.RetentionPolicy {
+	return entities.RetentionPolicy{
+		TrashRetention:    days(c.Retention.TrashDays),
+		ContractRetention: days(c.Retention.ContractDays),
+	}
+}
+
+// WebhookPolicy retorna la política de entregas para WebhookUseCase
+func (c *Config) WebhookPolicy() entities.WebhookPolicy {
+	return entities.WebhookPolicy{
+		Timeout:              c.Webhooks.Timeout,
+		MaxAttempts:          c.Webhooks.MaxAttempts,
+		RetryBackoff:         c.Webhooks.RetryBackoff,
+		AllowPrivateNetworks: c.Webhooks.AllowPrivateNetworks,
+	}
+}
+
+// ReportsDir retorna la carpeta donde la carpeta vigilada escribe los reportes (Output o,
+// sin ella, Dir); vacío si no hay ingesta
+func (c WatchConfig) ReportsDir() string {
+	if c.Dir == "" || c.Output != "" {
+		return c.Output
+	}
+	return c.Dir
+}
+
+// SplitPatterns separa una lista de patrones glob separados por comas
+func SplitPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, filepath.ToSlash(pattern))
+		}
+	}
+	return patterns
+}
+
+func days(n float64) time.Duration {
+	return time.Duration(n * float64(24*time.Hour))
+}
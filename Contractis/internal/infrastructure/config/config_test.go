@@ -0,0 +1,230 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig escribe un archivo de configuración temporal con la extensión indicada
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadPrecedence comprueba el orden defaults < archivo < entorno < flags para una
+// misma clave y que cada fuente solo pisa las claves que define
+func TestLoadPrecedence(t *testing.T) {
+	t.Setenv("CONTRACTIS_CONFIG", "")
+	yaml := writeConfig(t, "config.yaml", "server:\n  port: 9000\n  host: 127.0.0.1\n")
+
+	tests := []struct {
+		name     string
+		file     string
+		env      string
+		flag     string
+		wantPort int
+	}{
+		{"defaults", "", "", "", 8080},
+		{"file over defaults", yaml, "", "", 9000},
+		{"env over file", yaml, "9100", "", 9100},
+		{"flag over env", yaml, "9100", "9200", 9200},
+		{"flag over defaults", "", "", "9200", 9200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PORT", tt.env)
+			sources := Sources{File: tt.file, Flags: map[string]string{}}
+			if tt.flag != "" {
+				sources.Flags["server.port"] = tt.flag
+			}
+
+			cfg, err := Load(sources)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.wantPort {
+				t.Errorf("server.port = %d, want %d", cfg.Server.Port, tt.wantPort)
+			}
+			wantHost := ""
+			if tt.file != "" {
+				wantHost = "127.0.0.1"
+			}
+			if cfg.Server.Host != wantHost {
+				t.Errorf("server.host = %q, want %q (only the file sets it)", cfg.Server.Host, wantHost)
+			}
+		})
+	}
+}
+
+// TestLoadFileFromEnv usa CONTRACTIS_CONFIG cuando no se indica -config
+func TestLoadFileFromEnv(t *testing.T) {
+	path := writeConfig(t, "config.toml", "[watch]\ninterval = \"30s\"\n")
+	t.Setenv("CONTRACTIS_CONFIG", path)
+
+	cfg, err := Load(Sources{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.File != path || cfg.Watch.Interval != 30*time.Second {
+		t.Errorf("Load from CONTRACTIS_CONFIG = file %q, watch.interval %v", cfg.File, cfg.Watch.Interval)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Setenv("CONTRACTIS_CONFIG", "")
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		env     map[string]string
+		flags   map[string]string
+		wantErr string
+	}{
+		{"unknown key", "config.yaml", "server:\n  prot: 80\n", nil, nil, `:2: unknown key "server.prot"`},
+		{"unknown section", "config.toml", "[sever]\nport = 80\n", nil, nil, `:2: unknown key "sever.port"`},
+		{"unsupported format", "config.json", "{}", nil, nil, "unsupported config format"},
+		{"bad value in file", "config.yaml", "limits:\n  max_file_size: lots\n", nil, nil, `:2: limits.max_file_size: invalid size "lots"`},
+		{"bad value in env", "", "", map[string]string{"CONTRACTIS_WATCH_INTERVAL": "often"}, nil, `CONTRACTIS_WATCH_INTERVAL: invalid duration "often"`},
+		{"bad boolean in env", "", "", map[string]string{"CONTRACTIS_REDACTION": "maybe"}, nil, `CONTRACTIS_REDACTION: invalid boolean "maybe"`},
+		{"bad flag", "", "", nil, map[string]string{"server.port": "http"}, `-port: invalid integer "http"`},
+		{"unknown flag key", "", "", nil, map[string]string{"server.prot": "80"}, `unknown configuration key "server.prot"`},
+		{"invalid after merge", "", "", map[string]string{"PORT": "70000"}, nil, "server.port must be between 1 and 65535"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			sources := Sources{Flags: tt.flags}
+			if tt.file != "" {
+				sources.File = writeConfig(t, tt.file, tt.content)
+			}
+
+			_, err := Load(sources)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidate recorre cada regla de Validate partiendo de la configuración por defecto
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string
+	}{
+		{"environment", func(c *Config) { c.Environment = "staging" }, "environment must be development or production"},
+		{"port too low", func(c *Config) { c.Server.Port = 0 }, "server.port must be between 1 and 65535"},
+		{"port too high", func(c *Config) { c.Server.Port = 65536 }, "server.port must be between 1 and 65535"},
+		{"database path", func(c *Config) { c.Database.Path = "" }, "database.path is required"},
+		{"shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, "server.shutdown_timeout must be greater than zero"},
+		{"local slots", func(c *Config) { c.LLM.LocalSlots = 0 }, "llm.local_slots must be greater than zero"},
+		{"online slots", func(c *Config) { c.LLM.OnlineSlots = -1 }, "llm.online_slots must be greater than zero"},
+		{"local timeout", func(c *Config) { c.LLM.LocalTimeout = 0 }, "llm.local_timeout must be greater than zero"},
+		{"online timeout", func(c *Config) { c.LLM.OnlineTimeout = 0 }, "llm.online_timeout must be greater than zero"},
+		{"max retries", func(c *Config) { c.LLM.MaxRetries = 0 }, "llm.max_retries must be greater than zero"},
+		{"analysis timeout", func(c *Config) { c.LLM.AnalysisTimeout = 0 }, "llm.analysis_timeout must be greater than zero"},
+		{"local analysis timeout", func(c *Config) { c.LLM.LocalAnalysisTimeout = 0 }, "llm.local_analysis_timeout must be greater than zero"},
+		{"max file size", func(c *Config) { c.Limits.MaxFileSize = 0 }, "limits.max_file_size must be greater than zero"},
+		{"max batch upload size", func(c *Config) { c.Limits.MaxBatchUploadSize = 0 }, "limits.max_batch_upload_size must be greater than zero"},
+		{"max batch documents", func(c *Config) { c.Limits.MaxBatchDocuments = 0 }, "limits.max_batch_documents must be greater than zero"},
+		{"max batch entries", func(c *Config) { c.Limits.MaxBatchEntries = 0 }, "limits.max_batch_entries must be greater than zero"},
+		{"max batch extracted size", func(c *Config) { c.Limits.MaxBatchExtractedSize = 0 }, "limits.max_batch_extracted_size must be greater than zero"},
+		{"batch concurrency", func(c *Config) { c.Limits.BatchConcurrency = 0 }, "limits.batch_concurrency must be greater than zero"},
+		{"health timeout", func(c *Config) { c.Health.Timeout = 0 }, "health.timeout must be greater than zero"},
+		{"webhook timeout", func(c *Config) { c.Webhooks.Timeout = 0 }, "webhooks.timeout must be greater than zero"},
+		{"webhook attempts", func(c *Config) { c.Webhooks.MaxAttempts = 0 }, "webhooks.max_attempts must be greater than zero"},
+		{"webhook backoff", func(c *Config) { c.Webhooks.RetryBackoff = 0 }, "webhooks.retry_backoff must be greater than zero"},
+		{"watch interval", func(c *Config) { c.Watch.Interval = 0 }, "watch.interval must be greater than zero"},
+		{"negative server timeout", func(c *Config) { c.Server.IdleTimeout = -time.Second }, "server timeouts must not be negative"},
+		{"extracted below file size", func(c *Config) { c.Limits.MaxBatchExtractedSize = c.Limits.MaxFileSize - 1 }, "limits.max_batch_extracted_size must be at least limits.max_file_size"},
+		{"negative retention", func(c *Config) { c.Retention.TrashDays = -1 }, "retention days must not be negative"},
+		{"negative llm interval", func(c *Config) { c.Health.LLMInterval = -time.Second }, "health.llm_interval must not be negative"},
+		{"log level", func(c *Config) { c.Log.Level = "verbose" }, "log.level must be debug, info, warn or error"},
+		{"log format", func(c *Config) { c.Log.Format = "xml" }, "log.format must be text or json"},
+		{"tracing endpoint", func(c *Config) { c.Tracing.Endpoint = "collector:4318" }, "tracing.endpoint must be an http(s) URL"},
+		{"tracing service", func(c *Config) { c.Tracing.Endpoint, c.Tracing.ServiceName = "http://collector:4318", "" }, "tracing.service_name is required"},
+		{"llm type", func(c *Config) { c.LLM.Type = "remote" }, "llm.type must be local or online"},
+		{"llm profile", func(c *Config) { c.LLM.Profile = -1 }, "llm.profile must be a profile ID"},
+		{"llm max tokens", func(c *Config) { c.LLM.MaxTokens = -1 }, "llm.max_tokens must not be negative"},
+		{"oidc issuer", func(c *Config) { c.OIDC.Issuer = "sso.example.com" }, "oidc.issuer must be an http(s) URL"},
+		{"oidc client", func(c *Config) { c.OIDC.Issuer = "https://sso.example.com" }, "oidc.client_id is required"},
+		{"oidc redirect", func(c *Config) {
+			c.OIDC.Issuer, c.OIDC.ClientID, c.OIDC.RedirectURL = "https://sso.example.com", "contractis", "callback"
+		}, "oidc.redirect_url must be a URL"},
+		{"oidc role map", func(c *Config) {
+			c.OIDC.Issuer, c.OIDC.ClientID, c.OIDC.RoleMap = "https://sso.example.com", "contractis", "legal"
+		}, "oidc.role_map"},
+		{"redaction detectors", func(c *Config) { c.Redaction.Detectors = "email,passport" }, `redaction.detectors: unknown redaction detector "passport"`},
+		{"watch workspace", func(c *Config) { c.Watch.Workspace = -1 }, "watch.workspace must be a workspace ID"},
+		{"watch include", func(c *Config) { c.Watch.Include = "*.pdf,[a-" }, `watch.include: invalid glob pattern "[a-"`},
+		{"watch exclude", func(c *Config) { c.Watch.Exclude = "borradores/[" }, `watch.exclude: invalid glob pattern "borradores/["`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateOptionalSections comprueba que OIDC y la carpeta vigilada bien configurados
+// pasen la validación
+func TestValidateOptionalSections(t *testing.T) {
+	cfg := Default()
+	cfg.OIDC = OIDCConfig{
+		Issuer:      "https://sso.example.com/realms/legal",
+		ClientID:    "contractis",
+		RedirectURL: "http://localhost:8080/auth/callback",
+		RoleMap:     "legal-admins=1:admin,legal=1:analyst",
+	}
+	cfg.Redaction.Detectors = "email, iban"
+	cfg.Watch = WatchConfig{Dir: "/srv/in", Include: "**/*.pdf", Exclude: "borradores/**", Interval: time.Minute, User: "ingesta@localhost", Workspace: 2}
+	cfg.LLM.Type = "Online"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+	if got := cfg.Watch.ReportsDir(); got != "/srv/in" {
+		t.Errorf("ReportsDir without output = %q, want the watched dir", got)
+	}
+}
+
+// TestFlagsCollect comprueba que solo los flags indicados pasen a Sources y que -config
+// elija el archivo
+func TestFlagsCollect(t *testing.T) {
+	fs := flag.NewFlagSet("contractis", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"-config", "prod.yaml", "-port", "9090", "-log-format", "json", "history"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sources Sources
+	flags.Collect(&sources)
+	want := map[string]string{"server.port": "9090", "log.format": "json"}
+	if sources.File != "prod.yaml" || !reflect.DeepEqual(sources.Flags, want) {
+		t.Errorf("Collect = file %q, flags %v; want prod.yaml, %v", sources.File, sources.Flags, want)
+	}
+	if got := fs.Lookup("db").DefValue; got != "./contractis.db" {
+		t.Errorf("-db default = %q, want the configuration default", got)
+	}
+}
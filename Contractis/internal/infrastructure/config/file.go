@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileValue es un valor leído del archivo, con su clave completa (server.port)
+type fileValue struct {
+	key  string
+	raw  string
+	line int
+}
+
+// readFile lee un archivo .yaml/.yml o .toml. Se admite el subconjunto que usa la
+// configuración: claves escalares, agrupadas a lo sumo en un nivel de secciones
+// ("server:" con claves indentadas en YAML, "[server]" en TOML), y comentarios con #.
+func readFile(path string) ([]fileValue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var values []fileValue
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = parseYAML(string(data))
+	case ".toml":
+		values, err = parseTOML(string(data))
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format (use .yaml, .yml or .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	return values, nil
+}
+
+func parseYAML(data string) ([]fileValue, error) {
+	var values []fileValue
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := stripComment(scanner.Text())
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(line) == "---" {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+
+		key, raw, ok := strings.Cut(strings.TrimSpace(line), ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%d: expected \"key: value\"", n)
+		}
+		raw = strings.TrimSpace(raw)
+
+		switch {
+		case !indented && raw == "":
+			section = key
+			continue
+		case !indented:
+			section = ""
+		case section == "":
+			return nil, fmt.Errorf("%d: unexpected indentation", n)
+		default:
+			key = section + "." + key
+		}
+
+		value, err := unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", n, err)
+		}
+		values = append(values, fileValue{key: key, raw: value, line: n})
+	}
+	return values, scanner.Err()
+}
+
+func parseTOML(data string) ([]fileValue, error) {
+	var values []fileValue
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("%d: invalid section header", n)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%d: expected \"key = value\"", n)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		value, err := unquote(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", n, err)
+		}
+		values = append(values, fileValue{key: key, raw: value, line: n})
+	}
+	return values, scanner.Err()
+}
+
+// stripComment elimina un comentario # que no esté dentro de comillas
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+// unquote quita las comillas de un valor; sin comillas se usa tal cual
+func unquote(raw string) (string, error) {
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		return raw[1 : len(raw)-1], nil
+	}
+	if strings.HasPrefix(raw, "\"") {
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted string %s", raw)
+		}
+		return value, nil
+	}
+	return raw, nil
+}
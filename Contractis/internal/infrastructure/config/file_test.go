@@ -0,0 +1,184 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []fileValue
+		wantErr string
+	}{
+		{
+			name: "sections and top-level keys",
+			data: "---\nenvironment: production\n\nserver:\n  port: 9090\n\thost: 0.0.0.0\ndatabase:\n  path: ./data/c.db\n",
+			want: []fileValue{
+				{key: "environment", raw: "production", line: 2},
+				{key: "server.port", raw: "9090", line: 5},
+				{key: "server.host", raw: "0.0.0.0", line: 6},
+				{key: "database.path", raw: "./data/c.db", line: 8},
+			},
+		},
+		{
+			name: "a top-level key closes the section",
+			data: "log:\n  level: debug\nenvironment: development\n",
+			want: []fileValue{
+				{key: "log.level", raw: "debug", line: 2},
+				{key: "environment", raw: "development", line: 3},
+			},
+		},
+		{
+			name: "comments and # inside quotes",
+			data: "# encabezado\ntracing:\n  headers: \"x-team=a#b\" # comentario\n  service_name: 'svc # 1'\n  endpoint: http://collector:4318 # sin comillas\n",
+			want: []fileValue{
+				{key: "tracing.headers", raw: "x-team=a#b", line: 3},
+				{key: "tracing.service_name", raw: "svc # 1", line: 4},
+				{key: "tracing.endpoint", raw: "http://collector:4318", line: 5},
+			},
+		},
+		{
+			name: "empty quoted value",
+			data: "oidc:\n  issuer: \"\"\n",
+			want: []fileValue{{key: "oidc.issuer", raw: "", line: 2}},
+		},
+		{
+			name:    "indented key without a section",
+			data:    "environment: development\n  port: 8080\n",
+			wantErr: "2: unexpected indentation",
+		},
+		{
+			name:    "indentation at the start",
+			data:    "  port: 8080\n",
+			wantErr: "1: unexpected indentation",
+		},
+		{
+			name:    "missing colon",
+			data:    "server:\n  port 8080\n",
+			wantErr: `2: expected "key: value"`,
+		},
+		{
+			name:    "bad double-quoted string",
+			data:    "server:\n  host: \"127.0.0.1\n",
+			wantErr: "2: invalid quoted string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.data)
+			checkParse(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []fileValue
+		wantErr string
+	}{
+		{
+			name: "sections and top-level keys",
+			data: "environment = \"production\"\n\n[server]\nport = 9090\n[ limits ]\nmax_file_size = \"20MB\"\n",
+			want: []fileValue{
+				{key: "environment", raw: "production", line: 1},
+				{key: "server.port", raw: "9090", line: 4},
+				{key: "limits.max_file_size", raw: "20MB", line: 6},
+			},
+		},
+		{
+			name: "comments and # inside quotes",
+			data: "# encabezado\n[tracing]\nheaders = \"x-team=a#b\" # comentario\nservice_name = 'svc # 1'\n",
+			want: []fileValue{
+				{key: "tracing.headers", raw: "x-team=a#b", line: 3},
+				{key: "tracing.service_name", raw: "svc # 1", line: 4},
+			},
+		},
+		{
+			name:    "array of tables",
+			data:    "[[server]]\nport = 1\n",
+			wantErr: "1: invalid section header",
+		},
+		{
+			name:    "unclosed section",
+			data:    "[server\nport = 1\n",
+			wantErr: "1: invalid section header",
+		},
+		{
+			name:    "missing equals",
+			data:    "[server]\nport 8080\n",
+			wantErr: `2: expected "key = value"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.data)
+			checkParse(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func checkParse(t *testing.T, got []fileValue, err error, want []fileValue, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("error = %v, want %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("values = %+v, want %+v", got, want)
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"port: 8080", "port: 8080"},
+		{"port: 8080   # puerto", "port: 8080"},
+		{"# solo comentario", ""},
+		{`headers: "a#b"`, `headers: "a#b"`},
+		{`headers: "a#b" # c`, `headers: "a#b"`},
+		{`name: 'it"s # fine' # c`, `name: 'it"s # fine'`},
+		{`name: "it's # fine"`, `name: "it's # fine"`},
+		{"url: http://host/#frag", "url: http://host/"},
+		{"trailing: spaces \t", "trailing: spaces"},
+	}
+	for _, tt := range tests {
+		if got := stripComment(tt.line); got != tt.want {
+			t.Errorf("stripComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "plain", false},
+		{`"double"`, "double", false},
+		{`"tab\there"`, "tab\there", false},
+		{`'single \t raw'`, `single \t raw`, false},
+		{`''`, "", false},
+		{`'`, `'`, false},
+		{`"unterminated`, "", true},
+		{`"bad \q escape"`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := unquote(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("unquote(%s) = %q, %v; want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import "flag"
+
+// Flags son las opciones de configuración registradas en un FlagSet
+type Flags struct {
+	fs   *flag.FlagSet
+	file *string
+	keys map[string]string // nombre del flag → clave de configuración
+}
+
+// RegisterFlags agrega al FlagSet -config y los flags de las opciones que los admiten
+// (-port, -host, -db...). Solo los flags indicados explícitamente sobrescriben la configuración.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{
+		fs:   fs,
+		file: fs.String("config", "", "archivo de configuración YAML o TOML (o CONTRACTIS_CONFIG)"),
+		keys: make(map[string]string),
+	}
+	for _, field := range Default().fields() {
+		if field.flag == "" {
+			continue
+		}
+		fs.String(field.flag, field.String(), field.usage+" ("+field.env+")")
+		f.keys[field.flag] = field.key
+	}
+	return f
+}
+
+// Collect agrega a sources los flags indicados; se llama después de fs.Parse
+func (f *Flags) Collect(sources *Sources) {
+	if sources.Flags == nil {
+		sources.Flags = make(map[string]string)
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "config" {
+			sources.File = *f.file
+			return
+		}
+		if key, ok := f.keys[fl.Name]; ok {
+			sources.Flags[key] = fl.Value.String()
+		}
+	})
+}
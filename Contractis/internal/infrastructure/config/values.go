@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// set interpreta raw según el tipo del campo
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch v := f.value.(type) {
+	case *string:
+		*v = raw
+	case *int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		*v = n
+	case *int64:
+		n, err := parseSize(raw)
+		if err != nil {
+			return err
+		}
+		*v = n
+	case *float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		*v = n
+	case *bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		*v = b
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (e.g. 90s, 5m, 1h)", raw)
+		}
+		*v = d
+	default:
+		return fmt.Errorf("unsupported type %T", f.value)
+	}
+	return nil
+}
+
+// String retorna el valor actual del campo en el formato que acepta set
+func (f field) String() string {
+	switch v := f.value.(type) {
+	case *string:
+		return *v
+	case *int:
+		return strconv.Itoa(*v)
+	case *int64:
+		return formatSize(*v)
+	case *float64:
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case *bool:
+		return strconv.FormatBool(*v)
+	case *time.Duration:
+		return v.String()
+	}
+	return ""
+}
+
+// parseBool acepta true/false y, como YAML, on/off y yes/no
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q (true or false)", raw)
+	}
+	return b, nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseSize acepta bytes ("10485760") o un tamaño con unidad binaria ("10MB", "512KB")
+func parseSize(raw string) (int64, error) {
+	upper := strings.ToUpper(strings.ReplaceAll(raw, " ", ""))
+	factor := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			factor = unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 10MB, 512KB or bytes)", raw)
+	}
+	return n * factor, nil
+}
+
+func formatSize(n int64) string {
+	for _, unit := range sizeUnits[:3] {
+		if n > 0 && n%unit.factor == 0 {
+			return strconv.FormatInt(n/unit.factor, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
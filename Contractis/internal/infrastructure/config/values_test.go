@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr bool
+	}{
+		{"10485760", 10 << 20, false},
+		{"0", 0, false},
+		{"10MB", 10 << 20, false},
+		{"10mb", 10 << 20, false},
+		{"10 MB", 10 << 20, false},
+		{"512KB", 512 << 10, false},
+		{"2GB", 2 << 30, false},
+		{"100B", 100, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"1.5MB", 0, true},
+		{"-1MB", 0, true},
+		{"10TB", 0, true},
+		{"ten", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{100, "100"},
+		{512 << 10, "512KB"},
+		{10 << 20, "10MB"},
+		{2 << 30, "2GB"},
+		{1536 << 10, "1536KB"},
+	}
+	for _, tt := range tests {
+		got := formatSize(tt.n)
+		if got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+		// Lo que muestran los flags por defecto debe volver a leerse igual
+		if back, err := parseSize(got); err != nil || back != tt.n {
+			t.Errorf("parseSize(formatSize(%d)) = %d, %v", tt.n, back, err)
+		}
+	}
+}
+
+func TestFieldSet(t *testing.T) {
+	var (
+		s string
+		i int
+		b bool
+		f float64
+		d time.Duration
+	)
+	tests := []struct {
+		value   any
+		raw     string
+		check   func() bool
+		wantErr bool
+	}{
+		{&s, "  texto  ", func() bool { return s == "texto" }, false},
+		{&i, "42", func() bool { return i == 42 }, false},
+		{&i, "4.2", nil, true},
+		{&f, "7.5", func() bool { return f == 7.5 }, false},
+		{&f, "siete", nil, true},
+		{&d, "90s", func() bool { return d == 90*time.Second }, false},
+		{&d, "90", nil, true},
+		{&b, "true", func() bool { return b }, false},
+		{&b, "off", func() bool { return !b }, false},
+		{&b, "YES", func() bool { return b }, false},
+		{&b, "0", func() bool { return !b }, false},
+		{&b, "maybe", nil, true},
+		{new(uint), "1", nil, true},
+	}
+	for _, tt := range tests {
+		f := field{key: "test", value: tt.value}
+		err := f.set(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("set(%T, %q) error = %v, want error %v", tt.value, tt.raw, err, tt.wantErr)
+			continue
+		}
+		if tt.check != nil && !tt.check() {
+			t.Errorf("set(%T, %q) stored %q", tt.value, tt.raw, f.String())
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// APIKeyRepositoryImpl implementa APIKeyRepository usando SQLite
+type APIKeyRepositoryImpl struct {
+	db *DB
+}
+
+// NewAPIKeyRepository crea una nueva instancia del repositorio
+func NewAPIKeyRepository(db *DB) repositories.APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at`
+
+// Create guarda una nueva API key
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *entities.APIKey) (int64, error) {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding scopes: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, string(scopes), time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error creating API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetByID obtiene una API key por su ID
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = ?`
+	return r.getOne(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByHash obtiene una API key por el hash de la key en claro
+func (r *APIKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = ?`
+	return r.getOne(r.db.QueryRowContext(ctx, query, keyHash))
+}
+
+// List lista todas las API keys, incluidas las revocadas
+func (r *APIKeyRepositoryImpl) List(ctx context.Context) ([]*entities.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*entities.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marca una API key como revocada
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("error revoking API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("API key not found or already revoked")
+	}
+
+	return nil
+}
+
+// TouchLastUsed actualiza la fecha de último uso
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, time.Now(), id); err != nil {
+		return fmt.Errorf("error updating API key last use: %w", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) getOne(row *sql.Row) (*entities.APIKey, error) {
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting API key: %w", err)
+	}
+	return key, nil
+}
+
+// scanAPIKey escanea una fila con las columnas de apiKeyColumns
+func scanAPIKey(row rowScanner) (*entities.APIKey, error) {
+	key := &entities.APIKey{}
+	var scopes string
+	var userID sql.NullInt64
+	var createdAt, lastUsedAt, revokedAt sql.NullString
+
+	if err := row.Scan(&key.ID, &userID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &createdAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	key.UserID = userID.Int64
+
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, fmt.Errorf("error decoding scopes: %w", err)
+	}
+
+	if t, ok := parseDateTime(createdAt); ok {
+		key.CreatedAt = t
+	}
+	if t, ok := parseDateTime(lastUsedAt); ok {
+		key.LastUsedAt = &t
+	}
+	if t, ok := parseDateTime(revokedAt); ok {
+		key.RevokedAt = &t
+	}
+
+	return key, nil
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// AuditRepositoryImpl implementa AuditRepository usando SQLite
+type AuditRepositoryImpl struct {
+	db *DB
+}
+
+// NewAuditRepository crea una nueva instancia del repositorio
+func NewAuditRepository(db *DB) repositories.AuditRepository {
+	return &AuditRepositoryImpl{db: db}
+}
+
+const auditColumns = `id, created_at, actor, user_id, workspace_id, action, contract_id, ip, details, prev_hash, hash`
+
+// Append encadena la entrada con la última y la guarda. La lectura del último hash y
+// la inserción ocurren en la misma transacción para que dos escrituras no compartan padre.
+func (r *AuditRepositoryImpl) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting audit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	prevHash := entities.AuditGenesisHash
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error reading last audit hash: %w", err)
+	}
+
+	// El timestamp se guarda con la misma representación usada en el hash
+	entry.Timestamp = entry.Timestamp.UTC()
+	entry.PrevHash = prevHash
+	entry.Hash = entry.ComputeHash()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (created_at, actor, user_id, workspace_id, action, contract_id, ip, details, prev_hash, hash)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Timestamp.Format(time.RFC3339Nano), entry.Actor, nullableID(entry.UserID), nullableID(entry.WorkspaceID),
+		string(entry.Action), nullableID(entry.ContractID), entry.IP, entry.Details, entry.PrevHash, entry.Hash,
+	)
+	if err != nil {
+		return fmt.Errorf("error appending audit entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing audit entry: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// List retorna las entradas que cumplen el filtro, de la más reciente a la más antigua
+func (r *AuditRepositoryImpl) List(ctx context.Context, filter entities.AuditFilter) ([]*entities.AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.WorkspaceID > 0 {
+		conditions = append(conditions, "workspace_id = ?")
+		args = append(args, filter.WorkspaceID)
+	}
+	if filter.UserID > 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.ContractID > 0 {
+		conditions = append(conditions, "contract_id = ?")
+		args = append(args, filter.ContractID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, string(filter.Action))
+	}
+	// RFC 3339 en UTC ordena lexicográficamente igual que cronológicamente
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until.UTC().Format(time.RFC3339Nano))
+	}
+
+	query := `SELECT ` + auditColumns + ` FROM audit_log`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY id DESC LIMIT ? OFFSET ?`
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // sin límite en SQLite
+	}
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+// ListChain retorna hasta limit entradas con ID mayor a afterID en orden de inserción
+func (r *AuditRepositoryImpl) ListChain(ctx context.Context, afterID int64, limit int) ([]*entities.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+auditColumns+` FROM audit_log WHERE id > ? ORDER BY id LIMIT ?`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error reading audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]*entities.AuditEntry, error) {
+	var entries []*entities.AuditEntry
+	for rows.Next() {
+		entry := &entities.AuditEntry{}
+		var createdAt, action string
+		var userID, workspaceID, contractID sql.NullInt64
+		var ip, details sql.NullString
+
+		if err := rows.Scan(&entry.ID, &createdAt, &entry.Actor, &userID, &workspaceID, &action,
+			&contractID, &ip, &details, &entry.PrevHash, &entry.Hash); err != nil {
+			return nil, fmt.Errorf("error scanning audit entry: %w", err)
+		}
+
+		timestamp, err := time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit timestamp %q: %w", createdAt, err)
+		}
+		entry.Timestamp = timestamp
+		entry.Action = entities.AuditAction(action)
+		entry.UserID = userID.Int64
+		entry.WorkspaceID = workspaceID.Int64
+		entry.ContractID = contractID.Int64
+		entry.IP = ip.String
+		entry.Details = details.String
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// nullableID guarda los IDs ausentes (cero) como NULL
+func nullableID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
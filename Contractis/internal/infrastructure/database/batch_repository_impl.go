@@ -0,0 +1,281 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// BatchRepositoryImpl implementa BatchRepository usando SQLite
+type BatchRepositoryImpl struct {
+	db *DB
+}
+
+// NewBatchRepository crea una nueva instancia del repositorio
+func NewBatchRepository(db *DB) repositories.BatchRepository {
+	return &BatchRepositoryImpl{db: db}
+}
+
+const batchColumns = `id, workspace_id, name, status, created_by, created_at, finished_at`
+
+const batchDocumentColumns = `id, batch_id, position, filename, file_hash, file_size, status, contract_id, error_message, started_at, finished_at`
+
+// Create guarda el lote y sus documentos en una transacción
+func (r *BatchRepositoryImpl) Create(ctx context.Context, batch *entities.Batch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting batch transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO batches (workspace_id, name, status, created_by, created_at) VALUES (?, ?, ?, ?, ?)`,
+		batch.WorkspaceID, batch.Name, string(batch.Status), batch.CreatedBy, batch.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating batch: %w", err)
+	}
+	batch.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	for _, doc := range batch.Documents {
+		doc.BatchID = batch.ID
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO batch_documents (batch_id, position, filename, file_hash, file_size, status, error_message, finished_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			doc.BatchID, doc.Position, doc.Filename, nullableString(doc.FileHash), doc.FileSize,
+			string(doc.Status), nullableString(doc.Error), formatNullableTime(doc.FinishedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("error creating batch document: %w", err)
+		}
+		if doc.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error getting last insert id: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing batch: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene un lote con sus documentos en orden de subida
+func (r *BatchRepositoryImpl) GetByID(ctx context.Context, workspaceID, id int64) (*entities.Batch, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+batchColumns+` FROM batches WHERE id = ? AND workspace_id = ?`, id, workspaceID)
+	batch, err := scanBatch(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrBatchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting batch: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+batchDocumentColumns+` FROM batch_documents WHERE batch_id = ? ORDER BY position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error listing batch documents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		doc, err := scanBatchDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning batch document: %w", err)
+		}
+		batch.Documents = append(batch.Documents, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating batch documents: %w", err)
+	}
+
+	batch.ComputeStats()
+	return batch, nil
+}
+
+// List lista los lotes y calcula sus estadísticas con una sola consulta agregada
+func (r *BatchRepositoryImpl) List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.Batch, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+batchColumns+` FROM batches WHERE workspace_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`,
+		workspaceID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing batches: %w", err)
+	}
+
+	var batches []*entities.Batch
+	byID := make(map[int64]*entities.Batch)
+	for rows.Next() {
+		batch, err := scanBatch(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning batch: %w", err)
+		}
+		batches = append(batches, batch)
+		byID[batch.ID] = batch
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating batches: %w", err)
+	}
+	if len(batches) == 0 {
+		return batches, nil
+	}
+
+	placeholders := make([]string, len(batches))
+	args := make([]interface{}, len(batches))
+	for i, batch := range batches {
+		placeholders[i] = "?"
+		args[i] = batch.ID
+	}
+	statsRows, err := r.db.QueryContext(ctx, `
+		SELECT batch_id, status, COUNT(*), COALESCE(SUM(file_size), 0),
+		       COALESCE(SUM((julianday(finished_at) - julianday(started_at)) * 86400), 0)
+		FROM batch_documents
+		WHERE batch_id IN (`+strings.Join(placeholders, ", ")+`)
+		GROUP BY batch_id, status`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error computing batch stats: %w", err)
+	}
+	defer statsRows.Close()
+
+	for statsRows.Next() {
+		var batchID, size int64
+		var status string
+		var count int
+		var seconds float64
+		if err := statsRows.Scan(&batchID, &status, &count, &size, &seconds); err != nil {
+			return nil, fmt.Errorf("error scanning batch stats: %w", err)
+		}
+		if batch, ok := byID[batchID]; ok {
+			batch.Stats.Count(entities.BatchDocumentStatus(status), count, size, seconds)
+		}
+	}
+	if err := statsRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating batch stats: %w", err)
+	}
+	return batches, nil
+}
+
+// UpdateDocument guarda el avance de un documento
+func (r *BatchRepositoryImpl) UpdateDocument(ctx context.Context, doc *entities.BatchDocument) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE batch_documents SET
+			status = ?, file_hash = ?, contract_id = ?, error_message = ?, started_at = ?, finished_at = ?
+		WHERE id = ?`,
+		string(doc.Status), nullableString(doc.FileHash), nullableID(doc.ContractID), nullableString(doc.Error),
+		formatNullableTime(doc.StartedAt), formatNullableTime(doc.FinishedAt), doc.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating batch document: %w", err)
+	}
+	return nil
+}
+
+// Finish marca el lote como completado
+func (r *BatchRepositoryImpl) Finish(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE batches SET status = ?, finished_at = ? WHERE id = ?`,
+		string(entities.BatchCompleted), time.Now().UTC().Format(sqliteDateTime), id)
+	if err != nil {
+		return fmt.Errorf("error finishing batch: %w", err)
+	}
+	return nil
+}
+
+// FailInterrupted cierra los lotes que quedaron en proceso al detenerse el servidor
+func (r *BatchRepositoryImpl) FailInterrupted(ctx context.Context, reason string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting batch transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(sqliteDateTime)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE batch_documents SET status = ?, error_message = ?, finished_at = ?
+		WHERE status IN (?, ?) AND batch_id IN (SELECT id FROM batches WHERE status = ?)`,
+		string(entities.BatchDocFailed), reason, now,
+		string(entities.BatchDocPending), string(entities.BatchDocAnalyzing), string(entities.BatchProcessing),
+	); err != nil {
+		return 0, fmt.Errorf("error failing interrupted documents: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE batches SET status = ?, finished_at = ? WHERE status = ?`,
+		string(entities.BatchCompleted), now, string(entities.BatchProcessing))
+	if err != nil {
+		return 0, fmt.Errorf("error closing interrupted batches: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing batch recovery: %w", err)
+	}
+	return int(affected), nil
+}
+
+func scanBatch(row rowScanner) (*entities.Batch, error) {
+	batch := &entities.Batch{}
+	var status string
+	var createdBy, createdAt, finishedAt sql.NullString
+	if err := row.Scan(&batch.ID, &batch.WorkspaceID, &batch.Name, &status, &createdBy, &createdAt, &finishedAt); err != nil {
+		return nil, err
+	}
+	batch.Status = entities.BatchStatus(status)
+	batch.CreatedBy = createdBy.String
+	if t, ok := parseDateTime(createdAt); ok {
+		batch.CreatedAt = t
+	}
+	if t, ok := parseDateTime(finishedAt); ok {
+		batch.FinishedAt = &t
+	}
+	return batch, nil
+}
+
+func scanBatchDocument(row rowScanner) (*entities.BatchDocument, error) {
+	doc := &entities.BatchDocument{}
+	var status string
+	var fileHash, errorMessage, startedAt, finishedAt sql.NullString
+	var contractID sql.NullInt64
+	err := row.Scan(&doc.ID, &doc.BatchID, &doc.Position, &doc.Filename, &fileHash, &doc.FileSize,
+		&status, &contractID, &errorMessage, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	doc.Status = entities.BatchDocumentStatus(status)
+	doc.FileHash = fileHash.String
+	doc.ContractID = contractID.Int64
+	doc.Error = errorMessage.String
+	if t, ok := parseDateTime(startedAt); ok {
+		doc.StartedAt = &t
+	}
+	if t, ok := parseDateTime(finishedAt); ok {
+		doc.FinishedAt = &t
+	}
+	return doc, nil
+}
+
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// formatNullableTime guarda las fechas en el formato de CURRENT_TIMESTAMP para poder
+// operar con ellas en SQL (julianday)
+func formatNullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(sqliteDateTime)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,26 +11,33 @@ import (
 	"github.com/rodascaar/contractis/internal/domain/repositories"
 )
 
-// ContractRepositoryImpl implementa ContractRepository usando SQLite
+// ContractRepositoryImpl implementa ContractRepository usando SQLite. El análisis, el
+// mensaje de error y el motivo del legal hold se guardan cifrados con sealer.
 type ContractRepositoryImpl struct {
-	db *DB
+	db     *DB
+	sealer SecretSealer
 }
 
-// NewContractRepository crea una nueva instancia del repositorio
-func NewContractRepository(db *DB) repositories.ContractRepository {
-	return &ContractRepositoryImpl{db: db}
+// NewContractRepository crea una nueva instancia del repositorio. Con sealer nil los
+// datos se guardan en texto plano.
+func NewContractRepository(db *DB, sealer SecretSealer) repositories.ContractRepository {
+	if sealer == nil {
+		sealer = plaintextSealer{}
+	}
+	return &ContractRepositoryImpl{db: db, sealer: sealer}
 }
 
 // Create crea un nuevo registro de contrato
 func (r *ContractRepositoryImpl) Create(ctx context.Context, record *entities.ContractRecord) (int64, error) {
 	query := `
 		INSERT INTO contracts (
-			filename, file_hash, file_size, uploaded_at, status,
+			workspace_id, filename, file_hash, file_size, uploaded_at, status,
 			llm_type, llm_model, max_tokens, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
+		record.WorkspaceID,
 		record.Filename,
 		record.FileHash,
 		record.FileSize,
@@ -55,136 +63,46 @@ func (r *ContractRepositoryImpl) Create(ctx context.Context, record *entities.Co
 }
 
 // GetByID obtiene un contrato por su ID
-func (r *ContractRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.ContractRecord, error) {
+func (r *ContractRepositoryImpl) GetByID(ctx context.Context, workspaceID, id int64) (*entities.ContractRecord, error) {
 	query := `
-		SELECT id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
-		       llm_type, llm_model, max_tokens, analysis_result, character_count,
-		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
-		       created_at, updated_at
+		SELECT ` + contractColumns + `
 		FROM contracts
-		WHERE id = ?
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
 	`
 
-	record := &entities.ContractRecord{}
-	var analyzedAt, uploadedAt, createdAt, updatedAt sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&record.ID,
-		&record.Filename,
-		&record.FileHash,
-		&record.FileSize,
-		&uploadedAt,
-		&analyzedAt,
-		&record.Status,
-		&record.LLMType,
-		&record.LLMModel,
-		&record.MaxTokens,
-		&record.AnalysisResult,
-		&record.CharacterCount,
-		&record.EstimatedTokens,
-		&record.ChunksCount,
-		&record.ProcessingTimeSeconds,
-		&record.ErrorMessage,
-		&createdAt,
-		&updatedAt,
-	)
-
+	record, err := scanContract(r.db.QueryRowContext(ctx, query, id, workspaceID))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("contract not found")
+		return nil, entities.ErrContractNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("error getting contract: %w", err)
 	}
-
-	// Parse datetime strings
-	if uploadedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", uploadedAt.String); err == nil {
-			record.UploadedAt = t
-		}
-	}
-	if analyzedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", analyzedAt.String); err == nil {
-			record.AnalyzedAt = &t
-		}
-	}
-	if createdAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", createdAt.String); err == nil {
-			record.CreatedAt = t
-		}
-	}
-	if updatedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", updatedAt.String); err == nil {
-			record.UpdatedAt = t
-		}
+	if err := r.openRecord(record); err != nil {
+		return nil, err
 	}
 
 	return record, nil
 }
 
-// GetByHash obtiene un contrato por su hash (cualquier status)
-func (r *ContractRepositoryImpl) GetByHash(ctx context.Context, hash string) (*entities.ContractRecord, error) {
+// GetByHash obtiene un contrato por su hash (cualquier status, incluida la papelera)
+func (r *ContractRepositoryImpl) GetByHash(ctx context.Context, workspaceID int64, hash string) (*entities.ContractRecord, error) {
 	query := `
-		SELECT id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
-		       llm_type, llm_model, max_tokens, analysis_result, character_count,
-		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
-		       created_at, updated_at
+		SELECT ` + contractColumns + `
 		FROM contracts
-		WHERE file_hash = ?
+		WHERE file_hash = ? AND workspace_id = ?
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
 
-	record := &entities.ContractRecord{}
-	var analyzedAt, uploadedAt, createdAt, updatedAt sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, hash).Scan(
-		&record.ID,
-		&record.Filename,
-		&record.FileHash,
-		&record.FileSize,
-		&uploadedAt,
-		&analyzedAt,
-		&record.Status,
-		&record.LLMType,
-		&record.LLMModel,
-		&record.MaxTokens,
-		&record.AnalysisResult,
-		&record.CharacterCount,
-		&record.EstimatedTokens,
-		&record.ChunksCount,
-		&record.ProcessingTimeSeconds,
-		&record.ErrorMessage,
-		&createdAt,
-		&updatedAt,
-	)
-
+	record, err := scanContract(r.db.QueryRowContext(ctx, query, hash, workspaceID))
 	if err == sql.ErrNoRows {
 		return nil, nil // No encontrado, no es error
 	}
 	if err != nil {
 		return nil, fmt.Errorf("error getting contract by hash: %w", err)
 	}
-
-	// Parse datetime strings
-	if uploadedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", uploadedAt.String); err == nil {
-			record.UploadedAt = t
-		}
-	}
-	if analyzedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", analyzedAt.String); err == nil {
-			record.AnalyzedAt = &t
-		}
-	}
-	if createdAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", createdAt.String); err == nil {
-			record.CreatedAt = t
-		}
-	}
-	if updatedAt.Valid {
-		if t, err := time.Parse("2006-01-02 15:04:05", updatedAt.String); err == nil {
-			record.UpdatedAt = t
-		}
+	if err := r.openRecord(record); err != nil {
+		return nil, err
 	}
 
 	return record, nil
@@ -202,21 +120,37 @@ func (r *ContractRepositoryImpl) Update(ctx context.Context, record *entities.Co
 			chunks_count = ?,
 			processing_time_seconds = ?,
 			error_message = ?,
+			phase_models = ?,
 			updated_at = ?
-		WHERE id = ?
+		WHERE id = ? AND workspace_id = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	phaseModels, err := encodePhaseModels(record.PhaseModels)
+	if err != nil {
+		return err
+	}
+	analysisResult, err := r.sealer.Seal(record.AnalysisResult)
+	if err != nil {
+		return fmt.Errorf("error encrypting analysis result: %w", err)
+	}
+	errorMessage, err := r.sealer.Seal(record.ErrorMessage)
+	if err != nil {
+		return fmt.Errorf("error encrypting error message: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
 		record.Status,
 		record.AnalyzedAt,
-		record.AnalysisResult,
+		analysisResult,
 		record.CharacterCount,
 		record.EstimatedTokens,
 		record.ChunksCount,
 		record.ProcessingTimeSeconds,
-		record.ErrorMessage,
+		errorMessage,
+		phaseModels,
 		time.Now(),
 		record.ID,
+		record.WorkspaceID,
 	)
 
 	if err != nil {
@@ -227,18 +161,16 @@ func (r *ContractRepositoryImpl) Update(ctx context.Context, record *entities.Co
 }
 
 // List lista todos los contratos con paginación
-func (r *ContractRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*entities.ContractRecord, error) {
+func (r *ContractRepositoryImpl) List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error) {
 	query := `
-		SELECT id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
-		       llm_type, llm_model, max_tokens, analysis_result, character_count,
-		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
-		       created_at, updated_at
+		SELECT ` + contractColumns + `
 		FROM contracts
+		WHERE workspace_id = ? AND deleted_at IS NULL
 		ORDER BY uploaded_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, workspaceID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error listing contracts: %w", err)
 	}
@@ -248,19 +180,16 @@ func (r *ContractRepositoryImpl) List(ctx context.Context, limit, offset int) ([
 }
 
 // Search busca contratos por nombre de archivo
-func (r *ContractRepositoryImpl) Search(ctx context.Context, query string, limit, offset int) ([]*entities.ContractRecord, error) {
+func (r *ContractRepositoryImpl) Search(ctx context.Context, workspaceID int64, query string, limit, offset int) ([]*entities.ContractRecord, error) {
 	sqlQuery := `
-		SELECT id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
-		       llm_type, llm_model, max_tokens, analysis_result, character_count,
-		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
-		       created_at, updated_at
+		SELECT ` + contractColumns + `
 		FROM contracts
-		WHERE filename LIKE ?
+		WHERE workspace_id = ? AND deleted_at IS NULL AND filename LIKE ?
 		ORDER BY uploaded_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, "%"+query+"%", limit, offset)
+	rows, err := r.db.QueryContext(ctx, sqlQuery, workspaceID, "%"+query+"%", limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("error searching contracts: %w", err)
 	}
@@ -269,20 +198,36 @@ func (r *ContractRepositoryImpl) Search(ctx context.Context, query string, limit
 	return r.scanRows(rows)
 }
 
+// ListPage lista por ID descendente los contratos con ID menor que beforeID
+func (r *ContractRepositoryImpl) ListPage(ctx context.Context, workspaceID int64, query string, beforeID int64, limit int) ([]*entities.ContractRecord, error) {
+	sqlQuery := `
+		SELECT ` + contractColumns + `
+		FROM contracts
+		WHERE workspace_id = ? AND deleted_at IS NULL AND (? = 0 OR id < ?) AND (? = '' OR filename LIKE ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, workspaceID, beforeID, beforeID, query, "%"+query+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing contracts page: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
 // GetRecent obtiene los contratos más recientes
-func (r *ContractRepositoryImpl) GetRecent(ctx context.Context, limit int) ([]*entities.ContractRecord, error) {
+func (r *ContractRepositoryImpl) GetRecent(ctx context.Context, workspaceID int64, limit int) ([]*entities.ContractRecord, error) {
 	query := `
-		SELECT id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
-		       llm_type, llm_model, max_tokens, analysis_result, character_count,
-		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
-		       created_at, updated_at
+		SELECT ` + contractColumns + `
 		FROM contracts
-		WHERE status = 'completed'
+		WHERE workspace_id = ? AND deleted_at IS NULL AND status = 'completed'
 		ORDER BY analyzed_at DESC
 		LIMIT ?
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, workspaceID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error getting recent contracts: %w", err)
 	}
@@ -292,7 +237,7 @@ func (r *ContractRepositoryImpl) GetRecent(ctx context.Context, limit int) ([]*e
 }
 
 // GetStats obtiene estadísticas de contratos
-func (r *ContractRepositoryImpl) GetStats(ctx context.Context) (*repositories.ContractStats, error) {
+func (r *ContractRepositoryImpl) GetStats(ctx context.Context, workspaceID int64) (*repositories.ContractStats, error) {
 	query := `
 		SELECT 
 			COUNT(*) as total,
@@ -302,12 +247,13 @@ func (r *ContractRepositoryImpl) GetStats(ctx context.Context) (*repositories.Co
 			COALESCE(AVG(CASE WHEN status = 'completed' THEN processing_time_seconds ELSE NULL END), 0.0) as avg_time,
 			MAX(analyzed_at) as last_analyzed
 		FROM contracts
+		WHERE workspace_id = ? AND deleted_at IS NULL
 	`
 
 	stats := &repositories.ContractStats{}
 	var lastAnalyzed sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query).Scan(
+	err := r.db.QueryRowContext(ctx, query, workspaceID).Scan(
 		&stats.TotalContracts,
 		&stats.CompletedContracts,
 		&stats.FailedContracts,
@@ -330,82 +276,379 @@ func (r *ContractRepositoryImpl) GetStats(ctx context.Context) (*repositories.Co
 	return stats, nil
 }
 
-// Delete elimina un contrato por ID
-func (r *ContractRepositoryImpl) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM contracts WHERE id = ?`
+// Delete envía un contrato a la papelera
+func (r *ContractRepositoryImpl) Delete(ctx context.Context, workspaceID, id int64) error {
+	query := `
+		UPDATE contracts SET deleted_at = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL AND legal_hold = 0
+	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, query, now.Format(sqliteDateTime), now, id, workspaceID)
 	if err != nil {
 		return fmt.Errorf("error deleting contract: %w", err)
 	}
+	return r.checkAffected(ctx, result, workspaceID, id, "deleted_at IS NULL")
+}
+
+// ListTrash lista los contratos de la papelera
+func (r *ContractRepositoryImpl) ListTrash(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error) {
+	query := `
+		SELECT ` + contractColumns + `
+		FROM contracts
+		WHERE workspace_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, workspaceID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error listing trash: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
+// Restore saca un contrato de la papelera
+func (r *ContractRepositoryImpl) Restore(ctx context.Context, workspaceID, id int64) error {
+	query := `
+		UPDATE contracts SET deleted_at = NULL, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error restoring contract: %w", err)
+	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("error getting rows affected: %w", err)
 	}
-
 	if rowsAffected == 0 {
-		return fmt.Errorf("contract with ID %d not found", id)
+		return fmt.Errorf("contract with ID %d in trash: %w", id, entities.ErrContractNotFound)
 	}
+	return nil
+}
 
+// Purge borra definitivamente un contrato de la papelera. El análisis se guarda en la
+// misma fila, así que desaparece con ella; los PDFs nunca se conservan en el servidor.
+func (r *ContractRepositoryImpl) Purge(ctx context.Context, workspaceID, id int64) error {
+	query := `
+		DELETE FROM contracts
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NOT NULL AND legal_hold = 0
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error purging contract: %w", err)
+	}
+	return r.checkAffected(ctx, result, workspaceID, id, "deleted_at IS NOT NULL")
+}
+
+// SetLegalHold activa o libera el legal hold de un contrato
+func (r *ContractRepositoryImpl) SetLegalHold(ctx context.Context, workspaceID, id int64, hold bool, reason string) error {
+	var holdReason interface{}
+	if hold && reason != "" {
+		sealed, err := r.sealer.Seal(reason)
+		if err != nil {
+			return fmt.Errorf("error encrypting legal hold reason: %w", err)
+		}
+		holdReason = sealed
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE contracts SET legal_hold = ?, legal_hold_reason = ?, updated_at = ? WHERE id = ? AND workspace_id = ?`,
+		hold, holdReason, time.Now(), id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error setting legal hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("contract with ID %d: %w", id, entities.ErrContractNotFound)
+	}
 	return nil
 }
 
-// scanRows es un helper para escanear múltiples filas
-func (r *ContractRepositoryImpl) scanRows(rows *sql.Rows) ([]*entities.ContractRecord, error) {
-	var records []*entities.ContractRecord
+// ListForRetention lista los contratos sin legal hold de todos los workspaces
+func (r *ContractRepositoryImpl) ListForRetention(ctx context.Context) ([]*entities.ContractRecord, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, workspace_id, filename, uploaded_at, deleted_at FROM contracts WHERE legal_hold = 0 ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing contracts for retention: %w", err)
+	}
+	defer rows.Close()
 
+	var records []*entities.ContractRecord
 	for rows.Next() {
 		record := &entities.ContractRecord{}
-		var analyzedAt, uploadedAt, createdAt, updatedAt sql.NullString
-
-		err := rows.Scan(
-			&record.ID,
-			&record.Filename,
-			&record.FileHash,
-			&record.FileSize,
-			&uploadedAt,
-			&analyzedAt,
-			&record.Status,
-			&record.LLMType,
-			&record.LLMModel,
-			&record.MaxTokens,
-			&record.AnalysisResult,
-			&record.CharacterCount,
-			&record.EstimatedTokens,
-			&record.ChunksCount,
-			&record.ProcessingTimeSeconds,
-			&record.ErrorMessage,
-			&createdAt,
-			&updatedAt,
-		)
-
-		if err != nil {
+		var uploadedAt, deletedAt sql.NullString
+		if err := rows.Scan(&record.ID, &record.WorkspaceID, &record.Filename, &uploadedAt, &deletedAt); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
+		if t, ok := parseDateTime(uploadedAt); ok {
+			record.UploadedAt = t
+		}
+		if t, ok := parseDateTime(deletedAt); ok {
+			record.DeletedAt = &t
+		}
+		records = append(records, record)
+	}
 
-		// Parse datetime strings
-		if uploadedAt.Valid {
-			if t, err := time.Parse("2006-01-02 15:04:05", uploadedAt.String); err == nil {
-				record.UploadedAt = t
-			}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return records, nil
+}
+
+// SaveCheckpoint guarda el checkpoint cifrado (nil lo elimina)
+func (r *ContractRepositoryImpl) SaveCheckpoint(ctx context.Context, workspaceID, id int64, checkpoint *entities.AnalysisCheckpoint) error {
+	var stored interface{}
+	if checkpoint != nil {
+		data, err := json.Marshal(checkpoint)
+		if err != nil {
+			return fmt.Errorf("error encoding checkpoint: %w", err)
 		}
-		if analyzedAt.Valid {
-			if t, err := time.Parse("2006-01-02 15:04:05", analyzedAt.String); err == nil {
-				record.AnalyzedAt = &t
-			}
+		sealed, err := r.sealer.Seal(string(data))
+		if err != nil {
+			return fmt.Errorf("error encrypting checkpoint: %w", err)
 		}
-		if createdAt.Valid {
-			if t, err := time.Parse("2006-01-02 15:04:05", createdAt.String); err == nil {
-				record.CreatedAt = t
-			}
+		stored = sealed
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE contracts SET checkpoint = ? WHERE id = ? AND workspace_id = ?`,
+		stored, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetCheckpoint obtiene y descifra el checkpoint de un contrato
+func (r *ContractRepositoryImpl) GetCheckpoint(ctx context.Context, workspaceID, id int64) (*entities.AnalysisCheckpoint, error) {
+	var stored sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT checkpoint FROM contracts WHERE id = ? AND workspace_id = ?`,
+		id, workspaceID).Scan(&stored)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrContractNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting checkpoint: %w", err)
+	}
+	if !stored.Valid || stored.String == "" {
+		return nil, nil
+	}
+
+	data, err := r.sealer.Open(stored.String)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting checkpoint of contract %d: %w", id, err)
+	}
+	checkpoint := &entities.AnalysisCheckpoint{}
+	if err := json.Unmarshal([]byte(data), checkpoint); err != nil {
+		return nil, fmt.Errorf("error decoding checkpoint of contract %d: %w", id, err)
+	}
+	return checkpoint, nil
+}
+
+// InterruptStale marca como interrumpidos los contratos pendientes o en análisis
+func (r *ContractRepositoryImpl) InterruptStale(ctx context.Context, reason string) (int, error) {
+	sealedReason, err := r.sealer.Seal(reason)
+	if err != nil {
+		return 0, fmt.Errorf("error encrypting error message: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE contracts SET status = ?, error_message = ?, updated_at = ? WHERE status IN (?, ?)`,
+		entities.StatusInterrupted, sealedReason, time.Now(), entities.StatusPending, entities.StatusAnalyzing)
+	if err != nil {
+		return 0, fmt.Errorf("error interrupting stale contracts: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting rows affected: %w", err)
+	}
+	return int(count), nil
+}
+
+// checkAffected distingue, cuando una modificación no afectó filas, entre un contrato
+// inexistente (o en otro estado según stateCondition) y uno retenido por legal hold
+func (r *ContractRepositoryImpl) checkAffected(ctx context.Context, result sql.Result, workspaceID, id int64, stateCondition string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var held bool
+	err = r.db.QueryRowContext(ctx,
+		`SELECT legal_hold FROM contracts WHERE id = ? AND workspace_id = ? AND `+stateCondition,
+		id, workspaceID).Scan(&held)
+	if err == nil && held {
+		return fmt.Errorf("contract with ID %d: %w", id, entities.ErrLegalHold)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("error checking contract: %w", err)
+	}
+	return fmt.Errorf("contract with ID %d: %w", id, entities.ErrContractNotFound)
+}
+
+// openRecord descifra los campos sensibles de un registro leído con scanContract
+func (r *ContractRepositoryImpl) openRecord(record *entities.ContractRecord) error {
+	var err error
+	if record.AnalysisResult, err = r.sealer.Open(record.AnalysisResult); err != nil {
+		return fmt.Errorf("error decrypting analysis result of contract %d: %w", record.ID, err)
+	}
+	if record.ErrorMessage, err = r.sealer.Open(record.ErrorMessage); err != nil {
+		return fmt.Errorf("error decrypting error message of contract %d: %w", record.ID, err)
+	}
+	if record.LegalHoldReason, err = r.sealer.Open(record.LegalHoldReason); err != nil {
+		return fmt.Errorf("error decrypting legal hold reason of contract %d: %w", record.ID, err)
+	}
+	return nil
+}
+
+// encodePhaseModels serializa el registro de modelos por fase (NULL si está vacío)
+func encodePhaseModels(phaseModels map[string][]string) (interface{}, error) {
+	if len(phaseModels) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(phaseModels)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding phase models: %w", err)
+	}
+	return string(data), nil
+}
+
+// contractColumns son las columnas que leen scanContract y scanRows, en orden
+const contractColumns = `id, workspace_id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
+		       llm_type, llm_model, max_tokens, analysis_result, character_count,
+		       estimated_tokens, chunks_count, processing_time_seconds, error_message,
+		       created_at, updated_at, phase_models, deleted_at, legal_hold, legal_hold_reason`
+
+// rowScanner abstrae *sql.Row y *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanContract escanea una fila con las columnas de contractColumns
+func scanContract(row rowScanner) (*entities.ContractRecord, error) {
+	record := &entities.ContractRecord{}
+	var analyzedAt, uploadedAt, createdAt, updatedAt, phaseModels, deletedAt sql.NullString
+	var llmType, llmModel, analysisResult, errorMessage, legalHoldReason sql.NullString
+	var maxTokens, characterCount, estimatedTokens, chunksCount sql.NullInt64
+	var processingTime sql.NullFloat64
+
+	err := row.Scan(
+		&record.ID,
+		&record.WorkspaceID,
+		&record.Filename,
+		&record.FileHash,
+		&record.FileSize,
+		&uploadedAt,
+		&analyzedAt,
+		&record.Status,
+		&llmType,
+		&llmModel,
+		&maxTokens,
+		&analysisResult,
+		&characterCount,
+		&estimatedTokens,
+		&chunksCount,
+		&processingTime,
+		&errorMessage,
+		&createdAt,
+		&updatedAt,
+		&phaseModels,
+		&deletedAt,
+		&record.LegalHold,
+		&legalHoldReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	record.LLMType = llmType.String
+	record.LLMModel = llmModel.String
+	record.MaxTokens = int(maxTokens.Int64)
+	record.AnalysisResult = analysisResult.String
+	record.CharacterCount = int(characterCount.Int64)
+	record.EstimatedTokens = int(estimatedTokens.Int64)
+	record.ChunksCount = int(chunksCount.Int64)
+	record.ProcessingTimeSeconds = processingTime.Float64
+	record.ErrorMessage = errorMessage.String
+	record.LegalHoldReason = legalHoldReason.String
+
+	// Parse datetime strings
+	if t, ok := parseDateTime(uploadedAt); ok {
+		record.UploadedAt = t
+	}
+	if t, ok := parseDateTime(analyzedAt); ok {
+		record.AnalyzedAt = &t
+	}
+	if t, ok := parseDateTime(createdAt); ok {
+		record.CreatedAt = t
+	}
+	if t, ok := parseDateTime(updatedAt); ok {
+		record.UpdatedAt = t
+	}
+	if t, ok := parseDateTime(deletedAt); ok {
+		record.DeletedAt = &t
+	}
+
+	if phaseModels.Valid && phaseModels.String != "" {
+		if err := json.Unmarshal([]byte(phaseModels.String), &record.PhaseModels); err != nil {
+			return nil, fmt.Errorf("error decoding phase models: %w", err)
 		}
-		if updatedAt.Valid {
-			if t, err := time.Parse("2006-01-02 15:04:05", updatedAt.String); err == nil {
-				record.UpdatedAt = t
-			}
+	}
+
+	return record, nil
+}
+
+// parseDateTime interpreta las fechas tal como las guarda el driver de SQLite
+func parseDateTime(value sql.NullString) (time.Time, bool) {
+	if !value.Valid {
+		return time.Time{}, false
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value.String); err == nil {
+			return t, true
 		}
+	}
+	return time.Time{}, false
+}
+
+// sqliteDateTime es el formato de CURRENT_TIMESTAMP (UTC); se usa en columnas que se comparan en SQL
+const sqliteDateTime = "2006-01-02 15:04:05"
+
+// dateTimeLayouts cubre CURRENT_TIMESTAMP y los time.Time serializados por el driver
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+}
+
+// scanRows es un helper para escanear múltiples filas
+func (r *ContractRepositoryImpl) scanRows(rows *sql.Rows) ([]*entities.ContractRecord, error) {
+	var records []*entities.ContractRecord
 
+	for rows.Next() {
+		record, err := scanContract(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+		if err := r.openRecord(record); err != nil {
+			return nil, err
+		}
 		records = append(records, record)
 	}
 
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/usecases"
+)
+
+// seedContract crea un contrato del workspace 1 cargado hace uploadedAgo
+func seedContract(t *testing.T, repo repositories.ContractRepository, filename string, uploadedAgo time.Duration) int64 {
+	t.Helper()
+	record := entities.NewContractRecord(1, filename, "hash-"+filename, 1024, "local", "stub", 800)
+	record.UploadedAt = time.Now().Add(-uploadedAgo)
+	id, err := repo.Create(context.Background(), record)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return id
+}
+
+// trash envía un contrato a la papelera y retrocede su fecha de eliminación
+func trash(t *testing.T, db *DB, repo repositories.ContractRepository, id int64, deletedAgo time.Duration) {
+	t.Helper()
+	ctx := context.Background()
+	if err := repo.Delete(ctx, 1, id); err != nil {
+		t.Fatalf("Delete(%d): %v", id, err)
+	}
+	deletedAt := time.Now().UTC().Add(-deletedAgo).Format(sqliteDateTime)
+	if _, err := db.ExecContext(ctx, `UPDATE contracts SET deleted_at = ? WHERE id = ?`, deletedAt, id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hold(t *testing.T, repo repositories.ContractRepository, id int64) {
+	t.Helper()
+	if err := repo.SetLegalHold(context.Background(), 1, id, true, "litigio en curso"); err != nil {
+		t.Fatalf("SetLegalHold(%d): %v", id, err)
+	}
+}
+
+func trashIDs(t *testing.T, repo repositories.ContractRepository) map[int64]bool {
+	t.Helper()
+	records, err := repo.ListTrash(context.Background(), 1, 100, 0)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	ids := make(map[int64]bool)
+	for _, record := range records {
+		ids[record.ID] = true
+	}
+	return ids
+}
+
+func TestLegalHoldBlocksDeleteAndPurge(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewContractRepository(db, nil)
+	ctx := context.Background()
+
+	active := seedContract(t, repo, "activo.pdf", 0)
+	trashed := seedContract(t, repo, "papelera.pdf", 0)
+	trash(t, db, repo, trashed, 0)
+	hold(t, repo, active)
+	hold(t, repo, trashed)
+
+	if err := repo.Delete(ctx, 1, active); !errors.Is(err, entities.ErrLegalHold) {
+		t.Errorf("Delete of a held contract = %v, want ErrLegalHold", err)
+	}
+	if _, err := repo.GetByID(ctx, 1, active); err != nil {
+		t.Errorf("held contract is no longer readable: %v", err)
+	}
+	if err := repo.Purge(ctx, 1, trashed); !errors.Is(err, entities.ErrLegalHold) {
+		t.Errorf("Purge of a held contract = %v, want ErrLegalHold", err)
+	}
+	if !trashIDs(t, repo)[trashed] {
+		t.Error("held contract left the trash after Purge")
+	}
+
+	// Liberado el hold, las operaciones vuelven a funcionar
+	if err := repo.SetLegalHold(ctx, 1, trashed, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Purge(ctx, 1, trashed); err != nil {
+		t.Errorf("Purge after releasing the hold: %v", err)
+	}
+	if err := repo.Purge(ctx, 1, trashed); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("second Purge = %v, want ErrContractNotFound", err)
+	}
+}
+
+func TestDeleteRestoreAndPurge(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewContractRepository(db, nil)
+	ctx := context.Background()
+	id := seedContract(t, repo, "alquiler.pdf", 0)
+
+	// Un contrato activo no se puede restaurar ni purgar
+	if err := repo.Restore(ctx, 1, id); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("Restore of an active contract = %v, want ErrContractNotFound", err)
+	}
+	if err := repo.Purge(ctx, 1, id); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("Purge of an active contract = %v, want ErrContractNotFound", err)
+	}
+
+	if err := repo.Delete(ctx, 1, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, 1, id); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("GetByID of a trashed contract = %v, want ErrContractNotFound", err)
+	}
+	if err := repo.Delete(ctx, 1, id); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("second Delete = %v, want ErrContractNotFound", err)
+	}
+	// Otro workspace no ve la papelera del primero
+	if err := repo.Restore(ctx, 2, id); !errors.Is(err, entities.ErrContractNotFound) {
+		t.Errorf("Restore from another workspace = %v, want ErrContractNotFound", err)
+	}
+
+	if err := repo.Restore(ctx, 1, id); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, 1, id); err != nil {
+		t.Errorf("GetByID after Restore: %v", err)
+	}
+	if trashIDs(t, repo)[id] {
+		t.Error("restored contract is still in the trash")
+	}
+}
+
+func TestListForRetentionSkipsHeldContracts(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewContractRepository(db, nil)
+	free := seedContract(t, repo, "libre.pdf", 48*time.Hour)
+	trashed := seedContract(t, repo, "papelera.pdf", 0)
+	trash(t, db, repo, trashed, time.Hour)
+	held := seedContract(t, repo, "retenido.pdf", 0)
+	hold(t, repo, held)
+
+	records, err := repo.ListForRetention(context.Background())
+	if err != nil {
+		t.Fatalf("ListForRetention: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != free || records[1].ID != trashed {
+		t.Fatalf("ListForRetention = %+v, want contracts %d and %d", records, free, trashed)
+	}
+	if age := time.Since(records[0].UploadedAt); age < 47*time.Hour || age > 49*time.Hour {
+		t.Errorf("uploaded_at parsed %v ago, want about 48h", age)
+	}
+	if records[0].DeletedAt != nil {
+		t.Errorf("active contract has deleted_at %v", records[0].DeletedAt)
+	}
+	if records[1].DeletedAt == nil || time.Since(*records[1].DeletedAt) < 59*time.Minute {
+		t.Errorf("trashed contract deleted_at = %v, want about an hour ago", records[1].DeletedAt)
+	}
+}
+
+// TestRetentionSweep purga la papelera vencida y envía a la papelera los contratos
+// vencidos, sin tocar los que tienen legal hold
+func TestRetentionSweep(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewContractRepository(db, nil)
+	ctx := context.Background()
+	policy := entities.RetentionPolicy{TrashRetention: 24 * time.Hour, ContractRetention: 30 * 24 * time.Hour}
+
+	expiredTrash := seedContract(t, repo, "papelera-vencida.pdf", 0)
+	trash(t, db, repo, expiredTrash, 48*time.Hour)
+	recentTrash := seedContract(t, repo, "papelera-reciente.pdf", 0)
+	trash(t, db, repo, recentTrash, time.Hour)
+	expired := seedContract(t, repo, "vencido.pdf", 60*24*time.Hour)
+	recent := seedContract(t, repo, "reciente.pdf", 24*time.Hour)
+	heldActive := seedContract(t, repo, "retenido.pdf", 60*24*time.Hour)
+	hold(t, repo, heldActive)
+	heldTrash := seedContract(t, repo, "retenido-papelera.pdf", 0)
+	trash(t, db, repo, heldTrash, 48*time.Hour)
+	hold(t, repo, heldTrash)
+
+	uc := usecases.NewRetentionUseCase(repo, usecases.NewAuditUseCase(NewAuditRepository(db)), policy, nil)
+	trashed, purged, err := uc.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if trashed != 1 || purged != 1 {
+		t.Errorf("Sweep = %d trashed, %d purged; want 1 and 1", trashed, purged)
+	}
+
+	inTrash := trashIDs(t, repo)
+	if inTrash[expiredTrash] {
+		t.Error("expired trash was not purged")
+	}
+	if !inTrash[recentTrash] {
+		t.Error("recent trash was purged before its retention")
+	}
+	if !inTrash[expired] {
+		t.Error("expired contract was not sent to the trash")
+	}
+	if _, err := repo.GetByID(ctx, 1, recent); err != nil {
+		t.Errorf("recent contract was removed: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, 1, heldActive); err != nil {
+		t.Errorf("held contract was removed by the sweep: %v", err)
+	}
+	if !inTrash[heldTrash] {
+		t.Error("held contract was purged from the trash by the sweep")
+	}
+
+	// Un segundo barrido no encuentra nada nuevo que hacer
+	trashed, purged, err = uc.Sweep(ctx)
+	if err != nil || trashed != 0 || purged != 0 {
+		t.Errorf("second Sweep = %d, %d, %v; want nothing to do", trashed, purged, err)
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// DataKeyRepositoryImpl implementa DataKeyRepository usando SQLite
+type DataKeyRepositoryImpl struct {
+	db *DB
+}
+
+// NewDataKeyRepository crea una nueva instancia del repositorio
+func NewDataKeyRepository(db *DB) repositories.DataKeyRepository {
+	return &DataKeyRepositoryImpl{db: db}
+}
+
+// Create guarda una clave nueva y la marca como activa, retirando la anterior
+func (r *DataKeyRepositoryImpl) Create(ctx context.Context, wrappedKey string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE data_keys SET active = 0, retired_at = ? WHERE active = 1`, now); err != nil {
+		return 0, fmt.Errorf("error retiring data key: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO data_keys (wrapped_key, active, created_at) VALUES (?, 1, ?)`,
+		wrappedKey, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error creating data key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing data key: %w", err)
+	}
+	return id, nil
+}
+
+// List lista todas las claves, la activa incluida
+func (r *DataKeyRepositoryImpl) List(ctx context.Context) ([]*entities.DataKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, wrapped_key, active, created_at, retired_at FROM data_keys ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing data keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*entities.DataKey
+	for rows.Next() {
+		key := &entities.DataKey{}
+		var createdAt, retiredAt sql.NullString
+		if err := rows.Scan(&key.ID, &key.WrappedKey, &key.Active, &createdAt, &retiredAt); err != nil {
+			return nil, fmt.Errorf("error scanning data key: %w", err)
+		}
+		if t, ok := parseDateTime(createdAt); ok {
+			key.CreatedAt = t
+		}
+		if t, ok := parseDateTime(retiredAt); ok {
+			key.RetiredAt = &t
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Rewrap reemplaza la clave envuelta (tras rotar la clave maestra)
+func (r *DataKeyRepositoryImpl) Rewrap(ctx context.Context, id int64, wrappedKey string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE data_keys SET wrapped_key = ? WHERE id = ?`, wrappedKey, id)
+	if err != nil {
+		return fmt.Errorf("error rewrapping data key: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return fmt.Errorf("data key %d not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Resealer es un SecretSealer que sabe si un valor debe volver a sellarse
+// (texto plano, o cifrado con una clave que ya no es la actual)
+type Resealer interface {
+	SecretSealer
+	NeedsReseal(stored string) bool
+}
+
+// plaintextSealer guarda los valores sin cifrar
+type plaintextSealer struct{}
+
+func (plaintextSealer) Seal(plaintext string) (string, error) { return plaintext, nil }
+func (plaintextSealer) Open(sealed string) (string, error)    { return sealed, nil }
+
+// SealedContractColumns son las columnas de contracts que se guardan cifradas
+var SealedContractColumns = []string{"analysis_result", "error_message", "legal_hold_reason", "checkpoint"}
+
+// resealBatchSize limita las filas leídas por vez; con una sola conexión no se puede
+// actualizar mientras se recorre un cursor
+const resealBatchSize = 100
+
+// ResealContracts vuelve a sellar con la clave activa los campos cifrados de todos los
+// contratos (papelera incluida) que estén en texto plano o con una clave anterior
+func ResealContracts(ctx context.Context, db *DB, sealer Resealer) (int, error) {
+	return resealTable(ctx, db, "contracts", SealedContractColumns, sealer)
+}
+
+// ResealProfiles vuelve a sellar las API keys de los perfiles LLM selladas con una clave maestra anterior
+func ResealProfiles(ctx context.Context, db *DB, sealer Resealer) (int, error) {
+	return resealTable(ctx, db, "llm_profiles", []string{"api_key_encrypted"}, sealer)
+}
+
+// ResealWebhooks vuelve a sellar los secretos de firma de los webhooks sellados con una clave maestra anterior
+func ResealWebhooks(ctx context.Context, db *DB, sealer Resealer) (int, error) {
+	return resealTable(ctx, db, "webhooks", []string{"secret_encrypted"}, sealer)
+}
+
+// ForEachSealedValue recorre los valores no vacíos de las columnas cifradas de contracts
+func ForEachSealedValue(ctx context.Context, db *DB, fn func(stored string)) error {
+	query := fmt.Sprintf(`SELECT %s FROM contracts`, strings.Join(SealedContractColumns, ", "))
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("error reading encrypted columns: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(SealedContractColumns))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+		for _, value := range values {
+			if value.Valid && value.String != "" {
+				fn(value.String)
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// resealTable recorre la tabla por lotes y actualiza las filas con algún valor a resellar
+func resealTable(ctx context.Context, db *DB, table string, columns []string, sealer Resealer) (int, error) {
+	selectQuery := fmt.Sprintf(`SELECT id, %s FROM %s WHERE id > ? ORDER BY id LIMIT ?`, strings.Join(columns, ", "), table)
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = ?"
+	}
+	updateQuery := fmt.Sprintf(`UPDATE %s SET %s WHERE id = ?`, table, strings.Join(assignments, ", "))
+
+	type row struct {
+		id     int64
+		values []sql.NullString
+	}
+
+	resealed := 0
+	var lastID int64
+	for {
+		rows, err := db.QueryContext(ctx, selectQuery, lastID, resealBatchSize)
+		if err != nil {
+			return resealed, fmt.Errorf("error reading %s: %w", table, err)
+		}
+
+		var batch []row
+		for rows.Next() {
+			r := row{values: make([]sql.NullString, len(columns))}
+			dest := []interface{}{&r.id}
+			for i := range r.values {
+				dest = append(dest, &r.values[i])
+			}
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return resealed, fmt.Errorf("error scanning %s: %w", table, err)
+			}
+			batch = append(batch, r)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return resealed, fmt.Errorf("error iterating %s: %w", table, err)
+		}
+		if len(batch) == 0 {
+			return resealed, nil
+		}
+
+		for _, r := range batch {
+			lastID = r.id
+
+			changed := false
+			args := make([]interface{}, 0, len(columns)+1)
+			for _, value := range r.values {
+				if !value.Valid || !sealer.NeedsReseal(value.String) {
+					args = append(args, value)
+					continue
+				}
+				plaintext, err := sealer.Open(value.String)
+				if err != nil {
+					return resealed, fmt.Errorf("error decrypting %s %d: %w", table, r.id, err)
+				}
+				sealed, err := sealer.Seal(plaintext)
+				if err != nil {
+					return resealed, fmt.Errorf("error encrypting %s %d: %w", table, r.id, err)
+				}
+				args = append(args, sealed)
+				changed = true
+			}
+			if !changed {
+				continue
+			}
+
+			if _, err := db.ExecContext(ctx, updateQuery, append(args, r.id)...); err != nil {
+				return resealed, fmt.Errorf("error updating %s %d: %w", table, r.id, err)
+			}
+			resealed++
+		}
+	}
+}
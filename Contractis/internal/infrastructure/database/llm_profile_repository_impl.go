@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// SecretSealer cifra y descifra las API keys guardadas
+type SecretSealer interface {
+	Seal(plaintext string) (string, error)
+	Open(sealed string) (string, error)
+}
+
+// LLMProfileRepositoryImpl implementa LLMProfileRepository usando SQLite
+type LLMProfileRepositoryImpl struct {
+	db     *DB
+	sealer SecretSealer
+}
+
+// NewLLMProfileRepository crea una nueva instancia del repositorio
+func NewLLMProfileRepository(db *DB, sealer SecretSealer) repositories.LLMProfileRepository {
+	return &LLMProfileRepositoryImpl{db: db, sealer: sealer}
+}
+
+const profileColumns = `id, workspace_id, owner_id, name, type, local_url, api_url, api_key_encrypted, model_name, max_tokens,
+		       concurrency, requests_per_minute, tokens_per_minute, failover_on, fallback_ids,
+		       created_at, updated_at`
+
+// Create crea un nuevo perfil
+func (r *LLMProfileRepositoryImpl) Create(ctx context.Context, profile *entities.LLMProfile) (int64, error) {
+	query := `
+		INSERT INTO llm_profiles (
+			name, type, local_url, api_url, api_key_encrypted, model_name, max_tokens,
+			concurrency, requests_per_minute, tokens_per_minute, failover_on, fallback_ids,
+			workspace_id, owner_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	args, err := r.writeArgs(profile)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, append(args, profile.WorkspaceID, nullableID(profile.OwnerID), now, now)...)
+	if err != nil {
+		return 0, fmt.Errorf("error creating LLM profile: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetByID obtiene un perfil del workspace por su ID
+func (r *LLMProfileRepositoryImpl) GetByID(ctx context.Context, workspaceID, id int64) (*entities.LLMProfile, error) {
+	query := `SELECT ` + profileColumns + ` FROM llm_profiles WHERE id = ? AND workspace_id = ?`
+
+	profile, err := r.scanProfile(r.db.QueryRowContext(ctx, query, id, workspaceID))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting LLM profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// List lista los perfiles del workspace ordenados por nombre
+func (r *LLMProfileRepositoryImpl) List(ctx context.Context, workspaceID int64) ([]*entities.LLMProfile, error) {
+	query := `SELECT ` + profileColumns + ` FROM llm_profiles WHERE workspace_id = ? ORDER BY name`
+	return r.list(ctx, query, workspaceID)
+}
+
+// ListAll lista los perfiles de todos los workspaces
+func (r *LLMProfileRepositoryImpl) ListAll(ctx context.Context) ([]*entities.LLMProfile, error) {
+	query := `SELECT ` + profileColumns + ` FROM llm_profiles ORDER BY workspace_id, name`
+	return r.list(ctx, query)
+}
+
+func (r *LLMProfileRepositoryImpl) list(ctx context.Context, query string, args ...interface{}) ([]*entities.LLMProfile, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing LLM profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*entities.LLMProfile
+	for rows.Next() {
+		profile, err := r.scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning LLM profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Update actualiza un perfil
+func (r *LLMProfileRepositoryImpl) Update(ctx context.Context, profile *entities.LLMProfile) error {
+	query := `
+		UPDATE llm_profiles SET
+			name = ?,
+			type = ?,
+			local_url = ?,
+			api_url = ?,
+			api_key_encrypted = ?,
+			model_name = ?,
+			max_tokens = ?,
+			concurrency = ?,
+			requests_per_minute = ?,
+			tokens_per_minute = ?,
+			failover_on = ?,
+			fallback_ids = ?,
+			updated_at = ?
+		WHERE id = ? AND workspace_id = ?
+	`
+
+	args, err := r.writeArgs(profile)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, append(args, time.Now(), profile.ID, profile.WorkspaceID)...)
+	if err != nil {
+		return fmt.Errorf("error updating LLM profile: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return entities.ErrProfileNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina un perfil del workspace por ID
+func (r *LLMProfileRepositoryImpl) Delete(ctx context.Context, workspaceID, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM llm_profiles WHERE id = ? AND workspace_id = ?`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error deleting LLM profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrProfileNotFound
+	}
+
+	return nil
+}
+
+// writeArgs prepara los valores de escritura en el orden de Create/Update, cifrando la API key
+func (r *LLMProfileRepositoryImpl) writeArgs(profile *entities.LLMProfile) ([]interface{}, error) {
+	sealedKey, err := r.sealer.Seal(profile.ApiKey)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting API key: %w", err)
+	}
+
+	failoverOn, err := json.Marshal(profile.FailoverOn)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding failover conditions: %w", err)
+	}
+
+	fallbackIDs, err := json.Marshal(profile.FallbackIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding fallback ids: %w", err)
+	}
+
+	return []interface{}{
+		profile.Name,
+		profile.Type,
+		profile.LocalUrl,
+		profile.ApiUrl,
+		sealedKey,
+		profile.ModelName,
+		profile.MaxTokens,
+		profile.Concurrency,
+		profile.RequestsPerMinute,
+		profile.TokensPerMinute,
+		string(failoverOn),
+		string(fallbackIDs),
+	}, nil
+}
+
+// scanProfile escanea una fila con las columnas de profileColumns y descifra la API key
+func (r *LLMProfileRepositoryImpl) scanProfile(row rowScanner) (*entities.LLMProfile, error) {
+	profile := &entities.LLMProfile{}
+	var ownerID sql.NullInt64
+	var localURL, apiURL, sealedKey, failoverOn, fallbackIDs, createdAt, updatedAt sql.NullString
+
+	err := row.Scan(
+		&profile.ID,
+		&profile.WorkspaceID,
+		&ownerID,
+		&profile.Name,
+		&profile.Type,
+		&localURL,
+		&apiURL,
+		&sealedKey,
+		&profile.ModelName,
+		&profile.MaxTokens,
+		&profile.Concurrency,
+		&profile.RequestsPerMinute,
+		&profile.TokensPerMinute,
+		&failoverOn,
+		&fallbackIDs,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.OwnerID = ownerID.Int64
+	profile.LocalUrl = localURL.String
+	profile.ApiUrl = apiURL.String
+
+	apiKey, err := r.sealer.Open(sealedKey.String)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting API key of profile %d: %w", profile.ID, err)
+	}
+	profile.ApiKey = apiKey
+	profile.HasApiKey = apiKey != ""
+
+	if failoverOn.String != "" {
+		if err := json.Unmarshal([]byte(failoverOn.String), &profile.FailoverOn); err != nil {
+			return nil, fmt.Errorf("error decoding failover conditions: %w", err)
+		}
+	}
+	if fallbackIDs.String != "" {
+		if err := json.Unmarshal([]byte(fallbackIDs.String), &profile.FallbackIDs); err != nil {
+			return nil, fmt.Errorf("error decoding fallback ids: %w", err)
+		}
+	}
+
+	if t, ok := parseDateTime(createdAt); ok {
+		profile.CreatedAt = t
+	}
+	if t, ok := parseDateTime(updatedAt); ok {
+		profile.UpdatedAt = t
+	}
+
+	return profile, nil
+}
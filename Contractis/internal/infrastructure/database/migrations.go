@@ -1,5 +1,11 @@
 package database
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
 const CreateContractsTableSQL = `
 CREATE TABLE IF NOT EXISTS contracts (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -35,11 +41,417 @@ CREATE INDEX IF NOT EXISTS idx_contracts_file_hash ON contracts(file_hash);
 CREATE INDEX IF NOT EXISTS idx_contracts_analyzed_at ON contracts(analyzed_at);
 `
 
-// RunMigrations ejecuta todas las migraciones necesarias
+const CreateLLMProfilesTableSQL = `
+CREATE TABLE IF NOT EXISTS llm_profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    type TEXT CHECK(type IN ('local', 'online')) NOT NULL,
+    local_url TEXT,
+    api_url TEXT,
+    api_key_encrypted TEXT,
+    model_name TEXT NOT NULL,
+    max_tokens INTEGER NOT NULL,
+    concurrency INTEGER DEFAULT 0,
+    requests_per_minute INTEGER DEFAULT 0,
+    tokens_per_minute INTEGER DEFAULT 0,
+    failover_on TEXT,
+    fallback_ids TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const CreateAPIKeysTableSQL = `
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT UNIQUE NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_used_at DATETIME,
+    revoked_at DATETIME
+);
+`
+
+// CreateWorkspacesSQL crea usuarios y workspaces, asigna los datos existentes al
+// workspace por defecto y reconstruye contracts: el hash pasa a ser único por workspace
+const CreateWorkspacesSQL = `
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT UNIQUE NOT NULL,
+    name TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workspaces (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT CHECK(role IN ('viewer', 'analyst', 'admin')) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user ON workspace_members(user_id);
+
+INSERT INTO workspaces (id, name) VALUES (1, 'Default');
+INSERT INTO users (id, email, name) VALUES (1, 'admin@localhost', 'Administrador');
+INSERT INTO workspace_members (workspace_id, user_id, role) VALUES (1, 1, 'admin');
+
+ALTER TABLE api_keys ADD COLUMN user_id INTEGER REFERENCES users(id);
+UPDATE api_keys SET user_id = 1;
+
+CREATE TABLE contracts_v5 (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspaces(id),
+    filename TEXT NOT NULL,
+    file_hash TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    uploaded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    analyzed_at DATETIME,
+    status TEXT CHECK(status IN ('pending', 'analyzing', 'completed', 'failed')) DEFAULT 'pending',
+    llm_type TEXT,
+    llm_model TEXT,
+    max_tokens INTEGER,
+    analysis_result TEXT,
+    character_count INTEGER,
+    estimated_tokens INTEGER,
+    chunks_count INTEGER,
+    processing_time_seconds REAL,
+    error_message TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    phase_models TEXT,
+    UNIQUE (workspace_id, file_hash)
+);
+
+INSERT INTO contracts_v5 (
+    id, workspace_id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
+    llm_type, llm_model, max_tokens, analysis_result, character_count, estimated_tokens,
+    chunks_count, processing_time_seconds, error_message, created_at, updated_at, phase_models
+)
+SELECT
+    id, 1, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
+    llm_type, llm_model, max_tokens, analysis_result, character_count, estimated_tokens,
+    chunks_count, processing_time_seconds, error_message, created_at, updated_at, phase_models
+FROM contracts;
+
+DROP TABLE contracts;
+ALTER TABLE contracts_v5 RENAME TO contracts;
+
+CREATE INDEX IF NOT EXISTS idx_contracts_workspace ON contracts(workspace_id, uploaded_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_filename ON contracts(filename);
+CREATE INDEX IF NOT EXISTS idx_contracts_uploaded_at ON contracts(uploaded_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_status ON contracts(status);
+CREATE INDEX IF NOT EXISTS idx_contracts_file_hash ON contracts(file_hash);
+CREATE INDEX IF NOT EXISTS idx_contracts_analyzed_at ON contracts(analyzed_at);
+`
+
+// CreateAuditLogSQL crea el log de auditoría. Los triggers rechazan UPDATE y DELETE
+// para que la tabla sea de solo inserción también fuera de la aplicación.
+const CreateAuditLogSQL = `
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    user_id INTEGER,
+    workspace_id INTEGER,
+    action TEXT NOT NULL,
+    contract_id INTEGER,
+    ip TEXT,
+    details TEXT,
+    prev_hash TEXT NOT NULL,
+    hash TEXT UNIQUE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_workspace ON audit_log(workspace_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_contract ON audit_log(contract_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user_id);
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit log is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit log is append-only');
+END;
+`
+
+// AddSoftDeleteSQL agrega la papelera (deleted_at) y el legal hold a contracts
+const AddSoftDeleteSQL = `
+ALTER TABLE contracts ADD COLUMN deleted_at DATETIME;
+ALTER TABLE contracts ADD COLUMN legal_hold INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE contracts ADD COLUMN legal_hold_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_contracts_deleted_at ON contracts(workspace_id, deleted_at);
+`
+
+// CreateDataKeysSQL crea la tabla de claves de datos (DEK) envueltas con la clave maestra
+const CreateDataKeysSQL = `
+CREATE TABLE IF NOT EXISTS data_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    wrapped_key TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    retired_at DATETIME
+);
+`
+
+// CreateBatchesSQL crea los lotes de subida y sus documentos. batch_documents guarda la
+// ruta del documento dentro del ZIP tal como llegó: nunca se usa como ruta en disco.
+const CreateBatchesSQL = `
+CREATE TABLE IF NOT EXISTS batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id),
+    name TEXT NOT NULL,
+    status TEXT CHECK(status IN ('processing', 'completed')) NOT NULL DEFAULT 'processing',
+    created_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    finished_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS batch_documents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    batch_id INTEGER NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    filename TEXT NOT NULL,
+    file_hash TEXT,
+    file_size INTEGER NOT NULL DEFAULT 0,
+    status TEXT CHECK(status IN ('pending', 'analyzing', 'completed', 'duplicate', 'failed', 'rejected')) NOT NULL DEFAULT 'pending',
+    contract_id INTEGER,
+    error_message TEXT,
+    started_at DATETIME,
+    finished_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_batches_workspace ON batches(workspace_id, id);
+CREATE INDEX IF NOT EXISTS idx_batch_documents_batch ON batch_documents(batch_id, position);
+`
+
+// AddInterruptedStatusSQL permite el status 'interrupted' (análisis cortado al detenerse el
+// servidor) y agrega checkpoint, los fragmentos ya analizados cifrados, para retomarlo.
+// SQLite no permite modificar un CHECK, así que la tabla se reconstruye.
+const AddInterruptedStatusSQL = `
+CREATE TABLE contracts_v10 (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspaces(id),
+    filename TEXT NOT NULL,
+    file_hash TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    uploaded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    analyzed_at DATETIME,
+    status TEXT CHECK(status IN ('pending', 'analyzing', 'completed', 'failed', 'interrupted')) DEFAULT 'pending',
+    llm_type TEXT,
+    llm_model TEXT,
+    max_tokens INTEGER,
+    analysis_result TEXT,
+    character_count INTEGER,
+    estimated_tokens INTEGER,
+    chunks_count INTEGER,
+    processing_time_seconds REAL,
+    error_message TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    phase_models TEXT,
+    deleted_at DATETIME,
+    legal_hold INTEGER NOT NULL DEFAULT 0,
+    legal_hold_reason TEXT,
+    checkpoint TEXT,
+    UNIQUE (workspace_id, file_hash)
+);
+
+INSERT INTO contracts_v10 (
+    id, workspace_id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
+    llm_type, llm_model, max_tokens, analysis_result, character_count, estimated_tokens,
+    chunks_count, processing_time_seconds, error_message, created_at, updated_at, phase_models,
+    deleted_at, legal_hold, legal_hold_reason
+)
+SELECT
+    id, workspace_id, filename, file_hash, file_size, uploaded_at, analyzed_at, status,
+    llm_type, llm_model, max_tokens, analysis_result, character_count, estimated_tokens,
+    chunks_count, processing_time_seconds, error_message, created_at, updated_at, phase_models,
+    deleted_at, legal_hold, legal_hold_reason
+FROM contracts;
+
+DROP TABLE contracts;
+ALTER TABLE contracts_v10 RENAME TO contracts;
+
+CREATE INDEX IF NOT EXISTS idx_contracts_workspace ON contracts(workspace_id, uploaded_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_filename ON contracts(filename);
+CREATE INDEX IF NOT EXISTS idx_contracts_uploaded_at ON contracts(uploaded_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_status ON contracts(status);
+CREATE INDEX IF NOT EXISTS idx_contracts_file_hash ON contracts(file_hash);
+CREATE INDEX IF NOT EXISTS idx_contracts_analyzed_at ON contracts(analyzed_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_deleted_at ON contracts(workspace_id, deleted_at);
+`
+
+// CreateWebhooksSQL crea los webhooks de cada workspace (con el secreto de firma cifrado)
+// y el log de entregas. Las entregas pendientes se retoman al reiniciar el servidor.
+const CreateWebhooksSQL = `
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id),
+    url TEXT NOT NULL,
+    description TEXT,
+    events TEXT NOT NULL,
+    secret_encrypted TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id),
+    event_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT CHECK(status IN ('pending', 'succeeded', 'failed')) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    response_body TEXT,
+    error_message TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at DATETIME,
+    next_attempt_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_workspace ON webhooks(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+`
+
+// ScopeLLMProfilesSQL asigna cada perfil LLM a un workspace (los existentes, al workspace
+// por defecto) y hace el nombre único por workspace; la tabla se reconstruye para cambiar
+// la restricción UNIQUE
+const ScopeLLMProfilesSQL = `
+CREATE TABLE llm_profiles_v12 (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    workspace_id INTEGER NOT NULL DEFAULT 1 REFERENCES workspaces(id),
+    name TEXT NOT NULL,
+    type TEXT CHECK(type IN ('local', 'online')) NOT NULL,
+    local_url TEXT,
+    api_url TEXT,
+    api_key_encrypted TEXT,
+    model_name TEXT NOT NULL,
+    max_tokens INTEGER NOT NULL,
+    concurrency INTEGER DEFAULT 0,
+    requests_per_minute INTEGER DEFAULT 0,
+    tokens_per_minute INTEGER DEFAULT 0,
+    failover_on TEXT,
+    fallback_ids TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workspace_id, name)
+);
+
+INSERT INTO llm_profiles_v12 (
+    id, name, type, local_url, api_url, api_key_encrypted, model_name, max_tokens,
+    concurrency, requests_per_minute, tokens_per_minute, failover_on, fallback_ids,
+    created_at, updated_at
+)
+SELECT
+    id, name, type, local_url, api_url, api_key_encrypted, model_name, max_tokens,
+    concurrency, requests_per_minute, tokens_per_minute, failover_on, fallback_ids,
+    created_at, updated_at
+FROM llm_profiles;
+
+DROP TABLE llm_profiles;
+ALTER TABLE llm_profiles_v12 RENAME TO llm_profiles;
+`
+
+// CreateSchemaMigrationsTableSQL registra las migraciones aplicadas
+const CreateSchemaMigrationsTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// migration representa un cambio de esquema versionado
+type migration struct {
+	version     int
+	description string
+	sql         string
+}
+
+// migrations se aplican en orden y una sola vez. La versión 1 es idempotente para
+// bases de datos creadas antes de existir schema_migrations.
+var migrations = []migration{
+	{1, "create contracts table", CreateContractsTableSQL},
+	{2, "add phase_models to contracts", `ALTER TABLE contracts ADD COLUMN phase_models TEXT;`},
+	{3, "create llm_profiles table", CreateLLMProfilesTableSQL},
+	{4, "create api_keys table", CreateAPIKeysTableSQL},
+	{5, "add users and workspaces", CreateWorkspacesSQL},
+	{6, "create audit_log table", CreateAuditLogSQL},
+	{7, "add soft delete and legal hold to contracts", AddSoftDeleteSQL},
+	{8, "create data_keys table", CreateDataKeysSQL},
+	{9, "create batches tables", CreateBatchesSQL},
+	{10, "add interrupted status and checkpoint to contracts", AddInterruptedStatusSQL},
+	{11, "create webhooks tables", CreateWebhooksSQL},
+	{12, "scope llm_profiles to workspaces", ScopeLLMProfilesSQL},
+	{13, "add owner to llm_profiles", `ALTER TABLE llm_profiles ADD COLUMN owner_id INTEGER REFERENCES users(id);`},
+}
+
+// RunMigrations ejecuta todas las migraciones pendientes
 func RunMigrations(db *DB) error {
-	_, err := db.Exec(CreateContractsTableSQL)
+	if _, err := db.Exec(CreateSchemaMigrationsTableSQL); err != nil {
+		return err
+	}
+
+	current, err := SchemaVersion(context.Background(), db)
 	if err != nil {
 		return err
 	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.description, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, description) VALUES (?, ?)`, m.version, m.description); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("recording migration %d: %w", m.version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.Info("migración aplicada", "version", m.version, "description", m.description)
+	}
 	return nil
 }
+
+// SchemaVersion retorna la última migración aplicada
+func SchemaVersion(ctx context.Context, db *DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+	return version, nil
+}
+
+// LatestSchemaVersion retorna la versión que espera este binario
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+)
+
+// QueryObserver recibe la duración de cada consulta. statement resume la consulta en su
+// verbo y tabla ("SELECT contracts") para agrupar sin multiplicar las series.
+type QueryObserver func(statement string, duration time.Duration, err error)
+
+// Observe registra un observador para las consultas hechas con ExecContext, QueryContext
+// y QueryRowContext (las de transacciones no se observan). En QueryContext la duración es
+// hasta obtener las filas, sin recorrerlas.
+func (db *DB) Observe(observer QueryObserver) {
+	db.observer = observer
+}
+
+// Trace crea un span por cada consulta observada cuyo contexto ya pertenezca a una traza
+// (un análisis o una petición HTTP); las consultas sueltas no generan trazas
+func (db *DB) Trace(tracer *tracing.Tracer) {
+	db.tracer = tracer
+}
+
+// ExecContext ejecuta una sentencia informando su duración al observador
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	q := db.start(ctx, query)
+	result, err := db.DB.ExecContext(q.ctx, query, args...)
+	q.finish(err)
+	return result, err
+}
+
+// QueryContext ejecuta una consulta informando su duración al observador
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	q := db.start(ctx, query)
+	rows, err := db.DB.QueryContext(q.ctx, query, args...)
+	q.finish(err)
+	return rows, err
+}
+
+// QueryRowContext ejecuta una consulta de una fila informando su duración al observador
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	q := db.start(ctx, query)
+	row := db.DB.QueryRowContext(q.ctx, query, args...)
+	err := row.Err()
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	q.finish(err)
+	return row
+}
+
+// observedQuery es una consulta en curso con su span
+type observedQuery struct {
+	db        *DB
+	ctx       context.Context
+	statement string
+	span      services.Span
+	start     time.Time
+}
+
+func (db *DB) start(ctx context.Context, query string) observedQuery {
+	statement := statementOf(query)
+	ctx, span := db.tracer.StartChild(ctx, statement, tracing.KindClient)
+	span.SetAttribute("db.system.name", "sqlite")
+	operation, table, _ := strings.Cut(statement, " ")
+	span.SetAttribute("db.operation.name", operation)
+	if table != "" {
+		span.SetAttribute("db.collection.name", table)
+	}
+	span.SetAttribute("db.query.text", query)
+	return observedQuery{db: db, ctx: ctx, statement: statement, span: span, start: time.Now()}
+}
+
+func (q observedQuery) finish(err error) {
+	q.span.RecordError(err)
+	q.span.End()
+	if q.db.observer != nil {
+		q.db.observer(q.statement, time.Since(q.start), err)
+	}
+}
+
+// statements guarda el resumen de cada consulta ya vista; las consultas son constantes
+// del código, así que el mapa no crece indefinidamente
+var statements sync.Map
+
+// statementOf resume una consulta en su verbo y la tabla principal
+func statementOf(query string) string {
+	if cached, ok := statements.Load(query); ok {
+		return cached.(string)
+	}
+
+	words := strings.Fields(query)
+	statement := "OTHER"
+	if len(words) > 0 {
+		verb := strings.ToUpper(words[0])
+		statement = verb
+		after := map[string]string{"SELECT": "FROM", "DELETE": "FROM", "INSERT": "INTO", "UPDATE": "UPDATE"}[verb]
+		for i, word := range words[:len(words)-1] {
+			if after != "" && strings.EqualFold(word, after) {
+				table := strings.Trim(words[i+1], "(),;`\"")
+				statement = verb + " " + strings.ToLower(table)
+				break
+			}
+		}
+	}
+	statements.Store(query, statement)
+	return statement
+}
@@ -3,14 +3,17 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
 	_ "modernc.org/sqlite"
 )
 
 // DB envuelve la conexión a SQLite
 type DB struct {
 	*sql.DB
+	observer QueryObserver
+	tracer   *tracing.Tracer
 }
 
 // NewSQLiteDB crea una nueva conexión a SQLite
@@ -35,7 +38,7 @@ func NewSQLiteDB(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("error running migrations: %w", err)
 	}
 
-	log.Printf("✓ Base de datos SQLite inicializada: %s", dbPath)
+	slog.Info("base de datos SQLite inicializada", "path", dbPath)
 	return wrapper, nil
 }
 
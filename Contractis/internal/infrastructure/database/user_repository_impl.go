@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// UserRepositoryImpl implementa UserRepository usando SQLite
+type UserRepositoryImpl struct {
+	db *DB
+}
+
+// NewUserRepository crea una nueva instancia del repositorio
+func NewUserRepository(db *DB) repositories.UserRepository {
+	return &UserRepositoryImpl{db: db}
+}
+
+// Create crea un nuevo usuario (el email se guarda en minúsculas)
+func (r *UserRepositoryImpl) Create(ctx context.Context, user *entities.User) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (email, name, created_at) VALUES (?, ?, ?)`,
+		normalizeEmail(user.Email), user.Name, time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error creating user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetByID obtiene un usuario por su ID
+func (r *UserRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.User, error) {
+	return r.getOne(r.db.QueryRowContext(ctx, `SELECT id, email, name, created_at FROM users WHERE id = ?`, id))
+}
+
+// GetByEmail obtiene un usuario por su email
+func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.getOne(r.db.QueryRowContext(ctx, `SELECT id, email, name, created_at FROM users WHERE email = ?`, normalizeEmail(email)))
+}
+
+// List lista todos los usuarios
+func (r *UserRepositoryImpl) List(ctx context.Context) ([]*entities.User, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, email, name, created_at FROM users ORDER BY email`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepositoryImpl) getOne(row *sql.Row) (*entities.User, error) {
+	user, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	return user, nil
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+	user := &entities.User{}
+	var name, createdAt sql.NullString
+
+	if err := row.Scan(&user.ID, &user.Email, &name, &createdAt); err != nil {
+		return nil, err
+	}
+
+	user.Name = name.String
+	if t, ok := parseDateTime(createdAt); ok {
+		user.CreatedAt = t
+	}
+	return user, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
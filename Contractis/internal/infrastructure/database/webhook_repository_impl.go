@@ -0,0 +1,325 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// WebhookRepositoryImpl implementa WebhookRepository usando SQLite
+type WebhookRepositoryImpl struct {
+	db     *DB
+	sealer SecretSealer
+}
+
+// NewWebhookRepository crea una nueva instancia del repositorio. Los secretos de firma
+// se guardan cifrados con sealer, como las API keys de los perfiles LLM.
+func NewWebhookRepository(db *DB, sealer SecretSealer) repositories.WebhookRepository {
+	return &WebhookRepositoryImpl{db: db, sealer: sealer}
+}
+
+const webhookColumns = `id, workspace_id, url, description, events, secret_encrypted, active, created_by, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, workspace_id, event_id, event, payload, status, attempts, response_status,
+		       response_body, error_message, duration_ms, created_at, last_attempt_at, next_attempt_at`
+
+// Create crea un webhook
+func (r *WebhookRepositoryImpl) Create(ctx context.Context, webhook *entities.Webhook) (int64, error) {
+	events, secret, err := r.writeArgs(webhook)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhooks (workspace_id, url, description, events, secret_encrypted, active, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		webhook.WorkspaceID, webhook.URL, nullableString(webhook.Description), events, secret,
+		webhook.Active, nullableString(webhook.CreatedBy), now, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// GetByID obtiene un webhook del workspace
+func (r *WebhookRepositoryImpl) GetByID(ctx context.Context, workspaceID, id int64) (*entities.Webhook, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = ? AND workspace_id = ?`, id, workspaceID)
+	webhook, err := r.scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// List lista los webhooks del workspace en orden de creación
+func (r *WebhookRepositoryImpl) List(ctx context.Context, workspaceID int64) ([]*entities.Webhook, error) {
+	return r.query(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE workspace_id = ? ORDER BY id`, workspaceID)
+}
+
+// ListSubscribed lista los webhooks activos del workspace suscritos al evento
+func (r *WebhookRepositoryImpl) ListSubscribed(ctx context.Context, workspaceID int64, event entities.WebhookEvent) ([]*entities.Webhook, error) {
+	webhooks, err := r.query(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE workspace_id = ? AND active = 1 ORDER BY id`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []*entities.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed, nil
+}
+
+// Update guarda los cambios del webhook
+func (r *WebhookRepositoryImpl) Update(ctx context.Context, webhook *entities.Webhook) error {
+	events, secret, err := r.writeArgs(webhook)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhooks SET url = ?, description = ?, events = ?, secret_encrypted = ?, active = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ?`,
+		webhook.URL, nullableString(webhook.Description), events, secret, webhook.Active, time.Now(),
+		webhook.ID, webhook.WorkspaceID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating webhook: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete elimina el webhook y su log de entregas en una transacción (SQLite no aplica
+// ON DELETE CASCADE sin PRAGMA foreign_keys)
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, workspaceID, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting webhook transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ? AND workspace_id = ?`, id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing webhook deletion: %w", err)
+	}
+	return nil
+}
+
+// CreateDelivery registra una entrega
+func (r *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, workspace_id, event_id, event, payload, status, created_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.WebhookID, delivery.WorkspaceID, delivery.EventID, string(delivery.Event), string(delivery.Payload),
+		string(delivery.Status), delivery.CreatedAt.UTC().Format(sqliteDateTime), formatNullableTime(delivery.NextAttemptAt),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating webhook delivery: %w", err)
+	}
+
+	delivery.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting last insert id: %w", err)
+	}
+	return nil
+}
+
+// UpdateDelivery guarda el resultado del último intento
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = ?, attempts = ?, response_status = ?, response_body = ?, error_message = ?,
+			duration_ms = ?, last_attempt_at = ?, next_attempt_at = ?
+		WHERE id = ?`,
+		string(delivery.Status), delivery.Attempts, sql.NullInt64{Int64: int64(delivery.ResponseStatus), Valid: delivery.ResponseStatus != 0},
+		nullableString(delivery.ResponseBody), nullableString(delivery.Error), delivery.DurationMs,
+		formatNullableTime(delivery.LastAttemptAt), formatNullableTime(delivery.NextAttemptAt), delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries lista las entregas del webhook de la más reciente a la más antigua
+func (r *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, webhookID, beforeID int64, limit int) ([]*entities.WebhookDelivery, error) {
+	return r.queryDeliveries(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE webhook_id = ? AND (? = 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?`, webhookID, beforeID, beforeID, limit)
+}
+
+// ListDue lista las entregas pendientes cuyo próximo intento ya venció
+func (r *WebhookRepositoryImpl) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	return r.queryDeliveries(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?`, string(entities.DeliveryPending), now.UTC().Format(sqliteDateTime), limit)
+}
+
+// PruneDeliveries borra las entregas terminadas anteriores a before
+func (r *WebhookRepositoryImpl) PruneDeliveries(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM webhook_deliveries WHERE status != ? AND created_at < ?`,
+		string(entities.DeliveryPending), before.UTC().Format(sqliteDateTime))
+	if err != nil {
+		return 0, fmt.Errorf("error pruning webhook deliveries: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	return int(affected), nil
+}
+
+// writeArgs codifica los eventos y cifra el secreto del webhook
+func (r *WebhookRepositoryImpl) writeArgs(webhook *entities.Webhook) (string, string, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return "", "", fmt.Errorf("error encoding webhook events: %w", err)
+	}
+	secret, err := r.sealer.Seal(webhook.Secret)
+	if err != nil {
+		return "", "", fmt.Errorf("error encrypting webhook secret: %w", err)
+	}
+	return string(events), secret, nil
+}
+
+func (r *WebhookRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*entities.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*entities.Webhook
+	for rows.Next() {
+		webhook, err := r.scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// scanWebhook escanea una fila con las columnas de webhookColumns y descifra el secreto
+func (r *WebhookRepositoryImpl) scanWebhook(row rowScanner) (*entities.Webhook, error) {
+	webhook := &entities.Webhook{}
+	var description, events, sealedSecret, createdBy, createdAt, updatedAt sql.NullString
+	err := row.Scan(&webhook.ID, &webhook.WorkspaceID, &webhook.URL, &description, &events, &sealedSecret,
+		&webhook.Active, &createdBy, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Description = description.String
+	webhook.CreatedBy = createdBy.String
+	if err := json.Unmarshal([]byte(events.String), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("error decoding events of webhook %d: %w", webhook.ID, err)
+	}
+
+	secret, err := r.sealer.Open(sealedSecret.String)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting secret of webhook %d: %w", webhook.ID, err)
+	}
+	webhook.Secret = secret
+
+	if t, ok := parseDateTime(createdAt); ok {
+		webhook.CreatedAt = t
+	}
+	if t, ok := parseDateTime(updatedAt); ok {
+		webhook.UpdatedAt = t
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepositoryImpl) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*entities.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entities.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func scanDelivery(row rowScanner) (*entities.WebhookDelivery, error) {
+	delivery := &entities.WebhookDelivery{}
+	var event, payload, status string
+	var responseStatus sql.NullInt64
+	var responseBody, errorMessage, createdAt, lastAttemptAt, nextAttemptAt sql.NullString
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.WorkspaceID, &delivery.EventID, &event, &payload,
+		&status, &delivery.Attempts, &responseStatus, &responseBody, &errorMessage, &delivery.DurationMs,
+		&createdAt, &lastAttemptAt, &nextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Event = entities.WebhookEvent(event)
+	delivery.Payload = json.RawMessage(payload)
+	delivery.Status = entities.DeliveryStatus(status)
+	delivery.ResponseStatus = int(responseStatus.Int64)
+	delivery.ResponseBody = responseBody.String
+	delivery.Error = errorMessage.String
+	if t, ok := parseDateTime(createdAt); ok {
+		delivery.CreatedAt = t
+	}
+	if t, ok := parseDateTime(lastAttemptAt); ok {
+		delivery.LastAttemptAt = &t
+	}
+	if t, ok := parseDateTime(nextAttemptAt); ok {
+		delivery.NextAttemptAt = &t
+	}
+	return delivery, nil
+}
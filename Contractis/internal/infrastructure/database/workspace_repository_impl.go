@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// WorkspaceRepositoryImpl implementa WorkspaceRepository usando SQLite
+type WorkspaceRepositoryImpl struct {
+	db *DB
+}
+
+// NewWorkspaceRepository crea una nueva instancia del repositorio
+func NewWorkspaceRepository(db *DB) repositories.WorkspaceRepository {
+	return &WorkspaceRepositoryImpl{db: db}
+}
+
+// Create crea un nuevo workspace
+func (r *WorkspaceRepositoryImpl) Create(ctx context.Context, workspace *entities.Workspace) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO workspaces (name, created_at) VALUES (?, ?)`, workspace.Name, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error creating workspace: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting last insert id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetByID obtiene un workspace por su ID
+func (r *WorkspaceRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.Workspace, error) {
+	workspace := &entities.Workspace{}
+	var createdAt sql.NullString
+
+	err := r.db.QueryRowContext(ctx, `SELECT id, name, created_at FROM workspaces WHERE id = ?`, id).
+		Scan(&workspace.ID, &workspace.Name, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting workspace: %w", err)
+	}
+
+	if t, ok := parseDateTime(createdAt); ok {
+		workspace.CreatedAt = t
+	}
+	return workspace, nil
+}
+
+// List lista todos los workspaces
+func (r *WorkspaceRepositoryImpl) List(ctx context.Context) ([]*entities.Workspace, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, created_at, '' FROM workspaces ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWorkspaces(rows)
+}
+
+// ListForUser lista los workspaces del usuario con su rol en cada uno
+func (r *WorkspaceRepositoryImpl) ListForUser(ctx context.Context, userID int64) ([]*entities.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, w.created_at, m.role
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = ?
+		ORDER BY w.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing workspaces for user: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWorkspaces(rows)
+}
+
+// GetMembership obtiene el rol del usuario en el workspace
+func (r *WorkspaceRepositoryImpl) GetMembership(ctx context.Context, workspaceID, userID int64) (*entities.Membership, error) {
+	membership := &entities.Membership{WorkspaceID: workspaceID, UserID: userID}
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT role FROM workspace_members WHERE workspace_id = ? AND user_id = ?`, workspaceID, userID).
+		Scan(&membership.Role)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting workspace membership: %w", err)
+	}
+
+	return membership, nil
+}
+
+// SetMember agrega un usuario al workspace o cambia su rol
+func (r *WorkspaceRepositoryImpl) SetMember(ctx context.Context, membership *entities.Membership) error {
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id, role, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = excluded.role
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, membership.WorkspaceID, membership.UserID, membership.Role, time.Now()); err != nil {
+		return fmt.Errorf("error setting workspace member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember quita un usuario del workspace
+func (r *WorkspaceRepositoryImpl) RemoveMember(ctx context.Context, workspaceID, userID int64) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM workspace_members WHERE workspace_id = ? AND user_id = ?`, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("error removing workspace member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user %d is not a member of workspace %d", userID, workspaceID)
+	}
+
+	return nil
+}
+
+// scanWorkspaces escanea filas (id, name, created_at, role)
+func scanWorkspaces(rows *sql.Rows) ([]*entities.Workspace, error) {
+	var workspaces []*entities.Workspace
+	for rows.Next() {
+		workspace := &entities.Workspace{}
+		var createdAt sql.NullString
+		var role string
+
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &createdAt, &role); err != nil {
+			return nil, fmt.Errorf("error scanning workspace: %w", err)
+		}
+
+		workspace.Role = entities.Role(role)
+		if t, ok := parseDateTime(createdAt); ok {
+			workspace.CreatedAt = t
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return workspaces, nil
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Build retorna la información del binario. version es la indicada al compilar
+// (-ldflags "-X main.version=..."); si está vacía se usa la versión del módulo. La revisión
+// y la fecha salen del control de versiones si go build las registró.
+func Build(version string) entities.BuildInfo {
+	build := entities.BuildInfo{Version: version, GoVersion: runtime.Version()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if build.Version == "" {
+			build.Version = "unknown"
+		}
+		return build
+	}
+	if build.Version == "" {
+		build.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.BuildTime = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
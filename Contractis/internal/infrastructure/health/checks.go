@@ -0,0 +1,76 @@
+// Package health implementa los chequeos de readiness de las dependencias del servidor
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rodascaar/contractis/internal/domain/services"
+	"github.com/rodascaar/contractis/internal/infrastructure/database"
+)
+
+// databaseCheck revisa que la base responda y tenga aplicadas las migraciones que espera
+// este binario
+type databaseCheck struct {
+	db *database.DB
+}
+
+// NewDatabaseCheck crea el chequeo de la base de datos
+func NewDatabaseCheck(db *database.DB) services.HealthCheck {
+	return &databaseCheck{db: db}
+}
+
+func (c *databaseCheck) Name() string   { return "database" }
+func (c *databaseCheck) Critical() bool { return true }
+
+func (c *databaseCheck) Check(ctx context.Context) (map[string]any, error) {
+	if err := c.db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("ping: %w", err)
+	}
+	version, err := database.SchemaVersion(ctx, c.db)
+	if err != nil {
+		return nil, err
+	}
+	expected := database.LatestSchemaVersion()
+	details := map[string]any{"schemaVersion": version, "expectedSchemaVersion": expected}
+	if version != expected {
+		return details, fmt.Errorf("schema version %d, expected %d", version, expected)
+	}
+	return details, nil
+}
+
+// storageCheck revisa que se pueda escribir en una carpeta donde el servidor guarda
+// archivos (PDF subidos, lotes, reportes)
+type storageCheck struct {
+	name string
+	dir  string
+}
+
+// NewStorageCheck crea el chequeo de escritura de dir, informado como name
+func NewStorageCheck(name, dir string) services.HealthCheck {
+	return &storageCheck{name: name, dir: dir}
+}
+
+func (c *storageCheck) Name() string   { return c.name }
+func (c *storageCheck) Critical() bool { return true }
+
+func (c *storageCheck) Check(ctx context.Context) (map[string]any, error) {
+	details := map[string]any{"path": c.dir}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return details, fmt.Errorf("not writable: %w", err)
+	}
+	f, err := os.CreateTemp(c.dir, ".contractis-health-*")
+	if err != nil {
+		return details, fmt.Errorf("not writable: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("ok"); err != nil {
+		f.Close()
+		return details, fmt.Errorf("write: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return details, fmt.Errorf("write: %w", err)
+	}
+	return details, nil
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// llmCheck revisa que los endpoints de los perfiles LLM respondan. No envía prompts: basta
+// con que el servidor conteste por HTTP (aunque sea 401, 404 o 405) para considerarlo alcanzable.
+// El resultado se reutiliza durante interval para no consultar a los proveedores en cada
+// chequeo del orquestador.
+type llmCheck struct {
+	profiles repositories.LLMProfileRepository
+	interval time.Duration
+	client   *http.Client
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	details   map[string]any
+	err       error
+}
+
+// NewLLMCheck crea el chequeo de alcance de los perfiles LLM, que se repite como mucho
+// una vez por interval. No es crítico: si falla, el servicio queda degraded.
+func NewLLMCheck(profiles repositories.LLMProfileRepository, interval time.Duration) services.HealthCheck {
+	return &llmCheck{profiles: profiles, interval: interval, client: &http.Client{}}
+}
+
+func (c *llmCheck) Name() string   { return "llm" }
+func (c *llmCheck) Critical() bool { return false }
+
+func (c *llmCheck) Check(ctx context.Context) (map[string]any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.interval {
+		return c.details, c.err
+	}
+	c.details, c.err = c.probeAll(ctx)
+	c.checkedAt = time.Now()
+	return c.details, c.err
+}
+
+// profileHealth es el estado de un perfil en el reporte; no incluye la URL del endpoint
+type profileHealth struct {
+	ID     int64                 `json:"id"`
+	Name   string                `json:"name"`
+	Model  string                `json:"model"`
+	Status entities.HealthStatus `json:"status"`
+	Error  string                `json:"error,omitempty"`
+}
+
+func (c *llmCheck) probeAll(ctx context.Context) (map[string]any, error) {
+	profiles, err := c.profiles.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]profileHealth, len(profiles))
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = profileHealth{ID: profile.ID, Name: profile.Name, Model: profile.ModelName, Status: entities.HealthUp}
+			if err := c.probe(ctx, profile.ToLLMConfig()); err != nil {
+				results[i].Status = entities.HealthDown
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	down := 0
+	for _, result := range results {
+		if result.Status != entities.HealthUp {
+			down++
+		}
+	}
+	details := map[string]any{"profiles": results}
+	if down > 0 {
+		return details, fmt.Errorf("%d of %d profiles unreachable", down, len(results))
+	}
+	return details, nil
+}
+
+// probe hace un GET al endpoint; un error de red o una respuesta de gateway o servicio no
+// disponible (502, 503, 504) lo marcan inalcanzable
+func (c *llmCheck) probe(ctx context.Context, config *entities.LLMConfig) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.GetEndpointURL(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("unreachable")
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// ReportSuffix se agrega al nombre del documento (sin extensión) para nombrar su reporte
+const ReportSuffix = ".analisis.txt"
+
+// ReportWriter escribe los reportes junto a cada documento o, si se indica outputDir,
+// en esa carpeta replicando la estructura de subcarpetas de la entrada
+type ReportWriter struct {
+	outputDir string
+}
+
+// NewReportWriter crea un ReportWriter; con outputDir vacío los reportes van junto al PDF
+func NewReportWriter(outputDir string) *ReportWriter {
+	return &ReportWriter{outputDir: outputDir}
+}
+
+// ReportPath retorna la ruta del reporte del documento y si ya existe
+func (w *ReportWriter) ReportPath(doc entities.IngestDocument) (string, bool) {
+	var path string
+	if w.outputDir == "" {
+		path = strings.TrimSuffix(doc.Path, filepath.Ext(doc.Path)) + ReportSuffix
+	} else {
+		rel := filepath.FromSlash(doc.RelPath)
+		path = filepath.Join(w.outputDir, strings.TrimSuffix(rel, filepath.Ext(rel))+ReportSuffix)
+	}
+	_, err := os.Stat(path)
+	return path, err == nil
+}
+
+// WriteReport escribe el reporte de forma atómica (archivo temporal y rename)
+func (w *ReportWriter) WriteReport(doc entities.IngestDocument, record *entities.ContractRecord) (string, error) {
+	path, _ := w.ReportPath(doc)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".reporte-*.tmp")
+	if err != nil {
+		return path, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(record.ExportText()); err != nil {
+		tmp.Close()
+		return path, err
+	}
+	if err := tmp.Close(); err != nil {
+		return path, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return path, err
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
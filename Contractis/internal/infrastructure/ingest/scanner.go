@@ -0,0 +1,185 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/utils"
+)
+
+// DefaultInclude es el filtro de archivos por defecto
+var DefaultInclude = []string{"*.pdf"}
+
+// Scanner recorre recursivamente una carpeta de entrada aplicando filtros glob.
+// Un patrón sin "/" se compara con el nombre del archivo; con "/" se compara con la
+// ruta relativa, donde "**" equivale a cualquier cantidad de directorios. Los filtros
+// no distinguen mayúsculas y los archivos y carpetas ocultos se ignoran.
+type Scanner struct {
+	root     string
+	include  []string
+	exclude  []string
+	skipDirs []string
+}
+
+// NewScanner crea un scanner de root; sin include se usa DefaultInclude
+func NewScanner(root string, include, exclude []string) (*Scanner, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(absRoot); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("input directory not found: %s", root)
+	}
+	if len(include) == 0 {
+		include = DefaultInclude
+	}
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return &Scanner{root: absRoot, include: include, exclude: exclude}, nil
+}
+
+// Root retorna la carpeta de entrada absoluta
+func (s *Scanner) Root() string {
+	return s.root
+}
+
+// SkipDir excluye una carpeta del recorrido (p. ej. la de salida si está dentro de root)
+func (s *Scanner) SkipDir(dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		s.skipDirs = append(s.skipDirs, abs)
+	}
+}
+
+// List retorna los documentos que pasan los filtros, sin calcular su hash
+func (s *Scanner) List(ctx context.Context) ([]entities.IngestDocument, error) {
+	var docs []entities.IngestDocument
+	err := filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			slog.WarnContext(ctx, "no se pudo leer", "path", p, "error", err)
+			if d != nil && d.IsDir() && p != s.root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if p == s.root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") || s.skipped(p) || matchAny(s.exclude, rel) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if !matchAny(s.include, rel) || matchAny(s.exclude, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		docs = append(docs, entities.IngestDocument{
+			Path:    p,
+			RelPath: rel,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	return docs, err
+}
+
+// Scan lista los documentos y calcula su hash
+func (s *Scanner) Scan(ctx context.Context) ([]entities.IngestDocument, error) {
+	docs, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	HashDocuments(docs)
+	return docs, nil
+}
+
+// HashDocuments calcula el hash SHA256 de cada documento; si falla queda vacío
+func HashDocuments(docs []entities.IngestDocument) {
+	for i := range docs {
+		hash, err := utils.CalculateFileHash(docs[i].Path)
+		if err != nil {
+			slog.Warn("error calculando hash", "file", docs[i].RelPath, "error", err)
+			continue
+		}
+		docs[i].Hash = hash
+	}
+}
+
+func (s *Scanner) skipped(dir string) bool {
+	for _, skip := range s.skipDirs {
+		if dir == skip {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob compara una ruta relativa (con "/") con un patrón glob
+func matchGlob(pattern, rel string) bool {
+	pattern = strings.ToLower(pattern)
+	rel = strings.ToLower(rel)
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,180 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeTree crea los archivos indicados (rutas con "/") bajo una carpeta temporal
+func writeTree(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("%PDF "+file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func listRel(t *testing.T, scanner *Scanner) []string {
+	t.Helper()
+	docs, err := scanner.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	rels := []string{}
+	for _, doc := range docs {
+		rels = append(rels, doc.RelPath)
+	}
+	sort.Strings(rels)
+	return rels
+}
+
+func TestScannerFilters(t *testing.T) {
+	root := writeTree(t,
+		"alquiler.pdf",
+		"Servicios.PDF",
+		"notas.txt",
+		"clientes/acme/marco.pdf",
+		"clientes/acme/borradores/v1.pdf",
+		"clientes/beta/anexo.pdf",
+		"borradores/viejo.pdf",
+		".oculta/secreto.pdf",
+		"clientes/.tmp.pdf",
+	)
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{
+			name: "default include, case insensitive, hidden ignored",
+			want: []string{"Servicios.PDF", "alquiler.pdf", "borradores/viejo.pdf", "clientes/acme/borradores/v1.pdf", "clientes/acme/marco.pdf", "clientes/beta/anexo.pdf"},
+		},
+		{
+			name:    "pattern without slash matches the file name at any depth",
+			include: []string{"a*.pdf"},
+			want:    []string{"alquiler.pdf", "clientes/beta/anexo.pdf"},
+		},
+		{
+			name:    "** matches any number of directories, including none",
+			include: []string{"clientes/**/*.pdf"},
+			want:    []string{"clientes/acme/borradores/v1.pdf", "clientes/acme/marco.pdf", "clientes/beta/anexo.pdf"},
+		},
+		{
+			name:    "** at the start",
+			include: []string{"**/borradores/*.pdf"},
+			want:    []string{"borradores/viejo.pdf", "clientes/acme/borradores/v1.pdf"},
+		},
+		{
+			name:    "single * does not cross directories",
+			include: []string{"clientes/*/*.pdf"},
+			want:    []string{"clientes/acme/marco.pdf", "clientes/beta/anexo.pdf"},
+		},
+		{
+			name:    "exclude a directory anywhere with **",
+			exclude: []string{"**/borradores"},
+			want:    []string{"Servicios.PDF", "alquiler.pdf", "clientes/acme/marco.pdf", "clientes/beta/anexo.pdf"},
+		},
+		{
+			name:    "exclude by file name",
+			include: []string{"*.pdf", "*.txt"},
+			exclude: []string{"*.TXT", "viejo.pdf"},
+			want:    []string{"Servicios.PDF", "alquiler.pdf", "clientes/acme/borradores/v1.pdf", "clientes/acme/marco.pdf", "clientes/beta/anexo.pdf"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := NewScanner(root, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewScanner: %v", err)
+			}
+			if got := listRel(t, scanner); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"*.pdf", "a/b/c.pdf", true},
+		{"**/*.pdf", "c.pdf", true},
+		{"**/*.pdf", "a/b/c.pdf", true},
+		{"a/**", "a", true},
+		{"a/**", "a/b/c.pdf", true},
+		{"a/**/c.pdf", "a/c.pdf", true},
+		{"a/**/c.pdf", "a/x/y/c.pdf", true},
+		{"a/**/c.pdf", "b/x/c.pdf", false},
+		{"a/*.pdf", "a/b/c.pdf", false},
+		{"A/**/*.PDF", "a/b/c.pdf", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+// TestScannerSkipDir no recorre la carpeta de reportes cuando está dentro de la de entrada
+func TestScannerSkipDir(t *testing.T) {
+	root := writeTree(t, "contrato.pdf", "reportes/contrato.pdf", "reportes/sub/otro.pdf", "reportes-2024/viejo.pdf")
+	scanner, err := NewScanner(root, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner.SkipDir(filepath.Join(root, "reportes"))
+
+	want := []string{"contrato.pdf", "reportes-2024/viejo.pdf"}
+	if got := listRel(t, scanner); !reflect.DeepEqual(got, want) {
+		t.Errorf("List = %v, want %v", got, want)
+	}
+}
+
+func TestNewScannerErrors(t *testing.T) {
+	root := t.TempDir()
+	if _, err := NewScanner(filepath.Join(root, "no-existe"), nil, nil); err == nil {
+		t.Error("NewScanner accepted a missing directory")
+	}
+	file := filepath.Join(root, "archivo.pdf")
+	os.WriteFile(file, []byte("%PDF"), 0o644)
+	if _, err := NewScanner(file, nil, nil); err == nil {
+		t.Error("NewScanner accepted a file as root")
+	}
+	if _, err := NewScanner(root, []string{"[a-"}, nil); err == nil {
+		t.Error("NewScanner accepted an invalid include pattern")
+	}
+	if _, err := NewScanner(root, nil, []string{"docs/["}); err == nil {
+		t.Error("NewScanner accepted an invalid exclude pattern")
+	}
+}
+
+func TestScanHashesDocuments(t *testing.T) {
+	root := writeTree(t, "a.pdf", "copia/a.pdf")
+	// Mismo contenido en las dos rutas
+	os.WriteFile(filepath.Join(root, "copia", "a.pdf"), []byte("%PDF a.pdf"), 0o644)
+	scanner, _ := NewScanner(root, nil, nil)
+
+	docs, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || docs[0].Hash == "" || docs[0].Hash != docs[1].Hash {
+		t.Errorf("Scan = %+v, want two documents with the same hash", docs)
+	}
+}
@@ -0,0 +1,103 @@
+package ingest
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// fileState identifica una versión de un archivo por tamaño y fecha de modificación
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func stateOf(doc entities.IngestDocument) fileState {
+	return fileState{size: doc.Size, modTime: doc.ModTime}
+}
+
+func (s fileState) equal(other fileState) bool {
+	return s.size == other.size && s.modTime.Equal(other.modTime)
+}
+
+// Watcher revisa periódicamente la carpeta de entrada (polling, sin depender de inotify,
+// que no funciona en volúmenes de red ni en algunos montajes de Docker)
+type Watcher struct {
+	scanner  *Scanner
+	interval time.Duration
+	seen     map[string]fileState
+	last     map[string]fileState
+}
+
+// NewWatcher crea un watcher que revisa la carpeta del scanner cada interval
+func NewWatcher(scanner *Scanner, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = entities.DefaultIngestInterval
+	}
+	return &Watcher{
+		scanner:  scanner,
+		interval: interval,
+		seen:     make(map[string]fileState),
+		last:     make(map[string]fileState),
+	}
+}
+
+// Run llama a handle con los archivos nuevos o modificados, con su hash ya calculado,
+// hasta que se cancele ctx. Un archivo se entrega cuando no cambió desde la revisión
+// anterior (o su última modificación es más vieja que el intervalo), para no leer copias a medias.
+func (w *Watcher) Run(ctx context.Context, handle func(context.Context, []entities.IngestDocument)) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx, handle); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "error revisando el directorio", "dir", w.scanner.Root(), "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context, handle func(context.Context, []entities.IngestDocument)) error {
+	docs, err := w.scanner.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]fileState, len(docs))
+	var ready []entities.IngestDocument
+	for _, doc := range docs {
+		state := stateOf(doc)
+		current[doc.Path] = state
+		if seen, ok := w.seen[doc.Path]; ok && seen.equal(state) {
+			continue
+		}
+		last, ok := w.last[doc.Path]
+		if (ok && last.equal(state)) || time.Since(doc.ModTime) >= w.interval {
+			ready = append(ready, doc)
+		}
+	}
+	w.last = current
+
+	// Un archivo borrado y vuelto a copiar se procesa de nuevo
+	for path := range w.seen {
+		if _, ok := current[path]; !ok {
+			delete(w.seen, path)
+		}
+	}
+
+	if len(ready) == 0 {
+		return nil
+	}
+	HashDocuments(ready)
+	handle(ctx, ready)
+	for _, doc := range ready {
+		w.seen[doc.Path] = stateOf(doc)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// pollOnce ejecuta una revisión y retorna las rutas relativas entregadas
+func pollOnce(t *testing.T, w *Watcher) []string {
+	t.Helper()
+	var got []string
+	err := w.poll(context.Background(), func(_ context.Context, docs []entities.IngestDocument) {
+		for _, doc := range docs {
+			if doc.Hash == "" {
+				t.Errorf("%s was delivered without a hash", doc.RelPath)
+			}
+			got = append(got, doc.RelPath)
+		}
+	})
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	sort.Strings(got)
+	return got
+}
+
+func age(t *testing.T, path string, d time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-d)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherDeliversStableFilesOnce(t *testing.T) {
+	root := writeTree(t, "viejo.pdf")
+	age(t, filepath.Join(root, "viejo.pdf"), 2*time.Hour)
+	scanner, _ := NewScanner(root, nil, nil)
+	w := NewWatcher(scanner, time.Hour)
+
+	// Un archivo sin cambios desde hace más de un intervalo se entrega en la primera revisión
+	if got := pollOnce(t, w); len(got) != 1 || got[0] != "viejo.pdf" {
+		t.Fatalf("first poll = %v, want [viejo.pdf]", got)
+	}
+	if got := pollOnce(t, w); len(got) != 0 {
+		t.Errorf("second poll = %v, want nothing new", got)
+	}
+
+	// Uno recién copiado espera a una revisión en la que no haya cambiado
+	nuevo := filepath.Join(root, "nuevo.pdf")
+	os.WriteFile(nuevo, []byte("%PDF parcial"), 0o644)
+	if got := pollOnce(t, w); len(got) != 0 {
+		t.Errorf("poll right after the copy = %v, want nothing until it is stable", got)
+	}
+	if got := pollOnce(t, w); len(got) != 1 || got[0] != "nuevo.pdf" {
+		t.Errorf("poll once stable = %v, want [nuevo.pdf]", got)
+	}
+
+	// Una modificación lo vuelve a entregar cuando se estabiliza
+	os.WriteFile(nuevo, []byte("%PDF completo y más largo"), 0o644)
+	if got := pollOnce(t, w); len(got) != 0 {
+		t.Errorf("poll right after the change = %v, want nothing", got)
+	}
+	if got := pollOnce(t, w); len(got) != 1 || got[0] != "nuevo.pdf" {
+		t.Errorf("poll after the change = %v, want [nuevo.pdf] again", got)
+	}
+}
+
+// TestWatcherRedeliversRecopiedFiles entrega de nuevo un archivo borrado y vuelto a copiar
+func TestWatcherRedeliversRecopiedFiles(t *testing.T) {
+	root := writeTree(t, "contrato.pdf")
+	path := filepath.Join(root, "contrato.pdf")
+	age(t, path, 2*time.Hour)
+	scanner, _ := NewScanner(root, nil, nil)
+	w := NewWatcher(scanner, time.Hour)
+
+	pollOnce(t, w)
+	os.Remove(path)
+	if got := pollOnce(t, w); len(got) != 0 {
+		t.Fatalf("poll after removal = %v", got)
+	}
+	os.WriteFile(path, []byte("%PDF contrato.pdf"), 0o644)
+	age(t, path, 2*time.Hour)
+	if got := pollOnce(t, w); len(got) != 1 {
+		t.Errorf("poll after copying it back = %v, want [contrato.pdf]", got)
+	}
+}
+
+func TestWatcherRunStopsOnCancel(t *testing.T) {
+	root := writeTree(t, "a.pdf")
+	age(t, filepath.Join(root, "a.pdf"), time.Hour)
+	scanner, _ := NewScanner(root, nil, nil)
+	w := NewWatcher(scanner, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	delivered := make(chan []entities.IngestDocument, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(_ context.Context, docs []entities.IngestDocument) {
+			delivered <- docs
+		})
+	}()
+
+	select {
+	case docs := <-delivered:
+		if len(docs) != 1 || docs[0].RelPath != "a.pdf" {
+			t.Errorf("delivered %+v, want a.pdf", docs)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watcher did not deliver the existing file")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
@@ -1,40 +1,119 @@
 package llm
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/rodascaar/contractis/internal/domain/entities"
 	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
 )
 
 // Client implementa el cliente para interactuar con LLMs
 type Client struct {
 	// No usamos un httpClient fijo, lo creamos dinámicamente según el tipo de LLM
+
+	// scheduler limita las peticiones simultáneas por endpoint (opcional)
+	scheduler *Scheduler
+
+	// rateLimiter aplica los límites por minuto de cada API key
+	rateLimiter *RateLimiter
+
+	options ClientOptions
+}
+
+// ClientOptions son los tiempos y reintentos configurables del cliente. Los valores
+// en cero toman los de entities (HTTPTimeoutLocal, HTTPTimeoutOnline, MaxRetries).
+type ClientOptions struct {
+	LocalTimeout  time.Duration
+	OnlineTimeout time.Duration
+	MaxRetries    int
+
+	// Observer recibe cada petición de chat terminada (opcional)
+	Observer RequestObserver
+
+	// Tracer crea un span por petición de chat y propaga la traza con traceparent (opcional)
+	Tracer *tracing.Tracer
 }
 
+// RequestObserver recibe una petición de chat terminada: proveedor (host del endpoint),
+// modelo, duración sin la espera en la cola, tokens estimados por caracteres y el error
+type RequestObserver func(provider, model string, duration time.Duration, promptTokens, completionTokens int, err error)
+
 // NewClient crea una nueva instancia de Client
-func NewClient() *Client {
-	return &Client{}
+func NewClient(scheduler *Scheduler, options ClientOptions) *Client {
+	if options.LocalTimeout <= 0 {
+		options.LocalTimeout = entities.HTTPTimeoutLocal
+	}
+	if options.OnlineTimeout <= 0 {
+		options.OnlineTimeout = entities.HTTPTimeoutOnline
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = entities.MaxRetries
+	}
+	return &Client{
+		scheduler:   scheduler,
+		rateLimiter: NewRateLimiter(),
+		options:     options,
+	}
+}
+
+// acquireSlot espera turno en el scheduler; sin scheduler no limita. Si queueWait no es
+// nil se le suma el tiempo esperado en la cola.
+func (c *Client) acquireSlot(ctx context.Context, config *entities.LLMConfig, queueWait *time.Duration) (func(), error) {
+	if c.scheduler == nil {
+		return func() {}, nil
+	}
+	queued := time.Now()
+	release, err := c.scheduler.Acquire(ctx, config)
+	if queueWait != nil {
+		*queueWait += time.Since(queued)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cancelado mientras esperaba turno en la cola del LLM: %w", err)
+	}
+	return release, nil
+}
+
+// slotBody libera el slot del scheduler al cerrar el cuerpo de la respuesta, para que
+// el turno dure hasta terminar de leerla
+type slotBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *slotBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
 }
 
 // getHTTPClientWithDynamicTimeout retorna un cliente HTTP con timeout dinámico basado en tokens esperados
-func (c *Client) getHTTPClientWithDynamicTimeout(config *entities.LLMConfig, expectedTokens int) *http.Client {
+func (c *Client) getHTTPClientWithDynamicTimeout(ctx context.Context, config *entities.LLMConfig, expectedTokens int) *http.Client {
+	return &http.Client{Timeout: c.dynamicTimeout(ctx, config, expectedTokens)}
+}
+
+// dynamicTimeout calcula el tiempo máximo de respuesta según los tokens esperados
+func (c *Client) dynamicTimeout(ctx context.Context, config *entities.LLMConfig, expectedTokens int) time.Duration {
 	// Timeouts base
-	baseTimeout := entities.HTTPTimeoutLocal
+	baseTimeout := c.options.LocalTimeout
 	tokensPerSecond := 10.0 // Modelos locales suelen ser más lentos
 	marginMultiplier := 3.0 // Margen de seguridad conservador
 
 	if config.IsOnline() {
-		baseTimeout = entities.HTTPTimeoutOnline
+		baseTimeout = c.options.OnlineTimeout
 		tokensPerSecond = 15.0 // Modelos online: más conservador (antes 30.0)
 		marginMultiplier = 4.0 // Margen aún más conservador para online
 	}
@@ -48,13 +127,14 @@ func (c *Client) getHTTPClientWithDynamicTimeout(config *entities.LLMConfig, exp
 
 	// Usar el mayor entre el timeout base y el dinámico
 	if dynamicTimeout > baseTimeout {
-		log.Printf("⏱️  Timeout dinámico: %.0fs (esperando ~%d tokens @ %.0f tps, margen %.1fx)",
-			dynamicTimeout.Seconds(), expectedTokens, tokensPerSecond, marginMultiplier)
-		return &http.Client{Timeout: dynamicTimeout}
+		slog.DebugContext(ctx, "timeout dinámico",
+			"timeout", dynamicTimeout, "expected_tokens", expectedTokens,
+			"tokens_per_second", tokensPerSecond, "margin", marginMultiplier)
+		return dynamicTimeout
 	}
 
-	log.Printf("⏱️  Usando timeout base: %.0fs", baseTimeout.Seconds())
-	return &http.Client{Timeout: baseTimeout}
+	slog.DebugContext(ctx, "timeout base", "timeout", baseTimeout)
+	return baseTimeout
 }
 
 // ChatRequest representa una solicitud de chat para modelos locales
@@ -92,12 +172,6 @@ type LocalChatResponse struct {
 	Done    bool        `json:"done,omitempty"` // Para respuestas de streaming
 }
 
-// StreamingResponse representa una respuesta de streaming
-type StreamingResponse struct {
-	Message ChatMessage `json:"message"`
-	Done    bool        `json:"done"`
-}
-
 // SendChatRequest envía una solicitud de chat al LLM
 func (c *Client) SendChatRequest(
 	ctx context.Context,
@@ -105,7 +179,22 @@ func (c *Client) SendChatRequest(
 	messages []repositories.ChatMessage,
 	maxTokens int,
 ) (string, error) {
-	return c.SendChatRequestWithStreaming(ctx, config, messages, maxTokens, false)
+	return c.sendChatRequest(ctx, config, messages, maxTokens, nil)
+}
+
+// StreamChatRequest envía una solicitud de chat en modo streaming; onToken recibe cada
+// fragmento a medida que llega. El streaming evita cortes por inactividad en respuestas largas.
+func (c *Client) StreamChatRequest(
+	ctx context.Context,
+	config *entities.LLMConfig,
+	messages []repositories.ChatMessage,
+	maxTokens int,
+	onToken func(token string),
+) (string, error) {
+	if onToken == nil {
+		onToken = func(string) {}
+	}
+	return c.sendChatRequest(ctx, config, messages, maxTokens, onToken)
 }
 
 // SendChatRequestWithStreaming envía una solicitud de chat al LLM con opción de streaming
@@ -116,6 +205,22 @@ func (c *Client) SendChatRequestWithStreaming(
 	maxTokens int,
 	stream bool,
 ) (string, error) {
+	if stream {
+		return c.StreamChatRequest(ctx, config, messages, maxTokens, nil)
+	}
+	return c.sendChatRequest(ctx, config, messages, maxTokens, nil)
+}
+
+// sendChatRequest envía la petición; con onToken distinto de nil usa streaming
+func (c *Client) sendChatRequest(
+	ctx context.Context,
+	config *entities.LLMConfig,
+	messages []repositories.ChatMessage,
+	maxTokens int,
+	onToken func(token string),
+) (result string, err error) {
+	stream := onToken != nil
+
 	// Convertir mensajes al formato interno
 	chatMessages := make([]ChatMessage, len(messages))
 	for i, msg := range messages {
@@ -127,7 +232,7 @@ func (c *Client) SendChatRequestWithStreaming(
 
 	modelName := config.ModelName
 	if modelName == "" {
-		log.Printf("⚠️  Advertencia: No se especificó un modelo LLM. Se requiere configuración explícita desde el panel de configuración.")
+		slog.WarnContext(ctx, "no se especificó un modelo LLM; se requiere configuración explícita desde el panel de configuración")
 		return "", fmt.Errorf("modelo LLM no configurado: por favor configura un modelo específico desde el panel de configuración")
 	}
 
@@ -159,6 +264,14 @@ func (c *Client) SendChatRequestWithStreaming(
 		return "", fmt.Errorf("error al serializar request: %w", err)
 	}
 
+	ctx, span := c.options.Tracer.StartSpan(ctx, "chat "+modelName, tracing.KindClient)
+	defer span.End()
+	span.SetAttribute("gen_ai.operation.name", "chat")
+	span.SetAttribute("gen_ai.request.model", modelName)
+	span.SetAttribute("gen_ai.request.max_tokens", maxTokens)
+	span.SetAttribute("server.address", providerName(config))
+	span.SetAttribute("contractis.llm.stream", stream)
+
 	headers := map[string]string{
 		"Content-Type": "application/json",
 	}
@@ -166,20 +279,49 @@ func (c *Client) SendChatRequestWithStreaming(
 	if authHeader := config.GetAuthorizationHeader(); authHeader != "" {
 		headers["Authorization"] = authHeader
 	}
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		headers["traceparent"] = traceparent
+	}
+
+	// El slot del scheduler se toma en cada intento HTTP; queueWait acumula esas esperas
+	var queueWait time.Duration
+	start := time.Now()
+	promptTokens := 0
+	for _, msg := range chatMessages {
+		promptTokens += len(msg.Content) / entities.CharsPerToken
+	}
+	defer func() {
+		completionTokens := len(result) / entities.CharsPerToken
+		span.SetAttribute("contractis.llm.queue_wait_ms", queueWait.Milliseconds())
+		span.SetAttribute("gen_ai.usage.input_tokens", promptTokens)
+		span.SetAttribute("gen_ai.usage.output_tokens", completionTokens)
+		span.RecordError(err)
+		if c.options.Observer != nil {
+			c.options.Observer(providerName(config), modelName, time.Since(start)-queueWait, promptTokens, completionTokens, err)
+		}
+	}()
 
-	// Usar timeout dinámico basado en maxTokens esperados
-	httpClient := c.getHTTPClientWithDynamicTimeout(config, maxTokens)
-	streamMode := ""
-	if stream {
-		streamMode = " (streaming)"
+	slog.InfoContext(ctx, "enviando petición al LLM",
+		"endpoint", config.GetEndpointURL(), "model", modelName, paramName, maxTokens, "stream", stream)
+
+	estimatedTokens := maxTokens
+	for _, msg := range chatMessages {
+		estimatedTokens += len(msg.Content) / entities.CharsPerToken
 	}
-	log.Printf("🔗 Enviando petición a %s (modelo: %s, %s: %d)%s", config.GetEndpointURL(), modelName, paramName, maxTokens, streamMode)
 
 	if stream {
-		return c.handleStreamingResponse(ctx, httpClient, config.GetEndpointURL(), jsonData, headers)
+		firstTokenTimeout := c.dynamicTimeout(ctx, config, maxTokens)
+		content, err := c.handleStreamingResponse(ctx, config, jsonData, headers, estimatedTokens, firstTokenTimeout, onToken, &queueWait)
+		if err != nil {
+			return "", err
+		}
+		return c.finalizeContent(ctx, content)
 	}
 
-	resp, err := c.makeRequestWithRetryCustomClient(ctx, httpClient, config.GetEndpointURL(), jsonData, headers, entities.MaxRetries)
+	// Usar timeout dinámico basado en maxTokens esperados
+	httpClient := c.getHTTPClientWithDynamicTimeout(ctx, config, maxTokens)
+
+	resp, err := c.makeRequestWithRetryCustomClient(ctx, httpClient, config, jsonData, headers, c.options.MaxRetries, estimatedTokens, &queueWait)
 	if err != nil {
 		return "", err
 	}
@@ -203,50 +345,62 @@ func (c *Client) SendChatRequestWithStreaming(
 		parseErr = fmt.Errorf("error al parsear respuesta local: %v", err)
 	} else if parsed.Message.Content != "" {
 		content = parsed.Message.Content
-		log.Printf("✅ Respuesta local parseada correctamente")
+		slog.DebugContext(ctx, "respuesta local parseada correctamente")
 	} else {
 		parseErr = fmt.Errorf("respuesta local no contiene contenido válido")
 	}
 
 	// Si el parsing local falló, intentar formato online (OpenAI-style)
 	if parseErr != nil {
-		log.Printf("⚠️  %v - intentando formato online", parseErr)
+		slog.DebugContext(ctx, "respuesta local no válida, intentando formato online", "error", parseErr)
 
 		var parsed ChatResponse
 		if err := json.Unmarshal(body, &parsed); err == nil && len(parsed.Choices) > 0 {
 			content = parsed.Choices[0].Message.Content
-			log.Printf("✅ Fallback exitoso: respuesta parseada como online")
+			slog.DebugContext(ctx, "respuesta parseada como online")
 		} else {
 			return "", fmt.Errorf("error al parsear respuesta local y fallback falló: %v\nCuerpo: %s", parseErr, string(body))
 		}
 	}
 
+	return c.finalizeContent(ctx, content)
+}
+
+// providerName identifica al proveedor por el host de su endpoint
+func providerName(config *entities.LLMConfig) string {
+	if u, err := url.Parse(config.GetEndpointURL()); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return config.Type
+}
+
+// finalizeContent valida y post-procesa el contenido completo de una respuesta
+func (c *Client) finalizeContent(ctx context.Context, content string) (string, error) {
 	// Validación básica de la respuesta antes de procesar
 	if strings.TrimSpace(content) == "" {
-		log.Printf("⚠️  Respuesta del LLM está vacía")
+		slog.WarnContext(ctx, "la respuesta del LLM está vacía")
 		return "", fmt.Errorf("el LLM devolvió una respuesta vacía")
 	}
 
 	// Log raw content for debugging
-	log.Printf("🔍 Raw LLM response: %d caracteres", len(content))
-	log.Printf("🔍 Raw content preview: %s", content[:min(200, len(content))])
+	slog.DebugContext(ctx, "respuesta cruda del LLM", "chars", len(content), "preview", preview(content, 200))
 
 	// Procesamiento inteligente de contenido según el tipo de modelo
-	processedContent := c.processLLMResponse(content)
+	processedContent := c.processLLMResponse(ctx, content)
 
 	// Validación final del contenido procesado
 	if strings.TrimSpace(processedContent) == "" {
-		log.Printf("⚠️  Content is empty after processing, returning raw content")
+		slog.WarnContext(ctx, "la respuesta quedó vacía tras el procesamiento, se usa la respuesta cruda")
 		return content, nil // Devolver contenido original si el procesamiento lo dejó vacío
 	}
 
-	log.Printf("✅ Respuesta procesada exitosamente: %d caracteres", len(processedContent))
+	slog.DebugContext(ctx, "respuesta procesada", "chars", len(processedContent))
 	return processedContent, nil
 }
 
 // TestConnection verifica la conexión con el LLM
 func (c *Client) TestConnection(ctx context.Context, config *entities.LLMConfig) error {
-	log.Printf("🔗 Probando conexión con LLM (%s) - URL: %s", config.Type, config.GetEndpointURL())
+	slog.InfoContext(ctx, "probando conexión con el LLM", "type", config.Type, "endpoint", config.GetEndpointURL())
 
 	testClient := &http.Client{Timeout: entities.TestConnectionTimeout}
 
@@ -283,37 +437,64 @@ func (c *Client) TestConnection(ctx context.Context, config *entities.LLMConfig)
 		headers["Authorization"] = authHeader
 	}
 
-	resp, err := c.makeRequestWithRetryCustomClient(ctx, testClient, config.GetEndpointURL(), jsonData, headers, 1)
+	resp, err := c.makeRequestWithRetryCustomClient(ctx, testClient, config, jsonData, headers, 1, 10, nil)
 	if err != nil {
+		// Un límite transitorio confirma que el endpoint responde; una cuota agotada no
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) && !rateErr.QuotaExhausted {
+			slog.WarnContext(ctx, "conexión con el LLM limitada por el proveedor, se continúa", "error", rateErr)
+			return nil
+		}
 		return fmt.Errorf("no se pudo conectar al LLM: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 500 {
-		return fmt.Errorf("el servidor LLM no está disponible (status: %d)", resp.StatusCode)
+		return fmt.Errorf("el servidor LLM no está disponible (status: %d): %w", resp.StatusCode, entities.ErrLLMConnectionFailed)
 	}
 
-	log.Printf("✓ Conexión con LLM exitosa (tipo: %s, URL: %s)", config.Type, config.GetEndpointURL())
+	slog.InfoContext(ctx, "conexión con el LLM exitosa", "type", config.Type, "endpoint", config.GetEndpointURL())
 	return nil
 }
 
+// makeRequestWithRetryCustomClient envía la petición respetando los límites de la API key.
+// Reintenta errores de red, 5xx y 429 con backoff exponencial con jitter; si el proveedor
+// envía Retry-After o x-ratelimit-reset-* se respeta esa pausa. Una cuota agotada no se
+// reintenta y se retorna como *RateLimitError.
+//
+// El slot del scheduler se toma solo durante cada intento, después de la espera del rate
+// limiter y del backoff; en la respuesta exitosa se libera al cerrar el body. queueWait
+// acumula la espera en la cola (opcional).
 func (c *Client) makeRequestWithRetryCustomClient(
 	ctx context.Context,
 	client *http.Client,
-	url string,
+	config *entities.LLMConfig,
 	jsonData []byte,
 	headers map[string]string,
 	maxRetries int,
+	estimatedTokens int,
+	queueWait *time.Duration,
 ) (*http.Response, error) {
 	var lastErr error
+	var wait time.Duration
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
-			log.Printf("Reintentando petición (intento %d/%d)...", attempt+1, maxRetries)
-			time.Sleep(entities.RetryDelay * time.Duration(attempt))
+			if wait <= 0 {
+				wait = backoffDelay(attempt)
+			}
+			slog.InfoContext(ctx, "reintentando petición", "attempt", attempt+1, "max_attempts", maxRetries, "wait", wait.Round(time.Millisecond))
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			wait = 0
+		}
+
+		if err := c.rateLimiter.Wait(ctx, config, estimatedTokens); err != nil {
+			return nil, fmt.Errorf("cancelado mientras esperaba cupo de la API: %w", err)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, "POST", config.GetEndpointURL(), bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, fmt.Errorf("error al crear request: %w", err)
 		}
@@ -322,16 +503,41 @@ func (c *Client) makeRequestWithRetryCustomClient(
 			req.Header.Set(key, value)
 		}
 
+		release, err := c.acquireSlot(ctx, config, queueWait)
+		if err != nil {
+			return nil, err
+		}
+
 		resp, err := client.Do(req)
 		if err == nil {
+			c.rateLimiter.Observe(config, resp.Header)
+
+			if resp.StatusCode == http.StatusTooManyRequests ||
+				resp.StatusCode == http.StatusServiceUnavailable ||
+				resp.StatusCode == http.StatusPaymentRequired {
+				rateErr := c.rateLimitError(resp)
+				release()
+				if rateErr.QuotaExhausted {
+					return nil, rateErr
+				}
+				c.rateLimiter.Block(config, rateErr.RetryAfter)
+				slog.WarnContext(ctx, "petición limitada por el proveedor", "error", rateErr)
+				lastErr = rateErr
+				wait = rateErr.RetryAfter
+				continue
+			}
+
 			if resp.StatusCode < 500 {
+				resp.Body = &slotBody{ReadCloser: resp.Body, release: release}
 				return resp, nil
 			}
 			resp.Body.Close()
+			release()
 			lastErr = fmt.Errorf("server error: status %d", resp.StatusCode)
 			continue
 		}
 
+		release()
 		lastErr = err
 
 		// Check for timeout errors
@@ -349,70 +555,110 @@ func (c *Client) makeRequestWithRetryCustomClient(
 		if ctx.Err() == context.DeadlineExceeded ||
 			strings.Contains(errMsg, "timeout") ||
 			strings.Contains(errMsg, "deadline exceeded") {
-			return nil, fmt.Errorf("timeout después de %d intentos: el servidor no respondió. Verifica la configuración del LLM", maxRetries)
+			return nil, fmt.Errorf("timeout después de %d intentos: el servidor no respondió. Verifica la configuración del LLM (%w)", maxRetries, entities.ErrLLMTimeout)
 		}
 	}
 
-	return nil, fmt.Errorf("falló después de %d intentos: %w", maxRetries, lastErr)
+	var rateErr *RateLimitError
+	if errors.As(lastErr, &rateErr) {
+		return nil, fmt.Errorf("falló después de %d intentos: %w", maxRetries, lastErr)
+	}
+	return nil, fmt.Errorf("falló después de %d intentos: %w (%w)", maxRetries, lastErr, entities.ErrLLMConnectionFailed)
+}
+
+// rateLimitError construye el error a partir de una respuesta 429/503/402 y cierra el body
+func (c *Client) rateLimitError(resp *http.Response) *RateLimitError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if retryAfter <= 0 {
+		retryAfter = max(
+			parseResetHeader(resp.Header.Get("x-ratelimit-reset-requests")),
+			parseResetHeader(resp.Header.Get("x-ratelimit-reset-tokens")),
+		)
+	}
+	if retryAfter > entities.MaxRetryDelay {
+		retryAfter = entities.MaxRetryDelay
+	}
+
+	return &RateLimitError{
+		StatusCode:     resp.StatusCode,
+		RetryAfter:     retryAfter,
+		QuotaExhausted: isQuotaExhausted(resp.StatusCode, string(body)),
+		Body:           strings.TrimSpace(string(body)),
+	}
 }
 
-// handleStreamingResponse maneja respuestas de streaming del LLM
+// handleStreamingResponse maneja respuestas de streaming del LLM (SSE estilo OpenAI o
+// NDJSON nativo de Ollama). La petición pasa por el mismo ciclo de reintentos que el modo
+// sin streaming: errores de red, 5xx y 429 se reintentan con backoff. Recibida la
+// respuesta, el primer token debe llegar antes de firstTokenTimeout y luego no pueden
+// pasar más de StreamIdleTimeout entre fragmentos.
 func (c *Client) handleStreamingResponse(
 	ctx context.Context,
-	client *http.Client,
-	url string,
+	config *entities.LLMConfig,
 	jsonData []byte,
 	headers map[string]string,
+	estimatedTokens int,
+	firstTokenTimeout time.Duration,
+	onToken func(token string),
+	queueWait *time.Duration,
 ) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error al crear streaming request: %w", err)
-	}
-
+	streamHeaders := make(map[string]string, len(headers)+1)
 	for key, value := range headers {
-		req.Header.Set(key, value)
+		streamHeaders[key] = value
 	}
+	streamHeaders["Accept"] = "text/event-stream, application/x-ndjson"
+
+	// El timeout dinámico acota cada intento y el stream completo; el watchdog corta antes
+	// los streams inactivos
+	httpClient := c.getHTTPClientWithDynamicTimeout(ctx, config, estimatedTokens)
 
-	resp, err := client.Do(req)
+	resp, err := c.makeRequestWithRetryCustomClient(ctx, httpClient, config, jsonData, streamHeaders, c.options.MaxRetries, estimatedTokens, queueWait)
 	if err != nil {
-		return "", fmt.Errorf("error en streaming request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 		return "", fmt.Errorf("error del servidor streaming (%d): %s", resp.StatusCode, string(body))
 	}
 
-	var fullContent strings.Builder
-	scanner := bufio.NewScanner(resp.Body)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
-			if data == "[DONE]" {
-				break
-			}
+	// Cerrar el body desbloquea la lectura cuando el stream queda inactivo
+	var idleExpired atomic.Bool
+	watchdog := time.AfterFunc(firstTokenTimeout, func() {
+		idleExpired.Store(true)
+		resp.Body.Close()
+	})
+	defer watchdog.Stop()
 
-			var streamResp StreamingResponse
-			if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
-				log.Printf("⚠️  Error parseando línea de streaming: %v", err)
-				continue
-			}
-
-			if streamResp.Message.Content != "" {
-				fullContent.WriteString(streamResp.Message.Content)
-			}
+	decoder, body := newStreamDecoder(resp.Header.Get("Content-Type"), resp.Body)
 
-			if streamResp.Done {
-				break
+	var fullContent strings.Builder
+	err = decoder.Decode(body, func(token string) {
+		watchdog.Reset(entities.StreamIdleTimeout)
+		fullContent.WriteString(token)
+		onToken(token)
+	})
+	if err != nil {
+		if idleExpired.Load() {
+			if fullContent.Len() == 0 {
+				return "", fmt.Errorf("timeout esperando el primer token (%v): %w", firstTokenTimeout, entities.ErrLLMTimeout)
 			}
+			return "", fmt.Errorf("stream inactivo por más de %v (%d caracteres recibidos): %w",
+				entities.StreamIdleTimeout, fullContent.Len(), entities.ErrLLMTimeout)
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error leyendo streaming response: %w", err)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return "", fmt.Errorf("timeout leyendo streaming response (%d caracteres recibidos): %w",
+				fullContent.Len(), entities.ErrLLMTimeout)
+		}
+		return "", fmt.Errorf("error leyendo streaming response: %w (%w)", err, entities.ErrLLMConnectionFailed)
 	}
 
 	content := fullContent.String()
@@ -420,34 +666,34 @@ func (c *Client) handleStreamingResponse(
 		return "", fmt.Errorf("no se recibió contenido en streaming")
 	}
 
-	log.Printf("✅ Streaming completado: %d caracteres", len(content))
+	slog.DebugContext(ctx, "streaming completado", "chars", len(content))
 	return content, nil
 }
 
 // processLLMResponse procesa la respuesta del LLM según el tipo de modelo
-func (c *Client) processLLMResponse(content string) string {
+func (c *Client) processLLMResponse(ctx context.Context, content string) string {
 	originalContent := content
 
 	// 1. Modelos razonadores (como DeepSeek-R1) - extraer respuesta final después del thinking
 	if strings.Contains(content, "</think>") {
-		log.Printf("🔍 Detected reasoning model (thinking tags), extracting final answer")
+		slog.DebugContext(ctx, "modelo razonador detectado, se extrae la respuesta final")
 		parts := strings.Split(content, "</think>")
 		if len(parts) > 1 {
 			content = strings.TrimSpace(parts[1])
-			log.Printf("🔍 Extracted content after </think>: %d caracteres", len(content))
+			slog.DebugContext(ctx, "contenido extraído tras </think>", "chars", len(content))
 		} else {
-			log.Printf("⚠️  </think> tag found but no content after it")
+			slog.WarnContext(ctx, "etiqueta </think> sin contenido posterior")
 			// Si no hay contenido después del thinking, intentar extraer del thinking mismo
 			if strings.Contains(content, "<think>") {
 				thinkStart := strings.Index(content, "<think>")
 				thinkEnd := strings.Index(content, "</think>")
 				if thinkStart >= 0 && thinkEnd > thinkStart {
 					thinkingContent := content[thinkStart+7 : thinkEnd]
-					log.Printf("🔍 Thinking content length: %d caracteres", len(thinkingContent))
+					slog.DebugContext(ctx, "contenido de razonamiento", "chars", len(thinkingContent))
 					// Si el thinking es muy largo, probablemente es la respuesta
 					if len(thinkingContent) > 100 {
 						content = strings.TrimSpace(thinkingContent)
-						log.Printf("🔍 Using thinking content as response: %d caracteres", len(content))
+						slog.DebugContext(ctx, "se usa el razonamiento como respuesta", "chars", len(content))
 					}
 				}
 			}
@@ -456,30 +702,30 @@ func (c *Client) processLLMResponse(content string) string {
 
 	// 2. Modelos que podrían devolver JSON - extraer texto plano si es necesario
 	if strings.HasPrefix(strings.TrimSpace(content), "{") && strings.HasSuffix(strings.TrimSpace(content), "}") {
-		log.Printf("🔍 Detected potential JSON response, checking if it contains text content")
+		slog.DebugContext(ctx, "posible respuesta JSON, se busca contenido de texto")
 		// Intentar parsear como JSON y extraer campos de texto comunes
 		var jsonResponse map[string]interface{}
 		if err := json.Unmarshal([]byte(content), &jsonResponse); err == nil {
 			// Buscar campos comunes que podrían contener la respuesta
 			if text, ok := jsonResponse["text"].(string); ok && text != "" {
-				log.Printf("🔍 Extracted text from JSON response")
+				slog.DebugContext(ctx, "texto extraído de la respuesta JSON", "field", "text")
 				content = text
 			} else if response, ok := jsonResponse["response"].(string); ok && response != "" {
-				log.Printf("🔍 Extracted response from JSON response")
+				slog.DebugContext(ctx, "texto extraído de la respuesta JSON", "field", "response")
 				content = response
 			} else if contentField, ok := jsonResponse["content"].(string); ok && contentField != "" {
-				log.Printf("🔍 Extracted content from JSON response")
+				slog.DebugContext(ctx, "texto extraído de la respuesta JSON", "field", "content")
 				content = contentField
 			}
 		}
 	}
 
 	// 3. Limpieza general de formato markdown no deseado
-	content = c.cleanMarkdownFormatting(content)
+	content = c.cleanMarkdownFormatting(ctx, content)
 
 	// 4. Si el contenido quedó vacío pero el original tenía algo, devolver el original
 	if strings.TrimSpace(content) == "" && strings.TrimSpace(originalContent) != "" {
-		log.Printf("⚠️  Content became empty after processing, returning original content")
+		slog.WarnContext(ctx, "el contenido quedó vacío tras el procesamiento, se devuelve el original")
 		return originalContent
 	}
 
@@ -487,7 +733,7 @@ func (c *Client) processLLMResponse(content string) string {
 }
 
 // cleanMarkdownFormatting limpia formato markdown no deseado
-func (c *Client) cleanMarkdownFormatting(content string) string {
+func (c *Client) cleanMarkdownFormatting(ctx context.Context, content string) string {
 	// Remover encabezados markdown que no aportan valor
 	content = strings.ReplaceAll(content, "# ", "")
 	content = strings.ReplaceAll(content, "## ", "")
@@ -496,7 +742,7 @@ func (c *Client) cleanMarkdownFormatting(content string) string {
 	// Remover negritas y cursivas excesivas si dominan el texto
 	asterisks := strings.Count(content, "*")
 	if asterisks > len(content)/10 { // Si más del 10% son asteriscos
-		log.Printf("🔍 Removing excessive markdown formatting")
+		slog.DebugContext(ctx, "se elimina formato markdown excesivo")
 		content = strings.ReplaceAll(content, "**", "")
 		content = strings.ReplaceAll(content, "*", "")
 	}
@@ -504,10 +750,14 @@ func (c *Client) cleanMarkdownFormatting(content string) string {
 	return strings.TrimSpace(content)
 }
 
-// Helper function for min operation
-func min(a, b int) int {
-	if a < b {
-		return a
+// preview retorna los primeros maxBytes bytes de content sin cortar un carácter UTF-8
+func preview(content string, maxBytes int) string {
+	if len(content) <= maxBytes {
+		return content
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(content[end]) {
+		end--
 	}
-	return b
+	return content[:end]
 }
@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+var testMessages = []repositories.ChatMessage{{Role: "user", Content: "hola"}}
+
+func TestClientBackoffDoesNotHoldSchedulerSlot(t *testing.T) {
+	var hits atomic.Int32
+	limited := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			close(limited)
+			return
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"ok"}}`))
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(1, 1)
+	client := NewClient(scheduler, ClientOptions{MaxRetries: 2})
+
+	// Dos API keys distintas sobre el mismo endpoint: comparten el slot pero no el rate limiter
+	limitedConfig := entities.NewLLMConfig("local", server.URL, "", "key-a", "stub", 100)
+	otherConfig := entities.NewLLMConfig("local", server.URL, "", "key-b", "stub", 100)
+
+	limitedDone := make(chan error, 1)
+	go func() {
+		_, err := client.SendChatRequest(context.Background(), limitedConfig, testMessages, 100)
+		limitedDone <- err
+	}()
+	<-limited
+
+	// Mientras la primera petición espera el Retry-After, el slot queda libre
+	start := time.Now()
+	if _, err := client.SendChatRequest(context.Background(), otherConfig, testMessages, 100); err != nil {
+		t.Fatalf("SendChatRequest: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("second request took %v, the backoff held the scheduler slot", elapsed)
+	}
+	select {
+	case err := <-limitedDone:
+		t.Fatalf("limited request finished before its Retry-After: %v", err)
+	default:
+	}
+
+	if err := <-limitedDone; err != nil {
+		t.Fatalf("limited request: %v", err)
+	}
+	if status := scheduler.QueueStatus("")[0]; status.Active != 0 || status.Waiting != 0 {
+		t.Errorf("final status = %+v, want the slot released", status)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("server hits = %d, want 3", hits.Load())
+	}
+}
+
+func TestStreamRetriesServerErrors(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"ho", "la"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(NewScheduler(1, 1), ClientOptions{MaxRetries: 2})
+	config := entities.NewLLMConfig("local", server.URL, "", "", "stub", 100)
+
+	var tokens []string
+	content, err := client.StreamChatRequest(context.Background(), config, testMessages, 100, func(token string) {
+		tokens = append(tokens, token)
+	})
+	if err != nil {
+		t.Fatalf("StreamChatRequest: %v", err)
+	}
+	if content != "hola" || len(tokens) != 2 {
+		t.Errorf("content = %q, tokens = %q", content, tokens)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server hits = %d, want the 502 retried once", hits.Load())
+	}
+}
+
+func TestStreamServerErrorAllowsFailover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(nil, ClientOptions{MaxRetries: 1})
+	config := entities.NewLLMConfig("local", server.URL, "", "", "stub", 100)
+
+	_, err := client.StreamChatRequest(context.Background(), config, testMessages, 100, nil)
+	if !errors.Is(err, entities.ErrLLMConnectionFailed) {
+		t.Fatalf("err = %v, want ErrLLMConnectionFailed", err)
+	}
+	if !config.ShouldFailover(err) {
+		t.Error("a 5xx while streaming should enable failover")
+	}
+}
+
+func TestPreviewKeepsRunesWhole(t *testing.T) {
+	tests := []struct {
+		content string
+		max     int
+		want    string
+	}{
+		{"corto", 200, "corto"},
+		{"cláusula", 3, "cl"}, // "á" ocupa los bytes 2 y 3
+		{"cláusula", 4, "clá"},
+		{"ñandú", 1, ""},
+		{"plazo 🕒 vencido", 8, "plazo "},
+		{"plazo 🕒 vencido", 10, "plazo 🕒"},
+	}
+	for _, tt := range tests {
+		got := preview(tt.content, tt.max)
+		if got != tt.want || !utf8.ValidString(got) || len(got) > tt.max {
+			t.Errorf("preview(%q, %d) = %q, want %q", tt.content, tt.max, got, tt.want)
+		}
+	}
+}
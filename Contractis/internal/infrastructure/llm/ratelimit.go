@@ -0,0 +1,290 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// RateLimitError representa una respuesta 429/503 del proveedor o una cuota agotada
+type RateLimitError struct {
+	StatusCode     int
+	RetryAfter     time.Duration
+	QuotaExhausted bool
+	Body           string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.QuotaExhausted {
+		return fmt.Sprintf("cuota del proveedor LLM agotada (status %d): %s", e.StatusCode, e.Body)
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("límite de peticiones del proveedor LLM alcanzado (status %d, reintentar en %v)", e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("límite de peticiones del proveedor LLM alcanzado (status %d)", e.StatusCode)
+}
+
+// Unwrap permite comparar con errors.Is contra los errores de dominio
+func (e *RateLimitError) Unwrap() error {
+	if e.QuotaExhausted {
+		return entities.ErrQuotaExceeded
+	}
+	return entities.ErrRateLimited
+}
+
+// tokenBucket es un token bucket clásico con recarga continua
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	perSec   float64
+	last     time.Time
+}
+
+func newTokenBucket(perMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		perSec:   float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+// resize ajusta la capacidad si la configuración cambió entre peticiones
+func (b *tokenBucket) resize(perMinute int) {
+	capacity := float64(perMinute)
+	if capacity == b.capacity {
+		return
+	}
+	b.capacity = capacity
+	b.perSec = capacity / 60
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+}
+
+// delay retorna cuánto falta para disponer de n unidades (n se acota a la capacidad)
+func (b *tokenBucket) delay(n float64) time.Duration {
+	n = math.Min(n, b.capacity)
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.perSec * float64(time.Second))
+}
+
+func (b *tokenBucket) take(n float64) {
+	b.tokens -= math.Min(n, b.capacity)
+}
+
+// keyLimiter agrupa los límites de una API key
+type keyLimiter struct {
+	requests     *tokenBucket
+	tokens       *tokenBucket
+	blockedUntil time.Time
+}
+
+// RateLimiter aplica límites de peticiones y tokens por minuto por API key, y
+// respeta las pausas que indica el proveedor (Retry-After, x-ratelimit-*)
+type RateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*keyLimiter
+}
+
+// NewRateLimiter crea una nueva instancia de RateLimiter
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		limiters: make(map[string]*keyLimiter),
+	}
+}
+
+// Wait bloquea hasta que la API key de la configuración tenga cupo para una
+// petición de estimatedTokens tokens, o hasta que el contexto se cancele
+func (l *RateLimiter) Wait(ctx context.Context, config *entities.LLMConfig, estimatedTokens int) error {
+	for {
+		l.mu.Lock()
+		kl := l.limiterFor(config)
+		now := time.Now()
+
+		var wait time.Duration
+		if now.Before(kl.blockedUntil) {
+			wait = kl.blockedUntil.Sub(now)
+		}
+		if kl.requests != nil {
+			kl.requests.refill(now)
+			wait = max(wait, kl.requests.delay(1))
+		}
+		if kl.tokens != nil {
+			kl.tokens.refill(now)
+			wait = max(wait, kl.tokens.delay(float64(estimatedTokens)))
+		}
+
+		if wait == 0 {
+			if kl.requests != nil {
+				kl.requests.take(1)
+			}
+			if kl.tokens != nil {
+				kl.tokens.take(float64(estimatedTokens))
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Block pausa todas las peticiones de la API key durante d
+func (l *RateLimiter) Block(config *entities.LLMConfig, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	kl := l.limiterFor(config)
+	if until := time.Now().Add(d); until.After(kl.blockedUntil) {
+		kl.blockedUntil = until
+	}
+}
+
+// Observe lee los headers x-ratelimit-* de una respuesta y, si el proveedor indica
+// que no quedan peticiones o tokens, pausa la API key hasta el reset informado
+func (l *RateLimiter) Observe(config *entities.LLMConfig, header http.Header) {
+	for _, kind := range []string{"requests", "tokens"} {
+		remaining := header.Get("x-ratelimit-remaining-" + kind)
+		if remaining == "" {
+			continue
+		}
+		if n, err := strconv.ParseFloat(remaining, 64); err != nil || n > 0 {
+			continue
+		}
+		if reset := parseResetHeader(header.Get("x-ratelimit-reset-" + kind)); reset > 0 {
+			l.Block(config, reset)
+		}
+	}
+}
+
+func (l *RateLimiter) limiterFor(config *entities.LLMConfig) *keyLimiter {
+	key := rateLimitKey(config)
+	kl, ok := l.limiters[key]
+	if !ok {
+		kl = &keyLimiter{}
+		l.limiters[key] = kl
+	}
+
+	if config.RequestsPerMinute > 0 {
+		if kl.requests == nil {
+			kl.requests = newTokenBucket(config.RequestsPerMinute)
+		}
+		kl.requests.resize(config.RequestsPerMinute)
+	} else {
+		kl.requests = nil
+	}
+
+	if config.TokensPerMinute > 0 {
+		if kl.tokens == nil {
+			kl.tokens = newTokenBucket(config.TokensPerMinute)
+		}
+		kl.tokens.resize(config.TokensPerMinute)
+	} else {
+		kl.tokens = nil
+	}
+
+	return kl
+}
+
+// rateLimitKey identifica la API key sin guardarla en memoria en claro; sin key se usa el endpoint
+func rateLimitKey(config *entities.LLMConfig) string {
+	if config.ApiKey == "" {
+		return "endpoint:" + config.GetEndpointURL()
+	}
+	sum := sha256.Sum256([]byte(config.ApiKey))
+	return fmt.Sprintf("key:%x", sum[:8])
+}
+
+// backoffDelay calcula un backoff exponencial con jitter ("equal jitter") para el intento dado (1-based)
+func backoffDelay(attempt int) time.Duration {
+	d := entities.RetryDelay << (attempt - 1)
+	if d <= 0 || d > entities.MaxRetryDelay {
+		d = entities.MaxRetryDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter interpreta el header Retry-After (segundos o fecha HTTP)
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// parseResetHeader interpreta x-ratelimit-reset-*: duración estilo OpenAI ("6m0s", "20ms"),
+// segundos, o un instante RFC 3339
+func parseResetHeader(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// isQuotaExhausted distingue una cuota agotada (no tiene sentido reintentar) de un límite transitorio
+func isQuotaExhausted(statusCode int, body string) bool {
+	if statusCode == http.StatusPaymentRequired {
+		return true
+	}
+	if statusCode != http.StatusTooManyRequests {
+		return false
+	}
+	lower := strings.ToLower(body)
+	return strings.Contains(lower, "insufficient_quota") ||
+		strings.Contains(lower, "quota exceeded") ||
+		strings.Contains(lower, "exceeded your current quota") ||
+		strings.Contains(lower, "billing")
+}
+
+// sleepContext espera d o hasta que el contexto se cancele
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,219 @@
+package llm
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Scheduler limita las peticiones simultáneas a cada endpoint LLM y reparte los
+// turnos en round-robin entre solicitantes, para que un análisis grande no acapare
+// el modelo mientras otros usuarios esperan.
+type Scheduler struct {
+	mu          sync.Mutex
+	endpoints   map[string]*endpointQueue
+	localSlots  int
+	onlineSlots int
+}
+
+// endpointQueue mantiene el semáforo y las colas por solicitante de un endpoint
+type endpointQueue struct {
+	limit  int
+	active int
+	queues map[string][]*waiter
+	order  []string // solicitantes con turnos pendientes, en orden de llegada
+	next   int      // índice en order del próximo solicitante a servir
+}
+
+type waiter struct {
+	requester string
+	ready     chan struct{}
+	granted   bool
+}
+
+// NewScheduler crea un scheduler con la cantidad de slots por endpoint local y online
+func NewScheduler(localSlots, onlineSlots int) *Scheduler {
+	if localSlots <= 0 {
+		localSlots = entities.LocalEndpointSlots
+	}
+	if onlineSlots <= 0 {
+		onlineSlots = entities.OnlineEndpointSlots
+	}
+	return &Scheduler{
+		endpoints:   make(map[string]*endpointQueue),
+		localSlots:  localSlots,
+		onlineSlots: onlineSlots,
+	}
+}
+
+// Acquire espera un slot libre en el endpoint de la configuración. El solicitante se
+// obtiene del contexto (entities.RequesterFromContext). La función retornada libera
+// el slot y es segura de llamar más de una vez.
+func (s *Scheduler) Acquire(ctx context.Context, config *entities.LLMConfig) (func(), error) {
+	endpoint := config.GetEndpointURL()
+	requester := entities.RequesterFromContext(ctx)
+
+	s.mu.Lock()
+	eq := s.endpointFor(endpoint, config.IsOnline())
+
+	if eq.active < eq.limit && len(eq.order) == 0 {
+		eq.active++
+		s.mu.Unlock()
+		return s.releaser(eq), nil
+	}
+
+	w := &waiter{requester: requester, ready: make(chan struct{})}
+	if _, ok := eq.queues[requester]; !ok {
+		eq.order = append(eq.order, requester)
+	}
+	eq.queues[requester] = append(eq.queues[requester], w)
+	position := eq.position(requester)
+	s.mu.Unlock()
+
+	slog.InfoContext(ctx, "petición encolada", "requester", requester, "endpoint", endpoint, "position", position)
+
+	select {
+	case <-w.ready:
+		return s.releaser(eq), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		if w.granted {
+			// El slot se asignó justo al cancelar: devolverlo
+			eq.active--
+			eq.dispatch()
+		} else {
+			eq.remove(w)
+		}
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// QueueStatus retorna el estado de cada endpoint conocido desde la perspectiva del solicitante
+func (s *Scheduler) QueueStatus(requester string) []entities.QueueStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]entities.QueueStatus, 0, len(s.endpoints))
+	for endpoint, eq := range s.endpoints {
+		statuses = append(statuses, entities.QueueStatus{
+			Endpoint: endpoint,
+			Limit:    eq.limit,
+			Active:   eq.active,
+			Waiting:  eq.waiting(),
+			Position: eq.position(requester),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Endpoint < statuses[j].Endpoint
+	})
+	return statuses
+}
+
+func (s *Scheduler) endpointFor(endpoint string, online bool) *endpointQueue {
+	eq, ok := s.endpoints[endpoint]
+	if !ok {
+		limit := s.localSlots
+		if online {
+			limit = s.onlineSlots
+		}
+		eq = &endpointQueue{
+			limit:  limit,
+			queues: make(map[string][]*waiter),
+		}
+		s.endpoints[endpoint] = eq
+	}
+	return eq
+}
+
+func (s *Scheduler) releaser(eq *endpointQueue) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			eq.active--
+			eq.dispatch()
+			s.mu.Unlock()
+		})
+	}
+}
+
+// dispatch asigna los slots libres rotando entre solicitantes
+func (eq *endpointQueue) dispatch() {
+	for eq.active < eq.limit && len(eq.order) > 0 {
+		if eq.next >= len(eq.order) {
+			eq.next = 0
+		}
+
+		requester := eq.order[eq.next]
+		queue := eq.queues[requester]
+		w := queue[0]
+
+		if len(queue) == 1 {
+			delete(eq.queues, requester)
+			eq.order = append(eq.order[:eq.next], eq.order[eq.next+1:]...)
+		} else {
+			eq.queues[requester] = queue[1:]
+			eq.next++
+		}
+
+		w.granted = true
+		eq.active++
+		close(w.ready)
+	}
+}
+
+// remove quita un turno cancelado de la cola de su solicitante
+func (eq *endpointQueue) remove(w *waiter) {
+	queue := eq.queues[w.requester]
+	for i, queued := range queue {
+		if queued == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+
+	if len(queue) > 0 {
+		eq.queues[w.requester] = queue
+		return
+	}
+
+	delete(eq.queues, w.requester)
+	for i, requester := range eq.order {
+		if requester == w.requester {
+			eq.order = append(eq.order[:i], eq.order[i+1:]...)
+			if i < eq.next {
+				eq.next--
+			}
+			break
+		}
+	}
+}
+
+func (eq *endpointQueue) waiting() int {
+	total := 0
+	for _, queue := range eq.queues {
+		total += len(queue)
+	}
+	return total
+}
+
+// position retorna la posición (1-based) del próximo turno del solicitante según el
+// round-robin, o cero si no tiene turnos en espera. Cada solicitante recibe un turno
+// por vuelta, así que solo importa su distancia al cursor.
+func (eq *endpointQueue) position(requester string) int {
+	if _, ok := eq.queues[requester]; !ok {
+		return 0
+	}
+
+	for i := range eq.order {
+		if eq.order[(eq.next+i)%len(eq.order)] == requester {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+var localConfig = entities.NewLLMConfig("local", "http://llm.test", "", "", "stub", 800)
+
+type grant struct {
+	requester string
+	release   func()
+}
+
+// enqueue pide un slot en nombre del solicitante y espera a que quede en la cola, para
+// que el orden de llegada sea determinista
+func enqueue(t *testing.T, s *Scheduler, requester string, grants chan<- grant) {
+	t.Helper()
+	before := waiting(s)
+	go func() {
+		release, err := s.Acquire(entities.WithRequester(context.Background(), requester), localConfig)
+		if err != nil {
+			t.Errorf("Acquire(%s): %v", requester, err)
+			return
+		}
+		grants <- grant{requester, release}
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for waiting(s) == before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s was not queued", requester)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func waiting(s *Scheduler) int {
+	total := 0
+	for _, status := range s.QueueStatus("") {
+		total += status.Waiting
+	}
+	return total
+}
+
+func TestSchedulerRoundRobinBetweenRequesters(t *testing.T) {
+	s := NewScheduler(1, 1)
+	hold, err := s.Acquire(context.Background(), localConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grants := make(chan grant)
+	// Un análisis grande encola cinco chunks antes de que llegue otro usuario con dos
+	for range 5 {
+		enqueue(t, s, "user:1", grants)
+	}
+	for range 2 {
+		enqueue(t, s, "user:2", grants)
+	}
+
+	if position := s.QueueStatus("user:2")[0].Position; position != 2 {
+		t.Errorf("user:2 position = %d, want 2 (one turn per requester per round)", position)
+	}
+
+	hold()
+	var order []string
+	for range 7 {
+		select {
+		case g := <-grants:
+			order = append(order, g.requester)
+			if status := s.QueueStatus(""); status[0].Active != 1 {
+				t.Fatalf("active = %d, want the single slot in use", status[0].Active)
+			}
+			g.release()
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for a grant, got %v", order)
+		}
+	}
+
+	want := []string{"user:1", "user:2", "user:1", "user:2", "user:1", "user:1", "user:1"}
+	if !slices.Equal(order, want) {
+		t.Errorf("grant order = %v, want %v", order, want)
+	}
+	if status := s.QueueStatus(""); status[0].Active != 0 || status[0].Waiting != 0 {
+		t.Errorf("final status = %+v, want an idle endpoint", status[0])
+	}
+}
+
+func TestSchedulerCancelledWaiterLeavesQueue(t *testing.T) {
+	s := NewScheduler(1, 1)
+	hold, err := s.Acquire(context.Background(), localConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(entities.WithRequester(context.Background(), "user:1"))
+	done := make(chan error)
+	go func() {
+		_, err := s.Acquire(ctx, localConfig)
+		done <- err
+	}()
+	for waiting(s) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire = %v, want context.Canceled", err)
+	}
+	if status := s.QueueStatus("user:1")[0]; status.Waiting != 0 || status.Position != 0 {
+		t.Errorf("status after cancel = %+v, want no waiting turns", status)
+	}
+
+	// El slot liberado queda disponible para el siguiente solicitante
+	hold()
+	release, err := s.Acquire(entities.WithRequester(context.Background(), "user:2"), localConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	release() // idempotente
+	if status := s.QueueStatus("")[0]; status.Active != 0 {
+		t.Errorf("active = %d after double release, want 0", status.Active)
+	}
+}
@@ -0,0 +1,171 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// streamChunk cubre los formatos de streaming soportados:
+//   - OpenAI y compatibles (LM Studio, vLLM, llama.cpp): choices[].delta.content
+//   - Ollama /api/chat: message.content + done
+//   - Ollama /api/generate: response + done
+type streamChunk struct {
+	Choices []struct {
+		Delta        ChatMessage `json:"delta"`
+		FinishReason *string     `json:"finish_reason"`
+	} `json:"choices"`
+	Message  ChatMessage     `json:"message"`
+	Response string          `json:"response"`
+	Done     bool            `json:"done"`
+	Error    json.RawMessage `json:"error"`
+}
+
+// token retorna el texto incremental del chunk y si el stream terminó
+func (c *streamChunk) token() (string, bool, error) {
+	if len(c.Error) > 0 && string(c.Error) != "null" {
+		return "", true, fmt.Errorf("error del LLM en streaming: %s", string(c.Error))
+	}
+
+	if len(c.Choices) > 0 {
+		var text strings.Builder
+		finished := false
+		for _, choice := range c.Choices {
+			text.WriteString(choice.Delta.Content)
+			if choice.FinishReason != nil && *choice.FinishReason != "" {
+				finished = true
+			}
+		}
+		return text.String(), finished, nil
+	}
+
+	if c.Message.Content != "" {
+		return c.Message.Content, c.Done, nil
+	}
+	return c.Response, c.Done, nil
+}
+
+// streamDecoder lee un stream de respuesta y emite los tokens en orden
+type streamDecoder interface {
+	Decode(r io.Reader, emit func(token string)) error
+}
+
+// sseDecoder decodifica Server-Sent Events (text/event-stream). Cada evento puede
+// tener varias líneas "data:", que se unen con "\n"; el evento termina con una línea vacía.
+type sseDecoder struct{}
+
+func (sseDecoder) Decode(r io.Reader, emit func(token string)) error {
+	scanner := newStreamScanner(r)
+	var data []string
+
+	dispatch := func() (bool, error) {
+		if len(data) == 0 {
+			return false, nil
+		}
+		payload := strings.Join(data, "\n")
+		data = data[:0]
+
+		if strings.TrimSpace(payload) == "[DONE]" {
+			return true, nil
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return false, fmt.Errorf("evento SSE inválido: %w", err)
+		}
+		token, done, err := chunk.token()
+		if err != nil {
+			return true, err
+		}
+		if token != "" {
+			emit(token)
+		}
+		return done, nil
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case line == "":
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comentario / keep-alive
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		default:
+			// event:, id:, retry: no se usan
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Stream cortado sin línea vacía final
+	_, err := dispatch()
+	return err
+}
+
+// ndjsonDecoder decodifica JSON delimitado por líneas (streaming nativo de Ollama)
+type ndjsonDecoder struct{}
+
+func (ndjsonDecoder) Decode(r io.Reader, emit func(token string)) error {
+	scanner := newStreamScanner(r)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return fmt.Errorf("línea NDJSON inválida: %w", err)
+		}
+		token, done, err := chunk.token()
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			emit(token)
+		}
+		if done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// newStreamDecoder elige el decoder según Content-Type; si el servidor no lo informa
+// correctamente se inspecciona el inicio del body. Retorna el reader a usar, que
+// incluye los bytes inspeccionados.
+func newStreamDecoder(contentType string, body io.Reader) (streamDecoder, io.Reader) {
+	contentType = strings.ToLower(contentType)
+	switch {
+	case strings.Contains(contentType, "text/event-stream"):
+		return sseDecoder{}, body
+	case strings.Contains(contentType, "ndjson"):
+		return ndjsonDecoder{}, body
+	}
+
+	reader := bufio.NewReader(body)
+	peek, _ := reader.Peek(64)
+	trimmed := bytes.TrimLeft(peek, " \r\n\t")
+	if bytes.HasPrefix(trimmed, []byte("data:")) ||
+		bytes.HasPrefix(trimmed, []byte("event:")) ||
+		bytes.HasPrefix(trimmed, []byte(":")) {
+		return sseDecoder{}, reader
+	}
+	return ndjsonDecoder{}, reader
+}
+
+// newStreamScanner crea un scanner con buffer amplio: un evento puede superar los 64KB por defecto
+func newStreamScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return scanner
+}
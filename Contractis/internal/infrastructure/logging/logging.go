@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+)
+
+// Formatos de salida de los logs
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel convierte debug, info, warn o error en un nivel de slog
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil || strings.ContainsAny(level, "+-") {
+		return 0, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	return l, nil
+}
+
+// New crea un logger que escribe en out con el formato (text o json) y el nivel mínimo
+// indicados. Cada registro lleva request_id, contract_id y trace_id si están en el contexto.
+func New(out io.Writer, format, level string) (*slog.Logger, error) {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	options := &slog.HandlerOptions{Level: l}
+
+	var handler slog.Handler
+	switch format {
+	case FormatText:
+		handler = slog.NewTextHandler(out, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(out, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: expected text or json", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// Setup instala el logger como predeterminado de slog. Los log.Printf que queden pasan
+// por el mismo handler con nivel info.
+func Setup(out io.Writer, format, level string) error {
+	logger, err := New(out, format, level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return nil
+}
+
+// contextHandler agrega al registro los identificadores de correlación del contexto
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if id := entities.RequestIDFromContext(ctx); id != "" {
+			record.AddAttrs(slog.String("request_id", id))
+		}
+		if id := entities.ContractIDFromContext(ctx); id != 0 {
+			record.AddAttrs(slog.Int64("contract_id", id))
+		}
+		if id := tracing.TraceIDFromContext(ctx); id != "" {
+			record.AddAttrs(slog.String("trace_id", id))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
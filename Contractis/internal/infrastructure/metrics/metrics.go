@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+var (
+	httpBuckets     = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+	llmBuckets      = []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600}
+	analysisBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1200, 2700}
+	chunkBuckets    = []float64{1, 2, 4, 8, 16, 32, 64, 128}
+	dbBuckets       = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+)
+
+// Metrics son las métricas de Contractis. Todos los métodos aceptan un receptor nil,
+// que no registra nada, para que las métricas sean opcionales.
+type Metrics struct {
+	registry *Registry
+
+	httpRequests *CounterVec
+	httpDuration *HistogramVec
+
+	llmRequests *CounterVec
+	llmDuration *HistogramVec
+	llmTokens   *CounterVec
+	llmErrors   *CounterVec
+
+	analysesActive   *GaugeVec
+	analyses         *CounterVec
+	analysisChunks   *HistogramVec
+	analysisDuration *HistogramVec
+
+	dbDuration *HistogramVec
+	dbErrors   *CounterVec
+}
+
+// New crea las métricas de Contractis en un registro nuevo
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		registry: r,
+
+		httpRequests: r.NewCounter("contractis_http_requests_total",
+			"Peticiones HTTP atendidas por ruta, método y status.", "method", "route", "status"),
+		httpDuration: r.NewHistogram("contractis_http_request_duration_seconds",
+			"Duración de las peticiones HTTP por ruta y método.", httpBuckets, "method", "route"),
+
+		llmRequests: r.NewCounter("contractis_llm_requests_total",
+			"Peticiones de chat enviadas al LLM por proveedor (host del endpoint) y modelo.", "provider", "model"),
+		llmDuration: r.NewHistogram("contractis_llm_request_duration_seconds",
+			"Duración de las peticiones de chat al LLM, sin la espera en la cola.", llmBuckets, "provider", "model"),
+		llmTokens: r.NewCounter("contractis_llm_tokens_total",
+			"Tokens estimados (por caracteres) de las peticiones al LLM.", "provider", "model", "type"),
+		llmErrors: r.NewCounter("contractis_llm_errors_total",
+			"Peticiones al LLM fallidas por motivo.", "provider", "model", "reason"),
+
+		analysesActive: r.NewGauge("contractis_analyses_active",
+			"Análisis de contratos en curso."),
+		analyses: r.NewCounter("contractis_analyses_total",
+			"Análisis de contratos terminados por status final.", "status"),
+		analysisChunks: r.NewHistogram("contractis_analysis_chunks",
+			"Chunks en que se dividió cada documento analizado con éxito.", chunkBuckets),
+		analysisDuration: r.NewHistogram("contractis_analysis_duration_seconds",
+			"Duración de los análisis de contratos.", analysisBuckets, "status"),
+
+		dbDuration: r.NewHistogram("contractis_db_query_duration_seconds",
+			"Duración de las consultas a la base de datos por sentencia y tabla.", dbBuckets, "statement"),
+		dbErrors: r.NewCounter("contractis_db_errors_total",
+			"Consultas a la base de datos fallidas por sentencia y tabla.", "statement"),
+	}
+}
+
+// Handler sirve las métricas en el formato de texto de Prometheus
+func (m *Metrics) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.registry.WriteTo(w)
+	}
+}
+
+// ObserveRequest registra una petición HTTP; route es el patrón de la ruta (no la URL,
+// para que los IDs no multipliquen las series)
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	if route == "" {
+		route = "unmatched"
+	}
+	// "GET /api/v1/contracts/{id}" → "/api/v1/contracts/{id}": el método ya es una etiqueta
+	if _, path, ok := strings.Cut(route, " "); ok {
+		route = path
+	}
+	m.httpRequests.Inc(method, route, strconv.Itoa(status))
+	m.httpDuration.Observe(duration.Seconds(), method, route)
+}
+
+// ObserveLLMRequest registra una petición de chat al LLM
+func (m *Metrics) ObserveLLMRequest(provider, model string, duration time.Duration, promptTokens, completionTokens int, err error) {
+	if m == nil {
+		return
+	}
+	m.llmRequests.Inc(provider, model)
+	m.llmDuration.Observe(duration.Seconds(), provider, model)
+	m.llmTokens.Add(float64(promptTokens), provider, model, "prompt")
+	m.llmTokens.Add(float64(completionTokens), provider, model, "completion")
+	if err != nil {
+		m.llmErrors.Inc(provider, model, llmErrorReason(err))
+	}
+}
+
+// llmErrorReason clasifica el error de una petición al LLM en un conjunto acotado de motivos
+func llmErrorReason(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, entities.ErrQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, entities.ErrLLMTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, entities.ErrLLMConnectionFailed):
+		return "connection"
+	case errors.Is(err, entities.ErrInvalidLLMResponse):
+		return "invalid_response"
+	default:
+		return "other"
+	}
+}
+
+// AnalysisStarted implementa services.AnalysisMetrics
+func (m *Metrics) AnalysisStarted() {
+	if m == nil {
+		return
+	}
+	m.analysesActive.Add(1)
+}
+
+// AnalysisFinished implementa services.AnalysisMetrics
+func (m *Metrics) AnalysisFinished(status entities.ContractStatus, chunks int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.analysesActive.Add(-1)
+	m.analyses.Inc(string(status))
+	m.analysisDuration.Observe(duration.Seconds(), string(status))
+	if chunks > 0 {
+		m.analysisChunks.Observe(float64(chunks))
+	}
+}
+
+// ObserveQuery registra una consulta a la base de datos; statement es el verbo y la
+// tabla ("SELECT contracts")
+func (m *Metrics) ObserveQuery(statement string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.dbDuration.Observe(duration.Seconds(), statement)
+	if err != nil {
+		m.dbErrors.Inc(statement)
+	}
+}
+
+// WatchQueue publica el estado de la cola del LLM por endpoint: peticiones en espera,
+// en curso y slots disponibles, leídos de status al consultar las métricas
+func (m *Metrics) WatchQueue(status func() []entities.QueueStatus) {
+	if m == nil {
+		return
+	}
+	gauge := func(name, help string, value func(entities.QueueStatus) int) {
+		m.registry.NewGaugeFunc(name, help, []string{"endpoint"}, func(emit func(float64, ...string)) {
+			for _, endpoint := range status() {
+				emit(float64(value(endpoint)), endpoint.Endpoint)
+			}
+		})
+	}
+	gauge("contractis_llm_queue_waiting", "Peticiones al LLM en espera de un slot, por endpoint.",
+		func(s entities.QueueStatus) int { return s.Waiting })
+	gauge("contractis_llm_queue_active", "Peticiones al LLM en curso, por endpoint.",
+		func(s entities.QueueStatus) int { return s.Active })
+	gauge("contractis_llm_queue_slots", "Peticiones simultáneas permitidas, por endpoint.",
+		func(s entities.QueueStatus) int { return s.Limit })
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHandlerUsesRoutePattern sirve las métricas con el content type de Prometheus y
+// etiqueta las peticiones por el patrón de la ruta, sin el método
+func TestHandlerUsesRoutePattern(t *testing.T) {
+	m := New()
+	m.ObserveRequest("GET", "GET /api/v1/contracts/{id}", 200, 30*time.Millisecond)
+	m.ObserveRequest("GET", "", 404, time.Millisecond)
+
+	w := httptest.NewRecorder()
+	m.Handler()(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := w.Body.String()
+	for _, line := range []string{
+		`contractis_http_requests_total{method="GET",route="/api/v1/contracts/{id}",status="200"} 1`,
+		`contractis_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`contractis_http_request_duration_seconds_bucket{method="GET",route="/api/v1/contracts/{id}",le="0.05"} 1`,
+		`contractis_http_request_duration_seconds_bucket{method="GET",route="/api/v1/contracts/{id}",le="0.025"} 0`,
+		"contractis_analyses_active 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics output is missing %q", line)
+		}
+	}
+
+	// Sin métricas habilitadas el endpoint no existe
+	w = httptest.NewRecorder()
+	(*Metrics)(nil).Handler()(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != 404 {
+		t.Errorf("nil metrics handler = %d, want 404", w.Code)
+	}
+}
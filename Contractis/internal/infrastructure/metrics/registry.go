@@ -0,0 +1,310 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry agrupa las métricas y las escribe en el formato de texto de Prometheus
+// (text/plain; version=0.0.4), en el orden en que se registraron
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// metric es una familia de métricas con nombre, ayuda y tipo
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry crea un registro vacío
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo escribe todas las métricas en formato de texto de Prometheus
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	counter := &countingWriter{w: out}
+	w := bufio.NewWriter(counter)
+	for _, m := range metrics {
+		m.write(w)
+	}
+	err := w.Flush()
+	return counter.n, err
+}
+
+// desc describe una familia: nombre, ayuda, tipo y nombres de las etiquetas
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+}
+
+// key identifica una serie por sus valores de etiqueta
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs arma {a="x",b="y"} con extra (p. ej. le) al final
+func (d desc) labelPairs(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(d.labels)+len(extra)/2)
+	for i, label := range d.labels {
+		pairs = append(pairs, label+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// series es un valor con sus etiquetas
+type series struct {
+	values []string
+	value  float64
+}
+
+// CounterVec es un contador con etiquetas
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// NewCounter registra un contador con las etiquetas indicadas
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{desc: desc{name, help, "counter", labels}, series: make(map[string]*series)}
+	if len(labels) == 0 {
+		c.Add(0) // sin etiquetas la serie existe desde el inicio, en cero
+	}
+	r.register(c)
+	return c
+}
+
+// Add suma delta (no negativo) a la serie de las etiquetas indicadas
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+// Inc suma uno a la serie de las etiquetas indicadas
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeHeader(w)
+	for _, s := range sortedSeries(c.series) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(s.values), formatValue(s.value))
+	}
+}
+
+// GaugeVec es un valor que sube y baja, con etiquetas
+type GaugeVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// NewGauge registra un gauge con las etiquetas indicadas
+func (r *Registry) NewGauge(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{desc: desc{name, help, "gauge", labels}, series: make(map[string]*series)}
+	if len(labels) == 0 {
+		g.Add(0)
+	}
+	r.register(g)
+	return g
+}
+
+// Add suma delta (puede ser negativo) a la serie de las etiquetas indicadas
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	key := g.key(values)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		g.series[key] = s
+	}
+	s.value += delta
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writeHeader(w)
+	for _, s := range sortedSeries(g.series) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(s.values), formatValue(s.value))
+	}
+}
+
+// GaugeFunc es un gauge cuyo valor se calcula al leer las métricas
+type GaugeFunc struct {
+	desc
+	collect func(emit func(value float64, values ...string))
+}
+
+// NewGaugeFunc registra un gauge calculado: collect llama a emit una vez por serie
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(emit func(value float64, values ...string))) {
+	r.register(&GaugeFunc{desc: desc{name, help, "gauge", labels}, collect: collect})
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	collected := make(map[string]*series)
+	g.collect(func(value float64, values ...string) {
+		collected[g.key(values)] = &series{values: values, value: value}
+	})
+	g.writeHeader(w)
+	for _, s := range sortedSeries(collected) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(s.values), formatValue(s.value))
+	}
+}
+
+// HistogramVec cuenta observaciones en buckets acumulativos, con etiquetas
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // por bucket, no acumulados
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram registra un histograma con los límites superiores de bucket indicados
+// (en orden creciente; +Inf se agrega solo)
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{
+		desc:    desc{name, help, "histogram", labels},
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe agrega una observación a la serie de las etiquetas indicadas
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w)
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(s.values), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(s.values), s.count)
+	}
+}
+
+func sortedSeries(m map[string]*series) []*series {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := make([]*series, len(keys))
+	for i, key := range keys {
+		out[i] = m[key]
+	}
+	return out
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
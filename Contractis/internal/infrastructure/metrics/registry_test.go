@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistryGolden compara la salida completa con el formato de texto de Prometheus:
+// encabezados HELP/TYPE, escape de ayuda y etiquetas, series ordenadas y buckets acumulados
+func TestRegistryGolden(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.NewCounter("test_requests_total", "Peticiones con \\ y\nsalto.", "route", "status")
+	requests.Inc(`/a"b`, "200")
+	requests.Add(2, "/línea\nnueva", "500")
+	requests.Add(-1, "/línea\nnueva", "500") // los contadores no bajan
+	requests.Inc(`C:\dir`, "404")
+
+	bytes := r.NewCounter("test_bytes_total", "Bytes enviados.")
+	bytes.Add(1.5)
+	r.NewCounter("test_unused_total", "Sin observaciones.", "route")
+
+	active := r.NewGauge("test_active", "En curso.")
+	active.Add(3)
+	active.Add(-1)
+
+	duration := r.NewHistogram("test_duration_seconds", "Duración.", []float64{0.5, 1}, "method")
+	for _, v := range []float64{0.25, 0.5, 0.5, 4} {
+		duration.Observe(v, "GET")
+	}
+	duration.Observe(2, "POST")
+
+	r.NewGaugeFunc("test_queue_waiting", "En espera.", []string{"endpoint"}, func(emit func(float64, ...string)) {
+		emit(1, "http://b")
+		emit(0, "http://a")
+	})
+
+	const want = `# HELP test_requests_total Peticiones con \\ y\nsalto.
+# TYPE test_requests_total counter
+test_requests_total{route="/a\"b",status="200"} 1
+test_requests_total{route="/línea\nnueva",status="500"} 2
+test_requests_total{route="C:\\dir",status="404"} 1
+# HELP test_bytes_total Bytes enviados.
+# TYPE test_bytes_total counter
+test_bytes_total 1.5
+# HELP test_unused_total Sin observaciones.
+# TYPE test_unused_total counter
+# HELP test_active En curso.
+# TYPE test_active gauge
+test_active 2
+# HELP test_duration_seconds Duración.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="GET",le="0.5"} 3
+test_duration_seconds_bucket{method="GET",le="1"} 3
+test_duration_seconds_bucket{method="GET",le="+Inf"} 4
+test_duration_seconds_sum{method="GET"} 5.25
+test_duration_seconds_count{method="GET"} 4
+test_duration_seconds_bucket{method="POST",le="0.5"} 0
+test_duration_seconds_bucket{method="POST",le="1"} 0
+test_duration_seconds_bucket{method="POST",le="+Inf"} 1
+test_duration_seconds_sum{method="POST"} 2
+test_duration_seconds_count{method="POST"} 1
+# HELP test_queue_waiting En espera.
+# TYPE test_queue_waiting gauge
+test_queue_waiting{endpoint="http://a"} 0
+test_queue_waiting{endpoint="http://b"} 1
+`
+
+	var out strings.Builder
+	n, err := r.WriteTo(&out)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("WriteTo output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if n != int64(out.Len()) {
+		t.Errorf("WriteTo returned %d bytes, wrote %d", n, out.Len())
+	}
+}
+
+func TestLabelCountMismatchPanics(t *testing.T) {
+	c := NewRegistry().NewCounter("test_total", "Prueba.", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with a missing label value did not panic")
+		}
+	}()
+	c.Inc("solo-a")
+}
@@ -0,0 +1,311 @@
+// Package oidctest implementa un proveedor OpenID Connect mínimo para pruebas y
+// desarrollo local: aprueba automáticamente cada login con un usuario configurable.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// User es la identidad que el emisor devuelve en el ID token
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// authRequest es un código de autorización pendiente de canje
+type authRequest struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	user          User
+	expiresAt     time.Time
+}
+
+// Issuer es un emisor OIDC en memoria. Soporta discovery, /authorize (sin pantalla:
+// aprueba directamente), /token con PKCE S256 obligatorio y /jwks.
+type Issuer struct {
+	URL          string
+	ClientID     string
+	ClientSecret string // vacío: cliente público
+	DefaultUser  User
+	Users        map[string]User // por email, seleccionable con login_hint
+
+	// TokenHook, si no es nil, modifica la cabecera y los claims de cada ID token antes
+	// de firmarlo, para probar que el cliente rechaza tokens inválidos. Con alg "none"
+	// el token se emite sin firma.
+	TokenHook func(header, claims map[string]interface{})
+
+	key   *rsa.PrivateKey
+	keyID string
+
+	mu    sync.Mutex
+	codes map[string]*authRequest
+}
+
+// NewIssuer crea un emisor con una clave RSA nueva. issuerURL debe ser la URL pública
+// del emisor (el valor del claim iss).
+func NewIssuer(issuerURL, clientID, clientSecret string) (*Issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("error generating RSA key: %w", err)
+	}
+	return &Issuer{
+		URL:          strings.TrimRight(issuerURL, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		DefaultUser: User{
+			Subject:       "mock-user",
+			Email:         "user@example.com",
+			EmailVerified: true,
+			Name:          "Mock User",
+		},
+		Users: make(map[string]User),
+		key:   key,
+		keyID: "mock-" + randomString(6),
+		codes: make(map[string]*authRequest),
+	}, nil
+}
+
+// NewServer arranca el emisor en un httptest.Server; el llamador debe cerrarlo
+func NewServer(clientID, clientSecret string) (*Issuer, *httptest.Server, error) {
+	issuer, err := NewIssuer("", clientID, clientSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := httptest.NewServer(issuer)
+	issuer.URL = server.URL
+	return issuer, server, nil
+}
+
+// ServeHTTP enruta las peticiones del emisor
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		i.handleDiscovery(w)
+	case "/authorize":
+		i.handleAuthorize(w, r)
+	case "/token":
+		i.handleToken(w, r)
+	case "/jwks":
+		i.handleJWKS(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (i *Issuer) handleDiscovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/authorize",
+		"token_endpoint":                        i.URL + "/token",
+		"jwks_uri":                              i.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile", "groups"},
+	})
+}
+
+func (i *Issuer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != i.ClientID || redirectURI == "" {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" ||
+		q.Get("code_challenge") == "" ||
+		q.Get("code_challenge_method") != "S256" ||
+		!strings.Contains(" "+q.Get("scope")+" ", " openid ") {
+		http.Error(w, "unsupported authorization request", http.StatusBadRequest)
+		return
+	}
+
+	user := i.DefaultUser
+	if hint := q.Get("login_hint"); hint != "" {
+		if u, ok := i.Users[hint]; ok {
+			user = u
+		}
+	}
+
+	code := randomString(24)
+	i.mu.Lock()
+	i.codes[code] = &authRequest{
+		clientID:      i.ClientID,
+		redirectURI:   redirectURI,
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		user:          user,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	i.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	values := target.Query()
+	values.Set("code", code)
+	values.Set("state", q.Get("state"))
+	target.RawQuery = values.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (i *Issuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, hasBasic := r.BasicAuth()
+	if hasBasic {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	if clientID != i.ClientID || (i.ClientSecret != "" && clientSecret != i.ClientSecret) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="mock-oidc"`)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	// Los códigos son de un solo uso
+	code := r.PostForm.Get("code")
+	i.mu.Lock()
+	req, ok := i.codes[code]
+	delete(i.codes, code)
+	i.mu.Unlock()
+
+	if !ok || time.Now().After(req.expiresAt) ||
+		req.redirectURI != r.PostForm.Get("redirect_uri") ||
+		s256(r.PostForm.Get("code_verifier")) != req.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	idToken, err := i.SignIDToken(req.user, req.nonce, time.Hour)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(24),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (i *Issuer) handleJWKS(w http.ResponseWriter) {
+	pub := i.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": i.keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// SignIDToken firma un ID token RS256 para el usuario dado
+func (i *Issuer) SignIDToken(user User, nonce string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":            i.URL,
+		"sub":            user.Subject,
+		"aud":            i.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(ttl).Unix(),
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+		"name":           user.Name,
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	if len(user.Groups) > 0 {
+		claims["groups"] = user.Groups
+	}
+
+	header := map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": i.keyID}
+	if i.TokenHook != nil {
+		i.TokenHook(header, claims)
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	if header["alg"] == "none" {
+		return signingInput + ".", nil
+	}
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,453 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+const (
+	// TransactionTTL es el tiempo máximo entre el inicio del login y el callback
+	TransactionTTL = 10 * time.Minute
+
+	// clockSkew es la tolerancia de reloj al validar exp/iat del ID token
+	clockSkew = time.Minute
+
+	// jwksRefreshInterval es el mínimo entre dos descargas del JWKS
+	jwksRefreshInterval = 10 * time.Second
+
+	// minSecretSize es el tamaño mínimo de la clave que firma las transacciones
+	minSecretSize = 32
+)
+
+// Config es la configuración del cliente OIDC
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // vacío para clientes públicos (solo PKCE)
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+}
+
+// discovery es el subconjunto usado de /.well-known/openid-configuration
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// transaction es el estado de un login en curso; viaja firmado en una cookie
+type transaction struct {
+	State     string `json:"s"`
+	Nonce     string `json:"n"`
+	Verifier  string `json:"v"`
+	ReturnTo  string `json:"r"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Provider implementa services.OIDCProvider con authorization code + PKCE (S256).
+// El documento de discovery y las claves se obtienen de forma perezosa y se
+// cachean; las claves se vuelven a pedir si el ID token usa un kid desconocido.
+type Provider struct {
+	config     Config
+	secret     []byte
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+	keysAt    time.Time
+}
+
+// NewProvider crea una nueva instancia de Provider. secret firma el estado de las transacciones.
+func NewProvider(config Config, secret []byte) (*Provider, error) {
+	config.Issuer = strings.TrimRight(strings.TrimSpace(config.Issuer), "/")
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("OIDC issuer, client ID and redirect URL are required")
+	}
+	if _, err := url.ParseRequestURI(config.RedirectURL); err != nil {
+		return nil, fmt.Errorf("invalid OIDC redirect URL: %w", err)
+	}
+	if len(secret) < minSecretSize {
+		return nil, fmt.Errorf("OIDC state secret must be at least %d bytes", minSecretSize)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if !containsString(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+
+	return &Provider{
+		config:     config,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// BeginLogin genera state, nonce y code verifier, y arma la URL de autorización
+func (p *Provider) BeginLogin(ctx context.Context, returnTo string) (string, string, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	tx := transaction{
+		ReturnTo:  returnTo,
+		ExpiresAt: time.Now().Add(TransactionTTL).Unix(),
+	}
+	for _, field := range []*string{&tx.State, &tx.Nonce, &tx.Verifier} {
+		if *field, err = randomToken(32); err != nil {
+			return "", "", fmt.Errorf("error generating OIDC state: %w", err)
+		}
+	}
+
+	authURL, err := url.Parse(disc.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", tx.State)
+	query.Set("nonce", tx.Nonce)
+	query.Set("code_challenge", pkceChallenge(tx.Verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	sealed, err := p.sealTransaction(&tx)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL.String(), sealed, nil
+}
+
+// CompleteLogin valida el state, canjea el código por tokens y verifica el ID token
+func (p *Provider) CompleteLogin(ctx context.Context, sealed, state, code string) (*entities.IdentityClaims, string, error) {
+	tx, err := p.openTransaction(sealed)
+	if err != nil {
+		return nil, "", err
+	}
+	if state == "" || !hmac.Equal([]byte(state), []byte(tx.State)) {
+		return nil, "", fmt.Errorf("%w: state mismatch", entities.ErrOIDCLogin)
+	}
+	if code == "" {
+		return nil, "", fmt.Errorf("%w: missing authorization code", entities.ErrOIDCLogin)
+	}
+
+	rawIDToken, err := p.exchangeCode(ctx, code, tx.Verifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims, err := p.verifyIDToken(ctx, rawIDToken, tx.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+	return claims, tx.ReturnTo, nil
+}
+
+// exchangeCode canjea el código en el token endpoint y retorna el ID token
+func (p *Provider) exchangeCode(ctx context.Context, code, verifier string) (string, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", disc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: token request failed: %v", entities.ErrOIDCLogin, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%w: reading token response: %v", entities.ErrOIDCLogin, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: token endpoint returned %d: %s", entities.ErrOIDCLogin, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return "", fmt.Errorf("%w: invalid token response: %v", entities.ErrOIDCLogin, err)
+	}
+	if tokens.IDToken == "" {
+		return "", fmt.Errorf("%w: token response has no id_token", entities.ErrOIDCLogin)
+	}
+	return tokens.IDToken, nil
+}
+
+// verifyIDToken valida firma, emisor, audiencia, expiración y nonce del ID token
+func (p *Provider) verifyIDToken(ctx context.Context, raw, nonce string) (*entities.IdentityClaims, error) {
+	token, err := parseJWT(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrOIDCLogin, err)
+	}
+
+	key, err := p.getKey(ctx, token.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := token.verifyRS256(key); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrOIDCLogin, err)
+	}
+
+	var claims struct {
+		Issuer        string   `json:"iss"`
+		Subject       string   `json:"sub"`
+		Audience      audience `json:"aud"`
+		AuthorizedBy  string   `json:"azp"`
+		ExpiresAt     int64    `json:"exp"`
+		IssuedAt      int64    `json:"iat"`
+		Nonce         string   `json:"nonce"`
+		Email         string   `json:"email"`
+		EmailVerified *bool    `json:"email_verified"`
+		Name          string   `json:"name"`
+	}
+	if err := json.Unmarshal(token.payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: invalid ID token claims: %v", entities.ErrOIDCLogin, err)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != p.config.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer %q", entities.ErrOIDCLogin, claims.Issuer)
+	case !claims.Audience.contains(p.config.ClientID):
+		return nil, fmt.Errorf("%w: ID token audience does not include client", entities.ErrOIDCLogin)
+	case len(claims.Audience) > 1 && claims.AuthorizedBy != p.config.ClientID:
+		return nil, fmt.Errorf("%w: ID token azp does not match client", entities.ErrOIDCLogin)
+	case claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)):
+		return nil, fmt.Errorf("%w: ID token expired", entities.ErrOIDCLogin)
+	case claims.IssuedAt > 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(clockSkew)):
+		return nil, fmt.Errorf("%w: ID token issued in the future", entities.ErrOIDCLogin)
+	case !hmac.Equal([]byte(claims.Nonce), []byte(nonce)):
+		return nil, fmt.Errorf("%w: nonce mismatch", entities.ErrOIDCLogin)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: ID token has no subject", entities.ErrOIDCLogin)
+	}
+
+	groups, err := extractGroups(token.payload, p.config.GroupsClaim)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrOIDCLogin, err)
+	}
+
+	return &entities.IdentityClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		Groups:        groups,
+	}, nil
+}
+
+// extractGroups lee el claim de grupos, que puede ser una lista o un string
+func extractGroups(payload []byte, claim string) ([]string, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &all); err != nil {
+		return nil, err
+	}
+	raw, ok := all[claim]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var groups []string
+	if err := json.Unmarshal(raw, &groups); err == nil {
+		return groups, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, fmt.Errorf("claim %q must be a string or a list of strings", claim)
+	}
+	return []string{single}, nil
+}
+
+// getDiscovery obtiene y cachea el documento de discovery del emisor
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var disc discovery
+	if err := p.getJSON(ctx, p.config.Issuer+"/.well-known/openid-configuration", &disc); err != nil {
+		return nil, fmt.Errorf("%w: discovery failed: %v", entities.ErrOIDCLogin, err)
+	}
+	if strings.TrimRight(disc.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", entities.ErrOIDCLogin, disc.Issuer, p.config.Issuer)
+	}
+	if disc.AuthorizationEndpoint == "" || disc.TokenEndpoint == "" || disc.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete discovery document", entities.ErrOIDCLogin)
+	}
+
+	p.mu.Lock()
+	p.discovery = &disc
+	p.mu.Unlock()
+	return &disc, nil
+}
+
+// getKey retorna la clave de firma con el kid dado, refrescando el JWKS si no se conoce.
+// Los refrescos se limitan a uno cada jwksRefreshInterval para que un kid inventado no genere tráfico.
+func (p *Provider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.lookupKey(kid)
+	throttled := p.keys != nil && time.Since(p.keysAt) < jwksRefreshInterval
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if throttled {
+		return nil, fmt.Errorf("%w: unknown signing key %q", entities.ErrOIDCLogin, kid)
+	}
+
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err := p.getJSON(ctx, disc.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("%w: fetching JWKS: %v", entities.ErrOIDCLogin, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = keys
+	p.keysAt = time.Now()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", entities.ErrOIDCLogin, kid)
+}
+
+// lookupKey busca por kid; sin kid solo es válido si el JWKS tiene una única clave
+func (p *Provider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
+
+// sealTransaction serializa y firma el estado de la transacción
+func (p *Provider) sealTransaction(tx *transaction) (string, error) {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + p.sign(payload), nil
+}
+
+// openTransaction verifica la firma y la expiración del estado de la transacción
+func (p *Provider) openTransaction(sealed string) (*transaction, error) {
+	payload, signature, ok := strings.Cut(sealed, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.sign(payload))) {
+		return nil, fmt.Errorf("%w: invalid or missing login transaction", entities.ErrOIDCLogin)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid login transaction", entities.ErrOIDCLogin)
+	}
+	var tx transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("%w: invalid login transaction", entities.ErrOIDCLogin)
+	}
+	if time.Now().Unix() > tx.ExpiresAt {
+		return nil, fmt.Errorf("%w: login transaction expired", entities.ErrOIDCLogin)
+	}
+	return &tx, nil
+}
+
+func (p *Provider) sign(data string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/infrastructure/oidc/oidctest"
+)
+
+const testRedirectURL = "http://contractis.test/auth/callback"
+
+// noRedirect devuelve la redirección del emisor en lugar de seguirla
+var noRedirect = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}}
+
+func newTestProvider(t *testing.T, clientSecret string) (*Provider, *oidctest.Issuer) {
+	t.Helper()
+	issuer, server, err := oidctest.NewServer("contractis", clientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	provider, err := NewProvider(Config{
+		Issuer:       issuer.URL,
+		ClientID:     "contractis",
+		ClientSecret: clientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "groups"},
+	}, bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider, issuer
+}
+
+// callback es lo que el navegador trae de vuelta del emisor
+type callback struct {
+	transaction string
+	state       string
+	code        string
+}
+
+// authorize inicia un login y sigue la redirección al emisor, que lo aprueba sin pantalla
+func authorize(t *testing.T, provider *Provider, returnTo string) callback {
+	t.Helper()
+	authURL, transaction, err := provider.BeginLogin(context.Background(), returnTo)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+
+	query, _ := url.Parse(authURL)
+	if query.Query().Get("code_challenge_method") != "S256" || query.Query().Get("code_challenge") == "" {
+		t.Fatalf("authorization URL %s has no PKCE challenge", authURL)
+	}
+
+	resp, err := noRedirect.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize = %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if !strings.HasPrefix(location.String(), testRedirectURL) {
+		t.Fatalf("issuer redirected to %s", location)
+	}
+	return callback{transaction, location.Query().Get("state"), location.Query().Get("code")}
+}
+
+func TestCompleteLoginWithPKCE(t *testing.T) {
+	for _, secret := range []string{"", "s3cret"} {
+		provider, issuer := newTestProvider(t, secret)
+		issuer.DefaultUser.Groups = []string{"legal", "admins"}
+
+		cb := authorize(t, provider, "/history")
+		claims, returnTo, err := provider.CompleteLogin(context.Background(), cb.transaction, cb.state, cb.code)
+		if err != nil {
+			t.Fatalf("client secret %q: CompleteLogin: %v", secret, err)
+		}
+		if claims.Subject != "mock-user" || claims.Email != "user@example.com" || len(claims.Groups) != 2 {
+			t.Errorf("claims = %+v", claims)
+		}
+		if claims.EmailVerified == nil || !*claims.EmailVerified {
+			t.Errorf("email_verified = %v, want true", claims.EmailVerified)
+		}
+		if returnTo != "/history" {
+			t.Errorf("returnTo = %q, want /history", returnTo)
+		}
+
+		// El código es de un solo uso
+		if _, _, err := provider.CompleteLogin(context.Background(), cb.transaction, cb.state, cb.code); !errors.Is(err, entities.ErrOIDCLogin) {
+			t.Errorf("code replay: err = %v, want ErrOIDCLogin", err)
+		}
+	}
+}
+
+func TestCompleteLoginRejectsTamperedCallbacks(t *testing.T) {
+	provider, _ := newTestProvider(t, "")
+
+	t.Run("wrong state", func(t *testing.T) {
+		cb := authorize(t, provider, "/")
+		_, _, err := provider.CompleteLogin(context.Background(), cb.transaction, "forged-state", cb.code)
+		wantLoginError(t, err, "state mismatch")
+	})
+
+	t.Run("missing transaction", func(t *testing.T) {
+		cb := authorize(t, provider, "/")
+		_, _, err := provider.CompleteLogin(context.Background(), "", cb.state, cb.code)
+		wantLoginError(t, err, "login transaction")
+	})
+
+	t.Run("forged transaction", func(t *testing.T) {
+		cb := authorize(t, provider, "/")
+		payload, _, _ := strings.Cut(cb.transaction, ".")
+		_, _, err := provider.CompleteLogin(context.Background(), payload+".Zm9yZ2Vk", cb.state, cb.code)
+		wantLoginError(t, err, "login transaction")
+	})
+
+	// Un código robado de otro login no sirve sin su code_verifier
+	t.Run("code from another login", func(t *testing.T) {
+		victim := authorize(t, provider, "/")
+		attacker := authorize(t, provider, "/")
+		_, _, err := provider.CompleteLogin(context.Background(), attacker.transaction, attacker.state, victim.code)
+		wantLoginError(t, err, "invalid_grant")
+	})
+}
+
+func TestCompleteLoginRejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		hook func(header, claims map[string]interface{})
+		want string
+	}{
+		{"wrong nonce", func(_, claims map[string]interface{}) { claims["nonce"] = "replayed" }, "nonce mismatch"},
+		{"missing nonce", func(_, claims map[string]interface{}) { delete(claims, "nonce") }, "nonce mismatch"},
+		{"other audience", func(_, claims map[string]interface{}) { claims["aud"] = "another-client" }, "audience"},
+		{"bad azp", func(_, claims map[string]interface{}) {
+			claims["aud"] = []string{"contractis", "another-client"}
+			claims["azp"] = "another-client"
+		}, "azp"},
+		{"expired", func(_, claims map[string]interface{}) {
+			claims["exp"] = time.Now().Add(-5 * time.Minute).Unix()
+		}, "expired"},
+		{"issued in the future", func(_, claims map[string]interface{}) {
+			claims["iat"] = time.Now().Add(time.Hour).Unix()
+		}, "future"},
+		{"other issuer", func(_, claims map[string]interface{}) { claims["iss"] = "https://evil.test" }, "issuer"},
+		{"alg none", func(header, _ map[string]interface{}) { header["alg"] = "none" }, "algorithm"},
+		{"alg HS256", func(header, _ map[string]interface{}) { header["alg"] = "HS256" }, "algorithm"},
+		{"unknown kid", func(header, _ map[string]interface{}) { header["kid"] = "rotated-away" }, "unknown signing key"},
+		{"no subject", func(_, claims map[string]interface{}) { claims["sub"] = "" }, "no subject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, issuer := newTestProvider(t, "")
+			issuer.TokenHook = tt.hook
+
+			cb := authorize(t, provider, "/")
+			claims, _, err := provider.CompleteLogin(context.Background(), cb.transaction, cb.state, cb.code)
+			if claims != nil {
+				t.Errorf("claims = %+v, want none", claims)
+			}
+			wantLoginError(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerifyIDTokenRejectsBadSignature(t *testing.T) {
+	provider, issuer := newTestProvider(t, "")
+	token, err := issuer.SignIDToken(issuer.DefaultUser, "n", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cambiar el payload conservando la firma
+	parts := strings.Split(token, ".")
+	other, err := issuer.SignIDToken(oidctest.User{Subject: "admin"}, "n", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2]
+
+	_, err = provider.verifyIDToken(context.Background(), forged, "n")
+	wantLoginError(t, err, "signature")
+}
+
+func wantLoginError(t *testing.T, err error, want string) {
+	t.Helper()
+	if !errors.Is(err, entities.ErrOIDCLogin) {
+		t.Fatalf("err = %v, want ErrOIDCLogin", err)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("err = %v, want it to mention %q", err, want)
+	}
+}
@@ -0,0 +1,160 @@
+package redaction
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// digitsOnly elimina separadores y retorna solo los dígitos
+func digitsOnly(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+func countDigits(value string) int {
+	return len(digitsOnly(value))
+}
+
+// validIBAN valida el checksum ISO 13616 (mod 97)
+func validIBAN(value string) bool {
+	iban := strings.ReplaceAll(value, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// validSpanishID valida la letra de control de un DNI o NIE
+func validSpanishID(value string) bool {
+	id := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	if len(id) != 9 {
+		return false
+	}
+
+	number := id[:8]
+	switch number[0] {
+	case 'X':
+		number = "0" + number[1:]
+	case 'Y':
+		number = "1" + number[1:]
+	case 'Z':
+		number = "2" + number[1:]
+	}
+
+	n := 0
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+		n = n*10 + int(r-'0')
+	}
+	return "TRWAGMYFPDXBNJZSQVHLCKE"[n%23] == id[8]
+}
+
+// validCUIT valida el dígito verificador de un CUIT/CUIL argentino
+func validCUIT(value string) bool {
+	digits := digitsOnly(value)
+	if len(digits) != 11 {
+		return false
+	}
+
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	check := 11 - sum%11
+	switch check {
+	case 11:
+		check = 0
+	case 10:
+		check = 9
+	}
+	return check == int(digits[10]-'0')
+}
+
+// validRUT valida el dígito verificador de un RUT chileno
+func validRUT(value string) bool {
+	value = strings.ToUpper(strings.ReplaceAll(value, ".", ""))
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 || len(parts[0]) < 7 {
+		return false
+	}
+
+	sum, factor := 0, 2
+	for i := len(parts[0]) - 1; i >= 0; i-- {
+		sum += int(parts[0][i]-'0') * factor
+		factor++
+		if factor > 7 {
+			factor = 2
+		}
+	}
+
+	var expected string
+	switch check := 11 - sum%11; check {
+	case 11:
+		expected = "0"
+	case 10:
+		expected = "K"
+	default:
+		expected = string(rune('0' + check))
+	}
+	return parts[1] == expected
+}
+
+// validPeruRUC valida el dígito verificador de un RUC peruano de 11 dígitos
+func validPeruRUC(value string) bool {
+	if len(value) != 11 || countDigits(value) != 11 {
+		return false
+	}
+
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(value[i]-'0') * w
+	}
+	check := (11 - sum%11) % 10
+	return check == int(value[10]-'0')
+}
+
+// validParaguayRUC valida el dígito verificador (módulo 11, base 11) de un RUC paraguayo "base-DV"
+func validParaguayRUC(value string) bool {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 || len(parts[1]) != 1 {
+		return false
+	}
+
+	sum, factor := 0, 2
+	for i := len(parts[0]) - 1; i >= 0; i-- {
+		sum += int(parts[0][i]-'0') * factor
+		factor++
+	}
+	check := 0
+	if rest := sum % 11; rest > 1 {
+		check = 11 - rest
+	}
+	return check == int(parts[1][0]-'0')
+}
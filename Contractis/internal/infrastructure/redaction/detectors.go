@@ -0,0 +1,197 @@
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexDetector detecta un patrón y, opcionalmente, valida cada coincidencia (dígito verificador)
+type regexDetector struct {
+	kind     string
+	pattern  *regexp.Regexp
+	group    int // subgrupo a ocultar; 0 = coincidencia completa
+	validate func(value string) bool
+}
+
+func (d *regexDetector) Kind() string { return d.kind }
+
+func (d *regexDetector) Find(text string) []Match {
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[2*d.group], loc[2*d.group+1]
+		if start < 0 {
+			continue
+		}
+		if d.validate != nil && !d.validate(text[start:end]) {
+			continue
+		}
+		matches = append(matches, Match{Start: start, End: end})
+	}
+	return matches
+}
+
+// Tipos de dato detectados; dan nombre a los marcadores
+const (
+	KindEmail  = "EMAIL"
+	KindIBAN   = "IBAN"
+	KindID     = "ID"
+	KindTaxID  = "NIF"
+	KindPhone  = "TELEFONO"
+	KindPerson = "PERSONA"
+)
+
+// NewEmailDetector detecta direcciones de email
+func NewEmailDetector() Detector {
+	return &regexDetector{
+		kind:    KindEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+}
+
+// NewIBANDetector detecta IBAN (con o sin espacios cada 4 caracteres) validando el checksum mod 97
+func NewIBANDetector() Detector {
+	return &regexDetector{
+		kind:     KindIBAN,
+		pattern:  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		validate: validIBAN,
+	}
+}
+
+// NewSpanishIDDetector detecta DNI y NIE españoles validando la letra de control
+func NewSpanishIDDetector() Detector {
+	return &regexDetector{
+		kind:     KindID,
+		pattern:  regexp.MustCompile(`\b(?:\d{8}|[XYZ]\d{7})[ -]?[A-Z]\b`),
+		validate: validSpanishID,
+	}
+}
+
+// NewCUITDetector detecta CUIT/CUIL argentinos (XX-XXXXXXXX-X) validando el dígito verificador
+func NewCUITDetector() Detector {
+	return &regexDetector{
+		kind:     KindTaxID,
+		pattern:  regexp.MustCompile(`\b(?:20|23|24|27|30|33|34)-?\d{8}-?\d\b`),
+		validate: validCUIT,
+	}
+}
+
+// NewRUTDetector detecta RUT/RUN chilenos (12.345.678-5) validando el dígito verificador
+func NewRUTDetector() Detector {
+	return &regexDetector{
+		kind:     KindTaxID,
+		pattern:  regexp.MustCompile(`\b\d{1,2}\.?\d{3}\.?\d{3}-[\dkK]\b`),
+		validate: validRUT,
+	}
+}
+
+// NewRUCDetector detecta RUC de Perú (11 dígitos) y de Paraguay (base-DV), validando el módulo 11
+func NewRUCDetector() Detector {
+	return &regexDetector{
+		kind:     KindTaxID,
+		pattern:  regexp.MustCompile(`\b(?:(?:10|15|17|20)\d{9}|\d{5,8}-\d)\b`),
+		validate: func(value string) bool { return validPeruRUC(value) || validParaguayRUC(value) },
+	}
+}
+
+// NewLabeledIDDetector detecta números de documento precedidos de su etiqueta
+// ("DNI 12.345.678", "C.I. N° 1.234.567", "cédula 0912345678", "RUC: ..."). Cubre los
+// documentos sin dígito verificador (DNI argentino, CI paraguaya, cédulas).
+func NewLabeledIDDetector() Detector {
+	return &regexDetector{
+		kind: KindID,
+		pattern: regexp.MustCompile(`(?i)\b(?:D\.?N\.?I\.?|N\.?I\.?E\.?|N\.?I\.?F\.?|C\.?I\.?|C\.?U\.?I\.?[TL]\.?|R\.?U\.?[CT]\.?|C\.?C\.?|c[ée]dula(?: de identidad)?(?: civil)?|pasaporte|documento(?: de identidad| nacional de identidad)?)` +
+			`(?:\s*(?:n[°ºo]\.?|núm(?:ero)?\.?|nro\.?))?\s*[:.]?\s*([A-Z]?\d[\d.\- ]{4,14}\d[A-Z]?)\b`),
+		group: 1,
+	}
+}
+
+// NewPhoneDetector detecta teléfonos con prefijo internacional (+34, +54, +595…) o formatos
+// nacionales habituales. Exige al menos 8 dígitos para no confundir importes o fechas.
+func NewPhoneDetector() Detector {
+	return &regexDetector{
+		kind:    KindPhone,
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{1,4}\)[ .\-]?)?\d{2,4}(?:[ .\-]\d{2,4}){1,4}|\+\d{8,14}|\b[6789]\d{8}\b`),
+		validate: func(value string) bool {
+			digits := countDigits(value)
+			if digits < 8 || digits > 15 || datePattern.MatchString(value) {
+				return false
+			}
+			// Sin prefijo internacional ni paréntesis solo se aceptan grupos separados por espacio o guion;
+			// "1.234.567" es un importe, no un teléfono
+			if !strings.HasPrefix(value, "+") && !strings.HasPrefix(value, "(") && strings.Contains(value, ".") {
+				return false
+			}
+			return true
+		},
+	}
+}
+
+// datePattern descarta fechas con formato numérico que parecen teléfonos (12-05-2024)
+var datePattern = regexp.MustCompile(`^\d{1,2}[ ./\-]\d{1,2}[ ./\-]\d{2,4}$`)
+
+// NewPersonDetector detecta nombres propios precedidos de un tratamiento
+// ("Sr. Juan Pérez", "Doña María López"). Los nombres sin tratamiento no se detectan.
+func NewPersonDetector() Detector {
+	return &regexDetector{
+		kind:    KindPerson,
+		pattern: regexp.MustCompile(`\b(?:Sr\.|Sra\.|Srta\.|Don|Doña|D\.|Dña\.|Lic\.|Dr\.|Dra\.|Ing\.|Abog\.)\s+((?:[A-ZÁÉÍÓÚÑ][a-záéíóúñü]+|[A-ZÁÉÍÓÚÑ]{2,})(?:\s+(?:de\s+(?:la\s+|los\s+)?|del\s+)?(?:[A-ZÁÉÍÓÚÑ][a-záéíóúñü]+|[A-ZÁÉÍÓÚÑ]{2,})){0,4})`),
+		group:   1,
+	}
+}
+
+// DefaultDetectors retorna los detectores en orden de prioridad: los validados por checksum
+// primero, luego los identificados por etiqueta y por último los heurísticos
+func DefaultDetectors() []Detector {
+	return []Detector{
+		NewEmailDetector(),
+		NewIBANDetector(),
+		NewSpanishIDDetector(),
+		NewCUITDetector(),
+		NewRUTDetector(),
+		NewRUCDetector(),
+		NewLabeledIDDetector(),
+		NewPhoneDetector(),
+		NewPersonDetector(),
+	}
+}
+
+// detectorsByName asocia los nombres aceptados en la configuración con su constructor
+var detectorsByName = map[string]func() Detector{
+	"email":   NewEmailDetector,
+	"iban":    NewIBANDetector,
+	"dni":     NewSpanishIDDetector,
+	"cuit":    NewCUITDetector,
+	"rut":     NewRUTDetector,
+	"ruc":     NewRUCDetector,
+	"labeled": NewLabeledIDDetector,
+	"phone":   NewPhoneDetector,
+	"person":  NewPersonDetector,
+}
+
+// DetectorsByName construye los detectores indicados (por ejemplo "email,iban,phone"),
+// respetando el orden de prioridad de DefaultDetectors
+func DetectorsByName(names string) ([]Detector, error) {
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := detectorsByName[name]; !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q", name)
+		}
+		wanted[name] = true
+	}
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("no redaction detectors selected")
+	}
+
+	var detectors []Detector
+	for _, name := range []string{"email", "iban", "dni", "cuit", "rut", "ruc", "labeled", "phone", "person"} {
+		if wanted[name] {
+			detectors = append(detectors, detectorsByName[name]())
+		}
+	}
+	return detectors, nil
+}
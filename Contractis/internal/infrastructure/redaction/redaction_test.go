@@ -0,0 +1,133 @@
+package redaction
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) bool
+		value    string
+		want     bool
+	}{
+		{"IBAN ES", validIBAN, "ES9121000418450200051332", true},
+		{"IBAN ES with spaces", validIBAN, "ES91 2100 0418 4502 0005 1332", true},
+		{"IBAN GB", validIBAN, "GB82WEST12345698765432", true},
+		{"IBAN wrong check digits", validIBAN, "ES9221000418450200051332", false},
+		{"IBAN altered digit", validIBAN, "ES9121000418450200051333", false},
+		{"IBAN too short", validIBAN, "ES91210004", false},
+		{"IBAN lowercase", validIBAN, "es9121000418450200051332", false},
+
+		{"DNI", validSpanishID, "12345678Z", true},
+		{"DNI with hyphen", validSpanishID, "12345678-Z", true},
+		{"DNI wrong letter", validSpanishID, "12345678A", false},
+		{"NIE X", validSpanishID, "X1234567L", true},
+		{"NIE wrong letter", validSpanishID, "X1234567T", false},
+		{"NIE Y", validSpanishID, "Y1234567X", true},
+		{"DNI short", validSpanishID, "1234567Z", false},
+
+		{"CUIT", validCUIT, "20-12345678-6", true},
+		{"CUIT without hyphens", validCUIT, "20123456786", true},
+		{"CUIT wrong digit", validCUIT, "20-12345678-5", false},
+		{"CUIT short", validCUIT, "20-1234567-6", false},
+
+		{"RUT", validRUT, "12.345.678-5", true},
+		{"RUT without dots", validRUT, "12345678-5", true},
+		{"RUT wrong digit", validRUT, "12.345.678-4", false},
+		{"RUT K", validRUT, "10.000.013-K", true},
+		{"RUT lowercase k", validRUT, "10.000.013-k", true},
+		{"RUT missing digit", validRUT, "12.345.678", false},
+
+		{"RUC Peru", validPeruRUC, "20100070970", true},
+		{"RUC Peru wrong digit", validPeruRUC, "20100070971", false},
+		{"RUC Peru short", validPeruRUC, "2010007097", false},
+
+		{"RUC Paraguay", validParaguayRUC, "80012345-0", true},
+		{"RUC Paraguay wrong digit", validParaguayRUC, "80012345-1", false},
+		{"RUC Paraguay without DV", validParaguayRUC, "80012345", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.validate(tt.value); got != tt.want {
+				t.Errorf("validate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhoneDetector(t *testing.T) {
+	tests := []struct {
+		text string
+		want string // valor detectado; vacío si no debe detectarse
+	}{
+		{"llamar al +34 612 345 678", "+34 612 345 678"},
+		{"teléfono +595981123456", "+595981123456"},
+		{"tel. (011) 4555-1234", "(011) 4555-1234"},
+		{"móvil 612 345 678", "612 345 678"},
+		{"móvil 612345678", "612345678"},
+		{"contacto 0981-123-456", "0981-123-456"},
+
+		// Importes con separador de miles
+		{"un importe de 1.234.567 euros", ""},
+		{"Gs. 12.500.000", ""},
+		{"USD 1.250.000,00", ""},
+		// Fechas
+		{"firmado el 12-05-2024", ""},
+		{"firmado el 12/05/2024", ""},
+		{"firmado el 12.05.2024", ""},
+		{"vence el 01 06 2025", ""},
+		// Números cortos
+		{"cláusula 12 34", ""},
+	}
+	detector := NewPhoneDetector()
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var found []string
+			for _, m := range detector.Find(tt.text) {
+				found = append(found, tt.text[m.Start:m.End])
+			}
+			if tt.want == "" {
+				if len(found) > 0 {
+					t.Errorf("Find(%q) = %q, want no phone", tt.text, found)
+				}
+				return
+			}
+			if len(found) != 1 || found[0] != tt.want {
+				t.Errorf("Find(%q) = %q, want [%q]", tt.text, found, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactRoundTrip(t *testing.T) {
+	text := "El Sr. Juan Pérez (juan@example.com, DNI 12345678Z) autoriza el cargo en " +
+		"ES91 2100 0418 4502 0005 1332. Contacto: juan@example.com o +34 612 345 678. " +
+		"Importe: 1.234.567 euros, con fecha 12-05-2024."
+
+	redacted, redaction := NewRedactor(DefaultDetectors()...).Redact(text)
+
+	for _, value := range []string{"Juan Pérez", "juan@example.com", "12345678Z", "ES91 2100 0418 4502 0005 1332", "+34 612 345 678"} {
+		if strings.Contains(redacted, value) {
+			t.Errorf("redacted text still contains %q: %s", value, redacted)
+		}
+	}
+	for _, value := range []string{"1.234.567", "12-05-2024"} {
+		if !strings.Contains(redacted, value) {
+			t.Errorf("redacted text lost %q: %s", value, redacted)
+		}
+	}
+
+	// El mismo valor recibe el mismo marcador
+	if n := strings.Count(redacted, "[EMAIL_1]"); n != 2 {
+		t.Errorf("[EMAIL_1] appears %d times, want 2: %s", n, redacted)
+	}
+	if strings.Contains(redacted, "[EMAIL_2]") {
+		t.Errorf("a repeated email got a second placeholder: %s", redacted)
+	}
+
+	if restored := redaction.Restore(redacted); restored != text {
+		t.Errorf("Restore = %q, want the original text", restored)
+	}
+}
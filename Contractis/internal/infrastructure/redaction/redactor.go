@@ -0,0 +1,177 @@
+package redaction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// Match es una ocurrencia de un dato sensible en el texto (offsets en bytes)
+type Match struct {
+	Start int
+	End   int
+}
+
+// Detector encuentra un tipo de dato sensible. Kind da nombre al marcador ("EMAIL" → "[EMAIL_1]").
+type Detector interface {
+	Kind() string
+	Find(text string) []Match
+}
+
+// Redactor aplica una lista de detectores y reemplaza cada valor por un marcador estable:
+// el mismo valor recibe siempre el mismo marcador dentro de un documento.
+// Implementa services.Redactor.
+type Redactor struct {
+	detectors []Detector
+}
+
+// NewRedactor crea un Redactor. Ante coincidencias solapadas gana el detector que
+// aparece primero en la lista, y luego la coincidencia más larga.
+func NewRedactor(detectors ...Detector) *Redactor {
+	return &Redactor{detectors: detectors}
+}
+
+type kindMatch struct {
+	Match
+	kind     string
+	priority int
+}
+
+// Redact reemplaza los datos detectados y retorna el texto y el mapeo reversible
+func (r *Redactor) Redact(text string) (string, *entities.Redaction) {
+	redaction := entities.NewRedaction()
+
+	var matches []kindMatch
+	for priority, detector := range r.detectors {
+		for _, m := range detector.Find(text) {
+			if m.Start < m.End && m.Start >= 0 && m.End <= len(text) {
+				matches = append(matches, kindMatch{Match: m, kind: detector.Kind(), priority: priority})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return text, redaction
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].priority != matches[j].priority {
+			return matches[i].priority < matches[j].priority
+		}
+		return matches[i].End-matches[i].Start > matches[j].End-matches[j].Start
+	})
+
+	// Selección sin solapamientos respetando la prioridad
+	var selected []kindMatch
+	for _, candidate := range matches {
+		overlaps := false
+		for _, s := range selected {
+			if candidate.Start < s.End && s.Start < candidate.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			selected = append(selected, candidate)
+		}
+	}
+	selected = propagate(text, selected)
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Start < selected[j].Start })
+
+	placeholders := make(map[string]string) // kind + valor normalizado → marcador
+	next := make(map[string]int)
+
+	var out strings.Builder
+	out.Grow(len(text))
+	last := 0
+	for _, m := range selected {
+		original := text[m.Start:m.End]
+		key := m.kind + "\x00" + normalize(original)
+
+		placeholder, ok := placeholders[key]
+		if !ok {
+			next[m.kind]++
+			placeholder = fmt.Sprintf("[%s_%d]", m.kind, next[m.kind])
+			placeholders[key] = placeholder
+		}
+		redaction.Add(m.kind, placeholder, original)
+
+		out.WriteString(text[last:m.Start])
+		out.WriteString(placeholder)
+		last = m.End
+	}
+	out.WriteString(text[last:])
+
+	return out.String(), redaction
+}
+
+// propagate agrega las demás apariciones literales de cada valor detectado. Así un nombre
+// detectado por su tratamiento ("Sr. Juan Pérez") también se oculta cuando se repite sin él.
+// Los valores solo numéricos no se propagan: podrían coincidir con importes o fechas.
+func propagate(text string, selected []kindMatch) []kindMatch {
+	seen := make(map[string]bool)
+	result := selected
+	for _, m := range selected {
+		value := text[m.Start:m.End]
+		if len(value) < minPropagateLength || seen[value] || !strings.ContainsFunc(value, unicode.IsLetter) {
+			continue
+		}
+		seen[value] = true
+
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], value)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(value)
+			offset = end
+			if !isWordBoundary(text, start, end) {
+				continue
+			}
+
+			overlaps := false
+			for _, s := range result {
+				if start < s.End && s.Start < end {
+					overlaps = true
+					break
+				}
+			}
+			if !overlaps {
+				result = append(result, kindMatch{Match: Match{Start: start, End: end}, kind: m.kind, priority: m.priority})
+			}
+		}
+	}
+	return result
+}
+
+// minPropagateLength evita propagar valores cortos que aparecerían como parte de otras palabras
+const minPropagateLength = 4
+
+// isWordBoundary indica si text[start:end] no está pegado a letras o dígitos
+func isWordBoundary(text string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize unifica variantes de formato de un mismo valor (espacios, guiones, puntos, mayúsculas)
+func normalize(value string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '/', ' ':
+			return -1
+		}
+		return r
+	}, value))
+}
@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeySize es el tamaño de la clave maestra (AES-256)
+const KeySize = 32
+
+// sealedPrefix versiona el formato de los valores cifrados
+const sealedPrefix = "v1:"
+
+// ErrInvalidCiphertext indica un valor cifrado corrupto o sellado con otra clave
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Box cifra y descifra secretos cortos (API keys, claves de datos) con AES-256-GCM.
+// Las claves anteriores solo se usan para descifrar, durante una rotación de la clave maestra.
+type Box struct {
+	aead     cipher.AEAD
+	previous []cipher.AEAD
+}
+
+// NewBox crea un Box a partir de una clave de KeySize bytes y, opcionalmente, de las
+// claves anteriores con las que todavía puede haber valores sellados
+func NewBox(key []byte, previous ...[]byte) (*Box, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	box := &Box{aead: aead}
+	for i, old := range previous {
+		oldAEAD, err := newAEAD(old)
+		if err != nil {
+			return nil, fmt.Errorf("previous key %d: %w", i+1, err)
+		}
+		box.previous = append(box.previous, oldAEAD)
+	}
+	return box, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCM: %w", err)
+	}
+	return aead, nil
+}
+
+// Seal cifra el texto plano; un valor vacío se mantiene vacío
+func (b *Box) Seal(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open descifra un valor producido por Seal, con la clave actual o una anterior
+func (b *Box) Open(sealed string) (string, error) {
+	plaintext, _, err := b.open(sealed)
+	return plaintext, err
+}
+
+// NeedsReseal indica si el valor fue sellado con una clave anterior (o no se puede abrir)
+func (b *Box) NeedsReseal(sealed string) bool {
+	_, current, err := b.open(sealed)
+	return err == nil && !current
+}
+
+// open descifra el valor e indica si se usó la clave actual
+func (b *Box) open(sealed string) (string, bool, error) {
+	if sealed == "" {
+		return "", true, nil
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix) {
+		return "", false, ErrInvalidCiphertext
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil {
+		return "", false, ErrInvalidCiphertext
+	}
+
+	for i, aead := range append([]cipher.AEAD{b.aead}, b.previous...) {
+		if len(data) < aead.NonceSize() {
+			return "", false, ErrInvalidCiphertext
+		}
+		nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, ciphertext, nil); err == nil {
+			return string(plaintext), i == 0, nil
+		}
+	}
+	return "", false, ErrInvalidCiphertext
+}
+
+// DeriveKey deriva de la clave maestra una subclave independiente para el propósito dado,
+// para no reutilizar la misma clave en algoritmos distintos (cifrado, firma de tokens)
+func DeriveKey(masterKey []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("contractis:" + purpose))
+	return mac.Sum(nil)
+}
+
+// LoadMasterKey obtiene la clave maestra de la variable de entorno envVar (base64) o,
+// si no está definida, del archivo keyPath. Si el archivo no existe se genera una
+// clave aleatoria con permisos 0600 y se advierte en el log dónde quedó, porque sin
+// ella no se pueden descifrar los datos.
+func LoadMasterKey(envVar, keyPath string) ([]byte, error) {
+	if encoded := strings.TrimSpace(os.Getenv(envVar)); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be base64: %w", envVar, err)
+		}
+		return key, nil
+	}
+
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading master key file: %w", err)
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating master key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0o700); err != nil {
+		return nil, fmt.Errorf("error creating master key directory: %w", err)
+	}
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("error writing master key file: %w", err)
+	}
+	absPath, _ := filepath.Abs(keyPath)
+	slog.Warn("se generó una clave maestra nueva: respáldala junto a la base de datos, sin ella los datos cifrados no se pueden recuperar",
+		"path", absPath, "env", envVar)
+	return key, nil
+}
+
+// LoadPreviousMasterKeys lee de envVar las claves maestras anteriores (base64, separadas
+// por comas). Se usan durante una rotación para abrir lo sellado con la clave vieja.
+func LoadPreviousMasterKeys(envVar string) ([][]byte, error) {
+	var keys [][]byte
+	for _, encoded := range strings.Split(os.Getenv(envVar), ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be base64: %w", envVar, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,244 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// envelopePrefix identifica los valores cifrados con una clave de datos: "e1:<id>:<sellado>"
+const envelopePrefix = "e1:"
+
+// Envelope implementa cifrado de sobre: los datos se cifran con claves de datos (DEK)
+// aleatorias, y las DEK se guardan en la base envueltas con la clave maestra (KEK).
+// Rotar la clave maestra solo requiere volver a envolver las DEK; rotar la DEK no
+// requiere tocar los datos existentes, que se siguen descifrando con su clave.
+//
+// Los valores sin prefijo se consideran texto plano anterior al cifrado y se
+// retornan tal cual, para que las bases existentes sigan funcionando.
+type Envelope struct {
+	repo repositories.DataKeyRepository
+	kek  *Box
+
+	mu     sync.RWMutex
+	keys   map[int64]*Box
+	active int64
+
+	reloadMu sync.Mutex // serializa las recargas desde la base
+}
+
+// NewEnvelope carga las claves de datos. Las que estaban envueltas con una clave maestra
+// anterior se vuelven a envolver con la actual; si no hay ninguna clave se genera la primera.
+func NewEnvelope(ctx context.Context, repo repositories.DataKeyRepository, kek *Box) (*Envelope, error) {
+	e := &Envelope{
+		repo: repo,
+		kek:  kek,
+		keys: make(map[int64]*Box),
+	}
+
+	if err := e.load(ctx); err != nil {
+		return nil, err
+	}
+	if e.active == 0 {
+		if _, err := e.Rotate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// load lee las claves de datos de la base y reemplaza las cargadas en memoria
+func (e *Envelope) load(ctx context.Context) error {
+	stored, err := e.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[int64]*Box, len(stored))
+	var active int64
+	for _, key := range stored {
+		raw, err := e.kek.Open(key.WrappedKey)
+		if err != nil {
+			return fmt.Errorf("data key %d cannot be unwrapped with the master key: %w", key.ID, err)
+		}
+		if e.kek.NeedsReseal(key.WrappedKey) {
+			if err := e.rewrap(ctx, key.ID, raw); err != nil {
+				return err
+			}
+		}
+
+		box, err := NewBox([]byte(raw))
+		if err != nil {
+			return fmt.Errorf("data key %d: %w", key.ID, err)
+		}
+		keys[key.ID] = box
+		if key.Active {
+			active = key.ID
+		}
+	}
+
+	e.mu.Lock()
+	e.keys = keys
+	if active != 0 {
+		e.active = active
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// reload vuelve a leer las claves de datos cuando aparece un valor cifrado con una clave
+// desconocida: otro proceso (por ejemplo "contractis encryption rotate" con el servidor
+// en marcha) pudo haber creado una clave nueva y activarla
+func (e *Envelope) reload(id int64) (*Box, error) {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+
+	// Otra goroutine pudo haber recargado mientras se esperaba el lock
+	e.mu.RLock()
+	box := e.keys[id]
+	e.mu.RUnlock()
+	if box != nil {
+		return box, nil
+	}
+
+	if err := e.load(context.Background()); err != nil {
+		return nil, fmt.Errorf("error reloading data keys: %w", err)
+	}
+
+	e.mu.RLock()
+	box, active := e.keys[id], e.active
+	e.mu.RUnlock()
+	if box != nil {
+		slog.Info("claves de datos recargadas", "key_id", id, "active_key_id", active)
+	}
+	return box, nil
+}
+
+// Rotate genera una clave de datos nueva y la activa. Los valores existentes se siguen
+// descifrando con su clave hasta que se vuelvan a sellar.
+func (e *Envelope) Rotate(ctx context.Context) (int64, error) {
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return 0, fmt.Errorf("error generating data key: %w", err)
+	}
+	box, err := NewBox(raw)
+	if err != nil {
+		return 0, err
+	}
+	wrapped, err := e.kek.Seal(string(raw))
+	if err != nil {
+		return 0, fmt.Errorf("error wrapping data key: %w", err)
+	}
+
+	id, err := e.repo.Create(ctx, wrapped)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.Lock()
+	e.keys[id] = box
+	e.active = id
+	e.mu.Unlock()
+	return id, nil
+}
+
+// ActiveKeyID retorna el ID de la clave de datos con la que se cifra
+func (e *Envelope) ActiveKeyID() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.active
+}
+
+// Seal cifra el valor con la clave de datos activa; un valor vacío se mantiene vacío
+func (e *Envelope) Seal(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	e.mu.RLock()
+	id, box := e.active, e.keys[e.active]
+	e.mu.RUnlock()
+
+	sealed, err := box.Seal(plaintext)
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + strconv.FormatInt(id, 10) + ":" + sealed, nil
+}
+
+// Open descifra un valor producido por Seal. El texto plano sin prefijo se retorna tal cual.
+// Un valor cifrado con una clave que no está en memoria provoca una recarga de las claves.
+func (e *Envelope) Open(stored string) (string, error) {
+	id, sealed, ok := parseEnvelope(stored)
+	if !ok {
+		if strings.HasPrefix(stored, envelopePrefix) {
+			return "", ErrInvalidCiphertext
+		}
+		return stored, nil
+	}
+
+	e.mu.RLock()
+	box := e.keys[id]
+	e.mu.RUnlock()
+	if box == nil {
+		var err error
+		if box, err = e.reload(id); err != nil {
+			return "", err
+		}
+	}
+	if box == nil {
+		return "", fmt.Errorf("%w: unknown data key %d", ErrInvalidCiphertext, id)
+	}
+	return box.Open(sealed)
+}
+
+// NeedsReseal indica si el valor está en texto plano o cifrado con una clave de datos
+// que ya no es la activa
+func (e *Envelope) NeedsReseal(stored string) bool {
+	if stored == "" {
+		return false
+	}
+	id, _, ok := parseEnvelope(stored)
+	return !ok || id != e.ActiveKeyID()
+}
+
+// KeyID retorna la clave de datos con la que se cifró el valor; false si está en texto plano
+func (e *Envelope) KeyID(stored string) (int64, bool) {
+	id, _, ok := parseEnvelope(stored)
+	return id, ok
+}
+
+func (e *Envelope) rewrap(ctx context.Context, id int64, raw string) error {
+	wrapped, err := e.kek.Seal(raw)
+	if err != nil {
+		return fmt.Errorf("error rewrapping data key %d: %w", id, err)
+	}
+	return e.repo.Rewrap(ctx, id, wrapped)
+}
+
+// parseEnvelope separa el ID de la clave de datos del valor sellado
+func parseEnvelope(stored string) (int64, string, bool) {
+	rest, ok := strings.CutPrefix(stored, envelopePrefix)
+	if !ok {
+		return 0, "", false
+	}
+	idPart, sealed, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, "", false
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || !strings.HasPrefix(sealed, sealedPrefix) {
+		return 0, "", false
+	}
+	if _, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix)); err != nil {
+		return 0, "", false
+	}
+	return id, sealed, true
+}
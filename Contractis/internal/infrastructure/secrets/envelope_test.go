@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// memoryDataKeys implementa repositories.DataKeyRepository en memoria
+type memoryDataKeys struct {
+	mu   sync.Mutex
+	keys []*entities.DataKey
+}
+
+func (m *memoryDataKeys) Create(ctx context.Context, wrappedKey string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		key.Active = false
+	}
+	key := &entities.DataKey{ID: int64(len(m.keys) + 1), WrappedKey: wrappedKey, Active: true}
+	m.keys = append(m.keys, key)
+	return key.ID, nil
+}
+
+func (m *memoryDataKeys) List(ctx context.Context) ([]*entities.DataKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]*entities.DataKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		stored := *key
+		keys = append(keys, &stored)
+	}
+	return keys, nil
+}
+
+func (m *memoryDataKeys) Rewrap(ctx context.Context, id int64, wrappedKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[id-1].WrappedKey = wrappedKey
+	return nil
+}
+
+func newTestKEK(t *testing.T) *Box {
+	t.Helper()
+	kek, err := NewBox(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatalf("NewBox: %v", err)
+	}
+	return kek
+}
+
+func TestEnvelopeReloadsKeysRotatedByAnotherProcess(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryDataKeys{}
+	kek := newTestKEK(t)
+
+	// server y cli comparten la base, como el servidor y "contractis encryption rotate"
+	server, err := NewEnvelope(ctx, repo, kek)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	cli, err := NewEnvelope(ctx, repo, kek)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	sealedOld, err := server.Seal("viejo")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	rotated, err := cli.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	sealed, err := cli.Seal("análisis")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	opened, err := server.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open with a key rotated by another process: %v", err)
+	}
+	if opened != "análisis" {
+		t.Errorf("Open = %q", opened)
+	}
+	if server.ActiveKeyID() != rotated {
+		t.Errorf("active key = %d, want the rotated key %d", server.ActiveKeyID(), rotated)
+	}
+	if server.NeedsReseal(sealed) {
+		t.Error("a value sealed with the new active key should not need resealing")
+	}
+
+	// Los valores cifrados con la clave anterior se siguen leyendo
+	if opened, err := server.Open(sealedOld); err != nil || opened != "viejo" {
+		t.Errorf("Open(old key) = %q, %v", opened, err)
+	}
+}
+
+func TestEnvelopeUnknownKey(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryDataKeys{}
+	kek := newTestKEK(t)
+
+	envelope, err := NewEnvelope(ctx, repo, kek)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	sealed, _ := envelope.Seal("x")
+
+	// Una clave que no existe en la base sigue siendo un error tras recargar
+	forged := "e1:99:" + sealed[len("e1:1:"):]
+	if _, err := envelope.Open(forged); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("Open(unknown key) = %v, want ErrInvalidCiphertext", err)
+	}
+	if envelope.ActiveKeyID() != 1 {
+		t.Errorf("active key = %d, want 1", envelope.ActiveKeyID())
+	}
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryExporter guarda los spans exportados en memoria, para verificarlos sin un
+// collector (por ejemplo en las pruebas de contrato del cliente)
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter crea un exporter en memoria vacío
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export implementa Exporter
+func (e *InMemoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Spans retorna una copia de los spans exportados hasta ahora
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset descarta los spans exportados
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
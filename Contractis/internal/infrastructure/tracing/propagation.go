@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+// Extract agrega al contexto el span remoto indicado en un header traceparent (W3C Trace
+// Context), para que los spans creados con ese contexto continúen la traza del cliente.
+// Un header vacío o inválido se ignora.
+func Extract(ctx context.Context, traceparent string) context.Context {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	var sc spanContext
+	if _, err := hex.Decode(sc.traceID[:], []byte(parts[1])); err != nil || !sc.traceID.IsValid() {
+		return ctx
+	}
+	if _, err := hex.Decode(sc.spanID[:], []byte(parts[2])); err != nil || !sc.spanID.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// Traceparent retorna el header traceparent del span activo en ctx, vacío si no hay
+func Traceparent(ctx context.Context) string {
+	sc, ok := ctx.Value(spanContextKey{}).(spanContext)
+	if !ok {
+		return ""
+	}
+	return "00-" + sc.traceID.String() + "-" + sc.spanID.String() + "-01"
+}
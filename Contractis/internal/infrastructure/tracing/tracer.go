@@ -0,0 +1,282 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+const (
+	// batchSize es la cantidad de spans que dispara una exportación sin esperar al intervalo
+	batchSize = 256
+	// maxQueue es la cantidad de spans pendientes a partir de la cual se descartan
+	maxQueue = 4096
+	// flushInterval es cada cuánto se exportan los spans pendientes
+	flushInterval = 5 * time.Second
+)
+
+// SpanKind es el tipo de span de OpenTelemetry
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// TraceID identifica una traza (16 bytes)
+type TraceID [16]byte
+
+// SpanID identifica un span dentro de la traza (8 bytes)
+type SpanID [8]byte
+
+// String retorna el ID en hexadecimal
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// String retorna el ID en hexadecimal
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsValid indica si el ID no es todo ceros
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// IsValid indica si el ID no es todo ceros
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// SpanData es un span terminado, listo para exportar
+type SpanData struct {
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Name         string
+	Kind         SpanKind
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]any
+	// Error es el mensaje del error registrado; vacío si el span terminó bien
+	Error string
+}
+
+// Exporter envía los spans terminados a su destino
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Tracer crea spans y los exporta por lotes en segundo plano. Todos los métodos aceptan
+// un receptor nil, que crea spans que no registran nada, para que el tracing sea opcional.
+type Tracer struct {
+	exporter Exporter
+
+	mu      sync.Mutex
+	pending []SpanData
+	dropped int
+
+	flush     chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTracer crea un tracer que exporta con exporter
+func NewTracer(exporter Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		flush:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Start implementa services.Tracer: crea un span interno hijo del que haya en ctx
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, services.Span) {
+	return t.StartSpan(ctx, name, KindInternal)
+}
+
+// StartSpan crea un span del tipo indicado, hijo del que haya en ctx (local o remoto, ver
+// Extract); sin padre inicia una traza nueva
+func (t *Tracer) StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, services.Span) {
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			SpanID:     newSpanID(),
+			Name:       name,
+			Kind:       kind,
+			Start:      time.Now(),
+			Attributes: make(map[string]any),
+		},
+	}
+	if parent, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		s.data.TraceID = parent.traceID
+		s.data.ParentSpanID = parent.spanID
+	} else {
+		s.data.TraceID = newTraceID()
+	}
+	return context.WithValue(ctx, spanContextKey{}, spanContext{s.data.TraceID, s.data.SpanID}), s
+}
+
+// StartChild es como StartSpan pero solo crea el span si ctx ya pertenece a una traza;
+// sirve para operaciones frecuentes (consultas a la BD) que solo interesan dentro de otra
+func (t *Tracer) StartChild(ctx context.Context, name string, kind SpanKind) (context.Context, services.Span) {
+	if _, ok := ctx.Value(spanContextKey{}).(spanContext); !ok {
+		return ctx, noopSpan{}
+	}
+	return t.StartSpan(ctx, name, kind)
+}
+
+// ForceFlush exporta los spans pendientes
+func (t *Tracer) ForceFlush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.pending
+	dropped := t.dropped
+	t.pending, t.dropped = nil, 0
+	t.mu.Unlock()
+
+	if dropped > 0 {
+		slog.Warn("se descartaron spans: la cola de exportación estaba llena", "dropped", dropped)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	return t.exporter.Export(ctx, spans)
+}
+
+// Shutdown detiene la exportación en segundo plano y exporta los spans pendientes
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.closeOnce.Do(func() { close(t.done) })
+	select {
+	case <-t.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.ForceFlush(ctx)
+}
+
+func (t *Tracer) run() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		case <-t.flush:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		if err := t.ForceFlush(ctx); err != nil {
+			slog.Warn("error exportando spans", "error", err)
+		}
+		cancel()
+	}
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) >= maxQueue {
+		t.dropped++
+		return
+	}
+	t.pending = append(t.pending, data)
+	if len(t.pending) >= batchSize {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// span implementa services.Span
+type span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *span) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+}
+
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Error = err.Error()
+	}
+}
+
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.tracer.enqueue(data)
+}
+
+// noopSpan es el span de un tracer nil
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+// spanContext identifica el span activo de un contexto
+type spanContext struct {
+	traceID TraceID
+	spanID  SpanID
+}
+
+type spanContextKey struct{}
+
+// TraceIDFromContext retorna el ID de la traza del contexto, vacío si no hay
+func TraceIDFromContext(ctx context.Context) string {
+	if sc, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		return sc.traceID.String()
+	}
+	return ""
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,101 @@
+// Package webhooks entrega los eventos de Contractis a las URLs suscritas, firmados con
+// HMAC-SHA256
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// Cabeceras de cada entrega
+const (
+	HeaderEvent     = "X-Contractis-Event"
+	HeaderDelivery  = "X-Contractis-Delivery"
+	HeaderSignature = "X-Contractis-Signature"
+)
+
+// userAgent identifica las entregas de Contractis
+const userAgent = "Contractis-Webhooks/1.0"
+
+// ErrPrivateAddress indica que la URL del webhook resolvió a una dirección no pública
+var ErrPrivateAddress = errors.New("webhook destination is a private or local address")
+
+// Sender entrega los webhooks por HTTP. No sigue redirecciones: una respuesta 3xx cuenta
+// como fallo, para que un cambio de URL se configure explícitamente en el webhook.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender crea el cliente de entregas; el plazo de cada intento lo fija el contexto.
+// Salvo con allowPrivateNetworks, cada conexión se comprueba con la IP ya resuelta y se
+// rechazan las de loopback, redes privadas y link-local (ver entities.IsPrivateAddress):
+// validar solo la URL no alcanza, porque un nombre público puede resolver a una IP interna.
+func NewSender(allowPrivateNetworks bool) services.WebhookSender {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectPrivateAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// rejectPrivateAddress se ejecuta antes de cada conexión, con la dirección ya resuelta
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || entities.IsPrivateAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Send hace un POST del payload firmado con el secreto del webhook
+func (s *Sender) Send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, time.Now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, "", errors.New("no response before the delivery timeout")
+		}
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, entities.WebhookResponseLimit))
+	// Se descarta el resto (acotado) para poder reutilizar la conexión
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, string(bytes.ToValidUTF8(body, []byte("�"))), nil
+}
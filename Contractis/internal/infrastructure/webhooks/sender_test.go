@@ -0,0 +1,64 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+func TestRejectPrivateAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		private bool
+	}{
+		{"127.0.0.1:80", true},
+		{"10.1.2.3:443", true},
+		{"172.16.0.1:443", true},
+		{"192.168.1.10:8080", true},
+		{"169.254.169.254:80", true}, // metadata de las nubes
+		{"100.100.100.200:80", true}, // metadata en el espacio compartido 100.64/10
+		{"0.0.0.0:80", true},
+		{"[::1]:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd00::1]:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"8.8.8.8:443", false},
+		{"[2001:4860:4860::8888]:443", false},
+	}
+	for _, tt := range tests {
+		err := rejectPrivateAddress("tcp", tt.address, nil)
+		if got := errors.Is(err, ErrPrivateAddress); got != tt.private {
+			t.Errorf("rejectPrivateAddress(%s) = %v, want private %v", tt.address, err, tt.private)
+		}
+	}
+}
+
+// TestSenderPrivateNetworks entrega a un receptor en 127.0.0.1: se rechaza por defecto y
+// llega solo con allowPrivateNetworks
+func TestSenderPrivateNetworks(t *testing.T) {
+	var received int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	webhook := &entities.Webhook{ID: 1, URL: receiver.URL, Secret: "secreto"}
+	delivery := &entities.WebhookDelivery{ID: 1, Event: entities.EventContractDeleted, Payload: []byte(`{}`)}
+
+	if _, _, err := NewSender(false).Send(context.Background(), webhook, delivery); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Send to %s = %v, want ErrPrivateAddress", receiver.URL, err)
+	}
+	if received != 0 {
+		t.Fatalf("the receiver got %d requests from the default sender", received)
+	}
+
+	status, body, err := NewSender(true).Send(context.Background(), webhook, delivery)
+	if err != nil || status != http.StatusOK || body != "ok" {
+		t.Errorf("Send with private networks allowed = %d %q %v, want 200 \"ok\"", status, body, err)
+	}
+}
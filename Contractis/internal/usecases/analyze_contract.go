@@ -2,9 +2,12 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rodascaar/contractis/internal/domain/entities"
@@ -12,32 +15,111 @@ import (
 	"github.com/rodascaar/contractis/internal/domain/services"
 )
 
+// interruptedMessage es el error de un análisis cortado al detenerse el servidor: los
+// fragmentos ya analizados quedan en su checkpoint y se retoman al volver a subir el archivo
+const interruptedMessage = "análisis interrumpido al detenerse el servidor: vuelva a subir el archivo para retomarlo"
+
 // AnalyzeContractUseCase maneja el caso de uso de análisis de contratos
 type AnalyzeContractUseCase struct {
 	pdfRepo       repositories.PDFRepository
 	llmRepo       repositories.LLMRepository
 	contractRepo  repositories.ContractRepository
 	textProcessor services.TextProcessor
+	auditor       services.AuditLogger
+	redactor      services.Redactor
+	metrics       services.AnalysisMetrics
+	tracer        services.Tracer
+	events        services.EventPublisher
 }
 
-// NewAnalyzeContractUseCase crea una nueva instancia del caso de uso
+// NewAnalyzeContractUseCase crea una nueva instancia del caso de uso. Con redactor nil
+// el texto se envía sin ocultar datos personales, también a los proveedores online.
+// metrics, tracer y events son opcionales; events recibe analysis.completed y
+// analysis.failed.
 func NewAnalyzeContractUseCase(
 	pdfRepo repositories.PDFRepository,
 	llmRepo repositories.LLMRepository,
 	contractRepo repositories.ContractRepository,
 	textProcessor services.TextProcessor,
+	auditor services.AuditLogger,
+	redactor services.Redactor,
+	metrics services.AnalysisMetrics,
+	tracer services.Tracer,
+	events services.EventPublisher,
 ) *AnalyzeContractUseCase {
 	return &AnalyzeContractUseCase{
 		pdfRepo:       pdfRepo,
 		llmRepo:       llmRepo,
 		contractRepo:  contractRepo,
 		textProcessor: textProcessor,
+		auditor:       auditor,
+		redactor:      redactor,
+		metrics:       metrics,
+		tracer:        tracer,
+		events:        events,
 	}
 }
 
-// Execute ejecuta el análisis del contrato
+// Execute ejecuta el análisis del contrato y lo registra en el workspace indicado
 func (uc *AnalyzeContractUseCase) Execute(
 	ctx context.Context,
+	workspaceID int64,
+	pdfPath string,
+	filename string,
+	fileHash string,
+	fileSize int64,
+	config *entities.LLMConfig,
+) (*entities.AnalysisResult, error) {
+	ctx, span := uc.startSpan(ctx, "analysis")
+	defer span.End()
+	span.SetAttribute("contractis.workspace_id", workspaceID)
+	span.SetAttribute("contractis.file_size", fileSize)
+	span.SetAttribute("contractis.llm.type", config.Type)
+	span.SetAttribute("gen_ai.request.model", config.ModelName)
+
+	startTime := time.Now()
+	if uc.metrics != nil {
+		uc.metrics.AnalysisStarted()
+	}
+	result, err := uc.execute(ctx, workspaceID, pdfPath, filename, fileHash, fileSize, config)
+
+	status, chunks := entities.StatusCompleted, 0
+	switch {
+	case errors.Is(err, entities.ErrInterrupted):
+		status = entities.StatusInterrupted
+	case err != nil:
+		status = entities.StatusFailed
+	default:
+		chunks = result.ChunksCount
+		span.SetAttribute("contractis.contract_id", result.ContractID)
+		span.SetAttribute("contractis.chunks", chunks)
+	}
+	span.SetAttribute("contractis.analysis.status", string(status))
+	span.RecordError(err)
+	if uc.metrics != nil {
+		uc.metrics.AnalysisFinished(status, chunks, time.Since(startTime))
+	}
+	return result, err
+}
+
+// startSpan inicia un span de una etapa del análisis; sin tracer no registra nada
+func (uc *AnalyzeContractUseCase) startSpan(ctx context.Context, name string) (context.Context, services.Span) {
+	if uc.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return uc.tracer.Start(ctx, name)
+}
+
+// noopSpan es el span de un análisis sin tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+func (uc *AnalyzeContractUseCase) execute(
+	ctx context.Context,
+	workspaceID int64,
 	pdfPath string,
 	filename string,
 	fileHash string,
@@ -45,6 +127,8 @@ func (uc *AnalyzeContractUseCase) Execute(
 	config *entities.LLMConfig,
 ) (*entities.AnalysisResult, error) {
 	startTime := time.Now()
+	// El estado final se guarda aunque el análisis se cancele
+	store := context.WithoutCancel(ctx)
 
 	// Validar configuración
 	if err := config.Validate(); err != nil {
@@ -52,9 +136,9 @@ func (uc *AnalyzeContractUseCase) Execute(
 	}
 
 	// Verificar si ya existe registro para este archivo
-	existingRecord, err := uc.contractRepo.GetByHash(ctx, fileHash)
+	existingRecord, err := uc.contractRepo.GetByHash(ctx, workspaceID, fileHash)
 	if err != nil {
-		log.Printf("⚠️  Error verificando registro existente: %v", err)
+		slog.WarnContext(ctx, "error verificando registro existente", "error", err)
 		// Continuar con el análisis
 	}
 
@@ -65,10 +149,24 @@ func (uc *AnalyzeContractUseCase) Execute(
 		// Usar registro existente
 		record = existingRecord
 		recordID = existingRecord.ID
-		log.Printf("📝 Usando registro existente en BD (ID: %d, Status: %s)", recordID, existingRecord.Status)
+		ctx = entities.WithContractID(ctx, recordID)
+		store = context.WithoutCancel(ctx)
+		slog.InfoContext(ctx, "usando registro existente en BD", "status", existingRecord.Status)
+
+		// Volver a subir un contrato de la papelera lo restaura
+		if existingRecord.DeletedAt != nil {
+			if err := uc.contractRepo.Restore(ctx, workspaceID, recordID); err != nil {
+				slog.WarnContext(ctx, "error restaurando registro de la papelera", "error", err)
+			} else {
+				record.DeletedAt = nil
+				uc.auditor.Record(ctx, entities.AuditRestore, workspaceID, recordID, filename)
+			}
+		}
+		uc.auditor.Record(ctx, entities.AuditReanalyze, workspaceID, recordID, filename)
 	} else {
 		// Crear nuevo registro en BD
 		record = entities.NewContractRecord(
+			workspaceID,
 			filename,
 			fileHash,
 			fileSize,
@@ -79,53 +177,65 @@ func (uc *AnalyzeContractUseCase) Execute(
 
 		recordID, err = uc.contractRepo.Create(ctx, record)
 		if err != nil {
-			log.Printf("⚠️  Error creando registro en BD: %v", err)
+			slog.WarnContext(ctx, "error creando registro en BD", "error", err)
 			// Continuar con el análisis aunque falle la BD
 		} else {
 			record.ID = recordID
-			log.Printf("💾 Registro creado en BD (ID: %d)", recordID)
+			ctx = entities.WithContractID(ctx, recordID)
+			store = context.WithoutCancel(ctx)
+			slog.InfoContext(ctx, "registro creado en BD")
+			uc.auditor.Record(ctx, entities.AuditUpload, workspaceID, recordID, filename)
 		}
 	}
 
-	// Probar conexión con LLM
-	if err := uc.llmRepo.TestConnection(ctx, config); err != nil {
-		if record.ID > 0 {
-			record.MarkFailed(err.Error())
-			uc.contractRepo.Update(ctx, record)
-		}
-		return nil, fmt.Errorf("LLM connection test failed: %w", err)
+	// Probar conexión con LLM (recorriendo la cadena de failover)
+	chain := newLLMChain(uc.llmRepo, config)
+	testCtx, span := uc.startSpan(ctx, "analysis.test_connection")
+	err = chain.TestConnection(testCtx)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return nil, fmt.Errorf("LLM connection test failed: %w", uc.markFailed(ctx, record, err))
 	}
 
 	// Marcar como analizando
 	if record.ID > 0 {
 		record.MarkAnalyzing()
-		uc.contractRepo.Update(ctx, record)
+		uc.contractRepo.Update(store, record)
 	}
 
 	// Extraer texto del PDF
+	_, span = uc.startSpan(ctx, "pdf.extract")
 	content, err := uc.pdfRepo.ExtractText(pdfPath)
+	span.SetAttribute("contractis.document.chars", len(content))
+	span.RecordError(err)
+	span.End()
 	if err != nil {
-		if record.ID > 0 {
-			record.MarkFailed(err.Error())
-			uc.contractRepo.Update(ctx, record)
-		}
-		return nil, fmt.Errorf("failed to extract text: %w", err)
+		return nil, fmt.Errorf("failed to extract text: %w", uc.markFailed(ctx, record, err))
 	}
 
-	log.Printf("Iniciando análisis de documento (%d caracteres)", len(content))
+	slog.InfoContext(ctx, "iniciando análisis de documento", "chars", len(content))
+
+	// Ocultar datos personales antes de enviarlos a un proveedor de terceros
+	llmContent, redaction := uc.redact(ctx, content, config)
+	if redaction.Len() > 0 {
+		if sink := entities.TokenSinkFromContext(ctx); sink != nil {
+			restorer := newRestoringSink(sink, redaction)
+			defer restorer.Flush()
+			ctx = entities.WithTokenSink(ctx, restorer.Write)
+		}
+	}
 
 	// Generar respuesta con RAG
-	result, err := uc.generateResponseWithRAG(ctx, content, config)
+	result, err := uc.generateResponseWithRAG(ctx, chain, record, llmContent, config, redaction.Len() > 0)
+	record.PhaseModels = chain.PhaseModels()
 	if err != nil {
-		if record.ID > 0 {
-			record.MarkFailed(err.Error())
-			uc.contractRepo.Update(ctx, record)
-		}
-		return nil, fmt.Errorf("analysis failed: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", uc.markFailed(ctx, record, err))
 	}
+	result = redaction.Restore(result)
 
 	duration := time.Since(startTime)
-	log.Printf("Análisis completado en %.2f segundos", duration.Seconds())
+	slog.InfoContext(ctx, "análisis completado", "duration", duration)
 
 	// Calcular chunks para metadata
 	maxChunkSize := uc.calculateMaxChunkSize("")
@@ -133,30 +243,75 @@ func (uc *AnalyzeContractUseCase) Execute(
 
 	// Validar resultado antes de marcar como exitoso
 	if result == "" {
-		log.Printf("⚠️ Advertencia: El resultado del análisis está vacío, usando mensaje por defecto")
+		slog.WarnContext(ctx, "el resultado del análisis está vacío, se usa el mensaje por defecto")
 		result = "No se pudo generar un análisis válido del contrato. Es posible que el documento esté vacío, corrupto o el modelo de lenguaje no haya podido procesarlo correctamente."
 	}
 
 	// Guardar resultado en BD
 	if record.ID > 0 {
 		record.MarkCompleted(result, len(content), len(content)/entities.CharsPerToken, len(chunks), duration.Seconds())
-		if err := uc.contractRepo.Update(ctx, record); err != nil {
-			log.Printf("⚠️  Error actualizando registro en BD: %v", err)
+		if err := uc.contractRepo.Update(store, record); err != nil {
+			slog.WarnContext(ctx, "error actualizando registro en BD", "error", err)
+		} else if err := uc.contractRepo.SaveCheckpoint(store, workspaceID, record.ID, nil); err != nil {
+			slog.WarnContext(ctx, "error eliminando el checkpoint del registro", "error", err)
 		}
+		uc.publish(ctx, entities.EventAnalysisCompleted, record)
 	}
 
-	analysisResult := entities.NewAnalysisResult("")
+	var contractID string
+	if record.ID > 0 {
+		contractID = strconv.FormatInt(record.ID, 10)
+	}
+	analysisResult := entities.NewAnalysisResult(contractID)
 	analysisResult.MarkSuccess(result, duration, len(chunks))
 
 	return analysisResult, nil
 }
 
+// markFailed registra el error en el contrato y retorna el error a informar. Si el análisis
+// se canceló (el servidor se está deteniendo) queda interrumpido en vez de fallido, para
+// retomarlo desde su checkpoint, y el error envuelve ErrInterrupted.
+func (uc *AnalyzeContractUseCase) markFailed(ctx context.Context, record *entities.ContractRecord, err error) error {
+	interrupted := errors.Is(ctx.Err(), context.Canceled)
+	if interrupted {
+		err = fmt.Errorf("%w: %v", entities.ErrInterrupted, err)
+	}
+	if record.ID == 0 {
+		return err
+	}
+
+	if interrupted {
+		slog.InfoContext(ctx, "análisis interrumpido")
+		record.MarkInterrupted(interruptedMessage)
+	} else {
+		record.MarkFailed(err.Error())
+	}
+	uc.contractRepo.Update(context.WithoutCancel(ctx), record)
+	if !interrupted {
+		uc.publish(ctx, entities.EventAnalysisFailed, record)
+	}
+	return err
+}
+
+// publish notifica el resultado del análisis a los webhooks del workspace
+func (uc *AnalyzeContractUseCase) publish(ctx context.Context, event entities.WebhookEvent, record *entities.ContractRecord) {
+	if uc.events != nil {
+		uc.events.Publish(ctx, record.WorkspaceID, event, entities.NewAnalysisEvent(record))
+	}
+}
+
 func (uc *AnalyzeContractUseCase) generateResponseWithRAG(
 	ctx context.Context,
+	chain *llmChain,
+	record *entities.ContractRecord,
 	documentContent string,
 	llmConfig *entities.LLMConfig,
+	redacted bool,
 ) (string, error) {
 	systemPrompt := `Analiza contratos legales en español. Identifica: terminación unilateral, penalizaciones, jurisdicción, riesgos. Respuesta completa en español, sin emojis ni formato markdown.`
+	if redacted {
+		systemPrompt += ` Los datos personales del contrato se reemplazaron por marcadores como [PERSONA_1] o [EMAIL_1]: cítalos tal cual, sin modificarlos.`
+	}
 
 	// Determinar si procesar en una sola petición o por chunks
 	totalChars := len(documentContent)
@@ -164,51 +319,145 @@ func (uc *AnalyzeContractUseCase) generateResponseWithRAG(
 
 	// Para modelos online con mucho contexto, procesar en una sola petición si cabe
 	if llmConfig.IsOnline() && totalTokens < (entities.OnlineContextWindow-entities.SafetyMargin-entities.MaxOutputTokens) {
-		log.Printf("📄 Documento pequeño (%d tokens), procesando en una sola petición", totalTokens)
-		return uc.processSingleRequest(ctx, documentContent, systemPrompt, llmConfig)
+		slog.InfoContext(ctx, "documento pequeño, se procesa en una sola petición", "tokens", totalTokens)
+		ctx, span := uc.startSpan(ctx, "analysis.single")
+		defer span.End()
+		span.SetAttribute("gen_ai.usage.input_tokens", totalTokens)
+		result, err := uc.processSingleRequest(ctx, chain, documentContent, systemPrompt)
+		span.SetAttribute("gen_ai.usage.output_tokens", len(result)/entities.CharsPerToken)
+		span.RecordError(err)
+		return result, err
 	}
 
 	// Procesamiento por chunks para documentos grandes o modelos locales
-	log.Printf("📄 Documento grande (%d tokens), procesando por chunks", totalTokens)
+	slog.InfoContext(ctx, "documento grande, se procesa por chunks", "tokens", totalTokens)
 
 	// Calcular tamaño de chunk
 	maxChunkSize := uc.calculateMaxChunkSize(systemPrompt)
-	log.Printf("Tamaño de chunk calculado: %d caracteres (~%d tokens)",
-		maxChunkSize, maxChunkSize/entities.CharsPerToken)
+	slog.DebugContext(ctx, "tamaño de chunk calculado", "chars", maxChunkSize, "tokens", maxChunkSize/entities.CharsPerToken)
 
 	chunks := uc.textProcessor.SplitText(documentContent, maxChunkSize)
-	var analysisFragments []string
 
-	// FASE 1: Análisis por fragmento
-	userQuery := `Analiza este fragmento del contrato. Identifica terminación unilateral, penalizaciones (con montos), jurisdicción/arbitraje y riesgos principales. Respuesta en español, sin emojis.`
+	// FASE 1: Análisis por fragmento, retomando los que ya estén en el checkpoint
+	phaseCtx, span := uc.startSpan(ctx, "analysis.phase1")
+	span.SetAttribute("contractis.chunks", len(chunks))
+	checkpoint := uc.loadCheckpoint(phaseCtx, record, chunks)
+	span.SetAttribute("contractis.chunks.resumed", checkpoint.Len())
+	analysisFragments, err := uc.processChunks(phaseCtx, chain, chunks, systemPrompt, llmConfig, checkpoint)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return "", err
+	}
 
-	for i, chunk := range chunks {
-		fmt.Printf("📄 Procesando parte %d/%d (%d caracteres)...\n", i+1, len(chunks), len(chunk))
+	// FASE 2: Consolidación final
+	return uc.consolidateFragments(ctx, chain, analysisFragments, systemPrompt, llmConfig)
+}
 
-		prompt := fmt.Sprintf(
-			"Parte %d/%d del contrato:\n%s\n\nInstrucción: %s",
-			i+1, len(chunks), chunk, userQuery,
-		)
+// processChunks analiza cada chunk con un pool acotado de workers (ver LLMConfig.GetConcurrency).
+// Los fragmentos se retornan en el mismo orden que los chunks. Ante el primer error se
+// cancela el contexto compartido para que los workers restantes abandonen sus peticiones.
+// Los chunks ya presentes en el checkpoint no se vuelven a enviar y cada fragmento nuevo
+// se guarda en él apenas se obtiene.
+func (uc *AnalyzeContractUseCase) processChunks(
+	ctx context.Context,
+	chain *llmChain,
+	chunks []string,
+	systemPrompt string,
+	llmConfig *entities.LLMConfig,
+	checkpoint *analysisCheckpoint,
+) ([]string, error) {
+	userQuery := `Analiza este fragmento del contrato. Identifica terminación unilateral, penalizaciones (con montos), jurisdicción/arbitraje y riesgos principales. Respuesta en español, sin emojis.`
 
-		messages := []repositories.ChatMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: prompt},
+	analysisFragments := make([]string, len(chunks))
+	var pending []int
+	for i := range chunks {
+		if fragment, ok := checkpoint.Fragment(i); ok {
+			analysisFragments[i] = fragment
+			continue
 		}
+		pending = append(pending, i)
+	}
 
-		responseText, err := uc.llmRepo.SendChatRequest(ctx, llmConfig, messages, entities.Phase1MaxTokens)
-		if err != nil {
-			return "", fmt.Errorf("error processing part %d/%d: %w", i+1, len(chunks), err)
-		}
+	workers := llmConfig.GetConcurrency()
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+	slog.InfoContext(ctx, "fase 1", "chunks", len(chunks), "pending", len(pending), "workers", workers)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				chunk := chunks[i]
+				slog.DebugContext(ctx, "procesando parte", "part", i+1, "parts", len(chunks), "chars", len(chunk))
+
+				prompt := fmt.Sprintf(
+					"Parte %d/%d del contrato:\n%s\n\nInstrucción: %s",
+					i+1, len(chunks), chunk, userQuery,
+				)
+
+				messages := []repositories.ChatMessage{
+					{Role: "system", Content: systemPrompt},
+					{Role: "user", Content: prompt},
+				}
+
+				chunkCtx, span := uc.startSpan(ctx, "analysis.chunk")
+				span.SetAttribute("contractis.chunk.index", i+1)
+				span.SetAttribute("contractis.chunk.chars", len(chunk))
+				span.SetAttribute("gen_ai.usage.input_tokens", (len(systemPrompt)+len(prompt))/entities.CharsPerToken)
+				responseText, err := chain.SendChatRequest(chunkCtx, PhaseChunks, messages, entities.Phase1MaxTokens)
+				span.SetAttribute("gen_ai.usage.output_tokens", len(responseText)/entities.CharsPerToken)
+				span.RecordError(err)
+				span.End()
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("error processing part %d/%d: %w", i+1, len(chunks), err)
+						cancel()
+					})
+					continue
+				}
+
+				slog.DebugContext(ctx, "respuesta de la parte", "part", i+1, "parts", len(chunks), "chars", len(responseText))
+
+				responseText = uc.textProcessor.CleanFragment(responseText)
+				analysisFragments[i] = fmt.Sprintf("PARTE %d/%d:\n%s", i+1, len(chunks), responseText)
+				checkpoint.Save(ctx, i, analysisFragments[i])
+			}
+		}()
+	}
 
-		log.Printf("📄 Respuesta parte %d/%d: %d caracteres", i+1, len(chunks), len(responseText))
+dispatch:
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		responseText = uc.textProcessor.CleanFragment(responseText)
-		analysisFragments = append(analysisFragments,
-			fmt.Sprintf("PARTE %d/%d:\n%s", i+1, len(chunks), responseText))
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("phase 1 cancelled: %w", err)
 	}
 
-	// FASE 2: Consolidación final
-	return uc.consolidateFragments(ctx, analysisFragments, systemPrompt, llmConfig)
+	return analysisFragments, nil
 }
 
 func (uc *AnalyzeContractUseCase) calculateMaxChunkSize(systemPrompt string) int {
@@ -229,9 +478,9 @@ func (uc *AnalyzeContractUseCase) calculateMaxChunkSize(systemPrompt string) int
 
 func (uc *AnalyzeContractUseCase) processSingleRequest(
 	ctx context.Context,
+	chain *llmChain,
 	documentContent string,
 	systemPrompt string,
-	llmConfig *entities.LLMConfig,
 ) (string, error) {
 	userQuery := `Analiza este contrato completo. Identifica terminación unilateral, penalizaciones (con montos), jurisdicción/arbitraje y riesgos principales. Respuesta completa en español, sin emojis, sin formato markdown.`
 
@@ -251,23 +500,28 @@ func (uc *AnalyzeContractUseCase) processSingleRequest(
 		availableTokens = 800
 	}
 
-	log.Printf("Procesando documento completo en una petición - Tokens disponibles: %d", availableTokens)
+	slog.DebugContext(ctx, "procesando documento completo en una petición", "max_tokens", availableTokens)
 
-	responseText, err := uc.llmRepo.SendChatRequest(ctx, llmConfig, messages, availableTokens)
+	responseText, err := chain.StreamChatRequest(ctx, PhaseSingle, messages, availableTokens, entities.TokenSinkFromContext(ctx))
 	if err != nil {
 		return "", fmt.Errorf("error processing single request: %w", err)
 	}
 
-	log.Printf("📄 Respuesta documento completo: %d caracteres", len(responseText))
+	slog.DebugContext(ctx, "respuesta del documento completo", "chars", len(responseText))
 	return responseText, nil
 }
 
 func (uc *AnalyzeContractUseCase) consolidateFragments(
 	ctx context.Context,
+	chain *llmChain,
 	analysisFragments []string,
 	systemPrompt string,
 	llmConfig *entities.LLMConfig,
 ) (string, error) {
+	ctx, span := uc.startSpan(ctx, "analysis.consolidate")
+	defer span.End()
+	span.SetAttribute("contractis.fragments", len(analysisFragments))
+
 	consolidationPrompt := `Consolida estos fragmentos en un reporte final completo en español sobre: terminación unilateral, penalizaciones, jurisdicción y riesgos. Incluye todos los detalles importantes sin omitir información. Respuesta en español, sin emojis, sin formato markdown.`
 
 	// Limitar tamaño de fragmentos
@@ -282,11 +536,11 @@ func (uc *AnalyzeContractUseCase) consolidateFragments(
 
 	// Consolidación jerárquica si hay muchos fragmentos
 	if len(analysisFragments) > entities.MaxFragments {
-		analysisFragments = uc.hierarchicalConsolidation(analysisFragments, maxCharsPerFragment)
+		analysisFragments = uc.hierarchicalConsolidation(ctx, analysisFragments, maxCharsPerFragment)
 	}
 
 	// Construir prompt final
-	finalPrompt := uc.buildFinalPrompt(consolidationPrompt, analysisFragments)
+	finalPrompt := uc.buildFinalPrompt(ctx, consolidationPrompt, analysisFragments)
 
 	// Calcular tokens disponibles
 	estimatedInputTokens := len(finalPrompt) / entities.CharsPerToken
@@ -307,24 +561,34 @@ func (uc *AnalyzeContractUseCase) consolidateFragments(
 		availableTokens = entities.MaxOutputTokens
 	}
 
-	log.Printf("Tokens para consolidación - Input: ~%d, Output: %d", estimatedInputTokens, availableTokens)
+	slog.DebugContext(ctx, "tokens para consolidación", "input_tokens", estimatedInputTokens, "max_tokens", availableTokens)
+	span.SetAttribute("gen_ai.usage.input_tokens", estimatedInputTokens)
+	span.SetAttribute("gen_ai.request.max_tokens", availableTokens)
 
 	messages := []repositories.ChatMessage{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: finalPrompt},
 	}
 
-	finalResult, err := uc.llmRepo.SendChatRequest(ctx, llmConfig, messages, availableTokens)
+	// Streaming: evita timeouts por inactividad en consolidaciones largas y permite
+	// reenviar el reporte parcial al cliente
+	finalResult, err := chain.StreamChatRequest(ctx, PhaseConsolidation, messages, availableTokens, entities.TokenSinkFromContext(ctx))
+	span.SetAttribute("gen_ai.usage.output_tokens", len(finalResult)/entities.CharsPerToken)
 	if err != nil {
+		span.RecordError(err)
 		return "", fmt.Errorf("error in consolidation: %w", err)
 	}
 
-	log.Printf("📄 Resultado consolidación: %d caracteres", len(finalResult))
+	slog.DebugContext(ctx, "resultado de la consolidación", "chars", len(finalResult))
 	return finalResult, nil
 }
 
-func (uc *AnalyzeContractUseCase) hierarchicalConsolidation(fragments []string, maxCharsPerFragment int) []string {
-	log.Printf("Aplicando consolidación jerárquica: %d fragmentos -> grupos", len(fragments))
+func (uc *AnalyzeContractUseCase) hierarchicalConsolidation(ctx context.Context, fragments []string, maxCharsPerFragment int) []string {
+	_, span := uc.startSpan(ctx, "analysis.consolidate.hierarchical")
+	defer span.End()
+	span.SetAttribute("contractis.fragments", len(fragments))
+
+	slog.DebugContext(ctx, "aplicando consolidación jerárquica", "fragments", len(fragments))
 
 	groupSize := 3
 	var consolidatedGroups []string
@@ -347,18 +611,19 @@ func (uc *AnalyzeContractUseCase) hierarchicalConsolidation(fragments []string,
 			fmt.Sprintf("GRUPO %d-%d:\n%s", i+1, end, groupText))
 	}
 
-	log.Printf("Fragmentos después de consolidación jerárquica: %d", len(consolidatedGroups))
+	slog.DebugContext(ctx, "consolidación jerárquica terminada", "groups", len(consolidatedGroups))
+	span.SetAttribute("contractis.groups", len(consolidatedGroups))
 	return consolidatedGroups
 }
 
-func (uc *AnalyzeContractUseCase) buildFinalPrompt(consolidationPrompt string, fragments []string) string {
+func (uc *AnalyzeContractUseCase) buildFinalPrompt(ctx context.Context, consolidationPrompt string, fragments []string) string {
 	var combinedFragments strings.Builder
 	totalChars := len(consolidationPrompt)
 	maxTotalChars := entities.MaxInputTokens * entities.CharsPerToken
 
 	for i, fragment := range fragments {
 		if totalChars+len(fragment)+4 > maxTotalChars {
-			log.Printf("⚠️ Omitiendo fragmentos %d-%d por límite de tokens", i+1, len(fragments))
+			slog.WarnContext(ctx, "se omiten fragmentos por límite de tokens", "from", i+1, "to", len(fragments))
 			combinedFragments.WriteString("\n\n[Fragmentos adicionales omitidos por límite de tokens]")
 			break
 		}
@@ -373,13 +638,13 @@ func (uc *AnalyzeContractUseCase) buildFinalPrompt(consolidationPrompt string, f
 	// Verificación final de tamaño
 	estimatedPromptTokens := len(finalPrompt) / entities.CharsPerToken
 	if estimatedPromptTokens > entities.MaxInputTokens {
-		log.Printf("⚠️ Advertencia: Prompt muy grande (%d tokens), truncando...", estimatedPromptTokens)
+		slog.WarnContext(ctx, "prompt de consolidación muy grande, se trunca", "tokens", estimatedPromptTokens)
 		maxPromptChars := entities.MaxInputTokens * entities.CharsPerToken
 		if len(finalPrompt) > maxPromptChars {
 			finalPrompt = finalPrompt[:maxPromptChars] + "\n\n[Contenido truncado por límite de tokens]"
 		}
 	} else {
-		log.Printf("✅ Prompt de consolidación: %d tokens (dentro del límite)", estimatedPromptTokens)
+		slog.DebugContext(ctx, "prompt de consolidación dentro del límite", "tokens", estimatedPromptTokens)
 	}
 
 	return finalPrompt
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+func newChunkTest(llm *fakeLLM, concurrency int) (*AnalyzeContractUseCase, *llmChain, *entities.LLMConfig) {
+	config := entities.NewLLMConfig("local", "http://llm.test", "", "", "stub", 800)
+	config.Concurrency = concurrency
+	uc := &AnalyzeContractUseCase{llmRepo: llm, textProcessor: fakeText{}}
+	return uc, newLLMChain(llm, config), config
+}
+
+func TestProcessChunksBoundsConcurrencyAndKeepsOrder(t *testing.T) {
+	const workers = 3
+	var inFlight, peak atomic.Int32
+	llm := &fakeLLM{send: func(ctx context.Context, _ *entities.LLMConfig, messages []repositories.ChatMessage) (string, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		// Los chunks de menor índice tardan más, para que terminen fuera de orden
+		chunk := chunkOf(messages)
+		var index int
+		fmt.Sscanf(chunk, "chunk-%d", &index)
+		time.Sleep(time.Duration(12-index) * time.Millisecond)
+		return "análisis de " + chunk, nil
+	}}
+
+	chunks := make([]string, 12)
+	for i := range chunks {
+		chunks[i] = fmt.Sprintf("chunk-%d", i)
+	}
+
+	uc, chain, config := newChunkTest(llm, workers)
+	checkpoint := uc.loadCheckpoint(context.Background(), &entities.ContractRecord{}, chunks)
+	fragments, err := uc.processChunks(context.Background(), chain, chunks, "system", config, checkpoint)
+	if err != nil {
+		t.Fatalf("processChunks: %v", err)
+	}
+
+	if got := peak.Load(); got > workers {
+		t.Errorf("peak concurrency = %d, want at most %d", got, workers)
+	} else if got < 2 {
+		t.Errorf("peak concurrency = %d, chunks were not processed in parallel", got)
+	}
+	for i, fragment := range fragments {
+		want := fmt.Sprintf("PARTE %d/%d:\nanálisis de chunk-%d", i+1, len(chunks), i)
+		if fragment != want {
+			t.Errorf("fragment %d = %q, want %q", i, fragment, want)
+		}
+	}
+	if models := chain.PhaseModels()[PhaseChunks]; len(models) != 1 {
+		t.Errorf("phase models = %v, want the single endpoint", models)
+	}
+}
+
+func TestProcessChunksSkipsCheckpointedChunks(t *testing.T) {
+	var calls atomic.Int32
+	llm := &fakeLLM{send: func(_ context.Context, _ *entities.LLMConfig, messages []repositories.ChatMessage) (string, error) {
+		calls.Add(1)
+		return "nuevo " + chunkOf(messages), nil
+	}}
+	chunks := []string{"a", "b", "c"}
+
+	uc, chain, config := newChunkTest(llm, 2)
+	checkpoint := uc.loadCheckpoint(context.Background(), &entities.ContractRecord{}, chunks)
+	checkpoint.Save(context.Background(), 1, "PARTE 2/3:\nguardado")
+
+	fragments, err := uc.processChunks(context.Background(), chain, chunks, "system", config, checkpoint)
+	if err != nil {
+		t.Fatalf("processChunks: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("LLM calls = %d, want 2 (chunk 2 comes from the checkpoint)", calls.Load())
+	}
+	if fragments[1] != "PARTE 2/3:\nguardado" || !strings.HasSuffix(fragments[2], "nuevo c") {
+		t.Errorf("fragments = %q", fragments)
+	}
+	if checkpoint.Len() != 3 {
+		t.Errorf("checkpoint has %d fragments, want 3", checkpoint.Len())
+	}
+}
+
+func TestProcessChunksErrorCancelsSiblings(t *testing.T) {
+	failure := errors.New("boom")
+	var started, cancelled atomic.Int32
+	llm := &fakeLLM{send: func(ctx context.Context, _ *entities.LLMConfig, messages []repositories.ChatMessage) (string, error) {
+		started.Add(1)
+		if chunkOf(messages) == "chunk-2" {
+			return "", failure
+		}
+		// Los demás workers quedan esperando hasta que el error cancele el contexto
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return "", ctx.Err()
+		case <-time.After(5 * time.Second):
+			return "demasiado tarde", nil
+		}
+	}}
+
+	chunks := make([]string, 20)
+	for i := range chunks {
+		chunks[i] = fmt.Sprintf("chunk-%d", i)
+	}
+
+	uc, chain, config := newChunkTest(llm, 4)
+	checkpoint := uc.loadCheckpoint(context.Background(), &entities.ContractRecord{}, chunks)
+
+	start := time.Now()
+	_, err := uc.processChunks(context.Background(), chain, chunks, "system", config, checkpoint)
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want the failing chunk's error", err)
+	}
+	if !strings.Contains(err.Error(), "part 3/20") {
+		t.Errorf("err = %v, want it to name part 3/20", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("processChunks took %v, siblings were not cancelled", elapsed)
+	}
+	if cancelled.Load() == 0 {
+		t.Error("no sibling observed the cancellation")
+	}
+	if started.Load() >= int32(len(chunks)) {
+		t.Errorf("%d chunks were sent, pending chunks should not be dispatched after the error", started.Load())
+	}
+}
+
+// TestExecuteInterruptedResumesFromCheckpoint corta un análisis al vencer el plazo de
+// apagado: queda interrumpido con los chunks terminados en el checkpoint, y al volver a
+// subir el archivo solo se envían los que faltaban
+func TestExecuteInterruptedResumesFromCheckpoint(t *testing.T) {
+	const text = "chunk-0\nchunk-1\nchunk-2\nchunk-3\nchunk-4"
+	blocked := make(chan struct{})
+	var block atomic.Bool
+	block.Store(true)
+	var sent []string
+	llm := &fakeLLM{
+		send: func(ctx context.Context, _ *entities.LLMConfig, messages []repositories.ChatMessage) (string, error) {
+			// Como el cliente real, no envía nada con el contexto cancelado
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+			chunk := chunkOf(messages)
+			sent = append(sent, chunk)
+			if chunk == "chunk-2" && block.Load() {
+				close(blocked)
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return "análisis de " + chunk, nil
+		},
+		stream: func(context.Context, *entities.LLMConfig, []repositories.ChatMessage, func(string)) (string, error) {
+			return "reporte final", nil
+		},
+	}
+	contracts := newMemoryContracts()
+	uc := newTestAnalyzer(&fakePDF{text: text}, llm, contracts)
+	config := entities.NewLLMConfig("local", "http://llm.test", "", "", "stub", 800)
+	config.Concurrency = 1
+
+	tracker := NewJobTracker()
+	jobCtx, done, err := tracker.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make(chan error, 1)
+	go func() {
+		defer done()
+		_, err := uc.Execute(jobCtx, 1, "/tmp/alquiler.pdf", "alquiler.pdf", "hash-a", 1024, config)
+		result <- err
+	}()
+
+	<-blocked
+	if err := tracker.Shutdown(expired()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	if err := <-result; !errors.Is(err, entities.ErrInterrupted) {
+		t.Fatalf("Execute = %v, want ErrInterrupted", err)
+	}
+
+	record, err := contracts.GetByHash(context.Background(), 1, "hash-a")
+	if err != nil || record == nil {
+		t.Fatalf("GetByHash = %v, %v", record, err)
+	}
+	if record.Status != entities.StatusInterrupted {
+		t.Errorf("status = %s, want interrupted", record.Status)
+	}
+	checkpoint, _ := contracts.GetCheckpoint(context.Background(), 1, record.ID)
+	if checkpoint == nil || len(checkpoint.Fragments) != 2 {
+		t.Fatalf("checkpoint = %+v, want the 2 chunks finished before the interruption", checkpoint)
+	}
+
+	// Al volver a subirlo se retoma sobre el mismo contrato
+	block.Store(false)
+	sent = nil
+	if _, err := uc.Execute(context.Background(), 1, "/tmp/alquiler.pdf", "alquiler.pdf", "hash-a", 1024, config); err != nil {
+		t.Fatalf("resumed Execute: %v", err)
+	}
+	if want := []string{"chunk-2", "chunk-3", "chunk-4"}; strings.Join(sent, ",") != strings.Join(want, ",") {
+		t.Errorf("resumed analysis sent %v, want only %v", sent, want)
+	}
+	record, _ = contracts.GetByID(context.Background(), 1, record.ID)
+	if record.Status != entities.StatusCompleted || record.AnalysisResult != "reporte final" {
+		t.Errorf("resumed record = %s %q, want completed with the report", record.Status, record.AnalysisResult)
+	}
+	if checkpoint, _ := contracts.GetCheckpoint(context.Background(), 1, record.ID); checkpoint != nil {
+		t.Errorf("checkpoint %+v was kept after completing the analysis", checkpoint)
+	}
+}
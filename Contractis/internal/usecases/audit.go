@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// auditVerifyBatch es el tamaño de página al recorrer la cadena de auditoría
+const auditVerifyBatch = 500
+
+// AuditUseCase registra y consulta el log de auditoría de contratos
+type AuditUseCase struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewAuditUseCase crea una nueva instancia del caso de uso
+func NewAuditUseCase(auditRepo repositories.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record registra una acción con el actor y la IP del contexto. Implementa services.AuditLogger.
+func (uc *AuditUseCase) Record(ctx context.Context, action entities.AuditAction, workspaceID, contractID int64, details string) {
+	entry := &entities.AuditEntry{
+		Timestamp:   time.Now(),
+		Actor:       entities.AnonymousRequester,
+		WorkspaceID: workspaceID,
+		Action:      action,
+		ContractID:  contractID,
+		IP:          entities.ClientIPFromContext(ctx),
+		Details:     details,
+	}
+	if principal := entities.PrincipalFromContext(ctx); principal != nil {
+		entry.Actor = principal.Subject
+		entry.UserID = principal.UserID
+	}
+
+	// El registro no debe perderse porque la petición se haya cancelado
+	if err := uc.auditRepo.Append(context.WithoutCancel(ctx), entry); err != nil {
+		slog.ErrorContext(ctx, "error registrando auditoría", "action", action, "contract_id", contractID, "actor", entry.Actor, "error", err)
+	}
+}
+
+// List consulta el log de auditoría
+func (uc *AuditUseCase) List(ctx context.Context, filter entities.AuditFilter) ([]*entities.AuditEntry, error) {
+	return uc.auditRepo.List(ctx, filter)
+}
+
+// Verify recorre la cadena completa y comprueba que cada entrada apunte al hash de la
+// anterior y que su propio hash corresponda a su contenido
+func (uc *AuditUseCase) Verify(ctx context.Context) (*entities.AuditVerification, error) {
+	result := &entities.AuditVerification{Valid: true}
+	prevHash := entities.AuditGenesisHash
+	var afterID int64
+
+	for {
+		entries, err := uc.auditRepo.ListChain(ctx, afterID, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			switch {
+			case entry.PrevHash != prevHash:
+				result.Reason = "previous hash does not match the preceding entry"
+			case entry.ComputeHash() != entry.Hash:
+				result.Reason = "entry content does not match its hash"
+			}
+			if result.Reason != "" {
+				result.Valid = false
+				result.BrokenAt = entry.ID
+				slog.ErrorContext(ctx, "cadena de auditoría rota", "entry_id", entry.ID, "reason", result.Reason)
+				return result, nil
+			}
+
+			prevHash = entry.Hash
+			afterID = entry.ID
+			result.Entries++
+		}
+
+		if len(entries) < auditVerifyBatch {
+			return result, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("audit verification interrupted: %w", err)
+		}
+	}
+}
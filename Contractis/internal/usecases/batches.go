@@ -0,0 +1,282 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// BatchUseCase gestiona los lotes de documentos: los registra, los analiza en segundo
+// plano (cada contenido distinto una sola vez) y arma la exportación combinada
+type BatchUseCase struct {
+	analyzeUseCase *AnalyzeContractUseCase
+	batchRepo      repositories.BatchRepository
+	contractRepo   repositories.ContractRepository
+	auditor        services.AuditLogger
+	jobs           *JobTracker
+	limits         entities.Limits
+}
+
+// NewBatchUseCase crea una nueva instancia de BatchUseCase
+func NewBatchUseCase(
+	analyzeUseCase *AnalyzeContractUseCase,
+	batchRepo repositories.BatchRepository,
+	contractRepo repositories.ContractRepository,
+	auditor services.AuditLogger,
+	jobs *JobTracker,
+	limits entities.Limits,
+) *BatchUseCase {
+	return &BatchUseCase{
+		analyzeUseCase: analyzeUseCase,
+		batchRepo:      batchRepo,
+		contractRepo:   contractRepo,
+		auditor:        auditor,
+		jobs:           jobs,
+		limits:         limits,
+	}
+}
+
+// Start registra el lote y lanza el análisis de sus documentos pendientes (con Path y
+// FileHash), cuyos archivos están en dir; dir se elimina al terminar. Retorna el lote creado.
+func (uc *BatchUseCase) Start(
+	ctx context.Context,
+	workspaceID int64,
+	name string,
+	docs []*entities.BatchDocument,
+	dir string,
+	config *entities.LLMConfig,
+) (*entities.Batch, error) {
+	if len(docs) == 0 {
+		return nil, entities.ErrEmptyBatch
+	}
+
+	// El análisis sigue aunque termine la petición que creó el lote; al detenerse el
+	// servidor no se aceptan lotes nuevos
+	jobCtx, done, err := uc.jobs.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var pending []*entities.BatchDocument
+	for _, doc := range docs {
+		if doc.Status == entities.BatchDocPending {
+			pending = append(pending, doc)
+		} else {
+			doc.FinishedAt = &now
+		}
+	}
+
+	batch := &entities.Batch{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Status:      entities.BatchProcessing,
+		CreatedAt:   now,
+		Documents:   docs,
+	}
+	if principal := entities.PrincipalFromContext(ctx); principal != nil {
+		batch.CreatedBy = principal.Subject
+	}
+	if err := uc.batchRepo.Create(ctx, batch); err != nil {
+		done()
+		return nil, err
+	}
+	batch.ComputeStats()
+
+	uc.auditor.Record(ctx, entities.AuditBatchUpload, workspaceID, 0,
+		fmt.Sprintf("lote %d: %s (%d documentos, %d rechazados)", batch.ID, name, batch.Stats.Total, batch.Stats.Rejected))
+	slog.InfoContext(ctx, "lote creado", "batch_id", batch.ID, "name", name, "pending", len(pending), "rejected", batch.Stats.Rejected)
+
+	go func() {
+		defer done()
+		uc.process(jobCtx, batch.ID, workspaceID, pending, dir, config)
+	}()
+	return batch, nil
+}
+
+// process analiza los documentos del lote; los de igual hash comparten el análisis
+func (uc *BatchUseCase) process(
+	ctx context.Context,
+	batchID int64,
+	workspaceID int64,
+	docs []*entities.BatchDocument,
+	dir string,
+	config *entities.LLMConfig,
+) {
+	defer os.RemoveAll(dir)
+
+	var groups [][]*entities.BatchDocument
+	byHash := make(map[string]int)
+	for _, doc := range docs {
+		if i, ok := byHash[doc.FileHash]; ok {
+			groups[i] = append(groups[i], doc)
+			continue
+		}
+		byHash[doc.FileHash] = len(groups)
+		groups = append(groups, []*entities.BatchDocument{doc})
+	}
+
+	jobs := make(chan []*entities.BatchDocument)
+	var wg sync.WaitGroup
+	for i := 0; i < max(uc.limits.BatchConcurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				uc.analyzeGroup(ctx, workspaceID, group, config)
+			}
+		}()
+	}
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := uc.batchRepo.Finish(context.WithoutCancel(ctx), batchID); err != nil {
+		slog.ErrorContext(ctx, "error cerrando el lote", "batch_id", batchID, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "lote terminado", "batch_id", batchID)
+}
+
+// analyzeGroup analiza el primer documento del grupo y asigna su contrato a los duplicados
+func (uc *BatchUseCase) analyzeGroup(ctx context.Context, workspaceID int64, group []*entities.BatchDocument, config *entities.LLMConfig) {
+	primary := group[0]
+	if ctx.Err() != nil {
+		// El servidor se detuvo antes de empezar este documento
+		now := time.Now()
+		for _, doc := range group {
+			doc.Status = entities.BatchDocFailed
+			doc.Error = interruptedMessage
+			doc.FinishedAt = &now
+			uc.saveDocument(ctx, doc)
+		}
+		return
+	}
+
+	started := time.Now()
+	primary.Status = entities.BatchDocAnalyzing
+	primary.StartedAt = &started
+	uc.saveDocument(ctx, primary)
+
+	analysisCtx, cancel := context.WithTimeout(ctx, uc.limits.AnalysisTimeoutFor(config))
+	result, err := uc.analyzeUseCase.Execute(analysisCtx, workspaceID, primary.Path, path.Base(primary.Filename), primary.FileHash, primary.FileSize, config)
+	cancel()
+
+	finished := time.Now()
+	primary.FinishedAt = &finished
+	if err != nil {
+		slog.ErrorContext(ctx, "error analizando documento del lote", "batch_id", primary.BatchID, "file", primary.Filename, "error", err)
+		primary.Status = entities.BatchDocFailed
+		primary.Error = err.Error()
+		if errors.Is(ctx.Err(), context.Canceled) {
+			primary.Error = interruptedMessage
+		}
+	} else {
+		primary.Status = entities.BatchDocCompleted
+		primary.ContractID, _ = strconv.ParseInt(result.ContractID, 10, 64)
+	}
+	uc.saveDocument(ctx, primary)
+
+	for _, dup := range group[1:] {
+		dup.StartedAt = &finished
+		dup.FinishedAt = &finished
+		if primary.Status == entities.BatchDocCompleted {
+			dup.Status = entities.BatchDocDuplicate
+			dup.ContractID = primary.ContractID
+			dup.Error = ""
+		} else {
+			dup.Status = entities.BatchDocFailed
+			dup.Error = "duplicado de " + primary.Filename + ", que no pudo analizarse"
+		}
+		uc.saveDocument(ctx, dup)
+	}
+}
+
+// saveDocument guarda el estado del documento aunque ctx esté cancelado
+func (uc *BatchUseCase) saveDocument(ctx context.Context, doc *entities.BatchDocument) {
+	if err := uc.batchRepo.UpdateDocument(context.WithoutCancel(ctx), doc); err != nil {
+		slog.ErrorContext(ctx, "error guardando el estado del documento", "batch_id", doc.BatchID, "file", doc.Filename, "error", err)
+	}
+}
+
+// Get obtiene un lote con sus documentos y estadísticas
+func (uc *BatchUseCase) Get(ctx context.Context, workspaceID, id int64) (*entities.Batch, error) {
+	return uc.batchRepo.GetByID(ctx, workspaceID, id)
+}
+
+// List lista los lotes del workspace con sus estadísticas
+func (uc *BatchUseCase) List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.Batch, error) {
+	return uc.batchRepo.List(ctx, workspaceID, limit, offset)
+}
+
+// Export arma un único texto con el resumen del lote y el análisis de cada documento.
+// Los duplicados remiten al documento con el mismo contenido en vez de repetir el análisis.
+func (uc *BatchUseCase) Export(ctx context.Context, workspaceID, id int64) (*entities.Batch, string, error) {
+	batch, err := uc.batchRepo.GetByID(ctx, workspaceID, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Lote de Contratos: %s\n", batch.Name)
+	fmt.Fprintf(&b, "Creado: %s\n", batch.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if batch.Status != entities.BatchCompleted {
+		fmt.Fprintf(&b, "Estado: en proceso (%.0f%%)\n", batch.Stats.Progress)
+	}
+	fmt.Fprintf(&b, "Documentos: %d (analizados %d, duplicados %d, con error %d, rechazados %d)\n",
+		batch.Stats.Total, batch.Stats.Completed, batch.Stats.Duplicates, batch.Stats.Failed, batch.Stats.Rejected)
+
+	exported := make(map[int64]string)
+	for _, doc := range batch.Documents {
+		fmt.Fprintf(&b, "\n%s\n%d. %s\n%s\n\n", strings.Repeat("=", 72), doc.Position, doc.Filename, strings.Repeat("=", 72))
+
+		switch {
+		case doc.Status == entities.BatchDocPending || doc.Status == entities.BatchDocAnalyzing:
+			b.WriteString("Análisis en curso.\n")
+			continue
+		case doc.ContractID == 0:
+			fmt.Fprintf(&b, "Sin análisis: %s\n", doc.Error)
+			continue
+		}
+		if first, ok := exported[doc.ContractID]; ok {
+			fmt.Fprintf(&b, "Mismo contenido que %s: ver su análisis.\n", first)
+			continue
+		}
+
+		contract, err := uc.contractRepo.GetByID(ctx, workspaceID, doc.ContractID)
+		if err != nil || contract.Status != entities.StatusCompleted {
+			b.WriteString("El análisis ya no está disponible (contrato eliminado).\n")
+			continue
+		}
+		exported[doc.ContractID] = doc.Filename
+		b.WriteString(contract.ExportText())
+	}
+
+	uc.auditor.Record(ctx, entities.AuditBatchExport, workspaceID, 0, fmt.Sprintf("lote %d: %s", batch.ID, batch.Name))
+	return batch, b.String(), nil
+}
+
+// RecoverInterrupted cierra los lotes que quedaron a medias en una ejecución anterior
+func (uc *BatchUseCase) RecoverInterrupted(ctx context.Context) {
+	count, err := uc.batchRepo.FailInterrupted(ctx, "análisis interrumpido al detenerse el servidor")
+	if err != nil {
+		slog.ErrorContext(ctx, "error cerrando lotes interrumpidos", "error", err)
+		return
+	}
+	if count > 0 {
+		slog.WarnContext(ctx, "lotes interrumpidos por un reinicio quedaron cerrados", "count", count)
+	}
+}
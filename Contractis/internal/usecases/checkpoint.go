@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// analysisCheckpoint guarda en el contrato los fragmentos de la fase 1 a medida que se
+// completan, para que un análisis interrumpido o fallido se retome sin repetirlos
+type analysisCheckpoint struct {
+	repo   repositories.ContractRepository
+	record *entities.ContractRecord
+
+	mu    sync.Mutex
+	state entities.AnalysisCheckpoint
+}
+
+// loadCheckpoint obtiene el checkpoint del contrato si corresponde a los mismos chunks;
+// si no hay registro en BD el checkpoint solo vive en memoria
+func (uc *AnalyzeContractUseCase) loadCheckpoint(ctx context.Context, record *entities.ContractRecord, chunks []string) *analysisCheckpoint {
+	checkpoint := &analysisCheckpoint{
+		repo:   uc.contractRepo,
+		record: record,
+		state: entities.AnalysisCheckpoint{
+			Key:       checkpointKey(chunks),
+			Fragments: make(map[int]string),
+		},
+	}
+	if record.ID == 0 {
+		return checkpoint
+	}
+
+	saved, err := uc.contractRepo.GetCheckpoint(ctx, record.WorkspaceID, record.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "error leyendo el checkpoint", "error", err)
+		return checkpoint
+	}
+	if saved != nil && saved.Key == checkpoint.state.Key && len(saved.Fragments) > 0 {
+		checkpoint.state.Fragments = saved.Fragments
+		slog.InfoContext(ctx, "retomando análisis desde el checkpoint", "done", len(saved.Fragments), "parts", len(chunks))
+	}
+	return checkpoint
+}
+
+// Fragment retorna el fragmento ya analizado del chunk i
+func (c *analysisCheckpoint) Fragment(i int) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fragment, ok := c.state.Fragments[i]
+	return fragment, ok
+}
+
+// Len retorna la cantidad de fragmentos ya analizados
+func (c *analysisCheckpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.state.Fragments)
+}
+
+// Save agrega el fragmento del chunk i y persiste el checkpoint aunque ctx esté cancelado
+func (c *analysisCheckpoint) Save(ctx context.Context, i int, fragment string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Fragments[i] = fragment
+	if c.record.ID == 0 {
+		return
+	}
+	if err := c.repo.SaveCheckpoint(context.WithoutCancel(ctx), c.record.WorkspaceID, c.record.ID, &c.state); err != nil {
+		slog.WarnContext(ctx, "error guardando el checkpoint", "error", err)
+	}
+}
+
+// checkpointKey identifica la división del texto en chunks
+func checkpointKey(chunks []string) string {
+	h := sha256.New()
+	for _, chunk := range chunks {
+		h.Write([]byte(strconv.Itoa(len(chunk))))
+		h.Write([]byte{0})
+		h.Write([]byte(chunk))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
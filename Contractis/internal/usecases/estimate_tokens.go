@@ -2,7 +2,7 @@ package usecases
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/rodascaar/contractis/internal/domain/entities"
 	"github.com/rodascaar/contractis/internal/domain/repositories"
@@ -54,12 +54,12 @@ func (uc *EstimateTokensUseCase) estimateTokens(text string, maxTokensConfig int
 
 	// Para modelos online con mucho contexto, estimar procesamiento en una sola petición
 	if estimatedInputTokens < (entities.OnlineContextWindow - entities.SafetyMargin - entities.MaxOutputTokens) {
-		log.Printf("Estimando procesamiento en una sola petición para documento pequeño")
+		slog.Debug("estimando procesamiento en una sola petición", "tokens", estimatedInputTokens)
 		return uc.estimateSingleRequest(text, maxTokensConfig)
 	}
 
 	// Procesamiento por chunks para documentos grandes
-	log.Printf("Estimando procesamiento por chunks para documento grande")
+	slog.Debug("estimando procesamiento por chunks", "tokens", estimatedInputTokens)
 
 	// Calcular chunks
 	maxChunkSize := entities.DefaultChunkSize
@@ -0,0 +1,223 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// fakeLLM implementa repositories.LLMRepository delegando en funciones del test
+type fakeLLM struct {
+	send   func(ctx context.Context, config *entities.LLMConfig, messages []repositories.ChatMessage) (string, error)
+	stream func(ctx context.Context, config *entities.LLMConfig, messages []repositories.ChatMessage, onToken func(string)) (string, error)
+}
+
+func (f *fakeLLM) SendChatRequest(ctx context.Context, config *entities.LLMConfig, messages []repositories.ChatMessage, maxTokens int) (string, error) {
+	return f.send(ctx, config, messages)
+}
+
+func (f *fakeLLM) StreamChatRequest(ctx context.Context, config *entities.LLMConfig, messages []repositories.ChatMessage, maxTokens int, onToken func(string)) (string, error) {
+	if f.stream != nil {
+		return f.stream(ctx, config, messages, onToken)
+	}
+	response, err := f.send(ctx, config, messages)
+	if err == nil {
+		onToken(response)
+	}
+	return response, err
+}
+
+func (f *fakeLLM) TestConnection(ctx context.Context, config *entities.LLMConfig) error {
+	return nil
+}
+
+// fakeText implementa services.TextProcessor partiendo el texto en líneas
+type fakeText struct{}
+
+func (fakeText) SplitText(text string, maxSize int) []string { return strings.Split(text, "\n") }
+func (fakeText) CleanFragment(text string) string            { return strings.TrimSpace(text) }
+func (fakeText) EstimateTokens(text string, charsPerToken int) int {
+	return len(text) / charsPerToken
+}
+
+// chunkOf extrae el texto del chunk del prompt de fase 1 ("Parte i/n del contrato:\n<chunk>\n\n...")
+func chunkOf(messages []repositories.ChatMessage) string {
+	prompt := messages[len(messages)-1].Content
+	_, rest, _ := strings.Cut(prompt, "del contrato:\n")
+	chunk, _, _ := strings.Cut(rest, "\n\nInstrucción:")
+	return chunk
+}
+
+// fakePDF implementa repositories.PDFRepository con un texto fijo por archivo
+type fakePDF struct {
+	mu    sync.Mutex
+	text  string
+	calls map[string]int
+}
+
+func (f *fakePDF) ExtractText(pdfPath string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[pdfPath]++
+	return f.text, nil
+}
+
+func (f *fakePDF) total() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, calls := range f.calls {
+		n += calls
+	}
+	return n
+}
+
+// nopAuditor implementa services.AuditLogger sin registrar nada
+type nopAuditor struct{}
+
+func (nopAuditor) Record(context.Context, entities.AuditAction, int64, int64, string) {}
+
+// memoryContracts implementa repositories.ContractRepository en memoria, con copias de
+// los registros para que los cambios pasen siempre por Create y Update
+type memoryContracts struct {
+	mu          sync.Mutex
+	records     map[int64]*entities.ContractRecord
+	checkpoints map[int64]*entities.AnalysisCheckpoint
+	nextID      int64
+}
+
+func newMemoryContracts() *memoryContracts {
+	return &memoryContracts{
+		records:     make(map[int64]*entities.ContractRecord),
+		checkpoints: make(map[int64]*entities.AnalysisCheckpoint),
+	}
+}
+
+func (m *memoryContracts) Create(ctx context.Context, record *entities.ContractRecord) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	stored := *record
+	stored.ID = m.nextID
+	m.records[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (m *memoryContracts) get(workspaceID, id int64) (*entities.ContractRecord, bool) {
+	record, ok := m.records[id]
+	return record, ok && record.WorkspaceID == workspaceID
+}
+
+func (m *memoryContracts) GetByID(ctx context.Context, workspaceID, id int64) (*entities.ContractRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.get(workspaceID, id)
+	if !ok || record.DeletedAt != nil {
+		return nil, entities.ErrContractNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (m *memoryContracts) GetByHash(ctx context.Context, workspaceID int64, hash string) (*entities.ContractRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, record := range m.records {
+		if record.WorkspaceID == workspaceID && record.FileHash == hash {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryContracts) Update(ctx context.Context, record *entities.ContractRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.get(record.WorkspaceID, record.ID); !ok {
+		return entities.ErrContractNotFound
+	}
+	stored := *record
+	m.records[record.ID] = &stored
+	return nil
+}
+
+func (m *memoryContracts) List(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) Search(ctx context.Context, workspaceID int64, query string, limit, offset int) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) ListPage(ctx context.Context, workspaceID int64, query string, beforeID int64, limit int) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) GetStats(ctx context.Context, workspaceID int64) (*repositories.ContractStats, error) {
+	return &repositories.ContractStats{}, nil
+}
+
+func (m *memoryContracts) Delete(ctx context.Context, workspaceID, id int64) error {
+	return nil
+}
+
+func (m *memoryContracts) ListTrash(ctx context.Context, workspaceID int64, limit, offset int) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) Restore(ctx context.Context, workspaceID, id int64) error {
+	return nil
+}
+
+func (m *memoryContracts) Purge(ctx context.Context, workspaceID, id int64) error {
+	return nil
+}
+
+func (m *memoryContracts) SetLegalHold(ctx context.Context, workspaceID, id int64, hold bool, reason string) error {
+	return nil
+}
+
+func (m *memoryContracts) ListForRetention(ctx context.Context) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) GetRecent(ctx context.Context, workspaceID int64, limit int) ([]*entities.ContractRecord, error) {
+	return nil, nil
+}
+
+func (m *memoryContracts) SaveCheckpoint(ctx context.Context, workspaceID, id int64, checkpoint *entities.AnalysisCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if checkpoint == nil {
+		delete(m.checkpoints, id)
+		return nil
+	}
+	copied := &entities.AnalysisCheckpoint{Key: checkpoint.Key, Fragments: make(map[int]string, len(checkpoint.Fragments))}
+	for index, fragment := range checkpoint.Fragments {
+		copied.Fragments[index] = fragment
+	}
+	m.checkpoints[id] = copied
+	return nil
+}
+
+func (m *memoryContracts) GetCheckpoint(ctx context.Context, workspaceID, id int64) (*entities.AnalysisCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkpoints[id], nil
+}
+
+func (m *memoryContracts) InterruptStale(ctx context.Context, reason string) (int, error) {
+	return 0, nil
+}
+
+// newTestAnalyzer arma un AnalyzeContractUseCase con un PDF y un LLM falsos
+func newTestAnalyzer(pdf *fakePDF, llm *fakeLLM, contracts *memoryContracts) *AnalyzeContractUseCase {
+	return NewAnalyzeContractUseCase(pdf, llm, contracts, fakeText{}, nopAuditor{}, nil, nil, nil, nil)
+}
@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// HealthUseCase arma los reportes de liveness (el proceso responde) y readiness (sus
+// dependencias funcionan y puede recibir tráfico)
+type HealthUseCase struct {
+	build     entities.BuildInfo
+	jobs      *JobTracker
+	timeout   time.Duration
+	checks    []services.HealthCheck
+	startedAt time.Time
+}
+
+// NewHealthUseCase crea una nueva instancia de HealthUseCase. Cada chequeo de readiness
+// tiene hasta timeout para responder; jobs (opcional) indica si el servidor se está deteniendo.
+func NewHealthUseCase(build entities.BuildInfo, jobs *JobTracker, timeout time.Duration, checks ...services.HealthCheck) *HealthUseCase {
+	return &HealthUseCase{
+		build:     build,
+		jobs:      jobs,
+		timeout:   timeout,
+		checks:    checks,
+		startedAt: time.Now(),
+	}
+}
+
+// Liveness retorna el estado del proceso sin revisar sus dependencias
+func (uc *HealthUseCase) Liveness() *entities.HealthReport {
+	return uc.report(entities.HealthUp)
+}
+
+// Readiness revisa todos los componentes en paralelo. El servicio queda down si falla un
+// componente crítico o se está deteniendo, y degraded si solo fallan componentes no críticos.
+func (uc *HealthUseCase) Readiness(ctx context.Context) *entities.HealthReport {
+	components := make([]entities.ComponentHealth, len(uc.checks))
+	var wg sync.WaitGroup
+	for i, check := range uc.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = uc.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	if uc.jobs != nil {
+		server := entities.ComponentHealth{
+			Name:     "server",
+			Status:   entities.HealthUp,
+			Critical: true,
+			Details:  map[string]any{"activeJobs": uc.jobs.Active()},
+		}
+		if uc.jobs.Draining() {
+			server.Status = entities.HealthDown
+			server.Error = entities.ErrShuttingDown.Error()
+		}
+		components = append([]entities.ComponentHealth{server}, components...)
+	}
+
+	status := entities.HealthUp
+	for _, component := range components {
+		if component.Status == entities.HealthUp {
+			continue
+		}
+		if component.Critical {
+			status = entities.HealthDown
+			break
+		}
+		status = entities.HealthDegraded
+	}
+
+	report := uc.report(status)
+	report.Components = components
+	return report
+}
+
+// run ejecuta un chequeo con su plazo
+func (uc *HealthUseCase) run(ctx context.Context, check services.HealthCheck) entities.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check.Check(ctx)
+	component := entities.ComponentHealth{
+		Name:       check.Name(),
+		Status:     entities.HealthUp,
+		Critical:   check.Critical(),
+		DurationMs: time.Since(start).Milliseconds(),
+		Details:    details,
+	}
+	if err != nil {
+		component.Status = entities.HealthDown
+		component.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			component.Error = "no response within " + uc.timeout.String()
+		}
+	}
+	return component
+}
+
+func (uc *HealthUseCase) report(status entities.HealthStatus) *entities.HealthReport {
+	now := time.Now()
+	return &entities.HealthReport{
+		Status:        status,
+		Timestamp:     now.UTC(),
+		UptimeSeconds: int64(now.Sub(uc.startedAt).Seconds()),
+		Build:         uc.build,
+	}
+}
@@ -0,0 +1,220 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// IngestUseCase analiza en lote los documentos de una carpeta: deduplica por hash,
+// reutiliza los análisis ya completados y escribe un reporte por documento
+type IngestUseCase struct {
+	analyzeUseCase *AnalyzeContractUseCase
+	contractRepo   repositories.ContractRepository
+	reports        services.ReportWriter
+	limits         entities.Limits
+}
+
+// NewIngestUseCase crea una nueva instancia de IngestUseCase
+func NewIngestUseCase(
+	analyzeUseCase *AnalyzeContractUseCase,
+	contractRepo repositories.ContractRepository,
+	reports services.ReportWriter,
+	limits entities.Limits,
+) *IngestUseCase {
+	return &IngestUseCase{
+		analyzeUseCase: analyzeUseCase,
+		contractRepo:   contractRepo,
+		reports:        reports,
+		limits:         limits,
+	}
+}
+
+// ingestGroup son los documentos de una pasada con el mismo contenido; el primero se analiza
+type ingestGroup struct {
+	primary    entities.IngestDocument
+	duplicates []entities.IngestDocument
+	result     entities.IngestResult
+	record     *entities.ContractRecord
+}
+
+// Process analiza los documentos (con hash ya calculado) y retorna el resumen en el orden recibido
+func (uc *IngestUseCase) Process(
+	ctx context.Context,
+	docs []entities.IngestDocument,
+	config *entities.LLMConfig,
+	opts entities.IngestOptions,
+) *entities.IngestSummary {
+	var groups []*ingestGroup
+	byHash := make(map[string]*ingestGroup)
+	for _, doc := range docs {
+		if group, ok := byHash[doc.Hash]; ok && doc.Hash != "" {
+			group.duplicates = append(group.duplicates, doc)
+			continue
+		}
+		group := &ingestGroup{primary: doc}
+		groups = append(groups, group)
+		if doc.Hash != "" {
+			byHash[doc.Hash] = group
+		}
+	}
+
+	var pending []*ingestGroup
+	for _, group := range groups {
+		if uc.reuseExisting(ctx, group, opts) {
+			continue
+		}
+		pending = append(pending, group)
+	}
+
+	if len(pending) > 0 {
+		slog.InfoContext(ctx, "ingesta",
+			"pending", len(pending), "analyzed", len(groups)-len(pending), "duplicates", len(docs)-len(groups))
+	}
+	uc.analyzeAll(ctx, pending, config, opts)
+
+	summary := &entities.IngestSummary{}
+	for _, group := range groups {
+		summary.Add(group.result)
+		for _, dup := range group.duplicates {
+			summary.Add(uc.duplicateResult(dup, group))
+		}
+	}
+	return summary
+}
+
+// reuseExisting completa el grupo si ya hay un análisis completado para su hash
+func (uc *IngestUseCase) reuseExisting(ctx context.Context, group *ingestGroup, opts entities.IngestOptions) bool {
+	doc := group.primary
+	group.result = entities.IngestResult{Path: doc.Path, Hash: doc.Hash}
+	if doc.Hash == "" {
+		group.result.Status = entities.IngestFailed
+		group.result.Error = "no se pudo calcular el hash del archivo"
+		return true
+	}
+	if doc.Size > uc.limits.MaxFileSize {
+		group.result.Status = entities.IngestFailed
+		group.result.Error = fmt.Sprintf("archivo demasiado grande (máximo %dMB)", uc.limits.FileSizeMB())
+		return true
+	}
+	if opts.Force {
+		return false
+	}
+
+	existing, err := uc.contractRepo.GetByHash(ctx, opts.WorkspaceID, doc.Hash)
+	if err != nil || existing == nil || existing.Status != entities.StatusCompleted || existing.DeletedAt != nil {
+		return false
+	}
+
+	group.record = existing
+	group.result.Status = entities.IngestSkipped
+	group.result.ContractID = existing.ID
+	group.result.Report = uc.ensureReport(doc, existing, &group.result)
+	return true
+}
+
+// analyzeAll analiza los grupos pendientes con un pool de workers
+func (uc *IngestUseCase) analyzeAll(ctx context.Context, groups []*ingestGroup, config *entities.LLMConfig, opts entities.IngestOptions) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan *ingestGroup)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				uc.analyze(ctx, group, config, opts)
+			}
+		}()
+	}
+
+	for _, group := range groups {
+		if ctx.Err() != nil {
+			group.result.Status = entities.IngestFailed
+			group.result.Error = ctx.Err().Error()
+			continue
+		}
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func (uc *IngestUseCase) analyze(ctx context.Context, group *ingestGroup, config *entities.LLMConfig, opts entities.IngestOptions) {
+	doc := group.primary
+	slog.InfoContext(ctx, "analizando documento", "file", doc.RelPath)
+
+	analysisCtx, cancel := context.WithTimeout(ctx, uc.limits.AnalysisTimeoutFor(config))
+	result, err := uc.analyzeUseCase.Execute(analysisCtx, opts.WorkspaceID, doc.Path, filepath.Base(doc.Path), doc.Hash, doc.Size, config)
+	cancel()
+	if err != nil {
+		slog.ErrorContext(ctx, "error analizando documento", "file", doc.RelPath, "error", err)
+		group.result.Status = entities.IngestFailed
+		group.result.Error = err.Error()
+		return
+	}
+
+	group.result.Status = entities.IngestAnalyzed
+	if id, err := strconv.ParseInt(result.ContractID, 10, 64); err == nil {
+		group.result.ContractID = id
+		if record, err := uc.contractRepo.GetByID(ctx, opts.WorkspaceID, id); err == nil {
+			group.record = record
+		}
+	}
+	if group.record == nil {
+		// Sin registro en la base el reporte se arma con el resultado en memoria
+		group.record = entities.NewContractRecord(opts.WorkspaceID, filepath.Base(doc.Path), doc.Hash, doc.Size, config.Type, config.ModelName, config.MaxTokens)
+		group.record.MarkCompleted(result.Content, 0, 0, result.ChunksCount, result.DurationSec)
+	}
+
+	report, err := uc.reports.WriteReport(doc, group.record)
+	if err != nil {
+		slog.ErrorContext(ctx, "error escribiendo el reporte", "file", doc.RelPath, "error", err)
+		group.result.Status = entities.IngestFailed
+		group.result.Error = fmt.Sprintf("análisis completado pero no se pudo escribir el reporte: %v", err)
+		return
+	}
+	group.result.Report = report
+	slog.InfoContext(ctx, "documento analizado", "file", doc.RelPath, "report", report)
+}
+
+// duplicateResult escribe el reporte de un duplicado con el análisis de su grupo
+func (uc *IngestUseCase) duplicateResult(doc entities.IngestDocument, group *ingestGroup) entities.IngestResult {
+	result := entities.IngestResult{
+		Path:       doc.Path,
+		Hash:       doc.Hash,
+		Status:     entities.IngestDuplicate,
+		ContractID: group.result.ContractID,
+	}
+	if group.record == nil {
+		result.Status = entities.IngestFailed
+		result.Error = "duplicado de " + group.primary.RelPath + ", que no pudo analizarse"
+		return result
+	}
+	result.Report = uc.ensureReport(doc, group.record, &result)
+	return result
+}
+
+// ensureReport escribe el reporte si todavía no existe y retorna su ruta
+func (uc *IngestUseCase) ensureReport(doc entities.IngestDocument, record *entities.ContractRecord, result *entities.IngestResult) string {
+	if path, exists := uc.reports.ReportPath(doc); exists {
+		return path
+	}
+	path, err := uc.reports.WriteReport(doc, record)
+	if err != nil {
+		result.Status = entities.IngestFailed
+		result.Error = fmt.Sprintf("no se pudo escribir el reporte: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// memoryReports implementa services.ReportWriter guardando los reportes por ruta
+type memoryReports struct {
+	mu      sync.Mutex
+	reports map[string]int64 // ruta → contrato del reporte
+	writes  int
+}
+
+func (r *memoryReports) ReportPath(doc entities.IngestDocument) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := doc.Path + ".analisis.txt"
+	_, exists := r.reports[path]
+	return path, exists
+}
+
+func (r *memoryReports) WriteReport(doc entities.IngestDocument, record *entities.ContractRecord) (string, error) {
+	path, _ := r.ReportPath(doc)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports[path] = record.ID
+	r.writes++
+	return path, nil
+}
+
+type ingestTest struct {
+	uc        *IngestUseCase
+	pdf       *fakePDF
+	contracts *memoryContracts
+	reports   *memoryReports
+	config    *entities.LLMConfig
+}
+
+func newIngestTest() *ingestTest {
+	llm := &fakeLLM{send: func(context.Context, *entities.LLMConfig, []repositories.ChatMessage) (string, error) {
+		return "cláusulas revisadas", nil
+	}}
+	test := &ingestTest{
+		pdf:       &fakePDF{text: "El arrendatario pagará una penalidad.\nJurisdicción: Asunción."},
+		contracts: newMemoryContracts(),
+		reports:   &memoryReports{reports: make(map[string]int64)},
+		config:    entities.NewLLMConfig("local", "http://llm.test", "", "", "stub", 800),
+	}
+	test.uc = NewIngestUseCase(newTestAnalyzer(test.pdf, llm, test.contracts), test.contracts, test.reports, entities.DefaultLimits())
+	return test
+}
+
+func ingestDoc(path, hash string) entities.IngestDocument {
+	return entities.IngestDocument{Path: "/in/" + path, RelPath: path, Size: 1024, Hash: hash}
+}
+
+// TestIngestDedupesByHash analiza una sola vez cada contenido: los duplicados de la misma
+// pasada reciben el reporte del análisis de su grupo y una pasada posterior los omite
+func TestIngestDedupesByHash(t *testing.T) {
+	test := newIngestTest()
+	ctx := context.Background()
+	opts := entities.IngestOptions{WorkspaceID: 1, Concurrency: 2}
+	docs := []entities.IngestDocument{
+		ingestDoc("alquiler.pdf", "hash-a"),
+		ingestDoc("copias/alquiler (1).pdf", "hash-a"),
+		ingestDoc("servicios.pdf", "hash-b"),
+		ingestDoc("ilegible.pdf", ""),
+	}
+
+	summary := test.uc.Process(ctx, docs, test.config, opts)
+	if summary.Analyzed != 2 || summary.Duplicates != 1 || summary.Failed != 1 || summary.Skipped != 0 {
+		t.Fatalf("first pass = %+v, want 2 analyzed, 1 duplicate, 1 failed", summary)
+	}
+	if got := test.pdf.total(); got != 2 {
+		t.Errorf("extracted %d PDFs, want 2 (one per distinct hash)", got)
+	}
+	want := []entities.IngestStatus{entities.IngestAnalyzed, entities.IngestDuplicate, entities.IngestAnalyzed, entities.IngestFailed}
+	for i, result := range summary.Results {
+		if result.Status != want[i] {
+			t.Errorf("result %d (%s) = %s, want %s", i, result.Path, result.Status, want[i])
+		}
+	}
+	original, duplicate := summary.Results[0], summary.Results[1]
+	if original.ContractID == 0 || duplicate.ContractID != original.ContractID {
+		t.Errorf("duplicate contract = %d, want the original's %d", duplicate.ContractID, original.ContractID)
+	}
+	if duplicate.Report == "" || test.reports.reports[duplicate.Report] != original.ContractID {
+		t.Errorf("duplicate report %q was not written from contract %d", duplicate.Report, original.ContractID)
+	}
+
+	// Segunda pasada: los análisis completados se reutilizan sin volver al LLM
+	writes := test.reports.writes
+	summary = test.uc.Process(ctx, docs[:3], test.config, opts)
+	if summary.Skipped != 2 || summary.Duplicates != 1 || summary.Analyzed != 0 {
+		t.Errorf("second pass = %+v, want 2 skipped and 1 duplicate", summary)
+	}
+	if got := test.pdf.total(); got != 2 {
+		t.Errorf("second pass extracted %d PDFs in total, want still 2", got)
+	}
+	if test.reports.writes != writes {
+		t.Errorf("second pass rewrote %d existing reports", test.reports.writes-writes)
+	}
+}
+
+// TestIngestForce vuelve a analizar un documento ya analizado sobre el mismo contrato
+func TestIngestForce(t *testing.T) {
+	test := newIngestTest()
+	ctx := context.Background()
+	docs := []entities.IngestDocument{ingestDoc("alquiler.pdf", "hash-a")}
+
+	first := test.uc.Process(ctx, docs, test.config, entities.IngestOptions{WorkspaceID: 1})
+	forced := test.uc.Process(ctx, docs, test.config, entities.IngestOptions{WorkspaceID: 1, Force: true})
+
+	if forced.Analyzed != 1 || forced.Skipped != 0 {
+		t.Fatalf("forced pass = %+v, want the document analyzed again", forced)
+	}
+	if got := test.pdf.total(); got != 2 {
+		t.Errorf("extracted %d PDFs, want 2 (Force skips the completed analysis)", got)
+	}
+	if forced.Results[0].ContractID != first.Results[0].ContractID {
+		t.Errorf("forced analysis created contract %d, want the existing %d", forced.Results[0].ContractID, first.Results[0].ContractID)
+	}
+
+	// Otro workspace no ve el análisis y analiza el mismo contenido por su cuenta
+	other := test.uc.Process(ctx, docs, test.config, entities.IngestOptions{WorkspaceID: 2})
+	if other.Analyzed != 1 || other.Results[0].ContractID == first.Results[0].ContractID {
+		t.Errorf("workspace 2 pass = %+v, want a new analysis", other)
+	}
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// interruptGrace es cuánto se espera, tras cancelar los análisis, a que registren su estado
+const interruptGrace = 10 * time.Second
+
+// JobTracker lleva la cuenta de los análisis en curso (subidas, lotes, carpeta vigilada)
+// para que el servidor pueda detenerse sin cortarlos: al apagar deja de aceptar trabajos
+// nuevos, espera a los activos hasta un plazo y luego los cancela para que queden
+// marcados como interrumpidos.
+type JobTracker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	active   int
+	wg       sync.WaitGroup
+}
+
+// NewJobTracker crea una nueva instancia de JobTracker
+func NewJobTracker() *JobTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobTracker{ctx: ctx, cancel: cancel}
+}
+
+// Begin registra un trabajo nuevo. El contexto retornado conserva los valores de ctx
+// (identidad, IP, solicitante) pero no su cancelación: sigue aunque termine la petición
+// y solo se cancela al vencer el plazo de apagado. done debe llamarse al terminar.
+// Si el servidor se está deteniendo retorna ErrShuttingDown.
+func (t *JobTracker) Begin(ctx context.Context) (context.Context, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return nil, nil, entities.ErrShuttingDown
+	}
+	t.active++
+	t.wg.Add(1)
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			t.mu.Lock()
+			t.active--
+			t.mu.Unlock()
+			t.wg.Done()
+		})
+	}
+	return jobContext{Context: t.ctx, values: ctx}, done, nil
+}
+
+// Draining indica si el servidor dejó de aceptar trabajos
+func (t *JobTracker) Draining() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.draining
+}
+
+// Active retorna la cantidad de trabajos en curso
+func (t *JobTracker) Active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// Shutdown deja de aceptar trabajos y espera a los activos hasta que venza ctx; entonces
+// los cancela y espera (hasta interruptGrace) a que registren su estado. Retorna el error
+// de ctx si hubo que interrumpir trabajos.
+func (t *JobTracker) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	active := t.active
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	if active > 0 {
+		slog.Info("esperando análisis en curso", "active", active)
+	}
+	select {
+	case <-finished:
+		t.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	slog.Warn("plazo de apagado vencido, se interrumpen los análisis", "active", t.active)
+	t.mu.Unlock()
+	t.cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(interruptGrace):
+		slog.Warn("hay análisis que no terminaron tras cancelarlos")
+	}
+	return ctx.Err()
+}
+
+// jobContext toma la cancelación del tracker y los valores del contexto de origen
+type jobContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c jobContext) Value(key any) any {
+	return c.values.Value(key)
+}
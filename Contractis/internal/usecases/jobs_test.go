@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// expired retorna un contexto cuyo plazo ya venció
+func expired() context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	cancel()
+	return ctx
+}
+
+func TestJobTrackerWaitsForJobsAndRejectsNewOnes(t *testing.T) {
+	tracker := NewJobTracker()
+	jobCtx, done, err := tracker.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if tracker.Active() != 1 {
+		t.Errorf("Active = %d, want 1", tracker.Active())
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdown <- tracker.Shutdown(ctx)
+	}()
+
+	// Mientras espera al trabajo activo no acepta trabajos nuevos
+	deadline := time.Now().Add(5 * time.Second)
+	for !tracker.Draining() {
+		if time.Now().After(deadline) {
+			t.Fatal("the tracker did not start draining")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, _, err := tracker.Begin(context.Background()); !errors.Is(err, entities.ErrShuttingDown) {
+		t.Errorf("Begin while draining = %v, want ErrShuttingDown", err)
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned %v before the active job finished", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if jobCtx.Err() != nil {
+		t.Errorf("job context was cancelled before the deadline: %v", jobCtx.Err())
+	}
+
+	done()
+	done() // llamar done dos veces no descuenta otro trabajo
+	select {
+	case err := <-shutdown:
+		if err != nil {
+			t.Errorf("Shutdown = %v, want nil once the job finished", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the job finished")
+	}
+	if tracker.Active() != 0 {
+		t.Errorf("Active = %d after done, want 0", tracker.Active())
+	}
+}
+
+type jobKey struct{}
+
+// TestJobTrackerDeadlineCancelsJobs cancela los trabajos que siguen activos al vencer el
+// plazo; hasta entonces su contexto no depende del de la petición que los inició
+func TestJobTrackerDeadlineCancelsJobs(t *testing.T) {
+	tracker := NewJobTracker()
+	request, cancelRequest := context.WithCancel(context.WithValue(context.Background(), jobKey{}, "ana"))
+	jobCtx, done, err := tracker.Begin(request)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	cancelRequest()
+	if jobCtx.Err() != nil {
+		t.Fatalf("job context followed the request cancellation: %v", jobCtx.Err())
+	}
+	if got := jobCtx.Value(jobKey{}); got != "ana" {
+		t.Errorf("job context value = %v, want the request's", got)
+	}
+
+	// El trabajo registra su estado al ver la cancelación
+	stopped := make(chan error, 1)
+	go func() {
+		defer done()
+		<-jobCtx.Done()
+		stopped <- jobCtx.Err()
+	}()
+
+	if err := tracker.Shutdown(expired()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("job context error = %v, want Canceled", err)
+		}
+	default:
+		t.Fatal("Shutdown returned before the cancelled job finished")
+	}
+	if tracker.Active() != 0 {
+		t.Errorf("Active = %d, want 0", tracker.Active())
+	}
+}
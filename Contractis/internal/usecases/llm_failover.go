@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// Fases del análisis registradas en ContractRecord.PhaseModels
+const (
+	PhaseSingle        = "single"
+	PhaseChunks        = "phase1"
+	PhaseConsolidation = "consolidation"
+)
+
+// llmChain recorre la cadena de failover de una configuración LLM durante un análisis
+// y registra qué modelo y endpoint respondió en cada fase. Es seguro para uso concurrente.
+type llmChain struct {
+	llmRepo    repositories.LLMRepository
+	primary    *entities.LLMConfig
+	candidates []*entities.LLMConfig
+
+	mu     sync.Mutex
+	start  int // primer candidato a probar; avanza cuando un endpoint no es alcanzable
+	models map[string][]string
+}
+
+func newLLMChain(llmRepo repositories.LLMRepository, config *entities.LLMConfig) *llmChain {
+	return &llmChain{
+		llmRepo:    llmRepo,
+		primary:    config,
+		candidates: config.Candidates(),
+		models:     make(map[string][]string),
+	}
+}
+
+// TestConnection prueba los candidatos en orden hasta encontrar uno disponible
+func (c *llmChain) TestConnection(ctx context.Context) error {
+	var lastErr error
+	for i := c.firstCandidate(); i < len(c.candidates); i++ {
+		candidate := c.candidates[i]
+
+		err := c.llmRepo.TestConnection(ctx, candidate)
+		if err == nil {
+			c.advanceTo(i)
+			return nil
+		}
+
+		lastErr = err
+		if !c.primary.ShouldFailover(err) {
+			return err
+		}
+		slog.WarnContext(ctx, "endpoint no disponible, se prueba el siguiente",
+			"endpoint", candidate.GetEndpointURL(), "model", candidate.ModelName, "error", err)
+	}
+	return lastErr
+}
+
+// SendChatRequest envía la petición al primer candidato disponible y registra el candidato usado en la fase
+func (c *llmChain) SendChatRequest(
+	ctx context.Context,
+	phase string,
+	messages []repositories.ChatMessage,
+	maxTokens int,
+) (string, error) {
+	return c.send(ctx, phase, messages, maxTokens, nil)
+}
+
+// StreamChatRequest es como SendChatRequest pero en streaming, reenviando los tokens a onToken.
+// Solo hay failover si el candidato falló antes de emitir algún token.
+func (c *llmChain) StreamChatRequest(
+	ctx context.Context,
+	phase string,
+	messages []repositories.ChatMessage,
+	maxTokens int,
+	onToken func(token string),
+) (string, error) {
+	if onToken == nil {
+		onToken = func(string) {}
+	}
+	return c.send(ctx, phase, messages, maxTokens, onToken)
+}
+
+func (c *llmChain) send(
+	ctx context.Context,
+	phase string,
+	messages []repositories.ChatMessage,
+	maxTokens int,
+	onToken func(token string),
+) (string, error) {
+	var lastErr error
+	for i := c.firstCandidate(); i < len(c.candidates); i++ {
+		candidate := c.candidates[i]
+
+		var (
+			response string
+			err      error
+			emitted  bool
+		)
+		if onToken != nil {
+			response, err = c.llmRepo.StreamChatRequest(ctx, candidate, messages, maxTokens, func(token string) {
+				emitted = true
+				onToken(token)
+			})
+		} else {
+			response, err = c.llmRepo.SendChatRequest(ctx, candidate, messages, maxTokens)
+		}
+		if err == nil {
+			c.recordModel(phase, candidate)
+			return response, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || emitted || !c.primary.ShouldFailover(err) {
+			return "", err
+		}
+		if i+1 < len(c.candidates) {
+			slog.WarnContext(ctx, "failover",
+				"phase", phase, "endpoint", candidate.GetEndpointURL(), "model", candidate.ModelName,
+				"next_endpoint", c.candidates[i+1].GetEndpointURL(), "next_model", c.candidates[i+1].ModelName, "error", err)
+		}
+
+		// Un endpoint caído no se vuelve a intentar en el resto del análisis;
+		// timeouts y rate limits son transitorios y se reintentan en la próxima petición
+		if errors.Is(err, entities.ErrLLMConnectionFailed) {
+			c.advanceTo(i + 1)
+		}
+	}
+	return "", fmt.Errorf("todos los endpoints LLM fallaron: %w", lastErr)
+}
+
+// PhaseModels retorna una copia del registro de candidatos por fase ("modelo@endpoint")
+func (c *llmChain) PhaseModels() map[string][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	models := make(map[string][]string, len(c.models))
+	for phase, names := range c.models {
+		models[phase] = append([]string(nil), names...)
+	}
+	return models
+}
+
+func (c *llmChain) firstCandidate() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.start
+}
+
+func (c *llmChain) advanceTo(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if i > c.start && i < len(c.candidates) {
+		c.start = i
+	}
+}
+
+// recordModel registra el candidato por modelo y endpoint: el mismo modelo servido por
+// dos endpoints de la cadena cuenta como dos entradas
+func (c *llmChain) recordModel(phase string, candidate *entities.LLMConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	label := candidate.Label()
+	for _, recorded := range c.models[phase] {
+		if recorded == label {
+			return
+		}
+	}
+	c.models[phase] = append(c.models[phase], label)
+}
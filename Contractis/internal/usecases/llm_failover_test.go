@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+func TestLLMChainRecordsEndpointAndModel(t *testing.T) {
+	// El mismo modelo servido por dos endpoints: el primero está caído
+	config := entities.NewLLMConfig("local", "http://gpu-1.test/v1/chat/completions", "", "", "qwen3-4b", 800)
+	config.Fallbacks = []*entities.LLMConfig{
+		entities.NewLLMConfig("local", "http://gpu-2.test/v1/chat/completions?key=secret", "", "", "qwen3-4b", 0),
+	}
+
+	llm := &fakeLLM{send: func(_ context.Context, candidate *entities.LLMConfig, _ []repositories.ChatMessage) (string, error) {
+		if candidate.LocalUrl == config.LocalUrl {
+			return "", fmt.Errorf("connection refused (%w)", entities.ErrLLMConnectionFailed)
+		}
+		return "ok", nil
+	}}
+
+	chain := newLLMChain(llm, config)
+	for range 2 {
+		if _, err := chain.SendChatRequest(context.Background(), PhaseChunks, nil, 100); err != nil {
+			t.Fatalf("SendChatRequest: %v", err)
+		}
+	}
+
+	want := []string{"qwen3-4b@gpu-2.test/v1/chat/completions"}
+	if got := chain.PhaseModels()[PhaseChunks]; !slices.Equal(got, want) {
+		t.Errorf("phase models = %v, want %v", got, want)
+	}
+}
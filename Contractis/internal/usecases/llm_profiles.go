@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// LLMProfilesUseCase gestiona los perfiles LLM guardados en el servidor. Cada perfil es de
+// un workspace: desde otro workspace no se puede ver, usar ni referenciar como fallback.
+// Dentro del workspace, los analyst administran sus perfiles personales y los admin,
+// además, los compartidos (ver entities.LLMProfile).
+type LLMProfilesUseCase struct {
+	profileRepo repositories.LLMProfileRepository
+}
+
+// NewLLMProfilesUseCase crea una nueva instancia del caso de uso
+func NewLLMProfilesUseCase(profileRepo repositories.LLMProfileRepository) *LLMProfilesUseCase {
+	return &LLMProfilesUseCase{
+		profileRepo: profileRepo,
+	}
+}
+
+// List lista los perfiles del workspace que el miembro puede ver
+func (uc *LLMProfilesUseCase) List(ctx context.Context, membership *entities.Membership) ([]*entities.LLMProfile, error) {
+	profiles, err := uc.profileRepo.List(ctx, membership.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	visible := profiles[:0]
+	for _, profile := range profiles {
+		if profile.VisibleTo(membership) {
+			visible = append(visible, profile)
+		}
+	}
+	return visible, nil
+}
+
+// Get obtiene un perfil del workspace por ID; uno que el miembro no puede ver no existe
+func (uc *LLMProfilesUseCase) Get(ctx context.Context, membership *entities.Membership, id int64) (*entities.LLMProfile, error) {
+	profile, err := uc.profileRepo.GetByID(ctx, membership.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.VisibleTo(membership) {
+		return nil, entities.ErrProfileNotFound
+	}
+	return profile, nil
+}
+
+// Create valida y guarda un perfil nuevo en el workspace del miembro. Con OwnerID el
+// perfil es personal del miembro; los de un analyst siempre lo son.
+func (uc *LLMProfilesUseCase) Create(ctx context.Context, membership *entities.Membership, profile *entities.LLMProfile) (*entities.LLMProfile, error) {
+	profile.WorkspaceID = membership.WorkspaceID
+	if !profile.Shared() || !membership.Role.Allows(entities.RoleAdmin) {
+		profile.OwnerID = membership.UserID
+	}
+
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.validateFallbacks(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.profileRepo.Create(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	return uc.profileRepo.GetByID(ctx, profile.WorkspaceID, id)
+}
+
+// Update reemplaza un perfil existente del workspace del miembro, que conserva su dueño.
+// Si la API key viene vacía y keepApiKey es true se conserva la guardada, para que los
+// clientes no necesiten conocerla.
+func (uc *LLMProfilesUseCase) Update(ctx context.Context, membership *entities.Membership, profile *entities.LLMProfile, keepApiKey bool) (*entities.LLMProfile, error) {
+	existing, err := uc.Get(ctx, membership, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.EditableBy(membership) {
+		return nil, fmt.Errorf("%w: only %s can modify profile %d", entities.ErrForbidden, editorOf(existing), existing.ID)
+	}
+	profile.WorkspaceID = existing.WorkspaceID
+	profile.OwnerID = existing.OwnerID
+
+	if profile.ApiKey == "" && keepApiKey {
+		profile.ApiKey = existing.ApiKey
+	}
+
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.validateFallbacks(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	if err := uc.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	return uc.profileRepo.GetByID(ctx, profile.WorkspaceID, profile.ID)
+}
+
+// Delete elimina un perfil del workspace del miembro, salvo que otro perfil lo use como fallback
+func (uc *LLMProfilesUseCase) Delete(ctx context.Context, membership *entities.Membership, id int64) error {
+	existing, err := uc.Get(ctx, membership, id)
+	if err != nil {
+		return err
+	}
+	if !existing.DeletableBy(membership) {
+		return fmt.Errorf("%w: only %s can delete profile %d", entities.ErrForbidden, editorOf(existing), existing.ID)
+	}
+
+	profiles, err := uc.profileRepo.List(ctx, membership.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		for _, fallbackID := range profile.FallbackIDs {
+			if fallbackID == id {
+				return fmt.Errorf("%w: %q", entities.ErrProfileInUse, profile.Name)
+			}
+		}
+	}
+	return uc.profileRepo.Delete(ctx, membership.WorkspaceID, id)
+}
+
+// ResolveConfig construye la configuración LLM de un perfil que el miembro puede usar, con
+// sus fallbacks resueltos. Un perfil de otro workspace o personal de otro usuario se informa
+// como inexistente (entities.ErrProfileNotFound), igual que WorkspaceUseCase.Authorize
+// oculta los workspaces ajenos.
+func (uc *LLMProfilesUseCase) ResolveConfig(ctx context.Context, membership *entities.Membership, id int64) (*entities.LLMConfig, error) {
+	profile, err := uc.profileRepo.GetByID(ctx, membership.WorkspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.UsableBy(membership.UserID) {
+		return nil, entities.ErrProfileNotFound
+	}
+
+	config := profile.ToLLMConfig()
+	for _, fallbackID := range profile.FallbackIDs {
+		fallback, err := uc.profileRepo.GetByID(ctx, membership.WorkspaceID, fallbackID)
+		if err == nil && !fallback.UsableBy(membership.UserID) {
+			err = entities.ErrProfileNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fallback profile %d: %w", fallbackID, err)
+		}
+		config.Fallbacks = append(config.Fallbacks, fallback.ToLLMConfig())
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// editorOf describe quién puede modificar el perfil, para los mensajes de error
+func editorOf(profile *entities.LLMProfile) string {
+	if profile.Shared() {
+		return "a workspace admin"
+	}
+	return "its owner"
+}
+
+// validateFallbacks comprueba que los perfiles de fallback existan en el mismo workspace y
+// que la cadena no vuelva al propio perfil, ni directamente ni a través de los fallbacks
+// de otro perfil
+func (uc *LLMProfilesUseCase) validateFallbacks(ctx context.Context, profile *entities.LLMProfile) error {
+	seen := make(map[int64]bool, len(profile.FallbackIDs))
+	for _, fallbackID := range profile.FallbackIDs {
+		if profile.ID != 0 && fallbackID == profile.ID {
+			return errors.New("a profile cannot be its own fallback")
+		}
+		if seen[fallbackID] {
+			return fmt.Errorf("fallback profile %d is listed more than once", fallbackID)
+		}
+		seen[fallbackID] = true
+
+		// El fallback debe poder usarlo quien use el perfil: uno compartido solo admite
+		// compartidos y uno personal, además, los de su dueño
+		fallback, err := uc.profileRepo.GetByID(ctx, profile.WorkspaceID, fallbackID)
+		if err == nil && !fallback.UsableBy(profile.OwnerID) {
+			err = entities.ErrProfileNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("fallback profile %d: %w", fallbackID, err)
+		}
+	}
+	if profile.ID == 0 {
+		// Un perfil nuevo todavía no puede ser fallback de nadie
+		return nil
+	}
+
+	// Recorrer el grafo de fallbacks guardado, con los del perfil ya reemplazados
+	profiles, err := uc.profileRepo.List(ctx, profile.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	fallbacks := make(map[int64][]int64, len(profiles))
+	for _, p := range profiles {
+		fallbacks[p.ID] = p.FallbackIDs
+	}
+	fallbacks[profile.ID] = profile.FallbackIDs
+
+	visited := make(map[int64]bool)
+	var visit func(id int64) error
+	visit = func(id int64) error {
+		for _, next := range fallbacks[id] {
+			if next == profile.ID {
+				return fmt.Errorf("fallback profile %d leads back to profile %d: cycles are not allowed", id, profile.ID)
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(profile.ID)
+}
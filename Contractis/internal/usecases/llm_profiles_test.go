@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// memoryProfiles implementa repositories.LLMProfileRepository en memoria, acotado por workspace
+type memoryProfiles struct {
+	profiles map[int64]*entities.LLMProfile
+	nextID   int64
+}
+
+func newMemoryProfiles() *memoryProfiles {
+	return &memoryProfiles{profiles: make(map[int64]*entities.LLMProfile)}
+}
+
+func (m *memoryProfiles) Create(ctx context.Context, profile *entities.LLMProfile) (int64, error) {
+	m.nextID++
+	stored := *profile
+	stored.ID = m.nextID
+	m.profiles[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (m *memoryProfiles) GetByID(ctx context.Context, workspaceID, id int64) (*entities.LLMProfile, error) {
+	profile, ok := m.profiles[id]
+	if !ok || profile.WorkspaceID != workspaceID {
+		return nil, entities.ErrProfileNotFound
+	}
+	stored := *profile
+	return &stored, nil
+}
+
+func (m *memoryProfiles) List(ctx context.Context, workspaceID int64) ([]*entities.LLMProfile, error) {
+	var profiles []*entities.LLMProfile
+	for _, profile := range m.profiles {
+		if profile.WorkspaceID == workspaceID {
+			stored := *profile
+			profiles = append(profiles, &stored)
+		}
+	}
+	return profiles, nil
+}
+
+func (m *memoryProfiles) ListAll(ctx context.Context) ([]*entities.LLMProfile, error) {
+	var profiles []*entities.LLMProfile
+	for _, profile := range m.profiles {
+		stored := *profile
+		profiles = append(profiles, &stored)
+	}
+	return profiles, nil
+}
+
+func (m *memoryProfiles) Update(ctx context.Context, profile *entities.LLMProfile) error {
+	if _, err := m.GetByID(ctx, profile.WorkspaceID, profile.ID); err != nil {
+		return err
+	}
+	stored := *profile
+	m.profiles[profile.ID] = &stored
+	return nil
+}
+
+func (m *memoryProfiles) Delete(ctx context.Context, workspaceID, id int64) error {
+	if _, err := m.GetByID(ctx, workspaceID, id); err != nil {
+		return err
+	}
+	delete(m.profiles, id)
+	return nil
+}
+
+func TestLLMProfilesRejectFallbackCycles(t *testing.T) {
+	ctx := context.Background()
+	uc := NewLLMProfilesUseCase(newMemoryProfiles())
+	admin := &entities.Membership{WorkspaceID: 1, UserID: 1, Role: entities.RoleAdmin}
+
+	create := func(name string, fallbacks ...int64) *entities.LLMProfile {
+		t.Helper()
+		profile, err := uc.Create(ctx, admin, &entities.LLMProfile{
+			Name: name, Type: "local", LocalUrl: "http://" + name + ".test", ModelName: "stub", MaxTokens: 800,
+			FallbackIDs: fallbacks,
+		})
+		if err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+		return profile
+	}
+	c := create("c")
+	b := create("b", c.ID)
+	a := create("a", b.ID)
+
+	tests := []struct {
+		name      string
+		profile   *entities.LLMProfile
+		fallbacks []int64
+		want      string
+	}{
+		{"self reference", a, []int64{a.ID}, "own fallback"},
+		{"direct cycle", b, []int64{a.ID}, "cycles are not allowed"},
+		{"indirect cycle", c, []int64{a.ID}, "cycles are not allowed"},
+		{"duplicate", a, []int64{b.ID, b.ID}, "more than once"},
+		{"missing profile", a, []int64{99}, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := *tt.profile
+			update.FallbackIDs = tt.fallbacks
+			_, err := uc.Update(ctx, admin, &update, true)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Update = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	// Una cadena sin ciclos sigue siendo válida
+	update := *c
+	update.FallbackIDs = nil
+	if _, err := uc.Update(ctx, admin, &update, true); err != nil {
+		t.Errorf("Update without fallbacks: %v", err)
+	}
+	update = *a
+	update.FallbackIDs = []int64{c.ID, b.ID}
+	if _, err := uc.Update(ctx, admin, &update, true); err != nil {
+		t.Errorf("Update with an acyclic chain: %v", err)
+	}
+}
+
+// TestLLMProfilesAreScopedToWorkspace comprueba que el perfil (y su API key) de un workspace
+// no se pueda ver, usar, modificar ni referenciar como fallback desde otro
+func TestLLMProfilesAreScopedToWorkspace(t *testing.T) {
+	ctx := context.Background()
+	uc := NewLLMProfilesUseCase(newMemoryProfiles())
+	legalAdmin := &entities.Membership{WorkspaceID: 1, UserID: 1, Role: entities.RoleAdmin}
+	fiscalAdmin := &entities.Membership{WorkspaceID: 2, UserID: 2, Role: entities.RoleAdmin}
+
+	legal, err := uc.Create(ctx, legalAdmin, &entities.LLMProfile{
+		Name: "openai", Type: "online", ApiUrl: "https://api.example.com/v1/chat/completions",
+		ApiKey: "sk-legal", ModelName: "gpt", MaxTokens: 800,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legal.WorkspaceID != 1 || !legal.Shared() {
+		t.Fatalf("Create by an admin = workspace %d, owner %d, want a shared profile of workspace 1", legal.WorkspaceID, legal.OwnerID)
+	}
+
+	config, err := uc.ResolveConfig(ctx, legalAdmin, legal.ID)
+	if err != nil || config.ApiKey != "sk-legal" {
+		t.Fatalf("ResolveConfig from the owning workspace = %v, %v", config, err)
+	}
+	if config, err := uc.ResolveConfig(ctx, fiscalAdmin, legal.ID); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("ResolveConfig from another workspace = %v, %v, want ErrProfileNotFound", config, err)
+	}
+	if _, err := uc.Get(ctx, fiscalAdmin, legal.ID); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Get from another workspace = %v, want ErrProfileNotFound", err)
+	}
+	if profiles, err := uc.List(ctx, fiscalAdmin); err != nil || len(profiles) != 0 {
+		t.Errorf("List of another workspace = %d profiles, %v", len(profiles), err)
+	}
+
+	// Cambiar la URL conservando la key la enviaría a otro servidor
+	hijack := *legal
+	hijack.ApiUrl = "https://attacker.example.com/v1/chat/completions"
+	if _, err := uc.Update(ctx, fiscalAdmin, &hijack, true); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Update from another workspace = %v, want ErrProfileNotFound", err)
+	}
+	if err := uc.Delete(ctx, fiscalAdmin, legal.ID); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Delete from another workspace = %v, want ErrProfileNotFound", err)
+	}
+
+	// Un perfil propio tampoco puede usar el ajeno como fallback
+	_, err = uc.Create(ctx, fiscalAdmin, &entities.LLMProfile{
+		Name: "local", Type: "local", LocalUrl: "http://llm.test", ModelName: "stub", MaxTokens: 800,
+		FallbackIDs: []int64{legal.ID},
+	})
+	if !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Create with another workspace's fallback = %v, want ErrProfileNotFound", err)
+	}
+}
+
+// TestLLMProfilesPersonal comprueba los perfiles personales: un analyst crea y modifica los
+// suyos y usa los compartidos, pero no modifica los compartidos ni usa los de otro usuario
+func TestLLMProfilesPersonal(t *testing.T) {
+	ctx := context.Background()
+	uc := NewLLMProfilesUseCase(newMemoryProfiles())
+	admin := &entities.Membership{WorkspaceID: 1, UserID: 1, Role: entities.RoleAdmin}
+	ana := &entities.Membership{WorkspaceID: 1, UserID: 2, Role: entities.RoleAnalyst}
+	bruno := &entities.Membership{WorkspaceID: 1, UserID: 3, Role: entities.RoleAnalyst}
+
+	online := func(name, key string, fallbacks ...int64) *entities.LLMProfile {
+		return &entities.LLMProfile{
+			Name: name, Type: "online", ApiUrl: "https://api.example.com/v1/chat/completions",
+			ApiKey: key, ModelName: "gpt", MaxTokens: 800, FallbackIDs: fallbacks,
+		}
+	}
+	shared, err := uc.Create(ctx, admin, online("equipo", "sk-equipo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Los perfiles de un analyst son personales aunque no lo pida
+	personal, err := uc.Create(ctx, ana, online("ana", "sk-ana", shared.ID))
+	if err != nil {
+		t.Fatalf("Create by an analyst: %v", err)
+	}
+	if personal.OwnerID != ana.UserID {
+		t.Errorf("Create by an analyst: owner %d, want %d", personal.OwnerID, ana.UserID)
+	}
+	if config, err := uc.ResolveConfig(ctx, ana, personal.ID); err != nil || config.ApiKey != "sk-ana" || len(config.Fallbacks) != 1 {
+		t.Errorf("ResolveConfig of the own profile = %+v, %v", config, err)
+	}
+	if _, err := uc.ResolveConfig(ctx, ana, shared.ID); err != nil {
+		t.Errorf("ResolveConfig of a shared profile as analyst: %v", err)
+	}
+	update := *personal
+	update.ModelName = "gpt-mini"
+	if _, err := uc.Update(ctx, ana, &update, true); err != nil {
+		t.Errorf("Update of the own profile: %v", err)
+	}
+
+	// Otro analyst no ve, usa, modifica ni referencia el perfil de Ana
+	if profiles, err := uc.List(ctx, bruno); err != nil || len(profiles) != 1 || profiles[0].ID != shared.ID {
+		t.Errorf("List as another analyst = %v, %v, want only the shared profile", profiles, err)
+	}
+	if _, err := uc.ResolveConfig(ctx, bruno, personal.ID); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("ResolveConfig of someone else's profile = %v, want ErrProfileNotFound", err)
+	}
+	hijack := *personal
+	hijack.ApiUrl = "https://attacker.example.com/v1/chat/completions"
+	if _, err := uc.Update(ctx, bruno, &hijack, true); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Update of someone else's profile = %v, want ErrProfileNotFound", err)
+	}
+	if _, err := uc.Create(ctx, bruno, online("bruno", "sk-bruno", personal.ID)); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Create with someone else's profile as fallback = %v, want ErrProfileNotFound", err)
+	}
+
+	// Un analyst no modifica ni elimina los compartidos
+	sharedUpdate := *shared
+	sharedUpdate.ApiUrl = "https://attacker.example.com/v1/chat/completions"
+	if _, err := uc.Update(ctx, ana, &sharedUpdate, true); !errors.Is(err, entities.ErrForbidden) {
+		t.Errorf("Update of a shared profile as analyst = %v, want ErrForbidden", err)
+	}
+	if err := uc.Delete(ctx, ana, shared.ID); !errors.Is(err, entities.ErrForbidden) {
+		t.Errorf("Delete of a shared profile as analyst = %v, want ErrForbidden", err)
+	}
+
+	// El admin ve el perfil personal y puede eliminarlo, pero no usarlo ni redirigir su key,
+	// y un perfil compartido no puede tener uno personal como fallback
+	if _, err := uc.Get(ctx, admin, personal.ID); err != nil {
+		t.Errorf("Get of a personal profile as admin: %v", err)
+	}
+	if _, err := uc.ResolveConfig(ctx, admin, personal.ID); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("ResolveConfig of a personal profile as admin = %v, want ErrProfileNotFound", err)
+	}
+	if _, err := uc.Update(ctx, admin, &hijack, true); !errors.Is(err, entities.ErrForbidden) {
+		t.Errorf("Update of a personal profile as admin = %v, want ErrForbidden", err)
+	}
+	sharedUpdate = *shared
+	sharedUpdate.FallbackIDs = []int64{personal.ID}
+	if _, err := uc.Update(ctx, admin, &sharedUpdate, true); !errors.Is(err, entities.ErrProfileNotFound) {
+		t.Errorf("Update of a shared profile with a personal fallback = %v, want ErrProfileNotFound", err)
+	}
+	if err := uc.Delete(ctx, admin, personal.ID); err != nil {
+		t.Errorf("Delete of a personal profile as admin: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// OIDCLoginUseCase convierte una identidad OIDC verificada en un principal de Contractis:
+// crea el usuario si no existe, sincroniza sus roles según los grupos del proveedor y
+// calcula los scopes de la sesión a partir de sus roles
+type OIDCLoginUseCase struct {
+	userRepo      repositories.UserRepository
+	workspaceRepo repositories.WorkspaceRepository
+	mappings      []entities.RoleMapping
+}
+
+// NewOIDCLoginUseCase crea una nueva instancia del caso de uso. Solo los workspaces
+// presentes en mappings se sincronizan con los grupos; el resto se gestiona por CLI.
+func NewOIDCLoginUseCase(
+	userRepo repositories.UserRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	mappings []entities.RoleMapping,
+) *OIDCLoginUseCase {
+	return &OIDCLoginUseCase{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		mappings:      mappings,
+	}
+}
+
+// Login aprovisiona al usuario y retorna el principal de su sesión
+func (uc *OIDCLoginUseCase) Login(ctx context.Context, claims *entities.IdentityClaims) (*entities.Principal, error) {
+	email := strings.TrimSpace(claims.Email)
+	if !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("%w: ID token has no email claim", entities.ErrOIDCLogin)
+	}
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, fmt.Errorf("%w: email %s is not verified", entities.ErrOIDCLogin, email)
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, entities.ErrUserNotFound) {
+		id, createErr := uc.userRepo.Create(ctx, &entities.User{Email: email, Name: strings.TrimSpace(claims.Name)})
+		if createErr != nil {
+			return nil, createErr
+		}
+		user, err = uc.userRepo.GetByID(ctx, id)
+		if err == nil {
+			slog.InfoContext(ctx, "usuario creado desde OIDC", "email", user.Email)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.syncMemberships(ctx, user.ID, claims.Groups); err != nil {
+		return nil, err
+	}
+
+	workspaces, err := uc.workspaceRepo.ListForUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("%w: %s has no workspace access", entities.ErrForbidden, email)
+	}
+
+	return &entities.Principal{
+		Subject: "oidc:" + user.Email,
+		UserID:  user.ID,
+		Scopes:  scopesForWorkspaces(workspaces),
+	}, nil
+}
+
+// syncMemberships aplica el mapeo grupo → rol: en cada workspace mapeado el usuario
+// recibe el rol más alto de sus grupos, o pierde el acceso si ningún grupo coincide
+func (uc *OIDCLoginUseCase) syncMemberships(ctx context.Context, userID int64, groups []string) error {
+	if len(uc.mappings) == 0 {
+		return nil
+	}
+
+	inGroup := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		inGroup[group] = true
+	}
+
+	desired := make(map[int64]entities.Role)
+	managed := make(map[int64]bool)
+	for _, mapping := range uc.mappings {
+		managed[mapping.WorkspaceID] = true
+		if !inGroup[mapping.Group] {
+			continue
+		}
+		if current, ok := desired[mapping.WorkspaceID]; !ok || !current.Allows(mapping.Role) {
+			desired[mapping.WorkspaceID] = mapping.Role
+		}
+	}
+
+	existing, err := uc.workspaceRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	current := make(map[int64]entities.Role, len(existing))
+	for _, workspace := range existing {
+		current[workspace.ID] = workspace.Role
+	}
+
+	for workspaceID := range managed {
+		role, want := desired[workspaceID]
+		have, has := current[workspaceID]
+		switch {
+		case want && (!has || have != role):
+			if err := uc.workspaceRepo.SetMember(ctx, &entities.Membership{
+				WorkspaceID: workspaceID,
+				UserID:      userID,
+				Role:        role,
+			}); err != nil {
+				return fmt.Errorf("workspace %d: %w", workspaceID, err)
+			}
+		case !want && has:
+			if err := uc.workspaceRepo.RemoveMember(ctx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scopesForWorkspaces une los scopes de los roles del usuario en todos sus workspaces;
+// el rol efectivo en cada workspace se vuelve a comprobar en cada petición
+func scopesForWorkspaces(workspaces []*entities.Workspace) []entities.Scope {
+	seen := make(map[entities.Scope]bool)
+	var scopes []entities.Scope
+	for _, workspace := range workspaces {
+		for _, scope := range entities.ScopesForRole(workspace.Role) {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+// maxPlaceholderLength acota cuánto texto se retiene en streaming esperando el cierre de un marcador
+const maxPlaceholderLength = 32
+
+// redact oculta los datos personales del texto si alguno de los endpoints de la cadena
+// de failover es online. Los modelos locales reciben el texto original.
+func (uc *AnalyzeContractUseCase) redact(ctx context.Context, content string, config *entities.LLMConfig) (string, *entities.Redaction) {
+	if uc.redactor == nil {
+		return content, nil
+	}
+
+	online := false
+	for _, candidate := range config.Candidates() {
+		if candidate.IsOnline() {
+			online = true
+			break
+		}
+	}
+	if !online {
+		return content, nil
+	}
+
+	redacted, redaction := uc.redactor.Redact(content)
+	if redaction.Len() > 0 {
+		slog.InfoContext(ctx, "datos personales ocultados antes de enviar al LLM", "counts", redaction.Counts())
+	}
+	return redacted, redaction
+}
+
+// restoringSink reinserta los valores originales en los tokens parciales del reporte.
+// Un marcador puede llegar partido entre tokens, así que se retiene el texto desde un
+// "[" sin cerrar hasta que se cierra o supera maxPlaceholderLength.
+type restoringSink struct {
+	sink      entities.TokenSink
+	redaction *entities.Redaction
+	pending   string
+}
+
+func newRestoringSink(sink entities.TokenSink, redaction *entities.Redaction) *restoringSink {
+	return &restoringSink{sink: sink, redaction: redaction}
+}
+
+// Write recibe un token del LLM y reenvía la parte que ya no puede formar parte de un marcador
+func (s *restoringSink) Write(token string) {
+	text := s.pending + token
+
+	cut := len(text)
+	if open := strings.LastIndex(text, "["); open >= 0 &&
+		!strings.Contains(text[open:], "]") && len(text)-open < maxPlaceholderLength {
+		cut = open
+	}
+
+	s.pending = text[cut:]
+	if cut > 0 {
+		s.sink(s.redaction.Restore(text[:cut]))
+	}
+}
+
+// Flush reenvía el texto retenido al terminar el stream
+func (s *restoringSink) Flush() {
+	if s.pending != "" {
+		s.sink(s.redaction.Restore(s.pending))
+		s.pending = ""
+	}
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+func TestRestoringSink(t *testing.T) {
+	redaction := entities.NewRedaction()
+	redaction.Add("EMAIL", "[EMAIL_1]", "juan@example.com")
+	redaction.Add("PERSONA", "[PERSONA_1]", "Juan Pérez")
+
+	tests := []struct {
+		name   string
+		tokens []string
+		want   string
+	}{
+		{"whole placeholder", []string{"Contacto: ", "[EMAIL_1]", "."}, "Contacto: juan@example.com."},
+		{"split placeholder", []string{"Contacto: [EMA", "IL_", "1] y ", "[PERSONA", "_1]"}, "Contacto: juan@example.com y Juan Pérez"},
+		{"one character per token", strings.Split("[PERSONA_1] firma", ""), "Juan Pérez firma"},
+		{"unknown placeholder", []string{"ver [NOTA_", "1]"}, "ver [NOTA_1]"},
+		{"unclosed bracket at end", []string{"lista: [a, b"}, "lista: [a, b"},
+		{"long bracket is not held", []string{"[" + strings.Repeat("x", maxPlaceholderLength), " fin"}, "[" + strings.Repeat("x", maxPlaceholderLength) + " fin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			var writes []string
+			sink := newRestoringSink(func(token string) {
+				writes = append(writes, token)
+				out.WriteString(token)
+			}, redaction)
+
+			for _, token := range tt.tokens {
+				sink.Write(token)
+			}
+			sink.Flush()
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+			// Ningún token reenviado contiene un marcador a medias
+			for _, w := range writes {
+				if strings.Contains(w, "[EMA") && !strings.Contains(w, "[EMAIL_1]") {
+					t.Errorf("token %q leaked a partial placeholder", w)
+				}
+				if strings.Contains(w, "[EMAIL_1]") || strings.Contains(w, "[PERSONA_1]") {
+					t.Errorf("token %q was not restored", w)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoringSinkHoldsOnlyTheOpenPlaceholder(t *testing.T) {
+	redaction := entities.NewRedaction()
+	redaction.Add("EMAIL", "[EMAIL_1]", "juan@example.com")
+
+	var writes []string
+	sink := newRestoringSink(func(token string) { writes = append(writes, token) }, redaction)
+
+	// El texto anterior al "[" se reenvía en cuanto llega
+	sink.Write("Escribir a [EMA")
+	if len(writes) != 1 || writes[0] != "Escribir a " {
+		t.Fatalf("writes = %q, want the text before the bracket", writes)
+	}
+	sink.Write("IL_1]")
+	if len(writes) != 2 || writes[1] != "juan@example.com" {
+		t.Fatalf("writes = %q, want the restored email", writes)
+	}
+	sink.Flush()
+	if len(writes) != 2 {
+		t.Errorf("Flush wrote %q with nothing pending", writes[2:])
+	}
+}
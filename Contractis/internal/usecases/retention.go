@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+// retentionActor identifica al barrido de retención en el log de auditoría
+const retentionActor = "system:retention"
+
+// RetentionUseCase aplica la política de retención: envía a la papelera los contratos
+// que superan su plazo y purga los que cumplieron el plazo en la papelera.
+// Los contratos con legal hold nunca se tocan.
+type RetentionUseCase struct {
+	contractRepo repositories.ContractRepository
+	auditor      services.AuditLogger
+	policy       entities.RetentionPolicy
+	events       services.EventPublisher
+}
+
+// NewRetentionUseCase crea una nueva instancia del caso de uso. events es opcional y
+// recibe contract.deleted por cada contrato enviado a la papelera o purgado.
+func NewRetentionUseCase(
+	contractRepo repositories.ContractRepository,
+	auditor services.AuditLogger,
+	policy entities.RetentionPolicy,
+	events services.EventPublisher,
+) *RetentionUseCase {
+	return &RetentionUseCase{
+		contractRepo: contractRepo,
+		auditor:      auditor,
+		policy:       policy,
+		events:       events,
+	}
+}
+
+// Sweep ejecuta un barrido y retorna cuántos contratos envió a la papelera y cuántos purgó
+func (uc *RetentionUseCase) Sweep(ctx context.Context) (trashed, purged int, err error) {
+	records, err := uc.contractRepo.ListForRetention(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ctx = entities.WithPrincipal(ctx, &entities.Principal{Subject: retentionActor})
+	now := time.Now()
+
+	for _, record := range records {
+		switch {
+		case uc.policy.ShouldPurge(record, now):
+			err = uc.contractRepo.Purge(ctx, record.WorkspaceID, record.ID)
+			if err == nil {
+				purged++
+				uc.auditor.Record(ctx, entities.AuditPurge, record.WorkspaceID, record.ID, record.Filename)
+				uc.publishDeleted(ctx, record, true)
+			}
+		case uc.policy.ShouldTrash(record, now):
+			err = uc.contractRepo.Delete(ctx, record.WorkspaceID, record.ID)
+			if err == nil {
+				trashed++
+				uc.auditor.Record(ctx, entities.AuditDelete, record.WorkspaceID, record.ID, record.Filename)
+				uc.publishDeleted(ctx, record, false)
+			}
+		default:
+			continue
+		}
+
+		// Un legal hold activado durante el barrido no es un error
+		if err != nil && !errors.Is(err, entities.ErrLegalHold) && !errors.Is(err, entities.ErrContractNotFound) {
+			return trashed, purged, err
+		}
+	}
+	return trashed, purged, nil
+}
+
+func (uc *RetentionUseCase) publishDeleted(ctx context.Context, record *entities.ContractRecord, permanent bool) {
+	if uc.events != nil {
+		event := entities.NewContractDeletedEvent(ctx, record.ID, record.Filename, permanent)
+		uc.events.Publish(ctx, record.WorkspaceID, entities.EventContractDeleted, event)
+	}
+}
+
+// Run ejecuta Sweep periódicamente hasta que el contexto se cancele
+func (uc *RetentionUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		trashed, purged, err := uc.Sweep(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "error en barrido de retención", "error", err)
+		} else if trashed > 0 || purged > 0 {
+			slog.InfoContext(ctx, "barrido de retención", "trashed", trashed, "purged", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/infrastructure/tracing"
+)
+
+// spanTree indexa los spans exportados por ID y por padre
+type spanTree struct {
+	byID     map[tracing.SpanID]tracing.SpanData
+	children map[tracing.SpanID][]tracing.SpanData
+}
+
+func newSpanTree(spans []tracing.SpanData) *spanTree {
+	tree := &spanTree{
+		byID:     make(map[tracing.SpanID]tracing.SpanData),
+		children: make(map[tracing.SpanID][]tracing.SpanData),
+	}
+	for _, span := range spans {
+		tree.byID[span.SpanID] = span
+		tree.children[span.ParentSpanID] = append(tree.children[span.ParentSpanID], span)
+	}
+	for _, children := range tree.children {
+		slices.SortFunc(children, func(a, b tracing.SpanData) int { return a.Start.Compare(b.Start) })
+	}
+	return tree
+}
+
+// child retorna los hijos directos de parent con ese nombre, en orden de inicio
+func (tree *spanTree) child(parent tracing.SpanData, name string) []tracing.SpanData {
+	var found []tracing.SpanData
+	for _, span := range tree.children[parent.SpanID] {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	return found
+}
+
+// one exige exactamente un hijo directo con ese nombre
+func (tree *spanTree) one(t *testing.T, parent tracing.SpanData, name string) tracing.SpanData {
+	t.Helper()
+	found := tree.child(parent, name)
+	if len(found) != 1 {
+		t.Fatalf("%s has %d %q children, want 1 (children: %v)", parent.Name, len(found), name, tree.names(parent))
+	}
+	return found[0]
+}
+
+func (tree *spanTree) names(parent tracing.SpanData) []string {
+	var names []string
+	for _, span := range tree.children[parent.SpanID] {
+		names = append(names, span.Name)
+	}
+	return names
+}
+
+// positive comprueba que el atributo entero existe y es mayor que cero
+func positive(t *testing.T, span tracing.SpanData, key string) int {
+	t.Helper()
+	value, ok := span.Attributes[key].(int)
+	if !ok || value <= 0 {
+		t.Errorf("%s: %s = %v, want a positive int", span.Name, key, span.Attributes[key])
+	}
+	return value
+}
+
+// tracedContracts registra un span por cada Update, como el observer de la base real
+type tracedContracts struct {
+	*memoryContracts
+	tracer *tracing.Tracer
+}
+
+func (c *tracedContracts) Update(ctx context.Context, record *entities.ContractRecord) error {
+	_, span := c.tracer.StartChild(ctx, "UPDATE contracts", tracing.KindClient)
+	defer span.End()
+	return c.memoryContracts.Update(ctx, record)
+}
+
+// tracedLLM arma un LLM falso que, como el cliente real, abre un span por petición
+// dentro del contexto que recibe
+func tracedLLM(tracer *tracing.Tracer) *fakeLLM {
+	chat := func(ctx context.Context, stream bool) {
+		_, span := tracer.StartChild(ctx, "chat stub", tracing.KindClient)
+		span.SetAttribute("contractis.llm.stream", stream)
+		span.End()
+	}
+	return &fakeLLM{
+		send: func(ctx context.Context, _ *entities.LLMConfig, messages []repositories.ChatMessage) (string, error) {
+			chat(ctx, false)
+			return "análisis de " + chunkOf(messages), nil
+		},
+		stream: func(ctx context.Context, _ *entities.LLMConfig, _ []repositories.ChatMessage, _ func(string)) (string, error) {
+			chat(ctx, true)
+			return "reporte final", nil
+		},
+	}
+}
+
+// TestAnalysisTrace analiza un contrato de varios fragmentos y comprueba la traza: la
+// extracción, un span por fragmento de la fase 1 con su petición al LLM, la consolidación
+// jerárquica y la final, y la actualización del contrato al terminar
+func TestAnalysisTrace(t *testing.T) {
+	ctx := context.Background()
+	spans := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(spans)
+	defer tracer.Shutdown(ctx)
+
+	// Más fragmentos que entities.MaxFragments, para que haya consolidación jerárquica
+	lines := make([]string, entities.MaxFragments+3)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("Clausula %d: el locatario abonara una penalidad ante cada incumplimiento.", i+1)
+	}
+	contracts := &tracedContracts{memoryContracts: newMemoryContracts(), tracer: tracer}
+	uc := NewAnalyzeContractUseCase(&fakePDF{text: strings.Join(lines, "\n")}, tracedLLM(tracer), contracts,
+		fakeText{}, nopAuditor{}, nil, nil, tracer, nil)
+	config := entities.NewLLMConfig("local", "http://llm.test", "", "", "stub", 800)
+
+	result, err := uc.Execute(ctx, 1, "/tmp/largo.pdf", "largo.pdf", "hash-largo", 4096, config)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if err := tracer.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	tree := newSpanTree(spans.Spans())
+	for _, span := range tree.byID {
+		if span.Error != "" {
+			t.Errorf("%s: recorded error %q", span.Name, span.Error)
+		}
+	}
+
+	root := tree.one(t, tracing.SpanData{}, "analysis")
+	if root.Attributes["gen_ai.request.model"] != "stub" || root.Attributes["contractis.llm.type"] != "local" ||
+		root.Attributes["contractis.analysis.status"] != "completed" || root.Attributes["contractis.contract_id"] != result.ContractID {
+		t.Errorf("analysis attributes = %v", root.Attributes)
+	}
+	for _, span := range tree.byID {
+		if span.TraceID != root.TraceID {
+			t.Errorf("%s belongs to another trace", span.Name)
+		}
+	}
+
+	// Etapas del análisis, en orden
+	testConnection := tree.one(t, root, "analysis.test_connection")
+	extract := tree.one(t, root, "pdf.extract")
+	phase1 := tree.one(t, root, "analysis.phase1")
+	consolidate := tree.one(t, root, "analysis.consolidate")
+	stages := []tracing.SpanData{testConnection, extract, phase1, consolidate}
+	for i := 1; i < len(stages); i++ {
+		if stages[i].Start.Before(stages[i-1].End) {
+			t.Errorf("%s starts before %s ends", stages[i].Name, stages[i-1].Name)
+		}
+	}
+	positive(t, extract, "contractis.document.chars")
+
+	// Fase 1: un span por fragmento, cada uno con su petición al LLM
+	chunks := positive(t, phase1, "contractis.chunks")
+	if chunks != len(lines) || root.Attributes["contractis.chunks"] != chunks {
+		t.Errorf("analysis chunks = %v, phase1 chunks = %d, want %d", root.Attributes["contractis.chunks"], chunks, len(lines))
+	}
+	if phase1.Attributes["contractis.chunks.resumed"] != 0 {
+		t.Errorf("phase1 resumed %v chunks, want 0", phase1.Attributes["contractis.chunks.resumed"])
+	}
+	chunkSpans := tree.child(phase1, "analysis.chunk")
+	if len(chunkSpans) != chunks {
+		t.Fatalf("analysis.phase1 has %d chunk spans, want %d", len(chunkSpans), chunks)
+	}
+	var indexes []int
+	for _, chunk := range chunkSpans {
+		indexes = append(indexes, positive(t, chunk, "contractis.chunk.index"))
+		positive(t, chunk, "contractis.chunk.chars")
+		positive(t, chunk, "gen_ai.usage.input_tokens")
+		positive(t, chunk, "gen_ai.usage.output_tokens")
+		if chat := tree.one(t, chunk, "chat stub"); chat.Attributes["contractis.llm.stream"] != false {
+			t.Errorf("chunk %v was streamed", chunk.Attributes["contractis.chunk.index"])
+		}
+	}
+	slices.Sort(indexes)
+	for i, index := range indexes {
+		if index != i+1 {
+			t.Errorf("chunk indexes = %v, want 1..%d", indexes, chunks)
+			break
+		}
+	}
+
+	// Consolidación: primero jerárquica (sin LLM), luego la final por streaming
+	if consolidate.Attributes["contractis.fragments"] != chunks {
+		t.Errorf("analysis.consolidate fragments = %v, want %d", consolidate.Attributes["contractis.fragments"], chunks)
+	}
+	hierarchical := tree.one(t, consolidate, "analysis.consolidate.hierarchical")
+	if hierarchical.Attributes["contractis.fragments"] != chunks || hierarchical.Attributes["contractis.groups"] != (chunks+2)/3 {
+		t.Errorf("hierarchical consolidation attributes = %v, want %d fragments in %d groups", hierarchical.Attributes, chunks, (chunks+2)/3)
+	}
+	if names := tree.names(hierarchical); len(names) != 0 {
+		t.Errorf("hierarchical consolidation has children %v, want none", names)
+	}
+	final := tree.one(t, consolidate, "chat stub")
+	if final.Attributes["contractis.llm.stream"] != true {
+		t.Error("the final consolidation was not streamed")
+	}
+	if final.Start.Before(hierarchical.End) {
+		t.Error("the final consolidation starts before the hierarchical one ends")
+	}
+	positive(t, consolidate, "gen_ai.usage.input_tokens")
+	positive(t, consolidate, "gen_ai.request.max_tokens")
+
+	// El contrato se marca en análisis antes de la fase 1 y se guarda después de consolidar
+	updates := tree.child(root, "UPDATE contracts")
+	if len(updates) != 2 || updates[0].End.After(phase1.Start) || updates[1].Start.Before(consolidate.End) {
+		t.Errorf("UPDATE contracts spans = %d, want one before phase 1 and one after the consolidation (analysis children: %v)",
+			len(updates), tree.names(root))
+	}
+}
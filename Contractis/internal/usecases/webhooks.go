@@ -0,0 +1,416 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+	"github.com/rodascaar/contractis/internal/domain/services"
+)
+
+const (
+	// webhookSecretPrefix identifica los secretos de firma de los webhooks
+	webhookSecretPrefix = "whsec_"
+	// webhookDueBatch es la cantidad de entregas vencidas que se leen por vez
+	webhookDueBatch = 50
+	// webhookConcurrency es la cantidad de entregas que se intentan a la vez
+	webhookConcurrency = 4
+	// webhookPruneInterval es cada cuánto se borran del log las entregas viejas
+	webhookPruneInterval = time.Hour
+)
+
+// WebhookUseCase gestiona los webhooks de cada workspace y entrega sus eventos. Publish
+// registra una entrega pendiente por cada webhook suscrito y Run las intenta, reintentando
+// las fallidas con espera exponencial. Las entregas se guardan en la base, así que las
+// pendientes se retoman al reiniciar el servidor.
+type WebhookUseCase struct {
+	webhookRepo repositories.WebhookRepository
+	sender      services.WebhookSender
+	policy      entities.WebhookPolicy
+	wake        chan struct{}
+}
+
+// NewWebhookUseCase crea una nueva instancia del caso de uso
+func NewWebhookUseCase(
+	webhookRepo repositories.WebhookRepository,
+	sender services.WebhookSender,
+	policy entities.WebhookPolicy,
+) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		policy:      policy,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// List lista los webhooks del workspace
+func (uc *WebhookUseCase) List(ctx context.Context, workspaceID int64) ([]*entities.Webhook, error) {
+	return uc.webhookRepo.List(ctx, workspaceID)
+}
+
+// Get obtiene un webhook del workspace
+func (uc *WebhookUseCase) Get(ctx context.Context, workspaceID, id int64) (*entities.Webhook, error) {
+	return uc.webhookRepo.GetByID(ctx, workspaceID, id)
+}
+
+// Create valida y guarda un webhook con un secreto de firma nuevo, que queda en el
+// webhook retornado para mostrarlo una única vez
+func (uc *WebhookUseCase) Create(ctx context.Context, webhook *entities.Webhook) (*entities.Webhook, error) {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	webhook.Description = strings.TrimSpace(webhook.Description)
+	webhook.Events = uniqueEvents(webhook.Events)
+	if err := uc.validate(webhook); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+	if principal := entities.PrincipalFromContext(ctx); principal != nil {
+		webhook.CreatedBy = principal.Subject
+	}
+
+	id, err := uc.webhookRepo.Create(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return uc.webhookRepo.GetByID(ctx, webhook.WorkspaceID, id)
+}
+
+// Update aplica los cambios indicados. Con RotateSecret se genera un secreto nuevo y el
+// anterior deja de ser válido en las entregas siguientes.
+func (uc *WebhookUseCase) Update(ctx context.Context, workspaceID, id int64, update entities.WebhookUpdate) (*entities.Webhook, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		webhook.URL = strings.TrimSpace(*update.URL)
+	}
+	if update.Description != nil {
+		webhook.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Events != nil {
+		webhook.Events = uniqueEvents(update.Events)
+	}
+	if update.Active != nil {
+		webhook.Active = *update.Active
+	}
+	if err := uc.validate(webhook); err != nil {
+		return nil, err
+	}
+	if update.RotateSecret {
+		if webhook.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return uc.webhookRepo.GetByID(ctx, workspaceID, id)
+}
+
+// validate comprueba el webhook y, salvo que la política lo permita, rechaza las URLs
+// a localhost o a una IP privada. Los nombres de host se comprueban al entregar, con
+// la IP resuelta (ver webhooks.Sender).
+func (uc *WebhookUseCase) validate(webhook *entities.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	if uc.policy.AllowPrivateNetworks {
+		return nil
+	}
+	u, err := url.Parse(webhook.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entities.ErrInvalidWebhook, err)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	ip, err := netip.ParseAddr(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (err == nil && entities.IsPrivateAddress(ip)) {
+		return fmt.Errorf("%w: url must not point to a private or local address", entities.ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// Delete elimina un webhook y su log de entregas
+func (uc *WebhookUseCase) Delete(ctx context.Context, workspaceID, id int64) error {
+	return uc.webhookRepo.Delete(ctx, workspaceID, id)
+}
+
+// Deliveries lista el log de entregas de un webhook, de la más reciente a la más antigua
+func (uc *WebhookUseCase) Deliveries(ctx context.Context, workspaceID, id, beforeID int64, limit int) ([]*entities.WebhookDelivery, error) {
+	if _, err := uc.webhookRepo.GetByID(ctx, workspaceID, id); err != nil {
+		return nil, err
+	}
+	return uc.webhookRepo.ListDeliveries(ctx, id, beforeID, limit)
+}
+
+// Test envía un evento webhook.test y espera el resultado. Se intenta una sola vez, también
+// con el webhook desactivado, y queda en el log de entregas como cualquier otra.
+func (uc *WebhookUseCase) Test(ctx context.Context, workspaceID, id int64) (*entities.WebhookDelivery, error) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, eventID, err := newWebhookPayload(workspaceID, entities.EventWebhookTest, map[string]any{
+		"webhook_id": webhook.ID,
+		"message":    "Entrega de prueba de Contractis",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// El resultado se guarda aunque el cliente corte la petición
+	ctx = context.WithoutCancel(ctx)
+	delivery := newDelivery(webhook, eventID, entities.EventWebhookTest, payload, nil)
+	if err := uc.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	uc.attempt(ctx, webhook, delivery, false)
+	return delivery, nil
+}
+
+// Publish registra una entrega pendiente del evento para cada webhook activo del workspace
+// suscrito a él y despierta a Run. Implementa services.EventPublisher.
+func (uc *WebhookUseCase) Publish(ctx context.Context, workspaceID int64, event entities.WebhookEvent, data any) {
+	// El evento no debe perderse porque la petición que lo originó se haya cancelado
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := uc.webhookRepo.ListSubscribed(ctx, workspaceID, event)
+	if err != nil {
+		slog.ErrorContext(ctx, "error buscando webhooks suscritos", "event", event, "workspace_id", workspaceID, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, eventID, err := newWebhookPayload(workspaceID, event, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "error codificando evento de webhook", "event", event, "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, webhook := range webhooks {
+		delivery := newDelivery(webhook, eventID, event, payload, &now)
+		if err := uc.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			slog.ErrorContext(ctx, "error registrando entrega de webhook", "event", event, "webhook_id", webhook.ID, "error", err)
+		}
+	}
+	slog.DebugContext(ctx, "evento encolado para webhooks", "event", event, "event_id", eventID, "webhooks", len(webhooks))
+
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run intenta las entregas pendientes a medida que se publican eventos o vencen sus
+// reintentos, hasta que el contexto se cancele. Un intento cortado por la cancelación no
+// cuenta: la entrega sigue pendiente y se retoma al volver a iniciar.
+func (uc *WebhookUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(entities.WebhookPollInterval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		uc.DeliverDue(ctx)
+
+		if time.Since(lastPrune) >= webhookPruneInterval {
+			lastPrune = time.Now()
+			if count, err := uc.webhookRepo.PruneDeliveries(ctx, lastPrune.Add(-entities.WebhookDeliveryRetention)); err != nil {
+				slog.ErrorContext(ctx, "error depurando el log de webhooks", "error", err)
+			} else if count > 0 {
+				slog.InfoContext(ctx, "entregas de webhooks depuradas del log", "count", count)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.wake:
+		}
+	}
+}
+
+// DeliverDue intenta todas las entregas pendientes cuyo próximo intento ya venció
+func (uc *WebhookUseCase) DeliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := uc.webhookRepo.ListDue(ctx, time.Now(), webhookDueBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "error leyendo entregas de webhooks pendientes", "error", err)
+			}
+			return
+		}
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, webhookConcurrency)
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func() {
+				defer func() { <-slots; wg.Done() }()
+				uc.deliver(ctx, delivery)
+			}()
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookDueBatch {
+			return
+		}
+	}
+}
+
+// deliver intenta una entrega pendiente; si el webhook ya no está activo la descarta
+func (uc *WebhookUseCase) deliver(ctx context.Context, delivery *entities.WebhookDelivery) {
+	webhook, err := uc.webhookRepo.GetByID(ctx, delivery.WorkspaceID, delivery.WebhookID)
+	switch {
+	case errors.Is(err, entities.ErrWebhookNotFound):
+		uc.discard(ctx, delivery, "webhook was deleted")
+		return
+	case err != nil:
+		slog.ErrorContext(ctx, "error obteniendo webhook", "webhook_id", delivery.WebhookID, "error", err)
+		return
+	case !webhook.Active:
+		uc.discard(ctx, delivery, "webhook is disabled")
+		return
+	}
+	uc.attempt(ctx, webhook, delivery, true)
+}
+
+// attempt hace un intento y guarda su resultado. Con retry, un fallo reprograma la entrega
+// según la política hasta agotar los intentos.
+func (uc *WebhookUseCase) attempt(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery, retry bool) {
+	attemptCtx, cancel := context.WithTimeout(ctx, uc.policy.Timeout)
+	start := time.Now()
+	status, body, err := uc.sender.Send(attemptCtx, webhook, delivery)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.DurationMs = now.Sub(start).Milliseconds()
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+	delivery.NextAttemptAt = nil
+
+	logAttrs := []any{"webhook_id", webhook.ID, "delivery_id", delivery.ID, "event", delivery.Event, "attempt", delivery.Attempts}
+	switch {
+	case err == nil && status >= 200 && status < 300:
+		delivery.Status = entities.DeliverySucceeded
+		delivery.Error = ""
+		slog.DebugContext(ctx, "webhook entregado", append(logAttrs, "status", status)...)
+	default:
+		if err != nil {
+			delivery.Error = err.Error()
+		} else {
+			delivery.Error = fmt.Sprintf("unexpected response status %d", status)
+		}
+		if retry && delivery.Attempts < uc.policy.MaxAttempts {
+			next := now.Add(uc.policy.Backoff(delivery.Attempts))
+			delivery.Status = entities.DeliveryPending
+			delivery.NextAttemptAt = &next
+			slog.WarnContext(ctx, "entrega de webhook fallida, se reintentará",
+				append(logAttrs, "error", delivery.Error, "next_attempt_at", next)...)
+		} else {
+			delivery.Status = entities.DeliveryFailed
+			slog.ErrorContext(ctx, "entrega de webhook fallida", append(logAttrs, "error", delivery.Error)...)
+		}
+	}
+
+	if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "error guardando resultado de entrega de webhook", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// discard marca como fallida una entrega que ya no se puede intentar
+func (uc *WebhookUseCase) discard(ctx context.Context, delivery *entities.WebhookDelivery, reason string) {
+	delivery.Status = entities.DeliveryFailed
+	delivery.Error = reason
+	delivery.NextAttemptAt = nil
+	if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "error guardando resultado de entrega de webhook", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// newDelivery arma una entrega pendiente; sin nextAttempt no la intenta Run
+func newDelivery(webhook *entities.Webhook, eventID string, event entities.WebhookEvent, payload []byte, nextAttempt *time.Time) *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		WorkspaceID:   webhook.WorkspaceID,
+		EventID:       eventID,
+		Event:         event,
+		Status:        entities.DeliveryPending,
+		Payload:       payload,
+		CreatedAt:     time.Now(),
+		NextAttemptAt: nextAttempt,
+	}
+}
+
+// newWebhookPayload codifica el cuerpo de las entregas de un evento. Todas las entregas
+// del mismo evento comparten el ID, que los receptores pueden usar para descartar duplicados.
+func newWebhookPayload(workspaceID int64, event entities.WebhookEvent, data any) ([]byte, string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", fmt.Errorf("error generating event id: %w", err)
+	}
+	eventID := "evt_" + hex.EncodeToString(id)
+
+	payload, err := json.Marshal(entities.WebhookPayload{
+		ID:          eventID,
+		Event:       event,
+		CreatedAt:   time.Now().UTC(),
+		WorkspaceID: workspaceID,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+	return payload, eventID, nil
+}
+
+// newWebhookSecret genera un secreto de firma aleatorio de 256 bits
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// uniqueEvents quita los eventos repetidos conservando el orden
+func uniqueEvents(events []entities.WebhookEvent) []entities.WebhookEvent {
+	unique := make([]entities.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		event = entities.WebhookEvent(strings.TrimSpace(string(event)))
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+)
+
+func TestWebhookValidateRejectsPrivateURLs(t *testing.T) {
+	policy := entities.DefaultWebhookPolicy()
+	strict := NewWebhookUseCase(nil, nil, policy)
+	policy.AllowPrivateNetworks = true
+	onPremise := NewWebhookUseCase(nil, nil, policy)
+
+	tests := []struct {
+		url     string
+		private bool
+	}{
+		{"http://localhost:8080/hook", true},
+		{"http://LOCALHOST./hook", true},
+		{"http://api.localhost/hook", true},
+		{"http://127.0.0.1/hook", true},
+		{"http://10.0.0.5/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://[::1]/hook", true},
+		{"http://[::ffff:192.168.0.1]/hook", true},
+		{"https://hooks.example.com/contractis", false},
+		{"https://8.8.8.8/hook", false},
+	}
+	for _, tt := range tests {
+		webhook := &entities.Webhook{URL: tt.url, Events: []entities.WebhookEvent{entities.EventContractDeleted}}
+		if err := strict.validate(webhook); (err != nil) != tt.private || (err != nil && !errors.Is(err, entities.ErrInvalidWebhook)) {
+			t.Errorf("validate(%s) = %v, want rejected %v", tt.url, err, tt.private)
+		}
+		if err := onPremise.validate(webhook); err != nil {
+			t.Errorf("validate(%s) with private networks allowed = %v", tt.url, err)
+		}
+	}
+}
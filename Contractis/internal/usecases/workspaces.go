@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rodascaar/contractis/internal/domain/entities"
+	"github.com/rodascaar/contractis/internal/domain/repositories"
+)
+
+// WorkspaceUseCase gestiona usuarios, workspaces y el control de acceso por rol
+type WorkspaceUseCase struct {
+	userRepo      repositories.UserRepository
+	workspaceRepo repositories.WorkspaceRepository
+}
+
+// NewWorkspaceUseCase crea una nueva instancia del caso de uso
+func NewWorkspaceUseCase(userRepo repositories.UserRepository, workspaceRepo repositories.WorkspaceRepository) *WorkspaceUseCase {
+	return &WorkspaceUseCase{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// Authorize resuelve el workspace de la petición y verifica que el usuario tenga al
+// menos el rol requerido. Con workspaceID cero se usa el primer workspace del usuario.
+// Un workspace ajeno se informa como inexistente para no revelar su existencia.
+func (uc *WorkspaceUseCase) Authorize(
+	ctx context.Context,
+	principal *entities.Principal,
+	workspaceID int64,
+	required entities.Role,
+) (*entities.Membership, error) {
+	if principal == nil || principal.UserID == 0 {
+		return nil, entities.ErrForbidden
+	}
+
+	if workspaceID == 0 {
+		workspaces, err := uc.workspaceRepo.ListForUser(ctx, principal.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(workspaces) == 0 {
+			return nil, entities.ErrWorkspaceNotFound
+		}
+		workspaceID = workspaces[0].ID
+	}
+
+	membership, err := uc.workspaceRepo.GetMembership(ctx, workspaceID, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !membership.Role.Allows(required) {
+		return nil, fmt.Errorf("%w: role %s, requires %s", entities.ErrForbidden, membership.Role, required)
+	}
+	return membership, nil
+}
+
+// ListForUser lista los workspaces del usuario con su rol
+func (uc *WorkspaceUseCase) ListForUser(ctx context.Context, userID int64) ([]*entities.Workspace, error) {
+	return uc.workspaceRepo.ListForUser(ctx, userID)
+}
+
+// ListWorkspaces lista todos los workspaces
+func (uc *WorkspaceUseCase) ListWorkspaces(ctx context.Context) ([]*entities.Workspace, error) {
+	return uc.workspaceRepo.List(ctx)
+}
+
+// CreateWorkspace crea un workspace
+func (uc *WorkspaceUseCase) CreateWorkspace(ctx context.Context, name string) (*entities.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("workspace name is required")
+	}
+
+	id, err := uc.workspaceRepo.Create(ctx, &entities.Workspace{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return uc.workspaceRepo.GetByID(ctx, id)
+}
+
+// ListUsers lista todos los usuarios
+func (uc *WorkspaceUseCase) ListUsers(ctx context.Context) ([]*entities.User, error) {
+	return uc.userRepo.List(ctx)
+}
+
+// GetUserByEmail obtiene un usuario por email
+func (uc *WorkspaceUseCase) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return uc.userRepo.GetByEmail(ctx, email)
+}
+
+// CreateUser crea un usuario
+func (uc *WorkspaceUseCase) CreateUser(ctx context.Context, email, name string) (*entities.User, error) {
+	email = strings.TrimSpace(email)
+	if !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("invalid email %q", email)
+	}
+
+	id, err := uc.userRepo.Create(ctx, &entities.User{Email: email, Name: strings.TrimSpace(name)})
+	if err != nil {
+		return nil, err
+	}
+	return uc.userRepo.GetByID(ctx, id)
+}
+
+// SetMember agrega un usuario (por email) a un workspace con el rol dado, o cambia su rol
+func (uc *WorkspaceUseCase) SetMember(ctx context.Context, workspaceID int64, email string, role entities.Role) error {
+	if !role.IsValid() {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	if _, err := uc.workspaceRepo.GetByID(ctx, workspaceID); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return uc.workspaceRepo.SetMember(ctx, &entities.Membership{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        role,
+	})
+}
+
+// RemoveMember quita un usuario (por email) de un workspace
+func (uc *WorkspaceUseCase) RemoveMember(ctx context.Context, workspaceID int64, email string) error {
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	return uc.workspaceRepo.RemoveMember(ctx, workspaceID, user.ID)
+}
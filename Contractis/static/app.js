@@ -49,6 +49,119 @@ const historyList = document.getElementById('historyList');
 // File handling
 let selectedFile = null;
 
+// Identificador estable del navegador, usado en el nombre de su perfil LLM online
+function getClientId() {
+    let id = localStorage.getItem('clientId');
+    if (!id) {
+        id = (crypto.randomUUID ? crypto.randomUUID() : Date.now().toString(36) + Math.random().toString(36).slice(2));
+        localStorage.setItem('clientId', id);
+    }
+    return id;
+}
+
+const clientId = getClientId();
+
+// Autenticación: el servidor exige una API key o una sesión. Ante un 401 se redirige al
+// proveedor SSO si está configurado; si no, se pide la API key una vez, se intercambia
+// por una cookie de sesión HttpOnly y se reintenta la petición.
+let sessionPromise = null;
+
+async function oidcEnabled() {
+    try {
+        const response = await fetch('/api/auth/config');
+        const data = await response.json();
+        return Boolean(data.oidc);
+    } catch (e) {
+        return false;
+    }
+}
+
+function login() {
+    if (!sessionPromise) {
+        sessionPromise = (async () => {
+            if (await oidcEnabled()) {
+                window.location.href = '/auth/login?return=' + encodeURIComponent(window.location.pathname);
+                // La página se recarga al volver del proveedor
+                await new Promise(() => {});
+            }
+            const key = window.prompt('Ingresa tu API key de Contractis:');
+            if (!key) {
+                throw new Error('Se requiere una API key para continuar');
+            }
+            const response = await fetch('/api/auth/session', {
+                method: 'POST',
+                headers: { 'Authorization': 'Bearer ' + key.trim() }
+            });
+            if (!response.ok) {
+                throw new Error('API key inválida');
+            }
+        })().finally(() => { sessionPromise = null; });
+    }
+    return sessionPromise;
+}
+
+// Workspace activo: el servidor acota el historial y los análisis a este workspace
+let workspaceId = localStorage.getItem('workspaceId') || '';
+
+function withWorkspace(options) {
+    const opts = Object.assign({}, options);
+    if (workspaceId) {
+        opts.headers = Object.assign({}, opts.headers, { 'X-Workspace-ID': workspaceId });
+    }
+    return opts;
+}
+
+async function apiFetch(url, options) {
+    const response = await fetch(url, withWorkspace(options));
+    if (response.status !== 401) {
+        return response;
+    }
+    await login();
+    return fetch(url, withWorkspace(options));
+}
+
+// loadWorkspaces muestra el selector de workspace si el usuario pertenece a más de uno
+async function loadWorkspaces() {
+    const select = document.getElementById('workspaceSelect');
+    if (!select) return;
+
+    try {
+        const response = await apiFetch('/api/workspaces');
+        const data = await response.json();
+        if (!data.success) return;
+
+        const workspaces = data.data || [];
+        if (!workspaces.some(ws => String(ws.id) === workspaceId)) {
+            workspaceId = workspaces.length > 0 ? String(workspaces[0].id) : '';
+            localStorage.setItem('workspaceId', workspaceId);
+        }
+
+        select.innerHTML = '';
+        workspaces.forEach(ws => {
+            const option = document.createElement('option');
+            option.value = String(ws.id);
+            option.textContent = ws.name + ' (' + ws.role + ')';
+            select.appendChild(option);
+        });
+        select.value = workspaceId;
+        select.style.display = workspaces.length > 1 ? 'inline-block' : 'none';
+    } catch (error) {
+        console.error('Error loading workspaces:', error);
+    }
+}
+
+document.addEventListener('DOMContentLoaded', () => {
+    const select = document.getElementById('workspaceSelect');
+    if (select) {
+        select.addEventListener('change', () => {
+            workspaceId = select.value;
+            localStorage.setItem('workspaceId', workspaceId);
+        });
+    }
+    loadWorkspaces();
+});
+let queuePollTimer = null;
+
 // Load initial LLM configuration from localStorage or use defaults
 function loadInitialConfig() {
     const saved = localStorage.getItem('llmConfig');
@@ -65,7 +178,6 @@ function loadInitialConfig() {
         type: 'local',
         localUrl: 'http://localhost:1234/v1/chat/completions',
         apiUrl: '',
-        apiKey: '',
         modelName: '',
         maxTokens: 800
     };
@@ -194,7 +306,7 @@ function estimateTokens() {
     formData.append('file', selectedFile);
     formData.append('maxTokens', llmConfig.maxTokens.toString());
 
-    fetch('/estimate', {
+    apiFetch('/estimate', {
         method: 'POST',
         body: formData
     })
@@ -283,19 +395,40 @@ function analyzeContract() {
 
     const formData = new FormData();
     formData.append('file', selectedFile);
-    formData.append('llmConfig', JSON.stringify(llmConfig));
+    // Los modelos en línea usan un perfil del servidor: la API key no sale del servidor
+    if (llmConfig.type === 'online') {
+        const profileId = currentProfileId();
+        if (!profileId) {
+            hideLoading();
+            showError('❌ Ingresa la API Key en Configuración: cada workspace guarda la suya');
+            return;
+        }
+        formData.append('profileId', String(profileId));
+    } else {
+        formData.append('llmConfig', JSON.stringify(llmConfig));
+    }
+
+    startQueuePolling();
 
-    fetch('/upload', {
+    // Respuesta en streaming (SSE): el reporte final se muestra a medida que se genera
+    apiFetch('/upload', {
         method: 'POST',
-        body: formData,
-        // Evitar timeout automático del navegador para modelos locales
-        signal: llmConfig.type === 'local' ? undefined : AbortSignal.timeout(60000) // 60s para online, sin límite para local
+        headers: { 'Accept': 'text/event-stream' },
+        body: formData
     })
     .then(response => {
+        const contentType = response.headers.get('Content-Type') || '';
         if (!response.ok) {
+            // Los errores de validación y de análisis traen el mensaje en el cuerpo JSON
+            if (contentType.includes('application/json')) {
+                return response.json();
+            }
             throw new Error(`HTTP ${response.status}: ${response.statusText}`);
         }
-        return response.json();
+        if (!contentType.includes('text/event-stream')) {
+            return response.json();
+        }
+        return readAnalysisStream(response);
     })
     .then(data => {
         hideLoading();
@@ -303,6 +436,7 @@ function analyzeContract() {
             showResults(data.data);
         } else {
             console.error('❌ Error en respuesta del backend:', data.error);
+            hideResults();
             showError(data.error || 'Error desconocido');
         }
     })
@@ -326,6 +460,61 @@ function analyzeContract() {
     });
 }
 
+// Lee los eventos SSE de /upload: "token" actualiza la vista parcial, "result"/"error" cierran
+async function readAnalysisStream(response) {
+    const reader = response.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = '';
+    let partial = '';
+    let finalData = null;
+
+    const handleEvent = (eventName, data) => {
+        if (eventName === 'token') {
+            partial += data.token;
+            showPartialResults(partial);
+        } else if (eventName === 'result' || eventName === 'error') {
+            finalData = data;
+        }
+    };
+
+    while (finalData === null) {
+        const { value, done } = await reader.read();
+        if (done) break;
+        buffer += decoder.decode(value, { stream: true });
+
+        let separator;
+        while ((separator = buffer.indexOf('\n\n')) !== -1) {
+            const rawEvent = buffer.slice(0, separator);
+            buffer = buffer.slice(separator + 2);
+
+            let eventName = 'message';
+            const dataLines = [];
+            rawEvent.split('\n').forEach(line => {
+                if (line.startsWith('event:')) eventName = line.slice(6).trim();
+                else if (line.startsWith('data:')) dataLines.push(line.slice(5).trim());
+            });
+            if (dataLines.length > 0) {
+                handleEvent(eventName, JSON.parse(dataLines.join('\n')));
+            }
+        }
+    }
+
+    if (finalData === null) {
+        throw new Error('La conexión se cerró antes de terminar el análisis');
+    }
+    return finalData;
+}
+
+// Muestra el reporte parcial mientras el modelo lo genera
+function showPartialResults(content) {
+    stopQueuePolling();
+    updateLoadingProgress('Generando reporte final...');
+    resultsContent.innerHTML = formatAnalysisContent(content);
+    const resultsHeader = document.querySelector('.results-header h3');
+    resultsHeader.textContent = 'Generando análisis...';
+    resultsSection.style.display = 'block';
+}
+
 function showResults(content, contractInfo = null) {
     // Validar que content sea válido
     if (content === null || content === undefined) {
@@ -377,10 +566,36 @@ function showLoading() {
 }
 
 function hideLoading() {
+    stopQueuePolling();
     loadingSection.style.display = 'none';
     enableControls();
 }
 
+// Consultar periódicamente la posición en la cola del LLM mientras se analiza
+function startQueuePolling() {
+    stopQueuePolling();
+    queuePollTimer = setInterval(() => {
+        apiFetch('/api/queue')
+            .then(response => response.json())
+            .then(data => {
+                if (!data.success) return;
+                if (data.position > 0) {
+                    updateLoadingProgress('En cola: posición ' + data.position + '. Esperando turno del modelo...');
+                } else {
+                    updateLoadingProgress('Procesando documento...');
+                }
+            })
+            .catch(() => {});
+    }, 3000);
+}
+
+function stopQueuePolling() {
+    if (queuePollTimer) {
+        clearInterval(queuePollTimer);
+        queuePollTimer = null;
+    }
+}
+
 function disableControls() {
     // Deshabilitar área de carga
     uploadArea.style.pointerEvents = 'none';
@@ -562,7 +777,11 @@ function loadSettings() {
         if (llmType) llmType.value = llmConfig.type || 'local';
         if (localUrl) localUrl.value = llmConfig.localUrl || '';
         if (apiUrl) apiUrl.value = llmConfig.apiUrl || '';
-        if (apiKey) apiKey.value = llmConfig.apiKey || '';
+        // La API key nunca se muestra: si hay perfil guardado, vacío = conservar la actual
+        if (apiKey) {
+            apiKey.value = '';
+            apiKey.placeholder = currentProfileId() ? '•••••••• (guardada en el servidor)' : 'sk-...';
+        }
 
         // Set model name based on type
         const localModelName = document.getElementById('localModelName');
@@ -579,7 +798,6 @@ function loadSettings() {
             type: 'local',
             localUrl: 'http://localhost:1234/v1/chat/completions',
             apiUrl: '',
-            apiKey: '',
             modelName: '',
             maxTokens: 800
         };
@@ -588,7 +806,55 @@ function loadSettings() {
     }
 }
 
-function saveSettings() {
+// currentProfileId retorna el perfil guardado en el servidor si es del workspace activo:
+// los perfiles son de cada workspace y el de otro no se puede usar
+function currentProfileId() {
+    if (!llmConfig.profileId || String(llmConfig.profileWorkspace || '') !== workspaceId) {
+        return null;
+    }
+    return llmConfig.profileId;
+}
+
+// saveOnlineProfile crea o actualiza el perfil personal del usuario en el servidor con la
+// API key y retorna su ID (no requiere permisos de administrador)
+async function saveOnlineProfile(config, key) {
+    const profile = {
+        name: 'web-' + clientId,
+        type: 'online',
+        apiUrl: config.apiUrl,
+        apiKey: key,
+        modelName: config.modelName,
+        maxTokens: config.maxTokens,
+        personal: true
+    };
+
+    const url = config.profileId ? '/api/profiles/update?id=' + config.profileId : '/api/profiles';
+    let response = await apiFetch(url, {
+        method: config.profileId ? 'PUT' : 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(profile)
+    });
+
+    // El perfil pudo haber sido eliminado en el servidor: crearlo de nuevo
+    if (response.status === 404 && config.profileId) {
+        if (!key) {
+            throw new Error('El perfil guardado ya no existe, ingresa la API Key nuevamente');
+        }
+        response = await apiFetch('/api/profiles', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(profile)
+        });
+    }
+
+    const data = await response.json().catch(() => ({}));
+    if (!response.ok || !data.success) {
+        throw new Error(data.error || 'No se pudo guardar el perfil en el servidor');
+    }
+    return data.data.id;
+}
+
+async function saveSettings() {
     try {
         // Obtener valores de los inputs
         const localModelName = document.getElementById('localModelName');
@@ -598,12 +864,12 @@ function saveSettings() {
             type: llmType.value,
             localUrl: localUrl ? localUrl.value.trim() : '',
             apiUrl: apiUrl ? apiUrl.value.trim() : '',
-            apiKey: apiKey ? apiKey.value.trim() : '',
             modelName: (llmType.value === 'local' ?
                 (localModelName ? localModelName.value.trim() : '') :
                 (onlineModelName ? onlineModelName.value.trim() : '')),
             maxTokens: parseInt(maxTokens ? maxTokens.value : '800') || 800
         };
+        const newApiKey = apiKey ? apiKey.value.trim() : '';
 
         // Validar configuración antes de guardar
         let validationError = null;
@@ -617,7 +883,7 @@ function saveSettings() {
         } else if (newConfig.type === 'online') {
             if (!newConfig.apiUrl) {
                 validationError = '❌ URL de API es requerida para modelos en línea';
-            } else if (!newConfig.apiKey) {
+            } else if (!newApiKey && !currentProfileId()) {
                 validationError = '❌ API Key es requerida para modelos en línea';
             } else if (!newConfig.modelName) {
                 validationError = '❌ Nombre del Modelo es requerido para modelos en línea';
@@ -635,6 +901,19 @@ function saveSettings() {
             return;
         }
 
+        // En línea: la API key se envía una sola vez al servidor y el navegador guarda solo el ID del perfil
+        // (el ID se conserva al pasar a local para reutilizar el mismo perfil después)
+        newConfig.profileId = llmConfig.profileId;
+        newConfig.profileWorkspace = llmConfig.profileWorkspace;
+        if (newConfig.type === 'online') {
+            newConfig.profileId = await saveOnlineProfile(
+                Object.assign({}, newConfig, { profileId: currentProfileId() }),
+                newApiKey
+            );
+            newConfig.profileWorkspace = workspaceId;
+            if (apiKey) apiKey.value = '';
+        }
+
         // Intentar guardar en localStorage
         localStorage.setItem('llmConfig', JSON.stringify(newConfig));
 
@@ -699,7 +978,7 @@ window.addEventListener('resize', () => {
             const currentContracts = historyList.querySelector('.history-table, .history-cards');
             if (currentContracts) {
                 // Obtener los contratos actuales y re-renderizar
-                fetch('/api/contracts?limit=50')
+                apiFetch('/api/contracts?limit=50')
                     .then(response => response.json())
                     .then(data => {
                         if (data.success && data.data) {
@@ -726,7 +1005,7 @@ function closeHistoryModalFn() {
 function loadHistory() {
     historyList.innerHTML = '<div class="loading-spinner"></div><p>Cargando historial...</p>';
     
-    fetch('/api/contracts?limit=50')
+    apiFetch('/api/contracts?limit=50')
         .then(response => response.json())
         .then(data => {
             if (data.success && data.data) {
@@ -751,7 +1030,7 @@ function searchHistory() {
     
     historyList.innerHTML = '<div class="loading-spinner"></div><p>Buscando...</p>';
     
-    fetch(`/api/contracts/search?q=${encodeURIComponent(query)}&limit=50`)
+    apiFetch(`/api/contracts/search?q=${encodeURIComponent(query)}&limit=50`)
         .then(response => response.json())
         .then(data => {
             if (data.success && data.data) {
@@ -813,7 +1092,8 @@ function displayHistory(contracts) {
                     </div>
                     <div class="history-card-actions">
                         ${contract.status === 'completed' ? 
-                            `<button class="btn-small" onclick="viewContract(${contract.id})">👁️ Ver</button>` : ''}
+                            `<button class="btn-small" onclick="viewContract(${contract.id})">👁️ Ver</button>
+                             <button class="btn-small" onclick="exportContract(${contract.id})">⬇️ Exportar</button>` : ''}
                         <button class="btn-small btn-danger" onclick="deleteContract(${contract.id})">🗑️ Eliminar</button>
                     </div>
                 </div>
@@ -841,7 +1121,8 @@ function displayHistory(contracts) {
                 <td>${time}</td>
                 <td class="history-actions">
                     ${contract.status === 'completed' ? 
-                        `<button class="btn-small" onclick="viewContract(${contract.id})">👁️ Ver</button>` : ''}
+                        `<button class="btn-small" onclick="viewContract(${contract.id})">👁️ Ver</button>
+                         <button class="btn-small" onclick="exportContract(${contract.id})" title="Exportar">⬇️</button>` : ''}
                     <button class="btn-small btn-danger" onclick="deleteContract(${contract.id})">🗑️</button>
                 </td>
             </tr>`;
@@ -857,13 +1138,14 @@ function getStatusBadge(status) {
         'completed': '<span class="badge badge-success">✓ Completado</span>',
         'failed': '<span class="badge badge-error">✗ Fallido</span>',
         'analyzing': '<span class="badge badge-warning">⏳ Analizando</span>',
+        'interrupted': '<span class="badge badge-warning">⏸️ Interrumpido</span>',
         'pending': '<span class="badge badge-info">⏸️ Pendiente</span>'
     };
     return badges[status] || status;
 }
 
 function loadStats() {
-    fetch('/api/contracts/stats')
+    apiFetch('/api/contracts/stats')
         .then(response => response.json())
         .then(data => {
             if (data.success && data.data) {
@@ -881,7 +1163,7 @@ function loadStats() {
 }
 
 function viewContract(id) {
-    fetch(`/api/contracts/get?id=${id}`)
+    apiFetch(`/api/contracts/get?id=${id}`)
         .then(response => response.json())
         .then(data => {
             if (data.success && data.data) {
@@ -902,12 +1184,33 @@ function viewContract(id) {
         });
 }
 
+// exportContract descarga el análisis desde el servidor (la exportación queda auditada)
+async function exportContract(id) {
+    try {
+        const response = await apiFetch(`/api/contracts/export?id=${id}`);
+        if (!response.ok) {
+            throw new Error(await response.text());
+        }
+        const disposition = response.headers.get('Content-Disposition') || '';
+        const match = disposition.match(/filename="([^"]+)"/);
+        const url = URL.createObjectURL(await response.blob());
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = match ? match[1] : `analisis-${id}.txt`;
+        link.click();
+        URL.revokeObjectURL(url);
+    } catch (error) {
+        console.error('Error exporting contract:', error);
+        alert('Error al exportar el contrato');
+    }
+}
+
 function deleteContract(id) {
-    if (!confirm('¿Estás seguro de que quieres eliminar este contrato del historial?')) {
+    if (!confirm('¿Enviar este contrato a la papelera? Podrá restaurarse hasta que se purgue.')) {
         return;
     }
     
-    fetch(`/api/contracts/delete?id=${id}`, { method: 'POST' })
+    apiFetch(`/api/contracts/delete?id=${id}`, { method: 'POST' })
         .then(response => response.json())
         .then(data => {
             if (data.success) {